@@ -0,0 +1,183 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Record is one recorded call, written as a single line of JSON in a capture file.
+type Record struct {
+	// Method is the full gRPC method name, for example "/shared.MagicMath/MagicAdd".
+	Method string `json:"method"`
+	// Time is when the server received the call.
+	Time time.Time `json:"time"`
+	// Request and Response are the messages, in the protobuf JSON format. Response is empty if the call failed.
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	// Code is the name of the gRPC status code, for example "OK" or "InvalidArgument", and Message its message.
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+	// LatencyNanos is how long the server took to handle the call, in nanoseconds.
+	LatencyNanos int64 `json:"latencyNanos"`
+}
+
+// StatusCode returns the record's gRPC status code.
+func (r *Record) StatusCode() codes.Code {
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == r.Code {
+			return code
+		}
+	}
+
+	return codes.Unknown
+}
+
+// Latency returns how long the server took to handle the call.
+func (r *Record) Latency() time.Duration {
+	return time.Duration(r.LatencyNanos)
+}
+
+// Writer appends records to a capture file. When the file grows past its size limit it is rotated: the file is
+// renamed to path.1, the previous path.1 to path.2 and so on, keeping at most the given number of old files.
+// It is safe to use from many go routines at once.
+type Writer struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	// This mutex is used to protect the file, and the number of bytes written to it.
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+// NewWriter opens the capture file at path for appending, creating it if needed.
+// The file is rotated once it holds maxBytes, a maxBytes of zero means it is never rotated.
+func NewWriter(path string, maxBytes int64, maxBackups int) (*Writer, error) {
+	w := &Writer{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write appends a record to the capture file.
+func (w *Writer) Write(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(line)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+
+	return err
+}
+
+// Close closes the capture file.
+func (w *Writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.file.Close()
+}
+
+// This function opens the capture file, and remembers how big it already is.
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+
+	return nil
+}
+
+// This function moves each old file up by one, drops the oldest, and starts a new capture file.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	if w.maxBackups > 0 {
+		for i := w.maxBackups - 1; i >= 1; i-- {
+			os.Rename(backupPath(w.path, i), backupPath(w.path, i+1))
+		}
+		if err := os.Rename(w.path, backupPath(w.path, 1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(w.path); err != nil {
+		return err
+	}
+
+	return w.open()
+}
+
+// Files returns the capture file at path along with its rotated files which exist, oldest first,
+// which is the order their records were written in.
+func Files(path string) []string {
+	var files []string
+	for i := 1; ; i++ {
+		if _, err := os.Stat(backupPath(path, i)); err != nil {
+			break
+		}
+		files = append([]string{backupPath(path, i)}, files...)
+	}
+
+	return append(files, path)
+}
+
+func backupPath(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// ReadFile reads every record in a capture file.
+func ReadFile(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", path, line, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
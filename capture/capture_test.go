@@ -0,0 +1,110 @@
+package capture
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	writer, err := NewWriter(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewWriter() returned error: %v", err)
+	}
+
+	written := &Record{
+		Method:       "/shared.MagicMath/MagicAdd",
+		Time:         time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC),
+		Request:      []byte(`{"termOne":1,"termTwo":2}`),
+		Response:     []byte(`{"result":3}`),
+		Code:         codes.OK.String(),
+		LatencyNanos: 1500,
+	}
+	if err := writer.Write(written); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	writer.Close()
+
+	records, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() returned error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("ReadFile() returned %d records; want 1", len(records))
+	}
+
+	read := records[0]
+	if read.Method != written.Method || !read.Time.Equal(written.Time) || string(read.Request) != string(written.Request) ||
+		string(read.Response) != string(written.Response) || read.StatusCode() != codes.OK || read.Latency() != 1500 {
+		t.Errorf("ReadFile() = %+v; want %+v", read, written)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want codes.Code
+	}{
+		{"OK", codes.OK},
+		{"InvalidArgument", codes.InvalidArgument},
+		{"NotACode", codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		record := Record{Code: tt.code}
+		if got := record.StatusCode(); got != tt.want {
+			t.Errorf("StatusCode() of %q = %v; want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	// Each record is about 100 bytes, so every file holds two records.
+	writer, err := NewWriter(path, 250, 2)
+	if err != nil {
+		t.Fatalf("NewWriter() returned error: %v", err)
+	}
+
+	for i := range 7 {
+		record := &Record{Method: "/shared.MagicMath/MagicAdd", Request: []byte(`{}`), Code: "OK", LatencyNanos: int64(i)}
+		if err := writer.Write(record); err != nil {
+			t.Fatalf("Write() returned error: %v", err)
+		}
+	}
+	writer.Close()
+
+	files := Files(path)
+	want := []string{path + ".2", path + ".1", path}
+	if len(files) != len(want) {
+		t.Fatalf("Files() = %v; want %v", files, want)
+	}
+
+	// Only the newest records survive, and reading the files in order gives them in the order they were written.
+	var latencies []int64
+	for i, file := range files {
+		if file != want[i] {
+			t.Errorf("Files()[%d] = %s; want %s", i, file, want[i])
+		}
+		records, err := ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) returned error: %v", file, err)
+		}
+		for _, record := range records {
+			latencies = append(latencies, record.LatencyNanos)
+		}
+	}
+
+	wantLatencies := []int64{2, 3, 4, 5, 6}
+	if len(latencies) != len(wantLatencies) {
+		t.Fatalf("records kept = %v; want %v", latencies, wantLatencies)
+	}
+	for i := range latencies {
+		if latencies[i] != wantLatencies[i] {
+			t.Fatalf("records kept = %v; want %v", latencies, wantLatencies)
+		}
+	}
+}
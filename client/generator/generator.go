@@ -0,0 +1,276 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Generator produces the terms the client sends to the server.
+// A generator draws from the random number generator it was created with, and is not safe for concurrent use.
+// Drawing every term from one go routine keeps runs with the same seed reproducible.
+type Generator interface {
+	// Double returns the next floating point term.
+	Double() float64
+	// Int returns the next integer term.
+	Int() int64
+}
+
+// Distributions lists the names of the distributions Parse accepts, along with their parameters.
+var Distributions = []string{
+	"uniform[:min:max]",
+	"normal[:mean:stddev]",
+	"zipf[:s:v:max]",
+	"edge[:probability]",
+}
+
+// Parse creates a generator from a distribution spec, which is the distribution's name optionally followed by its
+// parameters separated by colons, for example "uniform:-100:100", "normal:0:1", "zipf:1.5:1:1000" or "edge:0.5".
+// Every value is drawn from rng.
+func Parse(spec string, rng *rand.Rand) (Generator, error) {
+	name, rawParameters, _ := strings.Cut(spec, ":")
+
+	var parameters []float64
+	if rawParameters != "" {
+		for _, raw := range strings.Split(rawParameters, ":") {
+			parameter, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %q in distribution %q: %v", raw, spec, err)
+			}
+			parameters = append(parameters, parameter)
+		}
+	}
+
+	switch name {
+	case "uniform":
+		if len(parameters) == 0 {
+			return NewDefaultUniform(rng), nil
+		}
+		if len(parameters) != 2 {
+			return nil, fmt.Errorf("distribution %q needs a min and a max", spec)
+		}
+		return NewUniform(rng, parameters[0], parameters[1])
+	case "normal":
+		if len(parameters) == 0 {
+			parameters = []float64{0, 1}
+		}
+		if len(parameters) != 2 {
+			return nil, fmt.Errorf("distribution %q needs a mean and a standard deviation", spec)
+		}
+		return NewNormal(rng, parameters[0], parameters[1])
+	case "zipf":
+		if len(parameters) == 0 {
+			parameters = []float64{1.5, 1, 1000}
+		}
+		if len(parameters) != 3 {
+			return nil, fmt.Errorf("distribution %q needs s, v and max", spec)
+		}
+		return NewZipf(rng, parameters[0], parameters[1], parameters[2])
+	case "edge":
+		if len(parameters) == 0 {
+			parameters = []float64{0.5}
+		}
+		if len(parameters) != 1 {
+			return nil, fmt.Errorf("distribution %q needs a single probability", spec)
+		}
+		return NewEdgeCases(rng, parameters[0])
+	default:
+		return nil, fmt.Errorf("unknown distribution %q, expected one of %s", name, strings.Join(Distributions, ", "))
+	}
+}
+
+// ========================================== Uniform ==========================================
+
+// Uniform draws every value in a range with equal probability.
+type Uniform struct {
+	rng         *rand.Rand
+	doubleMin   float64
+	doubleWidth float64
+	intMin      int64
+	// The span is one less than the number of integers in the range, so that the full int64 range still fits.
+	intSpan uint64
+}
+
+// NewDefaultUniform draws doubles between zero and half the maximum float value, so that two of them can be added
+// without overflowing, and integers between zero and the 64 bit int max.
+func NewDefaultUniform(rng *rand.Rand) *Uniform {
+	return &Uniform{rng: rng, doubleMin: 0, doubleWidth: math.MaxFloat64 / 2, intMin: 0, intSpan: math.MaxInt64}
+}
+
+// NewUniform draws doubles between min and max, and integers between min and max rounded inwards.
+func NewUniform(rng *rand.Rand, min, max float64) (*Uniform, error) {
+	if math.IsNaN(min) || math.IsNaN(max) || math.IsInf(min, 0) || math.IsInf(max, 0) || min > max {
+		return nil, fmt.Errorf("uniform range [%v, %v] must be finite with min <= max", min, max)
+	}
+	// The width is what the drawn doubles are scaled by, a range such as [-MaxFloat64, MaxFloat64] is too wide for it.
+	if math.IsInf(max-min, 0) {
+		return nil, fmt.Errorf("uniform range [%v, %v] is too wide, max - min must be a finite number", min, max)
+	}
+
+	intMin, intMax := clampToInt64(math.Ceil(min)), clampToInt64(math.Floor(max))
+	if intMin > intMax {
+		return nil, fmt.Errorf("uniform range [%v, %v] contains no integers", min, max)
+	}
+
+	return &Uniform{
+		rng:         rng,
+		doubleMin:   min,
+		doubleWidth: max - min,
+		intMin:      intMin,
+		intSpan:     uint64(intMax) - uint64(intMin),
+	}, nil
+}
+
+// Double returns a value between the range's min and max.
+func (u *Uniform) Double() float64 {
+	return u.doubleMin + u.rng.Float64()*u.doubleWidth
+}
+
+// Int returns a value between the range's min and max.
+func (u *Uniform) Int() int64 {
+	if u.intSpan == math.MaxUint64 {
+		return int64(u.rng.Uint64())
+	}
+
+	return u.intMin + int64(u.rng.Uint64N(u.intSpan+1))
+}
+
+// ========================================== Normal ==========================================
+
+// Normal draws values from a normal (Gaussian) distribution.
+type Normal struct {
+	rng          *rand.Rand
+	mean, stddev float64
+}
+
+// NewNormal creates a normal distribution with the given mean and standard deviation.
+func NewNormal(rng *rand.Rand, mean, stddev float64) (*Normal, error) {
+	if math.IsNaN(mean) || math.IsInf(mean, 0) || math.IsNaN(stddev) || math.IsInf(stddev, 0) || stddev < 0 {
+		return nil, fmt.Errorf("normal distribution needs a finite mean and a finite, non-negative standard deviation")
+	}
+
+	return &Normal{rng: rng, mean: mean, stddev: stddev}, nil
+}
+
+// Double returns the next value.
+func (n *Normal) Double() float64 {
+	return n.mean + n.rng.NormFloat64()*n.stddev
+}
+
+// Int returns the next value rounded to the nearest integer, clamped to the int64 range.
+func (n *Normal) Int() int64 {
+	return clampToInt64(math.Round(n.Double()))
+}
+
+// ========================================== Zipf ==========================================
+
+// Zipf draws values between zero and max, where small values are far more likely than large ones.
+// The probability of value k is proportional to (v + k) ** (-s).
+type Zipf struct {
+	zipf *rand.Zipf
+}
+
+// NewZipf creates a Zipf distribution. s must be greater than 1 and v at least 1.
+func NewZipf(rng *rand.Rand, s, v, max float64) (*Zipf, error) {
+	if !(s > 1) || !(v >= 1) || !(max >= 0) || max >= math.MaxInt64 {
+		return nil, fmt.Errorf("zipf distribution needs s > 1, v >= 1 and 0 <= max < %d", int64(math.MaxInt64))
+	}
+
+	return &Zipf{zipf: rand.NewZipf(rng, s, v, uint64(max))}, nil
+}
+
+// Double returns the next value.
+func (z *Zipf) Double() float64 {
+	return float64(z.zipf.Uint64())
+}
+
+// Int returns the next value.
+func (z *Zipf) Int() int64 {
+	return int64(z.zipf.Uint64())
+}
+
+// ========================================== Edge cases ==========================================
+
+// These are the values most likely to expose bugs in the server's math.
+var (
+	edgeDoubles = []float64{
+		0,
+		math.Copysign(0, -1),
+		1,
+		-1,
+		math.MaxFloat64,
+		-math.MaxFloat64,
+		math.SmallestNonzeroFloat64,  // the smallest subnormal
+		-math.SmallestNonzeroFloat64, // the smallest negative subnormal
+		0x1p-1023,                    // the largest power of two which is subnormal
+		0x1p-1022,                    // the smallest normal
+		math.Inf(1),
+		math.Inf(-1),
+		math.NaN(),
+	}
+	edgeInts = []int64{
+		0,
+		1,
+		-1,
+		math.MaxInt64,
+		math.MaxInt64 - 1,
+		math.MinInt64,
+		math.MinInt64 + 1,
+	}
+)
+
+// EdgeCases returns values from a list of edge cases, such as the int64 limits, infinities, NaN and subnormal
+// numbers, with the given probability. Otherwise it returns a value drawn uniformly from the whole range.
+type EdgeCases struct {
+	rng         *rand.Rand
+	probability float64
+}
+
+// NewEdgeCases creates the edge case distribution, returning an edge case with the given probability.
+func NewEdgeCases(rng *rand.Rand, probability float64) (*EdgeCases, error) {
+	if !(probability >= 0 && probability <= 1) {
+		return nil, fmt.Errorf("edge case probability %v must be between 0 and 1", probability)
+	}
+
+	return &EdgeCases{rng: rng, probability: probability}, nil
+}
+
+// Double returns the next value.
+func (e *EdgeCases) Double() float64 {
+	if e.rng.Float64() < e.probability {
+		return edgeDoubles[e.rng.IntN(len(edgeDoubles))]
+	}
+
+	// Any finite float64, with its bits drawn at random until they aren't an infinity or NaN.
+	for {
+		value := math.Float64frombits(e.rng.Uint64())
+		if !math.IsInf(value, 0) && !math.IsNaN(value) {
+			return value
+		}
+	}
+}
+
+// Int returns the next value.
+func (e *EdgeCases) Int() int64 {
+	if e.rng.Float64() < e.probability {
+		return edgeInts[e.rng.IntN(len(edgeInts))]
+	}
+
+	return int64(e.rng.Uint64())
+}
+
+// This function converts a float to the nearest int64, saturating at the int64 limits.
+func clampToInt64(value float64) int64 {
+	switch {
+	case math.IsNaN(value):
+		return 0
+	case value >= math.MaxInt64:
+		return math.MaxInt64
+	case value <= math.MinInt64:
+		return math.MinInt64
+	default:
+		return int64(value)
+	}
+}
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// The number of values drawn from each distribution in the tests below.
+const draws = 10000
+
+func newRNG() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2))
+}
+
+func TestDefaultUniformRange(t *testing.T) {
+	g, err := Parse("uniform", newRNG())
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	for range draws {
+		if d := g.Double(); d < 0 || d > math.MaxFloat64/2 {
+			t.Fatalf("Double() = %v; want between 0 and MaxFloat64/2", d)
+		}
+		// This used to wrap around and return negative numbers.
+		if i := g.Int(); i < 0 {
+			t.Fatalf("Int() = %d; want between 0 and MaxInt64", i)
+		}
+	}
+}
+
+func TestUniformRange(t *testing.T) {
+	tests := []struct {
+		spec           string
+		doubleMin      float64
+		doubleMax      float64
+		intMin, intMax int64
+	}{
+		{"uniform:-100:100", -100, 100, -100, 100},
+		{"uniform:0.5:3.5", 0.5, 3.5, 1, 3},
+		{"uniform:7:7", 7, 7, 7, 7},
+		{"uniform:-1e300:1e300", -1e300, 1e300, math.MinInt64, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		g, err := Parse(tt.spec, newRNG())
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.spec, err)
+			continue
+		}
+
+		sawNegative := false
+		for range draws {
+			if d := g.Double(); d < tt.doubleMin || d > tt.doubleMax {
+				t.Fatalf("%s: Double() = %v; want between %v and %v", tt.spec, d, tt.doubleMin, tt.doubleMax)
+			}
+			i := g.Int()
+			if i < tt.intMin || i > tt.intMax {
+				t.Fatalf("%s: Int() = %d; want between %d and %d", tt.spec, i, tt.intMin, tt.intMax)
+			}
+			sawNegative = sawNegative || i < 0
+		}
+		if tt.intMin < 0 && !sawNegative {
+			t.Errorf("%s: Int() never returned a negative value", tt.spec)
+		}
+	}
+}
+
+func TestNormalMeanAndSpread(t *testing.T) {
+	g, err := Parse("normal:50:10", newRNG())
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	var sum float64
+	for range draws {
+		d := g.Double()
+		// Values more than eight standard deviations from the mean are practically impossible.
+		if d < -30 || d > 130 {
+			t.Fatalf("Double() = %v; want within eight standard deviations of 50", d)
+		}
+		sum += d
+	}
+	if mean := sum / draws; math.Abs(mean-50) > 1 {
+		t.Errorf("mean of Double() = %v; want about 50", mean)
+	}
+}
+
+func TestZipfRange(t *testing.T) {
+	g, err := Parse("zipf:2:1:100", newRNG())
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	zeros := 0
+	for range draws {
+		i := g.Int()
+		if i < 0 || i > 100 {
+			t.Fatalf("Int() = %d; want between 0 and 100", i)
+		}
+		if i == 0 {
+			zeros++
+		}
+		if d := g.Double(); d < 0 || d > 100 || d != math.Trunc(d) {
+			t.Fatalf("Double() = %v; want a whole number between 0 and 100", d)
+		}
+	}
+	// Small values are by far the most common.
+	if zeros < draws/4 {
+		t.Errorf("Int() returned 0 only %d times out of %d", zeros, draws)
+	}
+}
+
+func TestEdgeCasesCoverEveryEdge(t *testing.T) {
+	g, err := Parse("edge:1", newRNG())
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	seenInts := make(map[int64]bool)
+	seenNaN, seenPosInf, seenNegInf, seenSubnormal, seenNegZero := false, false, false, false, false
+	for range draws {
+		seenInts[g.Int()] = true
+
+		d := g.Double()
+		switch {
+		case math.IsNaN(d):
+			seenNaN = true
+		case math.IsInf(d, 1):
+			seenPosInf = true
+		case math.IsInf(d, -1):
+			seenNegInf = true
+		case d == 0 && math.Signbit(d):
+			seenNegZero = true
+		case d != 0 && math.Abs(d) < 0x1p-1022:
+			seenSubnormal = true
+		}
+	}
+
+	for _, edge := range []int64{math.MinInt64, math.MaxInt64, 0} {
+		if !seenInts[edge] {
+			t.Errorf("Int() never returned %d", edge)
+		}
+	}
+	if !seenNaN || !seenPosInf || !seenNegInf || !seenSubnormal || !seenNegZero {
+		t.Errorf("Double() missed an edge case: NaN %v, +Inf %v, -Inf %v, subnormal %v, -0 %v",
+			seenNaN, seenPosInf, seenNegInf, seenSubnormal, seenNegZero)
+	}
+}
+
+func TestEdgeCasesWithoutEdgesAreFinite(t *testing.T) {
+	g, err := Parse("edge:0", newRNG())
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	for range draws {
+		if d := g.Double(); math.IsNaN(d) || math.IsInf(d, 0) {
+			t.Fatalf("Double() = %v; want a finite value", d)
+		}
+	}
+}
+
+func TestSameSeedSameValues(t *testing.T) {
+	for _, spec := range []string{"uniform", "normal", "zipf", "edge"} {
+		first, _ := Parse(spec, newRNG())
+		second, _ := Parse(spec, newRNG())
+
+		for range 100 {
+			a, b := first.Double(), second.Double()
+			if a != b && !(math.IsNaN(a) && math.IsNaN(b)) {
+				t.Fatalf("%s: Double() differs between generators with the same seed: %v and %v", spec, a, b)
+			}
+			if a, b := first.Int(), second.Int(); a != b {
+				t.Fatalf("%s: Int() differs between generators with the same seed: %d and %d", spec, a, b)
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"poisson",
+		"uniform:1",
+		"uniform:5:1",
+		"uniform:0.2:0.8",
+		"uniform:a:b",
+		"uniform:-1.7e308:1.7e308",
+		"normal:0:-1",
+		"zipf:1:1:100",
+		"zipf:2:1",
+		"edge:2",
+	} {
+		if _, err := Parse(spec, newRNG()); err == nil {
+			t.Errorf("Parse(%q) returned no error", spec)
+		}
+	}
+}
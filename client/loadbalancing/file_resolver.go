@@ -0,0 +1,151 @@
+package loadbalancing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// FileScheme is the target scheme handled by the static file resolver, for example "file:///tmp/backends.json".
+const FileScheme = "file"
+
+// FilePollInterval is how often the static file resolver checks the backends file for changes.
+var FilePollInterval = time.Second
+
+func init() {
+	resolver.Register(&fileResolverBuilder{})
+}
+
+// BackendsFile is the JSON document read by the static file resolver.
+// An example looks like: {"backends": ["localhost:50051", "localhost:50052"]}
+type BackendsFile struct {
+	Backends []string `json:"backends"`
+}
+
+// ReadBackendsFile reads and validates a JSON file of backend addresses.
+func ReadBackendsFile(path string) ([]string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file BackendsFile
+	if err := json.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse backends file %s: %v", path, err)
+	}
+	if len(file.Backends) == 0 {
+		return nil, fmt.Errorf("backends file %s does not list any backends", path)
+	}
+
+	return file.Backends, nil
+}
+
+type fileResolverBuilder struct{}
+
+// Build is called by gRPC once for each client connection using the file scheme.
+func (*fileResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	path := target.URL.Path
+	if path == "" {
+		path = target.URL.Opaque
+	}
+
+	r := &fileResolver{
+		path:    path,
+		cc:      cc,
+		refresh: make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+	r.update()
+
+	r.waitGroup.Add(1)
+	go r.watch()
+
+	return r, nil
+}
+
+func (*fileResolverBuilder) Scheme() string {
+	return FileScheme
+}
+
+// The file resolver re-reads the backends file whenever its modification time or size changes,
+// and pushes the new list of addresses to the client connection.
+type fileResolver struct {
+	path    string
+	cc      resolver.ClientConn
+	refresh chan struct{}
+	closed  chan struct{}
+
+	waitGroup sync.WaitGroup
+	modTime   time.Time
+	size      int64
+}
+
+// This function runs in its own go routine until the resolver is closed.
+func (r *fileResolver) watch() {
+	defer r.waitGroup.Done()
+
+	ticker := time.NewTicker(FilePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closed:
+			return
+		case <-r.refresh:
+			r.update()
+		case <-ticker.C:
+			info, err := os.Stat(r.path)
+			if err != nil {
+				r.cc.ReportError(err)
+				continue
+			}
+			if !info.ModTime().Equal(r.modTime) || info.Size() != r.size {
+				r.update()
+			}
+		}
+	}
+}
+
+// This function reads the backends file and hands the addresses to gRPC.
+func (r *fileResolver) update() {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		r.cc.ReportError(err)
+		return
+	}
+	r.modTime = info.ModTime()
+	r.size = info.Size()
+
+	backends, err := ReadBackendsFile(r.path)
+	if err != nil {
+		r.cc.ReportError(err)
+		return
+	}
+
+	addresses := make([]resolver.Address, 0, len(backends))
+	for _, backend := range backends {
+		addresses = append(addresses, resolver.Address{Addr: backend})
+	}
+
+	if err := r.cc.UpdateState(resolver.State{Addresses: addresses}); err != nil {
+		r.cc.ReportError(err)
+	}
+}
+
+// ResolveNow is called by gRPC as a hint that the addresses might have changed, for example after a failed connection.
+func (r *fileResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops the watching go routine.
+func (r *fileResolver) Close() {
+	close(r.closed)
+	r.waitGroup.Wait()
+}
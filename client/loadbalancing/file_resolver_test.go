@@ -0,0 +1,43 @@
+package loadbalancing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadBackendsFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     []string
+		wantErr  bool
+	}{
+		{"two backends", `{"backends": ["localhost:50051", "localhost:50052"]}`, []string{"localhost:50051", "localhost:50052"}, false},
+		{"no backends", `{"backends": []}`, nil, true},
+		{"malformed", `["localhost:50051"`, nil, true},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "backends.json")
+		if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ReadBackendsFile(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ReadBackendsFile() error = %v; wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: ReadBackendsFile() = %v; want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: ReadBackendsFile() = %v; want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package loadbalancing
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+)
+
+// LeastRequestsName is the name the least outstanding requests balancer is registered under.
+// It is used in the "loadBalancingConfig" section of the client's service config.
+const LeastRequestsName = "least_outstanding_requests"
+
+func init() {
+	balancer.Register(newLeastRequestsBuilder())
+}
+
+// This creates the balancer builder. The base package takes care of creating and connecting a sub-connection
+// for each backend address, we only have to provide the picker which chooses a backend for each request.
+func newLeastRequestsBuilder() balancer.Builder {
+	return leastRequestsBuilder{}
+}
+
+// The balancer builder is registered once, and shared by every connection which uses the policy.
+// Each connection has sub-connections of its own, so each balancer it builds gets a picker builder of its own too.
+type leastRequestsBuilder struct{}
+
+// Build creates the balancer of one connection.
+func (leastRequestsBuilder) Build(cc balancer.ClientConn, options balancer.BuildOptions) balancer.Balancer {
+	pickerBuilder := &leastRequestsPickerBuilder{}
+	return base.NewBalancerBuilder(LeastRequestsName, pickerBuilder, base.Config{HealthCheck: true}).Build(cc, options)
+}
+
+// Name returns the name the balancer is registered under.
+func (leastRequestsBuilder) Name() string {
+	return LeastRequestsName
+}
+
+// The picker builder is called every time the set of ready backends of its connection changes.
+// It holds on to the outstanding request counters so that they survive a picker being rebuilt.
+type leastRequestsPickerBuilder struct {
+	mutex       sync.Mutex
+	outstanding map[balancer.SubConn]*atomic.Int64
+}
+
+// Build creates a new picker for the currently ready backends.
+func (b *leastRequestsPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.outstanding == nil {
+		b.outstanding = make(map[balancer.SubConn]*atomic.Int64)
+	}
+
+	// Forget the counters of backends which are no longer ready.
+	for subConn := range b.outstanding {
+		if _, ok := info.ReadySCs[subConn]; !ok {
+			delete(b.outstanding, subConn)
+		}
+	}
+
+	picker := &leastRequestsPicker{}
+	for subConn := range info.ReadySCs {
+		counter, ok := b.outstanding[subConn]
+		if !ok {
+			counter = &atomic.Int64{}
+			b.outstanding[subConn] = counter
+		}
+		picker.backends = append(picker.backends, leastRequestsBackend{subConn: subConn, outstanding: counter})
+	}
+
+	return picker
+}
+
+// A ready backend along with the number of requests currently in flight on it.
+type leastRequestsBackend struct {
+	subConn     balancer.SubConn
+	outstanding *atomic.Int64
+}
+
+// The picker sends each request to the backend with the fewest requests in flight.
+type leastRequestsPicker struct {
+	backends []leastRequestsBackend
+}
+
+// Pick is called by gRPC for every request, and returns the backend the request should be sent to.
+// The Done callback is called by gRPC once the request finishes, which is where the counter is decremented.
+func (p *leastRequestsPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	// Start scanning at a random backend, so that ties don't always go to the same backend.
+	start := rand.IntN(len(p.backends))
+	chosen := p.backends[start]
+	for i := 1; i < len(p.backends); i++ {
+		candidate := p.backends[(start+i)%len(p.backends)]
+		if candidate.outstanding.Load() < chosen.outstanding.Load() {
+			chosen = candidate
+		}
+	}
+
+	chosen.outstanding.Add(1)
+
+	return balancer.PickResult{
+		SubConn: chosen.subConn,
+		Done:    func(balancer.DoneInfo) { chosen.outstanding.Add(-1) },
+	}, nil
+}
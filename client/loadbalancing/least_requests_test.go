@@ -0,0 +1,133 @@
+package loadbalancing
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/resolver"
+)
+
+// A stand-in for a real sub-connection, only its identity matters to the picker.
+type fakeSubConn struct {
+	balancer.SubConn
+	name string
+	// The balancer's callback for changes to the sub-connection's state.
+	listener func(balancer.SubConnState)
+}
+
+func (*fakeSubConn) Connect()  {}
+func (*fakeSubConn) Shutdown() {}
+
+// This function tells the balancer that the sub-connection has changed state.
+func (s *fakeSubConn) setState(state connectivity.State) {
+	s.listener(balancer.SubConnState{ConnectivityState: state})
+}
+
+// A stand-in for a connection, which gives its balancer fake sub-connections and keeps its latest picker.
+type fakeClientConn struct {
+	balancer.ClientConn
+	subConns []*fakeSubConn
+	picker   balancer.Picker
+}
+
+func (c *fakeClientConn) NewSubConn(addresses []resolver.Address, options balancer.NewSubConnOptions) (balancer.SubConn, error) {
+	subConn := &fakeSubConn{name: addresses[0].Addr, listener: options.StateListener}
+	c.subConns = append(c.subConns, subConn)
+	return subConn, nil
+}
+
+func (c *fakeClientConn) UpdateState(state balancer.State) {
+	c.picker = state.Picker
+}
+
+// This function builds a least requests balancer for a new fake connection to two backends, both of them ready.
+func connectToTwoBackends(t *testing.T) *fakeClientConn {
+	t.Helper()
+
+	cc := &fakeClientConn{}
+	b := balancer.Get(LeastRequestsName).Build(cc, balancer.BuildOptions{})
+	t.Cleanup(b.Close)
+	addresses := []resolver.Address{{Addr: "one"}, {Addr: "two"}}
+	if err := b.UpdateClientConnState(balancer.ClientConnState{ResolverState: resolver.State{Addresses: addresses}}); err != nil {
+		t.Fatalf("UpdateClientConnState() returned error: %v", err)
+	}
+	for _, subConn := range cc.subConns {
+		subConn.setState(connectivity.Ready)
+	}
+
+	return cc
+}
+
+func TestLeastRequestsPickerPrefersIdleBackend(t *testing.T) {
+	busy := &fakeSubConn{name: "busy"}
+	idle := &fakeSubConn{name: "idle"}
+
+	builder := &leastRequestsPickerBuilder{}
+	picker := builder.Build(base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{busy: {}, idle: {}}})
+
+	// Hold two requests open on whichever backend is picked first, then make sure the other one is picked.
+	first, err := picker.Pick(balancer.PickInfo{})
+	if err != nil {
+		t.Fatalf("Pick() returned error: %v", err)
+	}
+	second, _ := picker.Pick(balancer.PickInfo{})
+	if second.SubConn == first.SubConn {
+		t.Fatalf("Pick() chose %v twice while the other backend was idle", first.SubConn.(*fakeSubConn).name)
+	}
+
+	// Finish the request on the second backend, it should now be preferred again.
+	second.Done(balancer.DoneInfo{})
+	third, _ := picker.Pick(balancer.PickInfo{})
+	if third.SubConn != second.SubConn {
+		t.Errorf("Pick() = %v; want %v", third.SubConn.(*fakeSubConn).name, second.SubConn.(*fakeSubConn).name)
+	}
+}
+
+func TestLeastRequestsCountersSurviveRebuild(t *testing.T) {
+	one := &fakeSubConn{name: "one"}
+	two := &fakeSubConn{name: "two"}
+	ready := map[balancer.SubConn]base.SubConnInfo{one: {}, two: {}}
+
+	builder := &leastRequestsPickerBuilder{}
+	first, _ := builder.Build(base.PickerBuildInfo{ReadySCs: ready}).Pick(balancer.PickInfo{})
+
+	// A rebuilt picker must still know about the request in flight.
+	second, _ := builder.Build(base.PickerBuildInfo{ReadySCs: ready}).Pick(balancer.PickInfo{})
+	if second.SubConn == first.SubConn {
+		t.Errorf("Pick() after rebuild chose the busy backend %v", first.SubConn.(*fakeSubConn).name)
+	}
+}
+
+func TestLeastRequestsNoReadyBackends(t *testing.T) {
+	builder := &leastRequestsPickerBuilder{}
+	_, err := builder.Build(base.PickerBuildInfo{}).Pick(balancer.PickInfo{})
+	if err != balancer.ErrNoSubConnAvailable {
+		t.Errorf("Pick() error = %v; want %v", err, balancer.ErrNoSubConnAvailable)
+	}
+}
+
+func TestLeastRequestsConnectionsKeepTheirOwnCounters(t *testing.T) {
+	// A connection which wrongly shared its counters with another would pick either backend half of the time, so
+	// the test is repeated to make that all but certain to show.
+	for range 10 {
+		first := connectToTwoBackends(t)
+		busy, _ := first.picker.Pick(balancer.PickInfo{})
+
+		// Building a balancer for a second connection must not touch the counters of the first.
+		connectToTwoBackends(t)
+
+		// Rebuild the first connection's picker, by taking the idle backend out of the ready set and back.
+		for _, subConn := range first.subConns {
+			if subConn != busy.SubConn {
+				subConn.setState(connectivity.Idle)
+				subConn.setState(connectivity.Ready)
+			}
+		}
+
+		if next, _ := first.picker.Pick(balancer.PickInfo{}); next.SubConn == busy.SubConn {
+			t.Fatalf("Pick() after another connection was built chose the busy backend %v", busy.SubConn.(*fakeSubConn).name)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package loadbalancing
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// The scheme used for targets given as a comma separated list of addresses.
+const listScheme = "list"
+
+// DialTarget turns the target given on the command line into a target and dial options for grpc.NewClient.
+// The target can be a single address, a comma separated list of addresses, a DNS name such as
+// "dns:///magic.example.com:50051", a Unix domain socket such as "unix:///run/magic/magic.sock",
+// or a backends file such as "file:///tmp/backends.json".
+// The policy is the name of the load balancing policy, for example "round_robin" or LeastRequestsName.
+func DialTarget(target string, policy string) (string, []grpc.DialOption) {
+	serviceConfig := fmt.Sprintf(`{"loadBalancingConfig": [{%q: {}}]}`, policy)
+	options := []grpc.DialOption{grpc.WithDefaultServiceConfig(serviceConfig)}
+
+	if !strings.Contains(target, ",") {
+		return target, options
+	}
+
+	// A list of addresses is handed to gRPC through a resolver which always returns the same addresses.
+	var addresses []resolver.Address
+	for _, address := range strings.Split(target, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, resolver.Address{Addr: address})
+		}
+	}
+
+	listResolver := manual.NewBuilderWithScheme(listScheme)
+	listResolver.InitialState(resolver.State{Addresses: addresses})
+	options = append(options, grpc.WithResolvers(listResolver))
+
+	return listScheme + ":///" + target, options
+}
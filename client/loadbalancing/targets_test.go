@@ -0,0 +1,26 @@
+package loadbalancing
+
+import "testing"
+
+func TestDialTarget(t *testing.T) {
+	tests := []struct {
+		target      string
+		wantTarget  string
+		wantOptions int
+	}{
+		{"localhost:50051", "localhost:50051", 1},
+		{"dns:///magic.example.com:50051", "dns:///magic.example.com:50051", 1},
+		{"unix:///tmp/magic.sock", "unix:///tmp/magic.sock", 1},
+		{"file:///tmp/backends.json", "file:///tmp/backends.json", 1},
+		// A list of addresses adds the resolver which returns them.
+		{"localhost:50051,localhost:50052", "list:///localhost:50051,localhost:50052", 2},
+	}
+
+	for _, tt := range tests {
+		gotTarget, gotOptions := DialTarget(tt.target, "round_robin")
+		if gotTarget != tt.wantTarget || len(gotOptions) != tt.wantOptions {
+			t.Errorf("DialTarget(%q) = %q with %d options; want %q with %d options",
+				tt.target, gotTarget, len(gotOptions), tt.wantTarget, tt.wantOptions)
+		}
+	}
+}
@@ -2,142 +2,931 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
 	"fmt"
-	"math"
+	"io"
 	"math/rand/v2"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/karldmenzel/go-grpc-client-server/capture"
+	"github.com/karldmenzel/go-grpc-client-server/client/generator"
+	"github.com/karldmenzel/go-grpc-client-server/client/loadbalancing"
+	"github.com/karldmenzel/go-grpc-client-server/client/replay"
+	"github.com/karldmenzel/go-grpc-client-server/client/retry"
+	"github.com/karldmenzel/go-grpc-client-server/client/shell"
 	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
 
+	"golang.org/x/term"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
-var waitGroup sync.WaitGroup
+// These are the client's exit codes. A command which fails with a gRPC status exits with exitStatusBase plus the
+// status code, such as 67 for INVALID_ARGUMENT or 78 for UNAVAILABLE, so scripts can tell why it failed.
+const (
+	exitOK = 0
+	// exitFailed means the command ran but its check failed, such as a replay which differed or an unhealthy server.
+	exitFailed = 1
+	// exitUsage means the command, its flags or its arguments are wrong.
+	exitUsage      = 2
+	exitStatusBase = 64
+)
+
+// These flags choose which server(s) the client talks to, and how requests are spread between them.
+// Every command has them, along with the flags below which secure and identify its calls.
+var (
+	target   string
+	lbPolicy string
+)
+
+// These flags secure the connection with TLS, send a bearer token in the authorization header of every call, and
+// choose how long a command may take.
+var (
+	useTLS     bool
+	caFile     string
+	serverName string
+	token      string
+	timeout    time.Duration
+)
+
+// These flags identify the client to the server's rate limits and quotas, set the priority of its calls when the
+// server is overloaded, and choose how often calls rejected by a rate limit are retried.
+var (
+	callerID    string
+	priority    string
+	maxAttempts int
+)
+
+// This flag chooses how the commands other than load print their results, one of shell.Formats.
+var outputFormat string
+
+// These flags of the load command choose how the terms sent to the server are generated.
+// Runs with the same seed send the same terms.
+var (
+	distribution string
+	seed         uint64
+)
+
+// These flags of the load command replay calls recorded by the server's -record flag, instead of making 1000 random
+// requests.
+var (
+	replayFile  string
+	replaySpeed float64
+)
+
+// These flags of the shell command choose whether it prints how long each command took, and where it keeps its
+// history.
+var (
+	timing      bool
+	historyFile string
+)
+
+// This flag of the health command chooses which service's health to check.
+var healthService string
+
+// This describes one of the client's commands, such as add or load.
+type clientCommand struct {
+	name, usage, help string
+	// flags registers the command's own flags, beyond the ones every command has.
+	flags func(flags *flag.FlagSet)
+	// run runs the command with the arguments left after its flags, and returns the exit code.
+	run func(args []string) int
+}
+
+// This function returns the client's commands: load, shell, health and a command for each of the shell's commands
+// which call the server, such as add, min or counts.
+func clientCommands() []clientCommand {
+	commands := []clientCommand{
+		{"load", "load [flags]", "Makes 1000 random requests, or replays a capture, and prints reports. This is the default.",
+			loadFlags, runLoad},
+		{"shell", "shell [flags]", "Starts an interactive shell, which makes one call for each command typed.",
+			shellFlags, runShell},
+		{"health", "health [flags]", "Checks the server's health, exiting with 1 unless it is serving.",
+			healthFlags, runHealth},
+	}
+	for _, command := range shell.Commands() {
+		commands = append(commands, clientCommand{command.Name, command.Usage + " [flags]", command.Help,
+			outputFlags, callCommand(command.Name)})
+	}
+
+	return commands
+}
 
 func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// This function runs the command named by the first argument, with the rest of the arguments, and returns the exit
+// code. Without a command, or when the first argument is a flag, it runs the load command.
+func run(args []string) int {
+	name := "load"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		printCommands(os.Stdout)
+		return exitOK
+	}
+
+	commands := clientCommands()
+	index := slices.IndexFunc(commands, func(command clientCommand) bool { return command.name == name })
+	if index < 0 {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+		printCommands(os.Stderr)
+		return exitUsage
+	}
+	command := commands[index]
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	connectionFlags(flags)
+	command.flags(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: client %s\n%s\n", command.usage, command.help)
+		if name != "load" {
+			fmt.Fprintln(flags.Output(), "Put -- before arguments which start with a minus sign, as in: client sub -- -1 2")
+		}
+		fmt.Fprintln(flags.Output(), "Flags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if name == "load" && flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flags.Arg(0))
+		printCommands(os.Stderr)
+		return exitUsage
+	}
+
+	return command.run(flags.Args())
+}
+
+// This function prints the list of the client's commands.
+func printCommands(out io.Writer) {
+	fmt.Fprintln(out, "usage: client [command] [flags] [arguments]")
+	fmt.Fprintln(out, "Commands:")
+	for _, command := range clientCommands() {
+		fmt.Fprintf(out, "  %-8s %s\n", command.name, command.help)
+	}
+	fmt.Fprintln(out, "Run client <command> -h for the flags and arguments of a command.")
+}
+
+// This function registers the flags every command has.
+func connectionFlags(flags *flag.FlagSet) {
+	flags.StringVar(&target, "target", "localhost:50051",
+		"Server address, comma separated list of addresses, DNS name (dns:///host:port), Unix domain socket (unix:///path/magic.sock) "+
+			"or backends file (file:///path/backends.json).")
+	flags.StringVar(&lbPolicy, "lb", "round_robin",
+		"Load balancing policy: pick_first, round_robin or "+loadbalancing.LeastRequestsName+".")
+	flags.BoolVar(&useTLS, "tls", false, "Connect with TLS, trusting the system's certificate authorities.")
+	flags.StringVar(&caFile, "ca-file", "",
+		"Connect with TLS, trusting the certificate authorities in this PEM file instead of the system's.")
+	flags.StringVar(&serverName, "server-name", "",
+		"Connect with TLS, checking that the server's certificate is for this name instead of the target's host.")
+	flags.StringVar(&token, "token", "",
+		"Send this bearer token in the authorization header of every call, which needs TLS. "+
+			"Defaults to the MAGICMATH_TOKEN environment variable, which keeps it out of the list of processes.")
+	flags.DurationVar(&timeout, "timeout", 5*time.Second,
+		"How long a command may take. For load this covers all 1000 requests, or each replayed call, and for shell each command typed.")
+	flags.StringVar(&callerID, "caller", "",
+		"The identity sent to the server in the x-caller-id header. Defaults to the client's IP address, as seen by the server.")
+	flags.StringVar(&priority, "priority", "",
+		"The priority sent to the server in the x-priority header: low, normal or high. An overloaded server sheds low priority calls first.")
+	flags.IntVar(&maxAttempts, "max-attempts", 10,
+		"How many times to try a call which the server rejected for going over a rate limit, waiting as long as it asks.")
+}
+
+// This function registers the flags of the load command.
+func loadFlags(flags *flag.FlagSet) {
+	flags.StringVar(&distribution, "distribution", "uniform",
+		"Distribution of the generated terms: "+strings.Join(generator.Distributions, ", ")+".")
+	flags.Uint64Var(&seed, "seed", 0, "Seed for the random number generator. Defaults to a random seed, which is printed.")
+	flags.StringVar(&replayFile, "replay", "",
+		"Replay the calls in this capture file, and its rotated files, comparing the responses with the recorded ones.")
+	flags.Float64Var(&replaySpeed, "replay-speed", 1,
+		"How fast to replay: 1 keeps the original timing, 2 is twice as fast, 0 is as fast as possible.")
+}
+
+// This function registers the output flag, which every command except load has.
+func outputFlags(flags *flag.FlagSet) {
+	outputFormat = shell.Formats[0]
+	flags.Func("output", "The output format: "+strings.Join(shell.Formats, " or ")+". Defaults to text.", func(value string) error {
+		if !slices.Contains(shell.Formats, value) {
+			return fmt.Errorf("the formats are %s", strings.Join(shell.Formats, ", "))
+		}
+		outputFormat = value
+		return nil
+	})
+}
+
+// This function registers the flags of the shell command.
+func shellFlags(flags *flag.FlagSet) {
+	outputFlags(flags)
+	flags.BoolVar(&timing, "timing", true, "Print how long each command took.")
+	flags.StringVar(&historyFile, "history", defaultHistoryFile(),
+		"The file which keeps the shell's history between sessions. Empty keeps it for the session only.")
+}
+
+// This function registers the flags of the health command.
+func healthFlags(flags *flag.FlagSet) {
+	outputFlags(flags)
+	flags.StringVar(&healthService, "service", "",
+		"The service to check, such as "+pb.MagicMath_ServiceDesc.ServiceName+". Empty checks the server as a whole.")
+}
+
+// This function returns the exit code for an error returned by a call to the server.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	return exitStatusBase + int(status.Code(err))
+}
+
+// This function runs the load command, which makes 1000 random requests, or replays a capture, and prints how many
+// times each function has been called, how much of its quotas the client has used, how the requests were spread
+// across the backends, and how many the server's math rejected.
+func runLoad([]string) int {
 	// Set up a connection to the server.
-	conn, server := connectToServer("localhost:50051")
-	// This is run after the end of the main function, and forcefully terminates the HTTP connection.
+	conn, server, err := connectToServer()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return exitUsage
+	}
+	// This is run after the end of the function, and forcefully terminates the HTTP connection.
 	defer conn.Close()
 
 	// Create the context / metadata object passed into each gRPC request.
-	// This context object just tells gRPC to cancel the call if the server hasn't responded in five seconds.
+	// This context object just tells gRPC to cancel the call if the server hasn't responded in time.
 	requestContext, cancel := createRequestContext()
-	// This is run after the end of the main function, it cancels any dangling requests.
+	// This is run after the end of the function, it cancels any dangling requests.
 	defer cancel()
 
+	if replayFile != "" {
+		matched, err := replayCapture(conn, replayFile, replaySpeed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return exitUsage
+		}
+		if !matched {
+			return exitFailed
+		}
+		return exitOK
+	}
+
+	// Create the generator for the random terms, and the random number generator it draws from.
+	rng, terms, err := createGenerator()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return exitUsage
+	}
+
 	// Make all 1000 requests concurrently.
-	make1000Requests(server, requestContext)
+	make1000Requests(server, requestContext, rng, terms)
 
 	// Wait for all 1000 requests to finish.
 	waitGroup.Wait()
 
+	// Print how the requests were spread across the backends, how many the server's math rejected, and how many failed.
+	backendReport.print()
+	rejectedReport.print()
+	failedReport.print()
+
 	// Print the stats on how many times each function was called.
-	getCounters(server, requestContext)
+	if err := getCounters(server, requestContext); err != nil {
+		fmt.Fprintf(os.Stderr, "error getting counts: %v\n", err)
+		return exitCode(err)
+	}
+
+	// Print how much of its quotas this client has used.
+	if err := getQuota(server, requestContext); err != nil {
+		fmt.Fprintf(os.Stderr, "error getting quota: %v\n", err)
+		return exitCode(err)
+	}
+
+	// The exit code of a load with failed requests is the one of the first request which failed.
+	if err := failedReport.first(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return exitCode(err)
+	}
+
+	return exitOK
 }
 
-// This function takes the URL of the server, and returns the raw connection object, and our server's object.
-// The connection object is returned strictly so that it can be closed by the main function.
+// This function returns the run function of a command which calls the server once, through the shell.
+func callCommand(name string) func(args []string) int {
+	return func(args []string) int {
+		conn, server, err := connectToServer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return exitUsage
+		}
+		defer conn.Close()
+
+		requestContext, cancel := createRequestContext()
+		defer cancel()
+
+		magicShell := shell.New(server, shell.Options{Format: outputFormat})
+		err = magicShell.Call(requestContext, os.Stdout, os.Stderr, append([]string{name}, args...))
+		if _, isStatus := status.FromError(err); !isStatus {
+			return exitUsage
+		}
+		return exitCode(err)
+	}
+}
+
+// This function runs the health command, which checks the health of the server, or of one of its services.
+func runHealth(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "error: health takes no arguments, choose the service with -service")
+		return exitUsage
+	}
+	conn, _, err := connectToServer()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return exitUsage
+	}
+	defer conn.Close()
+
+	requestContext, cancel := createRequestContext()
+	defer cancel()
+
+	start := time.Now()
+	response, err := healthpb.NewHealthClient(conn).Check(requestContext, &healthpb.HealthCheckRequest{Service: healthService})
+	shell.New(nil, shell.Options{Format: outputFormat}).Print(os.Stdout, os.Stderr, "health", response, err, time.Since(start))
+	if err != nil {
+		return exitCode(err)
+	}
+	if response.Status != healthpb.HealthCheckResponse_SERVING {
+		return exitFailed
+	}
+
+	return exitOK
+}
+
+// This function runs the interactive shell until the user leaves it.
+func runShell(args []string) int {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "error: shell takes no arguments, pipe the commands to it instead")
+		return exitUsage
+	}
+	conn, server, err := connectToServer()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return exitUsage
+	}
+	defer conn.Close()
+
+	magicShell := shell.New(server, shell.Options{
+		Format:      outputFormat,
+		Timing:      timing,
+		Timeout:     timeout,
+		HistoryFile: historyFile,
+	})
+
+	// On a terminal Ctrl-C is read as a key, which leaves the shell. Otherwise it interrupts the commands being run.
+	ctx, stop := signal.NotifyContext(withCallerMetadata(context.Background()), os.Interrupt)
+	defer stop()
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		err = magicShell.RunTerminal(ctx, os.Stdin)
+	} else {
+		err = magicShell.Run(ctx, os.Stdin, os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Shell failed: %v\n", err)
+		return exitFailed
+	}
+
+	return exitOK
+}
+
+var waitGroup sync.WaitGroup
+
+// This stores how many requests of each method were answered by each backend address.
+var backendReport = newBackendTally()
+
+// This stores how many math requests of each method the server rejected, for each status code.
+var rejectedReport = newRejectedTally()
+
+// This stores how many requests of each method failed for any other reason, such as the server being unavailable.
+var failedReport = newFailedTally()
+
+// This function connects to the server(s) chosen by the target and load balancing flags, securing the connection
+// and authenticating the calls as the TLS and token flags ask, and returns the raw connection object, and our
+// server's object. The connection object is returned strictly so that it can be closed.
 // The server object is what actually has the remote procedures exposed on it, and is what we will call.
-func connectToServer(serverAddress string) (*grpc.ClientConn, pb.MagicMathClient) {
+func connectToServer() (*grpc.ClientConn, pb.MagicMathClient, error) {
+	dialTarget, dialOptions := loadbalancing.DialTarget(target, lbPolicy)
+
+	transportCredentials, err := createTransportCredentials()
+	if err != nil {
+		return nil, nil, err
+	}
+	if token == "" {
+		token = os.Getenv("MAGICMATH_TOKEN")
+	}
+	if token != "" {
+		if !tlsEnabled() {
+			return nil, nil, errors.New("a token can only be sent over TLS, add -tls")
+		}
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(bearerToken(token)))
+	}
 
-	// Create a new gRPC connection with no authentication.
-	connection, err := grpc.NewClient(serverAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Create a new gRPC connection, which waits and retries calls rejected by a rate limit.
+	dialOptions = append(dialOptions,
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithUnaryInterceptor(retry.UnaryClientInterceptor(maxAttempts)),
+	)
+	connection, err := grpc.NewClient(dialTarget, dialOptions...)
 	if err != nil {
-		panic(fmt.Errorf("failed to connect to server: %v", err))
+		return nil, nil, fmt.Errorf("failed to connect to server: %v", err)
 	}
 
 	// This is the object which actually has the remote functions on it.
 	server := pb.NewMagicMathClient(connection)
 
-	return connection, server
+	return connection, server, nil
+}
+
+// This function returns whether any of the TLS flags are set.
+func tlsEnabled() bool {
+	return useTLS || caFile != "" || serverName != ""
+}
+
+// This function creates the credentials which secure the connection, which are TLS if any of the TLS flags are set,
+// and no security otherwise.
+func createTransportCredentials() (credentials.TransportCredentials, error) {
+	if !tlsEnabled() {
+		return insecure.NewCredentials(), nil
+	}
+
+	config := &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		certificates, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(certificates) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+	}
+
+	return credentials.NewTLS(config), nil
+}
+
+// bearerToken sends a token in the authorization header of every call. It refuses to send it over a connection
+// without TLS, where anyone watching could read it.
+type bearerToken string
+
+// GetRequestMetadata returns the authorization header sent with each call.
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity returns true, so that the token is never sent without TLS.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// This function creates the random number generator, seeded from the seed flag,
+// and the generator for the terms sent to the server, following the distribution flag.
+func createGenerator() (*rand.Rand, generator.Generator, error) {
+	runSeed := seed
+	if runSeed == 0 {
+		runSeed = rand.Uint64()
+	}
+	fmt.Printf("Using seed %d, run again with -seed %d to send the same requests\n", runSeed, runSeed)
+
+	rng := rand.New(rand.NewPCG(runSeed, runSeed))
+	terms, err := generator.Parse(distribution, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rng, terms, nil
 }
 
 // This function creates a context object which is passed in to all RPC requests.
-// For us, that context object just says that the request should time out after five seconds.
+// For us, that context object says that the request should time out after the timeout flag,
+// and carries the caller and priority flags as metadata if they are set.
 func createRequestContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	return withCallerMetadata(ctx), cancel
+}
+
+// This function adds the caller and priority flags to a context as metadata, if they are set.
+func withCallerMetadata(ctx context.Context) context.Context {
+	if callerID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-caller-id", callerID)
+	}
+	if priority != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-priority", priority)
+	}
+
+	return ctx
+}
+
+// This function returns where the shell keeps its history by default, which is a file in the user's home directory.
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".magicmath_history")
 }
 
 // This function makes 1000 requests to the server for a random function.
+// The function and its terms are chosen up front, in order, so that runs with the same seed make the same requests.
 // Each call is made in a go routine, which all run concurrently.
 // These go routines are grouped in a 'wait group', which allows us to wait for them all to finish.
-func make1000Requests(server pb.MagicMathClient, requestContext context.Context) {
+func make1000Requests(server pb.MagicMathClient, requestContext context.Context, rng *rand.Rand, terms generator.Generator) {
 	for range 1000 {
-		methodId := rand.IntN(4)
+		methodId := rng.IntN(9)
 		switch methodId {
 		case 0:
-			waitGroup.Go(func() { magicAdd(server, requestContext) })
+			doubleTerms := &pb.DoubleTerms{TermOne: terms.Double(), TermTwo: terms.Double()}
+			waitGroup.Go(func() { magicAdd(server, requestContext, doubleTerms) })
 		case 1:
-			waitGroup.Go(func() { magicSubtract(server, requestContext) })
+			doubleTerms := &pb.DoubleTerms{TermOne: terms.Double(), TermTwo: terms.Double()}
+			waitGroup.Go(func() { magicSubtract(server, requestContext, doubleTerms) })
 		case 2:
-			waitGroup.Go(func() { magicFindMin(server, requestContext) })
+			intTerms := &pb.IntTerms{TermOne: terms.Int(), TermTwo: terms.Int(), TermThree: terms.Int()}
+			waitGroup.Go(func() { magicFindMin(server, requestContext, intTerms) })
 		case 3:
-			waitGroup.Go(func() { magicFindMax(server, requestContext) })
+			intTerms := &pb.IntTerms{TermOne: terms.Int(), TermTwo: terms.Int(), TermThree: terms.Int()}
+			waitGroup.Go(func() { magicFindMax(server, requestContext, intTerms) })
+		case 4:
+			arithmeticTerms := createArithmeticTerms(rng, terms)
+			waitGroup.Go(func() { magicArithmetic(requestContext, "MagicMultiply", server.MagicMultiply, arithmeticTerms) })
+		case 5:
+			arithmeticTerms := createArithmeticTerms(rng, terms)
+			waitGroup.Go(func() { magicArithmetic(requestContext, "MagicDivide", server.MagicDivide, arithmeticTerms) })
+		case 6:
+			arithmeticTerms := createArithmeticTerms(rng, terms)
+			waitGroup.Go(func() { magicArithmetic(requestContext, "MagicModulo", server.MagicModulo, arithmeticTerms) })
+		case 7:
+			arithmeticTerms := createArithmeticTerms(rng, terms)
+			waitGroup.Go(func() { magicArithmetic(requestContext, "MagicPower", server.MagicPower, arithmeticTerms) })
+		case 8:
+			expression := &pb.Expression{
+				Expression: "(a + b) * c - min(a, b, c)",
+				Variables:  map[string]float64{"a": terms.Double(), "b": terms.Double(), "c": terms.Double()},
+			}
+			waitGroup.Go(func() { magicEvaluate(server, requestContext, expression) })
 		default:
-			panic("Random generation went out of range 0 - 3.")
+			panic("Random generation went out of range 0 - 8.")
 		}
 	}
 }
 
-// This function makes the gRPC addition call to the server using two random doubles.
-func magicAdd(server pb.MagicMathClient, requestContext context.Context) {
-	_, err := server.MagicAdd(requestContext, &pb.DoubleTerms{TermOne: randomDouble(), TermTwo: randomDouble()})
+// This function makes the gRPC addition call to the server using two doubles. The server rejects terms which
+// aren't finite numbers, which the edge cases include, so those calls are counted rather than treated as failures.
+func magicAdd(server pb.MagicMathClient, requestContext context.Context, terms *pb.DoubleTerms) {
+	var backend peer.Peer
+	_, err := server.MagicAdd(requestContext, terms, grpc.Peer(&backend))
+	if code := status.Code(err); code == codes.InvalidArgument {
+		rejectedReport.record("MagicAdd", code)
+	} else if err != nil {
+		failedReport.record("MagicAdd", err)
+		return
+	}
+	backendReport.record(&backend, "MagicAdd")
+}
+
+// This function makes the gRPC subtraction call to the server using two doubles. The server rejects terms which
+// aren't finite numbers, which the edge cases include, so those calls are counted rather than treated as failures.
+func magicSubtract(server pb.MagicMathClient, requestContext context.Context, terms *pb.DoubleTerms) {
+	var backend peer.Peer
+	_, err := server.MagicSubtract(requestContext, terms, grpc.Peer(&backend))
+	if code := status.Code(err); code == codes.InvalidArgument {
+		rejectedReport.record("MagicSubtract", code)
+	} else if err != nil {
+		failedReport.record("MagicSubtract", err)
+		return
+	}
+	backendReport.record(&backend, "MagicSubtract")
+}
+
+// This function makes the gRPC min call to the server using three integers.
+func magicFindMin(server pb.MagicMathClient, requestContext context.Context, terms *pb.IntTerms) {
+	var backend peer.Peer
+	_, err := server.MagicFindMin(requestContext, terms, grpc.Peer(&backend))
 	if err != nil {
-		panic("Error on MagicAdd.")
+		failedReport.record("MagicFindMin", err)
+		return
 	}
+	backendReport.record(&backend, "MagicFindMin")
 }
 
-// This function makes the gRPC subtraction call to the server using two random doubles.
-func magicSubtract(server pb.MagicMathClient, requestContext context.Context) {
-	_, err := server.MagicSubtract(requestContext, &pb.DoubleTerms{TermOne: randomDouble(), TermTwo: randomDouble()})
+// This function makes the gRPC max call to the server using three integers.
+func magicFindMax(server pb.MagicMathClient, requestContext context.Context, terms *pb.IntTerms) {
+	var backend peer.Peer
+	_, err := server.MagicFindMax(requestContext, terms, grpc.Peer(&backend))
 	if err != nil {
-		panic("Error on MagicSubtract.")
+		failedReport.record("MagicFindMax", err)
+		return
+	}
+	backendReport.record(&backend, "MagicFindMax")
+}
+
+// This function makes the gRPC expression evaluation call to the server. Like the arithmetic calls, random values may
+// overflow, so calls the server rejects for that reason are counted rather than treated as failures.
+func magicEvaluate(server pb.MagicMathClient, requestContext context.Context, expression *pb.Expression) {
+	var backend peer.Peer
+	_, err := server.MagicEvaluate(requestContext, expression, grpc.Peer(&backend))
+	if code := status.Code(err); code == codes.InvalidArgument || code == codes.OutOfRange {
+		rejectedReport.record("MagicEvaluate", code)
+	} else if err != nil {
+		failedReport.record("MagicEvaluate", err)
+		return
 	}
+	backendReport.record(&backend, "MagicEvaluate")
 }
 
-// This function makes the gRPC min call to the server using three random integers.
-func magicFindMin(server pb.MagicMathClient, requestContext context.Context) {
-	_, err := server.MagicFindMin(requestContext, &pb.IntTerms{TermOne: randomInt(), TermTwo: randomInt(), TermThree: randomInt()})
+// This function creates the terms for an arithmetic function, which are two doubles or two integers at random.
+func createArithmeticTerms(rng *rand.Rand, terms generator.Generator) *pb.ArithmeticTerms {
+	if rng.IntN(2) == 0 {
+		return &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Doubles{
+			Doubles: &pb.DoubleTerms{TermOne: terms.Double(), TermTwo: terms.Double()},
+		}}
+	}
+
+	return &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{
+		Ints: &pb.IntPair{TermOne: terms.Int(), TermTwo: terms.Int()},
+	}}
+}
+
+// This function makes a gRPC arithmetic call to the server, which is one of multiply, divide, modulo or power.
+// Random terms often overflow or divide by zero, so calls the server rejects for those reasons are counted
+// rather than treated as failures.
+func magicArithmetic(
+	requestContext context.Context,
+	name string,
+	call func(context.Context, *pb.ArithmeticTerms, ...grpc.CallOption) (*pb.ArithmeticResult, error),
+	terms *pb.ArithmeticTerms,
+) {
+	var backend peer.Peer
+	_, err := call(requestContext, terms, grpc.Peer(&backend))
+	if code := status.Code(err); code == codes.InvalidArgument || code == codes.OutOfRange {
+		rejectedReport.record(name, code)
+	} else if err != nil {
+		failedReport.record(name, err)
+		return
+	}
+	backendReport.record(&backend, name)
+}
+
+// This function replays every call in the capture file and its rotated files, and prints each call whose
+// response differs from the recorded one, followed by a summary. It returns whether every call matched, or an error
+// if the capture can't be read.
+func replayCapture(conn *grpc.ClientConn, path string, speed float64) (bool, error) {
+	var records []capture.Record
+	for _, file := range capture.Files(path) {
+		fileRecords, err := capture.ReadFile(file)
+		if err != nil {
+			return false, fmt.Errorf("failed to read capture file: %v", err)
+		}
+		records = append(records, fileRecords...)
+	}
+
+	fmt.Printf("Replaying %d calls from %s\n", len(records), path)
+	result := replay.Replay(context.Background(), conn, records, replay.Options{Speed: speed, Timeout: timeout})
+
+	for _, mismatch := range result.Mismatches {
+		fmt.Printf("Mismatch on %s at %s with request %s: %s\n",
+			mismatch.Record.Method, mismatch.Record.Time.Format(time.RFC3339Nano), mismatch.Record.Request, mismatch.Reason)
+	}
+	fmt.Printf("Replayed %d calls: %d matched, %d differed\n", result.Total, result.Matched, len(result.Mismatches))
+
+	return len(result.Mismatches) == 0, nil
+}
+
+// These are the counters of the math functions which getCounters prints, and the methods which get them.
+var mathCounters = []struct {
+	label string
+	get   func(pb.MagicMathClient, context.Context, *pb.Empty, ...grpc.CallOption) (*pb.Count, error)
+}{
+	{"Add", pb.MagicMathClient.GetAddCount},
+	{"Sub", pb.MagicMathClient.GetSubCount},
+	{"Min", pb.MagicMathClient.GetMinCount},
+	{"Max", pb.MagicMathClient.GetMaxCount},
+	{"Mul", pb.MagicMathClient.GetMulCount},
+	{"Div", pb.MagicMathClient.GetDivCount},
+	{"Mod", pb.MagicMathClient.GetModCount},
+	{"Pow", pb.MagicMathClient.GetPowCount},
+	{"Eval", pb.MagicMathClient.GetEvalCount},
+	{"Stats", pb.MagicMathClient.GetStatsCount},
+	{"Arbitrary precision", pb.MagicMathClient.GetBigCount},
+	{"Decimal", pb.MagicMathClient.GetDecimalCount},
+}
+
+// This function calls twelve gRPC methods to get the counter for each method, print them all, and then print the total.
+// It also prints how many calls failed because the server panicked, and the cache's stats.
+// It stops at the first call which fails, and returns its error.
+func getCounters(server pb.MagicMathClient, requestContext context.Context) error {
+	var total int64
+	for _, counter := range mathCounters {
+		count, err := counter.get(server, requestContext, &pb.Empty{})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s count: %d\n", counter.label, count.Count)
+		total += count.Count
+	}
+	fmt.Printf("Total request count: %d\n", total)
+
+	panicCount, err := server.GetPanicCount(requestContext, &pb.Empty{})
 	if err != nil {
-		panic("Error on MagicFindMin.")
+		return err
 	}
+	fmt.Printf("Panic count: %d\n", panicCount.Count)
+
+	cacheStats, err := server.GetCacheStats(requestContext, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Cache: %d hits, %d misses, %d entries, %d evictions\n", cacheStats.Hits, cacheStats.Misses,
+		cacheStats.Entries, cacheStats.Evictions)
+
+	return nil
 }
 
-// This function makes the gRPC max call to the server using three random integers.
-func magicFindMax(server pb.MagicMathClient, requestContext context.Context) {
-	_, err := server.MagicFindMax(requestContext, &pb.IntTerms{TermOne: randomInt(), TermTwo: randomInt(), TermThree: randomInt()})
+// This function calls the server to get the client's quota usage, and prints it.
+func getQuota(server pb.MagicMathClient, requestContext context.Context) error {
+	quota, err := server.GetQuota(requestContext, &pb.Empty{})
 	if err != nil {
-		panic("Error on MagicFindMin.")
+		return err
 	}
+
+	fmt.Printf("Quota for %s: %s this hour, %s today\n", quota.Caller,
+		formatQuota(quota.HourlyUsed, quota.HourlyLimit), formatQuota(quota.DailyUsed, quota.DailyLimit))
+
+	return nil
 }
 
-// This function generates a random double between 0 and half the maximum float value.
-func randomDouble() float64 {
-	// Here we divide by 2 so that if two large numbers are added they don't overflow
-	return rand.Float64() * (math.MaxFloat64 / 2)
+// This function formats how much of a quota has been used, a limit of zero means there is no quota.
+func formatQuota(used, limit int64) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+
+	return fmt.Sprintf("%d of %d calls used", used, limit)
 }
 
-// This function generates a random integer between zero and 64 bit int max.
-func randomInt() int64 {
-	return rand.Int64() * math.MaxInt64
+// This object counts how many requests of each method were answered by each backend address.
+type backendTally struct {
+	mutex  sync.Mutex
+	counts map[string]map[string]int64
 }
 
-// This function calls four gRPC methods to get the counter for each method, print them all, and then print the total.
-func getCounters(server pb.MagicMathClient, requestContext context.Context) {
-	addCount, err := server.GetAddCount(requestContext, &pb.Empty{})
-	subCount, err := server.GetSubCount(requestContext, &pb.Empty{})
-	minCount, err := server.GetMinCount(requestContext, &pb.Empty{})
-	maxCount, err := server.GetMaxCount(requestContext, &pb.Empty{})
-	if err != nil {
-		fmt.Printf("Error getting counts: %v\n", err)
+func newBackendTally() *backendTally {
+	return &backendTally{counts: make(map[string]map[string]int64)}
+}
+
+// This function records that the backend in the peer object answered a request for the given method.
+func (t *backendTally) record(backend *peer.Peer, method string) {
+	address := "unknown"
+	if backend.Addr != nil {
+		address = backend.Addr.String()
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.counts[address] == nil {
+		t.counts[address] = make(map[string]int64)
+	}
+	t.counts[address][method]++
+}
+
+// This function prints the number of requests of each method per backend, sorted by address.
+func (t *backendTally) print() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	addresses := make([]string, 0, len(t.counts))
+	for address := range t.counts {
+		addresses = append(addresses, address)
 	}
+	sort.Strings(addresses)
 
-	fmt.Printf("Add count: %d\n", addCount.Count)
-	fmt.Printf("Sub count: %d\n", subCount.Count)
-	fmt.Printf("Min count: %d\n", minCount.Count)
-	fmt.Printf("Max count: %d\n", maxCount.Count)
-	fmt.Printf("Total request count: %d\n", addCount.Count+subCount.Count+minCount.Count+maxCount.Count)
+	for _, address := range addresses {
+		counts := t.counts[address]
+		total := int64(0)
+		for _, count := range counts {
+			total += count
+		}
+		fmt.Printf("Backend %s: add %d, sub %d, min %d, max %d, mul %d, div %d, mod %d, pow %d, eval %d, total %d\n",
+			address, counts["MagicAdd"], counts["MagicSubtract"], counts["MagicFindMin"], counts["MagicFindMax"],
+			counts["MagicMultiply"], counts["MagicDivide"], counts["MagicModulo"], counts["MagicPower"],
+			counts["MagicEvaluate"], total)
+	}
+}
+
+// This object counts how many requests of each method the server rejected, for each status code.
+type rejectedTally struct {
+	mutex  sync.Mutex
+	counts map[string]map[codes.Code]int64
+}
+
+func newRejectedTally() *rejectedTally {
+	return &rejectedTally{counts: make(map[string]map[codes.Code]int64)}
+}
+
+// This function records that the server rejected a request for the given method with the status code.
+func (t *rejectedTally) record(method string, code codes.Code) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.counts[method] == nil {
+		t.counts[method] = make(map[codes.Code]int64)
+	}
+	t.counts[method][code]++
+}
+
+// This function prints the number of rejected requests of each method, sorted by method.
+func (t *rejectedTally) print() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	methods := make([]string, 0, len(t.counts))
+	for method := range t.counts {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		counts := t.counts[method]
+		fmt.Printf("Rejected %s: %d invalid argument, %d out of range\n",
+			method, counts[codes.InvalidArgument], counts[codes.OutOfRange])
+	}
+}
+
+// This object counts how many requests of each method failed, other than the ones the server's math rejected,
+// and keeps the error of the first one.
+type failedTally struct {
+	mutex      sync.Mutex
+	counts     map[string]int64
+	firstError error
+}
+
+func newFailedTally() *failedTally {
+	return &failedTally{counts: make(map[string]int64)}
+}
+
+// This function records that a request for the given method failed with the error.
+func (t *failedTally) record(method string, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.counts[method]++
+	if t.firstError == nil {
+		t.firstError = fmt.Errorf("%s failed: %w", method, err)
+	}
+}
+
+// This function returns the error of the first request which failed, or nil if none did.
+func (t *failedTally) first() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.firstError
+}
+
+// This function prints the number of failed requests of each method, sorted by method.
+func (t *failedTally) print() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	methods := make([]string, 0, len(t.counts))
+	for method := range t.counts {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		fmt.Printf("Failed %s: %d requests\n", method, t.counts[method])
+	}
 }
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	mathrand "math/rand/v2"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/karldmenzel/go-grpc-client-server/client/generator"
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/app"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMake1000Requests(t *testing.T) {
+	server := servertest.Start(t)
+	backendReport = newBackendTally()
+
+	rng := mathrand.New(mathrand.NewPCG(1, 2))
+	make1000Requests(server.Client, context.Background(), rng, generator.NewDefaultUniform(rng))
+	waitGroup.Wait()
+
+	var serverTotal int64
+	for _, name := range []string{
+		counter.Add, counter.Subtract, counter.FindMin, counter.FindMax,
+		counter.Multiply, counter.Divide, counter.Modulo, counter.Power, counter.Evaluate,
+	} {
+		serverTotal += server.Counters.Count(name)
+	}
+	if serverTotal != 1000 {
+		t.Errorf("server counted %d calls; want 1000", serverTotal)
+	}
+
+	var reportTotal int64
+	for _, counts := range backendReport.counts {
+		for _, count := range counts {
+			reportTotal += count
+		}
+	}
+	if reportTotal != 1000 {
+		t.Errorf("backend report counted %d calls; want 1000", reportTotal)
+	}
+
+	// Printing the counters and quota must not fail once the requests are done.
+	if err := getCounters(server.Client, context.Background()); err != nil {
+		t.Errorf("getCounters() returned error: %v", err)
+	}
+	if err := getQuota(server.Client, context.Background()); err != nil {
+		t.Errorf("getQuota() returned error: %v", err)
+	}
+}
+
+// This function serves a server with the given configuration on a loopback TCP port, which the client's commands can
+// connect to, and returns its address.
+func startTCPServer(t *testing.T, config app.Config) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := app.New(config)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestRunExitCodes(t *testing.T) {
+	target := startTCPServer(t, app.Config{})
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"add", "-target", target, "1.5", "2"}, exitOK},
+		{[]string{"MagicAdd", "-target", target, "1.5", "2"}, exitUsage},
+		{[]string{"min", "-target", target, "-output", "json", "3", "-1", "7"}, exitOK},
+		{[]string{"sub", "-target", target, "--", "-1", "2"}, exitOK},
+		{[]string{"counts", "-target", target}, exitOK},
+		{[]string{"div", "-target", target, "7", "0"}, exitStatusBase + int(codes.InvalidArgument)},
+		{[]string{"pow", "-target", target, "2", "64"}, exitStatusBase + int(codes.OutOfRange)},
+		{[]string{"add", "-target", target, "1"}, exitUsage},
+		{[]string{"add", "-target", target, "-output", "xml", "1", "2"}, exitUsage},
+		{[]string{"add", "-target", target, "-token", "secret", "1", "2"}, exitUsage},
+		{[]string{"square", "4"}, exitUsage},
+		{[]string{"-target", target, "square"}, exitUsage},
+		{[]string{"health", "-target", target}, exitOK},
+		{[]string{"health", "-target", target, "-service", "shared.MagicMath"}, exitOK},
+		{[]string{"health", "-target", target, "-service", "shared.Unknown"}, exitStatusBase + int(codes.NotFound)},
+		{[]string{"add", "-target", "127.0.0.1:1", "-max-attempts", "1", "1", "2"}, exitStatusBase + int(codes.Unavailable)},
+		{[]string{"help"}, exitOK},
+		{[]string{"add", "-h"}, exitOK},
+	}
+
+	for _, tt := range tests {
+		if got := run(tt.args); got != tt.want {
+			t.Errorf("run(%q) = %d; want %d", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRunHealthNotServing(t *testing.T) {
+	healthServer := health.NewServer()
+	target := startTCPServer(t, app.Config{Health: healthServer})
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	if got := run([]string{"health", "-target", target}); got != exitFailed {
+		t.Errorf("run(health) on a server which isn't serving = %d; want %d", got, exitFailed)
+	}
+}
+
+func TestRunWithoutCommandRunsLoad(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	target := startTCPServer(t, app.Config{Counters: counters})
+	backendReport, failedReport = newBackendTally(), newFailedTally()
+
+	if got := run([]string{"-target", target, "-seed", "7"}); got != exitOK {
+		t.Fatalf("run() = %d; want %d", got, exitOK)
+	}
+	if add := counters.Count(counter.Add); add == 0 {
+		t.Errorf("server counted %d additions; want the load's additions", add)
+	}
+}
+
+func TestRunLoadExitsWithCodeOfFailedCall(t *testing.T) {
+	denyMin := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if info.FullMethod == pb.MagicMath_MagicFindMin_FullMethodName {
+			return nil, status.Error(codes.PermissionDenied, "min is not allowed")
+		}
+		return handler(ctx, req)
+	}
+	target := startTCPServer(t, app.Config{UnaryInterceptors: []grpc.UnaryServerInterceptor{denyMin}})
+	backendReport, failedReport = newBackendTally(), newFailedTally()
+
+	if got, want := run([]string{"load", "-target", target, "-seed", "7"}), exitStatusBase+int(codes.PermissionDenied); got != want {
+		t.Errorf("run(load) with failing calls = %d; want %d", got, want)
+	}
+	if failedReport.counts["MagicFindMin"] == 0 || len(failedReport.counts) != 1 {
+		t.Errorf("failed requests = %v; want only MagicFindMin", failedReport.counts)
+	}
+}
+
+func TestRunWithTLSAndToken(t *testing.T) {
+	serverConfig, caFile := selfSignedCertificate(t)
+
+	var mutex sync.Mutex
+	var authorization []string
+	recordAuthorization := func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		mutex.Lock()
+		authorization = append(authorization, md.Get("authorization")...)
+		mutex.Unlock()
+		return handler(ctx, request)
+	}
+	target := startTCPServer(t, app.Config{
+		Credentials:       credentials.NewTLS(serverConfig),
+		UnaryInterceptors: []grpc.UnaryServerInterceptor{recordAuthorization},
+	})
+
+	if got := run([]string{"add", "-target", target, "-ca-file", caFile, "-token", "secret", "1", "2"}); got != exitOK {
+		t.Fatalf("run(add) with TLS and a token = %d; want %d", got, exitOK)
+	}
+	if len(authorization) != 1 || authorization[0] != "Bearer secret" {
+		t.Errorf("server received authorization %q; want [\"Bearer secret\"]", authorization)
+	}
+
+	// Without the certificate authority the server's certificate isn't trusted, and without TLS the server can't be
+	// reached at all.
+	for _, args := range [][]string{
+		{"add", "-target", target, "-tls", "-max-attempts", "1", "1", "2"},
+		{"add", "-target", target, "-max-attempts", "1", "1", "2"},
+	} {
+		if got := run(args); got != exitStatusBase+int(codes.Unavailable) {
+			t.Errorf("run(%q) = %d; want %d", args, got, exitStatusBase+int(codes.Unavailable))
+		}
+	}
+}
+
+// This function generates a self-signed certificate for 127.0.0.1, and returns a server TLS configuration presenting
+// it, along with the path of a PEM file holding the certificate, for the client to trust.
+func selfSignedCertificate(t *testing.T) (*tls.Config, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() returned error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() returned error: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}), 0o600); err != nil {
+		t.Fatalf("WriteFile() returned error: %v", err)
+	}
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{certificate}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	return serverConfig, caFile
+}
@@ -0,0 +1,161 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/karldmenzel/go-grpc-client-server/capture"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// The MagicMath messages, of both versions, must be registered so that recorded calls can be decoded.
+	_ "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	_ "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+)
+
+// The most calls in flight at once when replaying as fast as possible.
+const maxConcurrentCalls = 64
+
+// Options controls how recorded calls are replayed.
+type Options struct {
+	// Speed scales the time between calls: 1 keeps the original timing, 2 replays twice as fast,
+	// and 0 replays as fast as possible.
+	Speed float64
+	// Timeout is how long each call may take. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// Mismatch describes a replayed call whose result differs from the recorded one.
+type Mismatch struct {
+	Record capture.Record
+	// Reason describes the difference, in the form "recorded ... but got ...".
+	Reason string
+}
+
+// Result summarizes a replay.
+type Result struct {
+	Total      int
+	Matched    int
+	Mismatches []Mismatch
+}
+
+// Replay re-issues each recorded call on the connection, and compares each response and status with the
+// recorded one. Calls are issued concurrently, at the times given by the options.
+func Replay(ctx context.Context, connection grpc.ClientConnInterface, records []capture.Record, options Options) *Result {
+	result := &Result{Total: len(records)}
+	if len(records) == 0 {
+		return result
+	}
+
+	var mutex sync.Mutex
+	var waitGroup sync.WaitGroup
+	slots := make(chan struct{}, maxConcurrentCalls)
+
+	start := time.Now()
+	first := records[0].Time
+	for _, record := range records {
+		if options.Speed > 0 {
+			offset := time.Duration(float64(record.Time.Sub(first)) / options.Speed)
+			select {
+			case <-time.After(time.Until(start.Add(offset))):
+			case <-ctx.Done():
+			}
+		} else {
+			slots <- struct{}{}
+		}
+
+		waitGroup.Go(func() {
+			reason := replayOne(ctx, connection, record, options.Timeout)
+			if options.Speed <= 0 {
+				<-slots
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if reason == "" {
+				result.Matched++
+			} else {
+				result.Mismatches = append(result.Mismatches, Mismatch{Record: record, Reason: reason})
+			}
+		})
+	}
+	waitGroup.Wait()
+
+	return result
+}
+
+// This function re-issues a single call, and returns why its result differs from the recording,
+// or an empty string if it matches.
+func replayOne(ctx context.Context, connection grpc.ClientConnInterface, record capture.Record, timeout time.Duration) string {
+	method, err := findMethod(record.Method)
+	if err != nil {
+		return err.Error()
+	}
+
+	request := dynamicpb.NewMessage(method.Input())
+	if err := protojson.Unmarshal(record.Request, request); err != nil {
+		return fmt.Sprintf("recorded request can't be decoded: %v", err)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	response := dynamicpb.NewMessage(method.Output())
+	err = connection.Invoke(ctx, record.Method, request, response)
+
+	gotCode := status.Code(err)
+	if gotCode != record.StatusCode() {
+		return fmt.Sprintf("recorded status %s but got %s (%s)", record.Code, gotCode, status.Convert(err).Message())
+	}
+	if gotCode != codes.OK {
+		return ""
+	}
+
+	recorded := dynamicpb.NewMessage(method.Output())
+	if err := protojson.Unmarshal(record.Response, recorded); err != nil {
+		return fmt.Sprintf("recorded response can't be decoded: %v", err)
+	}
+	if !proto.Equal(recorded, response) {
+		got, _ := protojson.Marshal(response)
+		return fmt.Sprintf("recorded response %s but got %s", record.Response, got)
+	}
+
+	return ""
+}
+
+// This function looks up the descriptor of a method from its full name, for example "/shared.MagicMath/MagicAdd".
+func findMethod(fullMethod string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid method name %q", fullMethod)
+	}
+
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("unknown service in method %q", fullMethod)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("unknown service in method %q", fullMethod)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("unknown method %q", fullMethod)
+	}
+
+	return method, nil
+}
@@ -0,0 +1,85 @@
+package replay
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/karldmenzel/go-grpc-client-server/capture"
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/recorder"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc"
+)
+
+// recordCalls makes a few calls to a recording server, and returns what it recorded.
+func recordCalls(t *testing.T) []capture.Record {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	writer, err := capture.NewWriter(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewWriter() returned error: %v", err)
+	}
+	client := servertest.Start(t, servertest.WithUnaryInterceptors(recorder.UnaryServerInterceptor(writer))).Client
+
+	ctx := context.Background()
+	client.MagicAdd(ctx, &pb.DoubleTerms{TermOne: 0.1, TermTwo: 0.2})
+	client.MagicSubtract(ctx, &pb.DoubleTerms{TermOne: 5, TermTwo: 3})
+	time.Sleep(50 * time.Millisecond)
+	client.MagicFindMin(ctx, &pb.IntTerms{TermOne: 9, TermTwo: -9, TermThree: 0})
+	client.MagicFindMax(ctx, &pb.IntTerms{TermOne: 9, TermTwo: -9, TermThree: 0})
+	writer.Close()
+
+	records, err := capture.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() returned error: %v", err)
+	}
+
+	return records
+}
+
+func TestReplayMatchesSameBuild(t *testing.T) {
+	records := recordCalls(t)
+	server := servertest.Start(t)
+
+	result := Replay(context.Background(), server.Conn, records, Options{})
+	if result.Total != 4 || result.Matched != 4 || len(result.Mismatches) != 0 {
+		t.Errorf("Replay() = %+v; want all 4 calls to match", result)
+	}
+}
+
+func TestReplayReportsDifferences(t *testing.T) {
+	records := recordCalls(t)
+
+	// This server build has a broken MagicFindMin, which always answers zero.
+	brokenMin := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if info.FullMethod == pb.MagicMath_MagicFindMin_FullMethodName {
+			return &pb.IntResult{Result: 0}, nil
+		}
+		return handler(ctx, req)
+	}
+	server := servertest.Start(t, servertest.WithUnaryInterceptors(brokenMin))
+
+	result := Replay(context.Background(), server.Conn, records, Options{})
+	if result.Matched != 3 || len(result.Mismatches) != 1 {
+		t.Fatalf("Replay() = %+v; want 3 matches and 1 mismatch", result)
+	}
+	if result.Mismatches[0].Record.Method != pb.MagicMath_MagicFindMin_FullMethodName {
+		t.Errorf("mismatch on %s; want %s", result.Mismatches[0].Record.Method, pb.MagicMath_MagicFindMin_FullMethodName)
+	}
+}
+
+func TestReplayKeepsScaledTiming(t *testing.T) {
+	records := recordCalls(t)
+	server := servertest.Start(t)
+
+	// The recorded calls are spread over at least 50ms, so replaying at half speed takes at least 100ms.
+	start := time.Now()
+	Replay(context.Background(), server.Conn, records, Options{Speed: 0.5})
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("replay at half speed took %v; want at least 100ms", elapsed)
+	}
+}
@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The random extra wait added to a retry starts out spread over this long, or the server's delay if that is longer,
+// and is never spread over more than maxJitter.
+const (
+	baseJitter = 50 * time.Millisecond
+	maxJitter  = 10 * time.Second
+)
+
+// UnaryClientInterceptor retries calls which the server rejected with codes.ResourceExhausted, waiting at least as
+// long as the error's RetryInfo detail asks. A call is tried at most maxAttempts times, and is given up on when its
+// context ends or the server rejects it without saying when to retry.
+//
+// Many calls rejected at once are all told to wait the same time, so if they all retried together most of them would
+// be rejected again. Each retry adds a random extra wait, which doubles with every attempt, to spread them out.
+func UnaryClientInterceptor(maxAttempts int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if status.Code(err) != codes.ResourceExhausted || attempt >= maxAttempts {
+				return err
+			}
+
+			delay, ok := Delay(err)
+			if !ok {
+				return err
+			}
+
+			timer := time.NewTimer(delay + jitter(delay, attempt))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			}
+		}
+	}
+}
+
+// Delay returns how long the server asked the client to wait before retrying, from the error's RetryInfo detail.
+func Delay(err error) (time.Duration, bool) {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.RetryDelay.AsDuration(), true
+		}
+	}
+
+	return 0, false
+}
+
+// This function returns a random extra wait, spread over a time which doubles with each attempt.
+func jitter(delay time.Duration, attempt int) time.Duration {
+	spread := min(max(delay, baseJitter)<<(attempt-1), maxJitter)
+
+	return rand.N(spread)
+}
@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// This function returns an invoker which is rejected with the given errors in turn, then succeeds.
+func rejectingInvoker(calls *int, errs ...error) grpc.UnaryInvoker {
+	return func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func exhausted(delay time.Duration) error {
+	st, _ := status.New(codes.ResourceExhausted, "slow down").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	return st.Err()
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{"succeeds after waiting", []error{exhausted(time.Millisecond), exhausted(time.Millisecond)}, 3, codes.OK},
+		{"gives up after max attempts", []error{exhausted(0), exhausted(0), exhausted(0), exhausted(0)}, 3, codes.ResourceExhausted},
+		{"no retry info", []error{status.Error(codes.ResourceExhausted, "full")}, 1, codes.ResourceExhausted},
+		{"other errors", []error{status.Error(codes.Unavailable, "down")}, 1, codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+		err := UnaryClientInterceptor(3)(context.Background(), "/m", nil, nil, nil, rejectingInvoker(&calls, tt.errs...))
+		if calls != tt.wantCalls || status.Code(err) != tt.wantCode {
+			t.Errorf("%s: %d calls returning %v; want %d calls returning %v", tt.name, calls, err, tt.wantCalls, tt.wantCode)
+		}
+	}
+}
+
+func TestGivesUpWhenContextEnds(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := UnaryClientInterceptor(3)(ctx, "/m", nil, nil, nil, rejectingInvoker(&calls, exhausted(time.Hour)))
+	if status.Code(err) != codes.ResourceExhausted || time.Since(start) > time.Second {
+		t.Errorf("got %v after %v; want ResourceExhausted as soon as the context ends", err, time.Since(start))
+	}
+}
@@ -0,0 +1,252 @@
+package shell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+
+	"google.golang.org/grpc"
+)
+
+// Command is a shell command which makes calls to the server.
+type Command struct {
+	// Name is what the command is typed as, and Method is the MagicMath method it calls, which may be typed instead.
+	Name, Method string
+	// Usage shows the arguments of the command, and Help says what it does.
+	Usage, Help string
+	// run makes the call, and returns the server's response.
+	run func(ctx context.Context, client pb.MagicMathClient, args []string) (any, error)
+}
+
+// These are the commands which call the server, in the order the help lists them.
+var commands = []Command{
+	{"add", "MagicAdd", "add <a> <b>", "Adds two doubles.", doubleCall(pb.MagicMathClient.MagicAdd)},
+	{"sub", "MagicSubtract", "sub <a> <b>", "Subtracts the second double from the first.", doubleCall(pb.MagicMathClient.MagicSubtract)},
+	{"min", "MagicFindMin", "min <a> <b> <c>", "Finds the smallest of three integers.", intCall(pb.MagicMathClient.MagicFindMin)},
+	{"max", "MagicFindMax", "max <a> <b> <c>", "Finds the largest of three integers.", intCall(pb.MagicMathClient.MagicFindMax)},
+	{"mul", "MagicMultiply", "mul <a> <b>", "Multiplies two integers, or two doubles.", arithmeticCall(pb.MagicMathClient.MagicMultiply)},
+	{"div", "MagicDivide", "div <a> <b>", "Divides two integers, or two doubles.", arithmeticCall(pb.MagicMathClient.MagicDivide)},
+	{"mod", "MagicModulo", "mod <a> <b>", "The remainder of dividing two integers, or two doubles.", arithmeticCall(pb.MagicMathClient.MagicModulo)},
+	{"pow", "MagicPower", "pow <a> <b>", "Raises an integer, or a double, to a power.", arithmeticCall(pb.MagicMathClient.MagicPower)},
+	{"eval", "MagicEvaluate", `eval <expression> [name=value ...]`, "Evaluates an expression, such as eval \"(a + 1) * 2\" a=3.", evaluate},
+	{"stats", "MagicStats", "stats <value> ...", "Summarizes a list of doubles.", stats},
+	{"counts", "", "counts", "Shows how many times each function has been called.", counts},
+	{"quota", "GetQuota", "quota", "Shows how much of its quotas this client has used.", quota},
+}
+
+// Commands returns the commands which call the server.
+func Commands() []Command {
+	return slices.Clone(commands)
+}
+
+// This function finds a command by its name or by the name of its method.
+func findCommand(name string) (Command, bool) {
+	for _, command := range commands {
+		if name == command.Name || (command.Method != "" && name == command.Method) {
+			return command, true
+		}
+	}
+
+	return Command{}, false
+}
+
+// This function turns a method which takes two doubles into the run function of a command.
+func doubleCall(
+	method func(pb.MagicMathClient, context.Context, *pb.DoubleTerms, ...grpc.CallOption) (*pb.DoubleResult, error),
+) func(context.Context, pb.MagicMathClient, []string) (any, error) {
+	return func(ctx context.Context, client pb.MagicMathClient, args []string) (any, error) {
+		terms, err := parseDoubles(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return method(client, ctx, &pb.DoubleTerms{TermOne: terms[0], TermTwo: terms[1]})
+	}
+}
+
+// This function turns a method which takes three integers into the run function of a command.
+func intCall(
+	method func(pb.MagicMathClient, context.Context, *pb.IntTerms, ...grpc.CallOption) (*pb.IntResult, error),
+) func(context.Context, pb.MagicMathClient, []string) (any, error) {
+	return func(ctx context.Context, client pb.MagicMathClient, args []string) (any, error) {
+		terms, err := parseInts(args, 3)
+		if err != nil {
+			return nil, err
+		}
+		return method(client, ctx, &pb.IntTerms{TermOne: terms[0], TermTwo: terms[1], TermThree: terms[2]})
+	}
+}
+
+// This function turns a method which takes two integers or two doubles into the run function of a command.
+// The terms are sent as integers when both of them are written as integers, and as doubles otherwise.
+func arithmeticCall(
+	method func(pb.MagicMathClient, context.Context, *pb.ArithmeticTerms, ...grpc.CallOption) (*pb.ArithmeticResult, error),
+) func(context.Context, pb.MagicMathClient, []string) (any, error) {
+	return func(ctx context.Context, client pb.MagicMathClient, args []string) (any, error) {
+		if ints, err := parseInts(args, 2); err == nil {
+			pair := &pb.IntPair{TermOne: ints[0], TermTwo: ints[1]}
+			return method(client, ctx, &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{Ints: pair}})
+		}
+
+		doubles, err := parseDoubles(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		pair := &pb.DoubleTerms{TermOne: doubles[0], TermTwo: doubles[1]}
+		return method(client, ctx, &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Doubles{Doubles: pair}})
+	}
+}
+
+// This matches the variables given to eval, such as "a=3".
+var variablePattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.+)$`)
+
+// This function runs the eval command. The arguments at the end which look like name=value are the variables,
+// and the ones before them are joined with spaces into the expression, so quoting the expression is optional.
+func evaluate(ctx context.Context, client pb.MagicMathClient, args []string) (any, error) {
+	expression := &pb.Expression{Variables: map[string]float64{}}
+	end := len(args)
+	for end > 0 {
+		match := variablePattern.FindStringSubmatch(args[end-1])
+		if match == nil {
+			break
+		}
+		value, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("the value of %s must be a number, not %q", match[1], match[2])
+		}
+		expression.Variables[match[1]] = value
+		end--
+	}
+	if end == 0 {
+		return nil, fmt.Errorf("eval needs an expression")
+	}
+	expression.Expression = strings.Join(args[:end], " ")
+
+	return client.MagicEvaluate(ctx, expression)
+}
+
+// This function runs the stats command.
+func stats(ctx context.Context, client pb.MagicMathClient, args []string) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("stats needs at least one value")
+	}
+	values, err := parseDoubles(args, len(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return client.MagicStats(ctx, &pb.StatsRequest{Values: values})
+}
+
+// This function runs the quota command.
+func quota(ctx context.Context, client pb.MagicMathClient, args []string) (any, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("quota takes no arguments")
+	}
+
+	return client.GetQuota(ctx, &pb.Empty{})
+}
+
+// Counts are the values of the server's counters, in the order the counts command gets them.
+type Counts []Count
+
+// Count is the value of one of the server's counters.
+type Count struct {
+	Name  string
+	Count int64
+}
+
+// MarshalJSON writes the counts as an object from each counter's name to its value.
+func (c Counts) MarshalJSON() ([]byte, error) {
+	counts := make(map[string]int64, len(c))
+	for _, count := range c {
+		counts[count.Name] = count.Count
+	}
+
+	return json.Marshal(counts)
+}
+
+// These are the counters the counts command shows, and the methods which get them.
+var counters = []struct {
+	name string
+	get  func(pb.MagicMathClient, context.Context, *pb.Empty, ...grpc.CallOption) (*pb.Count, error)
+}{
+	{"add", pb.MagicMathClient.GetAddCount},
+	{"sub", pb.MagicMathClient.GetSubCount},
+	{"min", pb.MagicMathClient.GetMinCount},
+	{"max", pb.MagicMathClient.GetMaxCount},
+	{"mul", pb.MagicMathClient.GetMulCount},
+	{"div", pb.MagicMathClient.GetDivCount},
+	{"mod", pb.MagicMathClient.GetModCount},
+	{"pow", pb.MagicMathClient.GetPowCount},
+	{"eval", pb.MagicMathClient.GetEvalCount},
+	{"stats", pb.MagicMathClient.GetStatsCount},
+	{"big", pb.MagicMathClient.GetBigCount},
+	{"decimal", pb.MagicMathClient.GetDecimalCount},
+	{"panic", pb.MagicMathClient.GetPanicCount},
+}
+
+// This function runs the counts command, which gets every counter, and the cache's hits, misses, entries and evictions.
+func counts(ctx context.Context, client pb.MagicMathClient, args []string) (any, error) {
+	if len(args) != 0 {
+		return nil, fmt.Errorf("counts takes no arguments")
+	}
+
+	result := make(Counts, 0, len(counters)+4)
+	for _, counter := range counters {
+		count, err := counter.get(client, ctx, &pb.Empty{})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, Count{counter.name, count.Count})
+	}
+
+	cache, err := client.GetCacheStats(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, Count{"cacheHits", cache.Hits}, Count{"cacheMisses", cache.Misses},
+		Count{"cacheEntries", cache.Entries}, Count{"cacheEvictions", cache.Evictions}), nil
+}
+
+// This function parses exactly count doubles.
+func parseDoubles(args []string, count int) ([]float64, error) {
+	if len(args) != count {
+		return nil, fmt.Errorf("expected %d numbers, not %d", count, len(args))
+	}
+
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		value, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", arg)
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// This function parses exactly count integers.
+func parseInts(args []string, count int) ([]int64, error) {
+	if len(args) != count {
+		return nil, fmt.Errorf("expected %d integers, not %d", count, len(args))
+	}
+
+	values := make([]int64, len(args))
+	for i, arg := range args {
+		value, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", arg)
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,132 @@
+package shell
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCommands(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		line string
+		want proto.Message
+	}{
+		{"add 1.5 2", &pb.DoubleResult{Result: 3.5}},
+		{"MagicAdd 1.5 2", &pb.DoubleResult{Result: 3.5}},
+		{"sub 1 3", &pb.DoubleResult{Result: -2}},
+		{"min 3 -1 7", &pb.IntResult{Result: -1}},
+		{"max 3 -1 7", &pb.IntResult{Result: 7}},
+		{"mul 6 7", &pb.ArithmeticResult{Result: &pb.ArithmeticResult_IntResult{IntResult: 42}}},
+		{"div 7 2", &pb.ArithmeticResult{Result: &pb.ArithmeticResult_IntResult{IntResult: 3}}},
+		{"div 7 2.0", &pb.ArithmeticResult{Result: &pb.ArithmeticResult_DoubleResult{DoubleResult: 3.5}}},
+		{"pow 2 10", &pb.ArithmeticResult{Result: &pb.ArithmeticResult_IntResult{IntResult: 1024}}},
+		{"eval 1 + 2 * 3", &pb.DoubleResult{Result: 7}},
+		{`eval "(a + 1) * b" a=3 b=2`, &pb.DoubleResult{Result: 8}},
+	}
+
+	for _, tt := range tests {
+		args, _ := splitLine(tt.line)
+		command, ok := findCommand(args[0])
+		if !ok {
+			t.Errorf("findCommand(%q) found no command", args[0])
+			continue
+		}
+		result, err := command.run(context.Background(), client, args[1:])
+		if err != nil {
+			t.Errorf("%s returned error: %v", tt.line, err)
+			continue
+		}
+		if !proto.Equal(result.(proto.Message), tt.want) {
+			t.Errorf("%s = %v; want %v", tt.line, result, tt.want)
+		}
+	}
+}
+
+func TestCommandArgumentErrors(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"add 1", "expected 2 numbers, not 1"},
+		{"add 1 two", `"two" is not a number`},
+		{"min 1 2", "expected 3 integers, not 2"},
+		{"max 1 2 3.5", `"3.5" is not an integer`},
+		{"mul 1 x", `"x" is not a number`},
+		{"eval a=1", "eval needs an expression"},
+		{"stats", "stats needs at least one value"},
+		{"counts 1", "counts takes no arguments"},
+	}
+
+	for _, tt := range tests {
+		args, _ := splitLine(tt.line)
+		command, _ := findCommand(args[0])
+		_, err := command.run(context.Background(), client, args[1:])
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s returned error %v; want %q", tt.line, err, tt.want)
+		}
+		if _, isStatus := status.FromError(err); isStatus {
+			t.Errorf("%s returned a gRPC status; want an error from the arguments", tt.line)
+		}
+	}
+}
+
+func TestCommandServerErrors(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	command, _ := findCommand("div")
+	_, err := command.run(context.Background(), client, []string{"7", "0"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("div 7 0 returned error %v; want code %v", err, codes.InvalidArgument)
+	}
+
+	command, _ = findCommand("stats")
+	_, err = command.run(context.Background(), client, []string{"1", "Inf"})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "values[1]") {
+		t.Errorf("stats 1 Inf returned error %v; want code %v about values[1]", err, codes.InvalidArgument)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	server := servertest.Start(t)
+	for range 2 {
+		server.Client.MagicAdd(context.Background(), &pb.DoubleTerms{TermOne: 1, TermTwo: 2})
+	}
+
+	result, err := counts(context.Background(), server.Client, nil)
+	if err != nil {
+		t.Fatalf("counts returned error: %v", err)
+	}
+	got := result.(Counts)
+	if len(got) != len(counters)+4 || got[0] != (Count{"add", 2}) || got[1] != (Count{"sub", 0}) {
+		t.Errorf("counts = %v; want every counter, starting with add 2, sub 0", got)
+	}
+
+	json, err := got.MarshalJSON()
+	if err != nil || !strings.Contains(string(json), `"add":2`) {
+		t.Errorf("MarshalJSON() = %s, %v; want an object with \"add\":2", json, err)
+	}
+}
+
+func TestArithmeticCallKeepsLargeIntegersExact(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	command, _ := findCommand("mul")
+	result, err := command.run(context.Background(), client, []string{"9007199254740993", "1"})
+	if err != nil {
+		t.Fatalf("mul returned error: %v", err)
+	}
+	if got := result.(*pb.ArithmeticResult).GetIntResult(); got != 9007199254740993 {
+		t.Errorf("mul 9007199254740993 1 = %v; want 9007199254740993", result)
+	}
+}
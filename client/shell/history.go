@@ -0,0 +1,88 @@
+package shell
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// The most lines the history keeps.
+const maxHistory = 1000
+
+// History is the list of lines typed into the shell. It can be kept in a file, so that it lasts between sessions.
+// It implements term.History, so the up and down arrows step through it.
+type History struct {
+	lines []string
+	file  *os.File
+}
+
+// OpenHistory reads the history kept in a file, and appends every line added later to the same file.
+// The file is created if it doesn't exist. An empty path keeps the history in memory only.
+func OpenHistory(path string) (*History, error) {
+	history := &History{}
+	if path == "" {
+		return history, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		history.remember(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	history.file = file
+
+	return history, nil
+}
+
+// Add adds a line to the end of the history. Blank lines, and lines which repeat the one before them, are skipped.
+func (h *History) Add(line string) {
+	if h.remember(line) && h.file != nil {
+		// Losing a line of history isn't worth interrupting the shell for, so write errors are ignored.
+		h.file.WriteString(line + "\n")
+	}
+}
+
+// This function adds a line to the lines in memory, dropping the oldest line when there are too many.
+// It returns whether the line was added.
+func (h *History) remember(line string) bool {
+	if strings.TrimSpace(line) == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return false
+	}
+	if len(h.lines) == maxHistory {
+		h.lines = h.lines[1:]
+	}
+	h.lines = append(h.lines, line)
+
+	return true
+}
+
+// Len returns how many lines are in the history.
+func (h *History) Len() int {
+	return len(h.lines)
+}
+
+// At returns a line of the history, counting back from the most recent line, which is 0.
+func (h *History) At(index int) string {
+	return h.lines[len(h.lines)-1-index]
+}
+
+// Lines returns the lines of the history, oldest first.
+func (h *History) Lines() []string {
+	return h.lines
+}
+
+// Close closes the history's file.
+func (h *History) Close() error {
+	if h.file == nil {
+		return nil
+	}
+
+	return h.file.Close()
+}
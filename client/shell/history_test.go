@@ -0,0 +1,58 @@
+package shell
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	history, _ := OpenHistory("")
+	for _, line := range []string{"add 1 2", "", "  ", "counts", "counts", "min 1 2 3"} {
+		history.Add(line)
+	}
+
+	want := []string{"add 1 2", "counts", "min 1 2 3"}
+	if got := history.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q; want %q", got, want)
+	}
+	if history.Len() != 3 || history.At(0) != "min 1 2 3" || history.At(2) != "add 1 2" {
+		t.Errorf("Len(), At(0), At(2) = %d, %q, %q; want 3, most recent first", history.Len(), history.At(0), history.At(2))
+	}
+}
+
+func TestHistoryIsBounded(t *testing.T) {
+	history, _ := OpenHistory("")
+	for i := range maxHistory + 10 {
+		history.Add(string(rune('a'+i%26)) + " " + string(rune('0'+i%10)))
+	}
+
+	if history.Len() != maxHistory {
+		t.Errorf("Len() = %d; want %d", history.Len(), maxHistory)
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	history, err := OpenHistory(path)
+	if err != nil {
+		t.Fatalf("OpenHistory() returned error: %v", err)
+	}
+	history.Add("add 1 2")
+	history.Add("counts")
+	history.Close()
+
+	reopened, err := OpenHistory(path)
+	if err != nil {
+		t.Fatalf("OpenHistory() returned error: %v", err)
+	}
+	defer reopened.Close()
+	reopened.Add("counts")
+	reopened.Add("max 1 2 3")
+
+	want := []string{"add 1 2", "counts", "max 1 2 3"}
+	if got := reopened.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() after reopening = %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,576 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+
+	"golang.org/x/term"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Formats are the output formats the shell can print results in. Text is meant for people, and JSON prints one
+// object per command, for scripts.
+var Formats = []string{"text", "json"}
+
+// The prompt shown before each command typed on a terminal.
+const prompt = "magicmath> "
+
+// These are the commands handled by the shell itself, rather than by calling the server.
+var shellCommands = []string{"help", "history", "format", "timing", "watch", "exit", "quit"}
+
+// Options controls how the shell prints results, and how long calls may take.
+type Options struct {
+	// Format is the output format the shell starts with, one of Formats. Empty means text.
+	Format string
+	// Timing prints how long each command took, which the timing command can also turn on and off.
+	Timing bool
+	// Timeout is how long each command may take. Zero means no timeout.
+	Timeout time.Duration
+	// HistoryFile keeps the history of commands typed on a terminal between sessions. Empty keeps it in memory only.
+	HistoryFile string
+}
+
+// Shell runs commands, such as "add 1.5 2" or "counts", which each make calls to a MagicMath server and print its
+// response.
+type Shell struct {
+	client  pb.MagicMathClient
+	options Options
+	history *History
+	// keys receives what is typed while a watch command is running, which stops it. It is nil unless the shell
+	// runs on a terminal.
+	keys <-chan []byte
+}
+
+// New creates a shell which calls the server through the given client.
+func New(client pb.MagicMathClient, options Options) *Shell {
+	if options.Format == "" {
+		options.Format = Formats[0]
+	}
+
+	return &Shell{client: client, options: options, history: &History{}}
+}
+
+// Run reads commands from in, one per line, and prints their results to out, until the exit command or the end of
+// the input. It shows no prompt, and doesn't keep the commands in the history file, so it suits scripts and pipes.
+func (s *Shell) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for ctx.Err() == nil && scanner.Scan() {
+		s.history.Add(scanner.Text())
+		if s.execute(ctx, out, scanner.Text()) {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+// RunTerminal runs the shell on a terminal, with line editing, a history which the up and down arrows step
+// through, and tab completion of commands and method names. It returns at the exit command, Ctrl-C or Ctrl-D.
+func (s *Shell) RunTerminal(ctx context.Context, terminalFile *os.File) error {
+	fd := int(terminalFile.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	history, err := OpenHistory(s.options.HistoryFile)
+	if err != nil {
+		return err
+	}
+	defer history.Close()
+	s.history = history
+
+	// Everything typed is read by a single goroutine, so that a watch command can notice a key being pressed
+	// without stealing any of the following input from the terminal. The goroutine ends with the program.
+	keys := make(chan []byte)
+	go readKeys(terminalFile, keys)
+	s.keys = keys
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{&keyReader{keys: keys}, terminalFile}, prompt)
+	terminal.History = history
+	terminal.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, newPos, candidates := complete(line, pos)
+		if newLine == line && len(candidates) > 1 {
+			fmt.Fprintln(terminal, strings.Join(candidates, "  "))
+		}
+		return newLine, newPos, true
+	}
+	if width, height, err := term.GetSize(fd); err == nil {
+		terminal.SetSize(width, height)
+	}
+
+	fmt.Fprintln(terminal, "Type help for the list of commands, and exit or Ctrl-D to leave.")
+	for ctx.Err() == nil {
+		line, err := terminal.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if s.execute(ctx, terminal, line) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// This function reads everything typed on the terminal, and sends it on the channel, which is closed when reading
+// fails.
+func readKeys(terminalFile *os.File, keys chan<- []byte) {
+	defer close(keys)
+	for {
+		buffer := make([]byte, 256)
+		n, err := terminalFile.Read(buffer)
+		if n > 0 {
+			keys <- buffer[:n]
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// keyReader is an io.Reader of what readKeys sends on a channel.
+type keyReader struct {
+	keys    <-chan []byte
+	pending []byte
+}
+
+// Read copies what has been typed into the buffer, waiting until something is typed.
+func (r *keyReader) Read(buffer []byte) (int, error) {
+	if len(r.pending) == 0 {
+		keys, ok := <-r.keys
+		if !ok {
+			return 0, io.EOF
+		}
+		r.pending = keys
+	}
+	n := copy(buffer, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+// This function runs a single line of input, and returns true if the shell should exit.
+func (s *Shell) execute(ctx context.Context, out io.Writer, line string) bool {
+	args, err := splitLine(line)
+	if err != nil {
+		fmt.Fprintf(out, "error: %v\n", err)
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		s.help(out, args[1:])
+	case "history":
+		for i, line := range s.history.Lines() {
+			fmt.Fprintf(out, "%5d  %s\n", i+1, line)
+		}
+	case "format":
+		if len(args) > 1 {
+			if !slices.Contains(Formats, args[1]) {
+				fmt.Fprintf(out, "error: unknown format %q, the formats are %s\n", args[1], strings.Join(Formats, ", "))
+				return false
+			}
+			s.options.Format = args[1]
+		}
+		fmt.Fprintf(out, "format %s\n", s.options.Format)
+	case "timing":
+		if len(args) > 1 {
+			if args[1] != "on" && args[1] != "off" {
+				fmt.Fprintf(out, "error: timing must be on or off, not %q\n", args[1])
+				return false
+			}
+			s.options.Timing = args[1] == "on"
+		}
+		fmt.Fprintf(out, "timing %s\n", map[bool]string{true: "on", false: "off"}[s.options.Timing])
+	case "watch":
+		s.watch(ctx, out, args[1:])
+	default:
+		s.Call(ctx, out, out, args)
+	}
+
+	return false
+}
+
+// Call runs a command which calls the server, such as add 1.5 2, and prints its result to out. In the text format
+// errors are printed to errOut, and in the JSON format the errors returned by the server are printed to out, like
+// results. It returns the error, which is a gRPC status if it came from the server, and a plain error if the
+// command or its arguments are wrong.
+func (s *Shell) Call(ctx context.Context, out, errOut io.Writer, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no command")
+	}
+	command, ok := findCommand(args[0])
+	if !ok {
+		err := fmt.Errorf("unknown command %q, type help for the list of commands", args[0])
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return err
+	}
+
+	if s.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	result, err := command.run(ctx, s.client, args[1:])
+	elapsed := time.Since(start).Round(time.Microsecond)
+
+	// Errors which aren't gRPC statuses come from the arguments, before any call is made.
+	if _, isStatus := status.FromError(err); err != nil && !isStatus {
+		err = fmt.Errorf("%w, usage: %s", err, command.Usage)
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return err
+	}
+
+	s.Print(out, errOut, command.Name, result, err, elapsed)
+	return err
+}
+
+// Print prints the result of a command, or the error the server returned instead, in the shell's output format,
+// followed by how long the command took if timing is on. In the text format errors are printed to errOut, and in
+// the JSON format they are printed to out.
+func (s *Shell) Print(out, errOut io.Writer, name string, result any, err error, elapsed time.Duration) {
+	if s.options.Format == "json" {
+		s.printJSON(out, name, result, err, elapsed)
+	} else {
+		s.printText(out, errOut, result, err, elapsed)
+	}
+}
+
+// This function prints the result of a command, or the error the server returned, for people to read.
+func (s *Shell) printText(out, errOut io.Writer, result any, err error, elapsed time.Duration) {
+	if err != nil {
+		grpcStatus := status.Convert(err)
+		fmt.Fprintf(errOut, "error: %v: %s\n", grpcStatus.Code(), grpcStatus.Message())
+		for _, detail := range grpcStatus.Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, violation := range badRequest.FieldViolations {
+					fmt.Fprintf(errOut, "  %s %s\n", violation.Field, violation.Description)
+				}
+			}
+		}
+	} else {
+		fmt.Fprintln(out, textOf(result))
+	}
+
+	if s.options.Timing {
+		fmt.Fprintf(out, "(%v)\n", elapsed)
+	}
+}
+
+// This function writes a result as text, with just the number for the results which are a single number.
+func textOf(result any) string {
+	switch result := result.(type) {
+	case *pb.DoubleResult:
+		return strconv.FormatFloat(result.Result, 'g', -1, 64)
+	case *pb.IntResult:
+		return strconv.FormatInt(result.Result, 10)
+	case *pb.ArithmeticResult:
+		if ints, ok := result.Result.(*pb.ArithmeticResult_IntResult); ok {
+			return strconv.FormatInt(ints.IntResult, 10)
+		}
+		return strconv.FormatFloat(result.GetDoubleResult(), 'g', -1, 64)
+	case Counts:
+		lines := make([]string, len(result))
+		for i, count := range result {
+			lines[i] = fmt.Sprintf("%-13s %d", count.Name+":", count.Count)
+		}
+		return strings.Join(lines, "\n")
+	case proto.Message:
+		return strings.Join(messageText(result.ProtoReflect(), ""), "\n")
+	default:
+		return fmt.Sprint(result)
+	}
+}
+
+// This function writes each field which is set in a message as a "name: value" line, in the order of the fields.
+// Nested messages are indented under their field, and lists have a line for each element. Unlike prototext, which
+// varies its spacing on purpose, the text is the same every time, so scripts can rely on it.
+func messageText(message protoreflect.Message, indent string) []string {
+	var lines []string
+	fields := message.Descriptor().Fields()
+	for i := range fields.Len() {
+		field := fields.Get(i)
+		if !message.Has(field) {
+			continue
+		}
+		if field.IsList() {
+			list := message.Get(field).List()
+			for j := range list.Len() {
+				lines = append(lines, fieldText(field, list.Get(j), indent)...)
+			}
+		} else {
+			lines = append(lines, fieldText(field, message.Get(field), indent)...)
+		}
+	}
+
+	return lines
+}
+
+// This function writes a single value of a field, as the lines of messageText.
+func fieldText(field protoreflect.FieldDescriptor, value protoreflect.Value, indent string) []string {
+	name := indent + string(field.Name()) + ":"
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if timestamp, ok := value.Message().Interface().(*timestamppb.Timestamp); ok {
+			return []string{name + " " + timestamp.AsTime().Format(time.RFC3339)}
+		}
+		return append([]string{name}, messageText(value.Message(), indent+"  ")...)
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return []string{name + " " + string(enumValue.Name())}
+		}
+		return []string{name + " " + strconv.Itoa(int(value.Enum()))}
+	case protoreflect.DoubleKind, protoreflect.FloatKind:
+		return []string{name + " " + strconv.FormatFloat(value.Float(), 'g', -1, 64)}
+	default:
+		return []string{name + " " + fmt.Sprint(value.Interface())}
+	}
+}
+
+// jsonOutput is what the JSON format prints for each command.
+type jsonOutput struct {
+	Command string          `json:"command"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonError      `json:"error,omitempty"`
+	// Elapsed is how long the command took, such as "1.5ms", if timing is on.
+	Elapsed string `json:"elapsed,omitempty"`
+}
+
+// jsonError is the error the server returned, in the JSON format.
+type jsonError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// This function prints the result of a command, or the error the server returned, as a single line of JSON.
+func (s *Shell) printJSON(out io.Writer, name string, result any, err error, elapsed time.Duration) {
+	output := jsonOutput{Command: name}
+	var marshalErr error
+	if err != nil {
+		grpcStatus := status.Convert(err)
+		output.Error = &jsonError{grpcStatus.Code().String(), grpcStatus.Message()}
+	} else if message, ok := result.(proto.Message); ok {
+		output.Result, marshalErr = protojson.Marshal(message)
+	} else {
+		output.Result, marshalErr = json.Marshal(result)
+	}
+	if marshalErr != nil {
+		fmt.Fprintf(out, "error: the result can't be written as JSON: %v\n", marshalErr)
+		return
+	}
+	if s.options.Timing {
+		output.Elapsed = elapsed.String()
+	}
+
+	line, _ := json.Marshal(output)
+	fmt.Fprintf(out, "%s\n", line)
+}
+
+// This function runs the watch command, which repeats a command which calls the server, until it has run the
+// given number of times, a key is pressed, or the context is done.
+func (s *Shell) watch(ctx context.Context, out io.Writer, args []string) {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.SetOutput(out)
+	interval := flags.Duration("i", time.Second, "How long to wait between runs of the command.")
+	times := flags.Int("n", 0, "How many times to run the command. Zero runs it until a key is pressed.")
+	flags.Usage = func() {
+		fmt.Fprintln(out, "usage: watch [-i interval] [-n times] <command> [arguments ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(out, "error: watch needs a command, such as watch counts")
+		return
+	}
+	if _, ok := findCommand(flags.Arg(0)); !ok {
+		fmt.Fprintf(out, "error: watch can't run %q, only the commands which call the server\n", flags.Arg(0))
+		return
+	}
+
+	if *times == 0 && s.keys != nil {
+		fmt.Fprintln(out, "Press any key to stop.")
+	}
+	for i := 0; *times == 0 || i < *times; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(*interval):
+			case <-s.keys:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+		if s.options.Format != "json" {
+			fmt.Fprintf(out, "%s\n", time.Now().Format(time.TimeOnly))
+		}
+		s.Call(ctx, out, out, flags.Args())
+	}
+}
+
+// This function prints the list of commands, or the help of a single command.
+func (s *Shell) help(out io.Writer, args []string) {
+	if len(args) > 0 {
+		command, ok := findCommand(args[0])
+		if !ok {
+			fmt.Fprintf(out, "error: %q has no help, type help for the list of commands\n", args[0])
+			return
+		}
+		fmt.Fprintf(out, "usage: %s\n%s\n", command.Usage, command.Help)
+		if command.Method != "" {
+			fmt.Fprintf(out, "It calls %s, which can also be typed instead of %s.\n", command.Method, command.Name)
+		}
+		return
+	}
+
+	fmt.Fprintln(out, "Commands which call the server:")
+	for _, command := range commands {
+		fmt.Fprintf(out, "  %-36s %s\n", command.Usage, command.Help)
+	}
+	fmt.Fprintln(out, "Commands of the shell:")
+	for _, line := range [][2]string{
+		{"help [command]", "Shows the list of commands, or how to use a command."},
+		{"watch [-i interval] [-n times] <command>", "Repeats a command, by default every second until a key is pressed."},
+		{"history", "Shows the commands typed so far."},
+		{"format [" + strings.Join(Formats, "|") + "]", "Shows or changes the output format."},
+		{"timing [on|off]", "Shows or changes whether to print how long each command took."},
+		{"exit", "Leaves the shell, as does Ctrl-D."},
+	} {
+		fmt.Fprintf(out, "  %-36s %s\n", line[0], line[1])
+	}
+}
+
+// This function splits a line into words at spaces. Words may be quoted with double or single quotes to include
+// spaces, as in eval "(a + 1) * 2".
+func splitLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, char := range line {
+		switch {
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(char)
+		case char == '"' || char == '\'':
+			quote, inWord = char, true
+		case char == ' ' || char == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(char)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("missing closing quote %c", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// This function completes the word before the cursor, for the tab key. It returns the new line and cursor position,
+// and the words which the word could be completed to. When there are several, the word is only completed as far as
+// they all agree.
+func complete(line string, pos int) (string, int, []string) {
+	before := line[:pos]
+	start := strings.LastIndexAny(before, " \t") + 1
+	partial := before[start:]
+
+	var choices []string
+	if words := strings.Fields(before[:start]); len(words) == 0 {
+		choices = slices.Concat(commandNames(), shellCommands)
+	} else {
+		switch words[0] {
+		case "help", "watch":
+			choices = commandNames()
+		case "format":
+			choices = Formats
+		case "timing":
+			choices = []string{"on", "off"}
+		}
+	}
+
+	var candidates []string
+	for _, choice := range choices {
+		if strings.HasPrefix(choice, partial) {
+			candidates = append(candidates, choice)
+		}
+	}
+	if len(candidates) == 0 {
+		return line, pos, nil
+	}
+
+	completion := candidates[0]
+	if len(candidates) == 1 {
+		if !strings.HasPrefix(line[pos:], " ") {
+			completion += " "
+		}
+	} else {
+		for _, candidate := range candidates[1:] {
+			for !strings.HasPrefix(candidate, completion) {
+				completion = completion[:len(completion)-1]
+			}
+		}
+	}
+
+	return before[:start] + completion + line[pos:], start + len(completion), candidates
+}
+
+// This function returns the names of the commands which call the server, followed by the names of their methods.
+func commandNames() []string {
+	names := make([]string, 0, 2*len(commands))
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+	for _, command := range commands {
+		if command.Method != "" {
+			names = append(names, command.Method)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,256 @@
+package shell
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// This function runs the lines of a script in a shell connected to a test server, and returns what it printed.
+func runScript(t *testing.T, options Options, lines ...string) string {
+	t.Helper()
+
+	shell := New(servertest.Start(t).Client, options)
+	var out strings.Builder
+	if err := shell.Run(context.Background(), strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	return out.String()
+}
+
+func TestRun(t *testing.T) {
+	got := runScript(t, Options{},
+		"add 1.5 2",
+		"min 3 -1 7",
+		"",
+		"div 7 0",
+		"add 1",
+		"square 4",
+		"exit",
+		"add 2 2",
+	)
+
+	want := strings.Join([]string{
+		"3.5",
+		"-1",
+		"error: InvalidArgument: divide(7, 0): division by zero",
+		"error: expected 2 numbers, not 1, usage: add <a> <b>",
+		`error: unknown command "square", type help for the list of commands`,
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("Run() printed:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunPrintsFieldViolations(t *testing.T) {
+	got := runScript(t, Options{}, "stats NaN 1")
+
+	if !strings.HasPrefix(got, "error: InvalidArgument: ") || !strings.Contains(got, "\n  values[0] must be a finite number, not NaN\n") {
+		t.Errorf("Run() printed:\n%s\nwant the violation of values[0] on its own line", got)
+	}
+}
+
+func TestRunTiming(t *testing.T) {
+	got := runScript(t, Options{Timing: true}, "add 1 2", "timing off", "add 1 2", "timing")
+
+	pattern := regexp.MustCompile(`^3\n\([0-9.]+[µm]?s\)\ntiming off\n3\ntiming off\n$`)
+	if !pattern.MatchString(got) {
+		t.Errorf("Run() printed:\n%s\nwant the time of the first call only", got)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	got := runScript(t, Options{Format: "json"}, "add 1.5 2", "div 7 0", "counts", "format text", "add 1 1", "format xml")
+
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Run() printed %d lines; want 6:\n%s", len(lines), got)
+	}
+
+	var add struct {
+		Command string
+		Result  struct{ Result float64 }
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &add); err != nil || add.Command != "add" || add.Result.Result != 3.5 {
+		t.Errorf("add printed %s, %v; want command add and result 3.5", lines[0], err)
+	}
+
+	var div struct {
+		Error struct{ Code, Message string }
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &div); err != nil || div.Error.Code != "InvalidArgument" {
+		t.Errorf("div printed %s, %v; want an InvalidArgument error", lines[1], err)
+	}
+
+	var counts struct{ Result map[string]int64 }
+	if err := json.Unmarshal([]byte(lines[2]), &counts); err != nil || counts.Result["add"] != 1 || counts.Result["div"] != 1 {
+		t.Errorf("counts printed %s, %v; want add 1 and div 1", lines[2], err)
+	}
+
+	want := []string{"format text", "2", `error: unknown format "xml", the formats are text, json`}
+	if !slices.Equal(lines[3:], want) {
+		t.Errorf("Run() printed %q after the JSON; want %q", lines[3:], want)
+	}
+}
+
+func TestRunWatch(t *testing.T) {
+	got := runScript(t, Options{}, "watch -i 1ms -n 3 add 1 2", "watch -n 2 help", "watch", "counts")
+
+	if n := strings.Count(got, "\n3\n"); n != 3 {
+		t.Errorf("watch -n 3 add 1 2 printed 3 %d times; want 3:\n%s", n, got)
+	}
+	if !strings.Contains(got, `error: watch can't run "help"`) || !strings.Contains(got, "error: watch needs a command") {
+		t.Errorf("Run() printed:\n%s\nwant errors for watching help and for watching nothing", got)
+	}
+	if !strings.Contains(got, "add:          3\n") {
+		t.Errorf("counts printed:\n%s\nwant add: 3", got)
+	}
+}
+
+func TestRunHistoryAndHelp(t *testing.T) {
+	got := runScript(t, Options{}, "add 1 2", "help max", "history")
+
+	want := strings.Join([]string{
+		"3",
+		"usage: max <a> <b> <c>",
+		"Finds the largest of three integers.",
+		"It calls MagicFindMax, which can also be typed instead of max.",
+		"    1  add 1 2",
+		"    2  help max",
+		"    3  history",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("Run() printed:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCall(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		args       []string
+		format     string
+		wantOut    string
+		wantErrOut string
+		wantCode   codes.Code
+		wantStatus bool
+	}{
+		{[]string{"add", "1.5", "2"}, "text", "3.5\n", "", codes.OK, true},
+		{[]string{"div", "7", "0"}, "text", "", "error: InvalidArgument: divide(7, 0): division by zero\n", codes.InvalidArgument, true},
+		{[]string{"div", "7", "0"}, "json", `{"command":"div","error":{"code":"InvalidArgument","message":"divide(7, 0): division by zero"}}` + "\n",
+			"", codes.InvalidArgument, true},
+		{[]string{"add", "1"}, "json", "", "error: expected 2 numbers, not 1, usage: add <a> <b>\n", codes.Unknown, false},
+		{[]string{"square", "4"}, "text", "", "error: unknown command \"square\", type help for the list of commands\n", codes.Unknown, false},
+	}
+
+	for _, tt := range tests {
+		var out, errOut strings.Builder
+		err := New(client, Options{Format: tt.format}).Call(context.Background(), &out, &errOut, tt.args)
+
+		if out.String() != tt.wantOut || errOut.String() != tt.wantErrOut {
+			t.Errorf("Call(%q) printed %q and %q; want %q and %q", tt.args, out.String(), errOut.String(), tt.wantOut, tt.wantErrOut)
+		}
+		if _, isStatus := status.FromError(err); isStatus != tt.wantStatus || status.Code(err) != tt.wantCode {
+			t.Errorf("Call(%q) returned error %v; want code %v, a gRPC status: %v", tt.args, err, tt.wantCode, tt.wantStatus)
+		}
+	}
+}
+
+func TestTextOf(t *testing.T) {
+	reset := time.Date(2026, 10, 19, 16, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		result any
+		want   string
+	}{
+		{&pb.DoubleResult{Result: 0.30000000000000004}, "0.30000000000000004"},
+		{&pb.IntResult{Result: -7}, "-7"},
+		{&pb.ArithmeticResult{Result: &pb.ArithmeticResult_DoubleResult{DoubleResult: 3.5}}, "3.5"},
+		{&pb.ArithmeticResult{Result: &pb.ArithmeticResult_IntResult{IntResult: 0}}, "0"},
+		{Counts{{"add", 2}, {"cacheHits", 10}}, "add:          2\ncacheHits:    10"},
+		{&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, "status: SERVING"},
+		{
+			&pb.Stats{Count: 2, Mean: 1.5, Quantiles: []*pb.QuantileValue{{Quantile: 0.5, Value: 1.5}, {Quantile: 0.9, Value: 1.9}}},
+			"count: 2\nmean: 1.5\nquantiles:\n  quantile: 0.5\n  value: 1.5\nquantiles:\n  quantile: 0.9\n  value: 1.9",
+		},
+		{&pb.Quota{Caller: "alice", HourlyLimit: 10, HourlyReset: timestamppb.New(reset)},
+			"caller: alice\nhourlyLimit: 10\nhourlyReset: 2026-10-19T16:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		if got := textOf(tt.result); got != tt.want {
+			t.Errorf("textOf(%v) = %q; want %q", tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestSplitLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{"add 1.5 2", []string{"add", "1.5", "2"}, false},
+		{"  min\t3  -1 7 ", []string{"min", "3", "-1", "7"}, false},
+		{`eval "(a + 1) * 2" a=3`, []string{"eval", "(a + 1) * 2", "a=3"}, false},
+		{`eval 'say "hi"' ""`, []string{"eval", `say "hi"`, ""}, false},
+		{"", nil, false},
+		{`eval "1 + 2`, nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitLine(tt.line)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Errorf("splitLine(%q) = %q, %v; want %q, error %v", tt.line, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	methods := []string{"MagicAdd", "MagicSubtract", "MagicFindMin", "MagicFindMax", "MagicMultiply", "MagicDivide",
+		"MagicModulo", "MagicPower", "MagicEvaluate", "MagicStats"}
+
+	tests := []struct {
+		line           string
+		pos            int
+		wantLine       string
+		wantPos        int
+		wantCandidates []string
+	}{
+		{"ad", 2, "add ", 4, []string{"add"}},
+		{"Magic", 5, "Magic", 5, methods},
+		{"MagicF", 6, "MagicFindM", 10, []string{"MagicFindMin", "MagicFindMax"}},
+		{"MagicFindMa", 11, "MagicFindMax ", 13, []string{"MagicFindMax"}},
+		{"m", 1, "m", 1, []string{"min", "max", "mul", "mod"}},
+		{"h", 1, "h", 1, []string{"help", "history"}},
+		{"format j", 8, "format json ", 12, []string{"json"}},
+		{"watch -n 3 cou", 14, "watch -n 3 counts ", 18, []string{"counts"}},
+		{"help GetQ", 9, "help GetQuota ", 14, []string{"GetQuota"}},
+		{"ad 1 2", 2, "add 1 2", 3, []string{"add"}},
+		{"add 1 2", 7, "add 1 2", 7, nil},
+		{"xyz", 3, "xyz", 3, nil},
+	}
+
+	for _, tt := range tests {
+		gotLine, gotPos, gotCandidates := complete(tt.line, tt.pos)
+		if gotLine != tt.wantLine || gotPos != tt.wantPos || !slices.Equal(gotCandidates, tt.wantCandidates) {
+			t.Errorf("complete(%q, %d) = %q, %d, %q; want %q, %d, %q",
+				tt.line, tt.pos, gotLine, gotPos, gotCandidates, tt.wantLine, tt.wantPos, tt.wantCandidates)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: magicMath/cluster.proto
+
+package magicMath
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The counters known to one server, for every replica in the cluster.
+type CounterState struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ReplicaId string                 `protobuf:"bytes,1,opt,name=replicaId,proto3" json:"replicaId,omitempty"`
+	// Maps a counter name (such as "add") to how many times each replica has incremented it.
+	Counters      map[string]*ReplicaCounts `protobuf:"bytes,2,rep,name=counters,proto3" json:"counters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CounterState) Reset() {
+	*x = CounterState{}
+	mi := &file_magicMath_cluster_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CounterState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CounterState) ProtoMessage() {}
+
+func (x *CounterState) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_cluster_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CounterState.ProtoReflect.Descriptor instead.
+func (*CounterState) Descriptor() ([]byte, []int) {
+	return file_magicMath_cluster_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CounterState) GetReplicaId() string {
+	if x != nil {
+		return x.ReplicaId
+	}
+	return ""
+}
+
+func (x *CounterState) GetCounters() map[string]*ReplicaCounts {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
+type ReplicaCounts struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maps a replica ID to that replica's share of the counter.
+	Shares        map[string]*ReplicaShare `protobuf:"bytes,2,rep,name=shares,proto3" json:"shares,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicaCounts) Reset() {
+	*x = ReplicaCounts{}
+	mi := &file_magicMath_cluster_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicaCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaCounts) ProtoMessage() {}
+
+func (x *ReplicaCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_cluster_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaCounts.ProtoReflect.Descriptor instead.
+func (*ReplicaCounts) Descriptor() ([]byte, []int) {
+	return file_magicMath_cluster_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReplicaCounts) GetShares() map[string]*ReplicaShare {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+// One replica's share of a counter. Each time a replica starts it counts under a higher incarnation, and its share from
+// its earlier incarnations becomes the base, so that a restart neither loses counts nor adds an entry.
+type ReplicaShare struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Incarnation int64                  `protobuf:"zigzag64,1,opt,name=incarnation,proto3" json:"incarnation,omitempty"`
+	// Base is the replica's share from its earlier incarnations, as far as this server knows it.
+	Base int64 `protobuf:"zigzag64,2,opt,name=base,proto3" json:"base,omitempty"`
+	// Count is the replica's share from its current incarnation.
+	Count         int64 `protobuf:"zigzag64,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicaShare) Reset() {
+	*x = ReplicaShare{}
+	mi := &file_magicMath_cluster_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicaShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaShare) ProtoMessage() {}
+
+func (x *ReplicaShare) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_cluster_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaShare.ProtoReflect.Descriptor instead.
+func (*ReplicaShare) Descriptor() ([]byte, []int) {
+	return file_magicMath_cluster_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReplicaShare) GetIncarnation() int64 {
+	if x != nil {
+		return x.Incarnation
+	}
+	return 0
+}
+
+func (x *ReplicaShare) GetBase() int64 {
+	if x != nil {
+		return x.Base
+	}
+	return 0
+}
+
+func (x *ReplicaShare) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+var File_magicMath_cluster_proto protoreflect.FileDescriptor
+
+const file_magicMath_cluster_proto_rawDesc = "" +
+	"\n" +
+	"\x17magicMath/cluster.proto\x12\x06shared\"\xc0\x01\n" +
+	"\fCounterState\x12\x1c\n" +
+	"\treplicaId\x18\x01 \x01(\tR\treplicaId\x12>\n" +
+	"\bcounters\x18\x02 \x03(\v2\".shared.CounterState.CountersEntryR\bcounters\x1aR\n" +
+	"\rCountersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.shared.ReplicaCountsR\x05value:\x028\x01\"\xa9\x01\n" +
+	"\rReplicaCounts\x129\n" +
+	"\x06shares\x18\x02 \x03(\v2!.shared.ReplicaCounts.SharesEntryR\x06shares\x1aO\n" +
+	"\vSharesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.shared.ReplicaShareR\x05value:\x028\x01J\x04\b\x01\x10\x02R\x06counts\"Z\n" +
+	"\fReplicaShare\x12 \n" +
+	"\vincarnation\x18\x01 \x01(\x12R\vincarnation\x12\x12\n" +
+	"\x04base\x18\x02 \x01(\x12R\x04base\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x12R\x05count2C\n" +
+	"\vClusterPeer\x124\n" +
+	"\x06Gossip\x12\x14.shared.CounterState\x1a\x14.shared.CounterStateBBZ@github.com/karldmenzel/go-grpc-client-server/magicMath;magicMathb\x06proto3"
+
+var (
+	file_magicMath_cluster_proto_rawDescOnce sync.Once
+	file_magicMath_cluster_proto_rawDescData []byte
+)
+
+func file_magicMath_cluster_proto_rawDescGZIP() []byte {
+	file_magicMath_cluster_proto_rawDescOnce.Do(func() {
+		file_magicMath_cluster_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_magicMath_cluster_proto_rawDesc), len(file_magicMath_cluster_proto_rawDesc)))
+	})
+	return file_magicMath_cluster_proto_rawDescData
+}
+
+var file_magicMath_cluster_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_magicMath_cluster_proto_goTypes = []any{
+	(*CounterState)(nil),  // 0: shared.CounterState
+	(*ReplicaCounts)(nil), // 1: shared.ReplicaCounts
+	(*ReplicaShare)(nil),  // 2: shared.ReplicaShare
+	nil,                   // 3: shared.CounterState.CountersEntry
+	nil,                   // 4: shared.ReplicaCounts.SharesEntry
+}
+var file_magicMath_cluster_proto_depIdxs = []int32{
+	3, // 0: shared.CounterState.counters:type_name -> shared.CounterState.CountersEntry
+	4, // 1: shared.ReplicaCounts.shares:type_name -> shared.ReplicaCounts.SharesEntry
+	1, // 2: shared.CounterState.CountersEntry.value:type_name -> shared.ReplicaCounts
+	2, // 3: shared.ReplicaCounts.SharesEntry.value:type_name -> shared.ReplicaShare
+	0, // 4: shared.ClusterPeer.Gossip:input_type -> shared.CounterState
+	0, // 5: shared.ClusterPeer.Gossip:output_type -> shared.CounterState
+	5, // [5:6] is the sub-list for method output_type
+	4, // [4:5] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_magicMath_cluster_proto_init() }
+func file_magicMath_cluster_proto_init() {
+	if File_magicMath_cluster_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_magicMath_cluster_proto_rawDesc), len(file_magicMath_cluster_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_magicMath_cluster_proto_goTypes,
+		DependencyIndexes: file_magicMath_cluster_proto_depIdxs,
+		MessageInfos:      file_magicMath_cluster_proto_msgTypes,
+	}.Build()
+	File_magicMath_cluster_proto = out.File
+	file_magicMath_cluster_proto_goTypes = nil
+	file_magicMath_cluster_proto_depIdxs = nil
+}
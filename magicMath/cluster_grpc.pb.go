@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.1
+// source: magicMath/cluster.proto
+
+package magicMath
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ClusterPeer_Gossip_FullMethodName = "/shared.ClusterPeer/Gossip"
+)
+
+// ClusterPeerClient is the client API for ClusterPeer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The servers in a cluster use this service to share their function counters with each other.
+type ClusterPeerClient interface {
+	// Gossip sends the caller's counters to a peer, and the peer replies with its own counters.
+	// Both sides merge what they received, so a single call brings the two servers in sync.
+	Gossip(ctx context.Context, in *CounterState, opts ...grpc.CallOption) (*CounterState, error)
+}
+
+type clusterPeerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClusterPeerClient(cc grpc.ClientConnInterface) ClusterPeerClient {
+	return &clusterPeerClient{cc}
+}
+
+func (c *clusterPeerClient) Gossip(ctx context.Context, in *CounterState, opts ...grpc.CallOption) (*CounterState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CounterState)
+	err := c.cc.Invoke(ctx, ClusterPeer_Gossip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClusterPeerServer is the server API for ClusterPeer service.
+// All implementations must embed UnimplementedClusterPeerServer
+// for forward compatibility.
+//
+// The servers in a cluster use this service to share their function counters with each other.
+type ClusterPeerServer interface {
+	// Gossip sends the caller's counters to a peer, and the peer replies with its own counters.
+	// Both sides merge what they received, so a single call brings the two servers in sync.
+	Gossip(context.Context, *CounterState) (*CounterState, error)
+	mustEmbedUnimplementedClusterPeerServer()
+}
+
+// UnimplementedClusterPeerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedClusterPeerServer struct{}
+
+func (UnimplementedClusterPeerServer) Gossip(context.Context, *CounterState) (*CounterState, error) {
+	return nil, status.Error(codes.Unimplemented, "method Gossip not implemented")
+}
+func (UnimplementedClusterPeerServer) mustEmbedUnimplementedClusterPeerServer() {}
+func (UnimplementedClusterPeerServer) testEmbeddedByValue()                     {}
+
+// UnsafeClusterPeerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClusterPeerServer will
+// result in compilation errors.
+type UnsafeClusterPeerServer interface {
+	mustEmbedUnimplementedClusterPeerServer()
+}
+
+func RegisterClusterPeerServer(s grpc.ServiceRegistrar, srv ClusterPeerServer) {
+	// If the following call panics, it indicates UnimplementedClusterPeerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ClusterPeer_ServiceDesc, srv)
+}
+
+func _ClusterPeer_Gossip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CounterState)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterPeerServer).Gossip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterPeer_Gossip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterPeerServer).Gossip(ctx, req.(*CounterState))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ClusterPeer_ServiceDesc is the grpc.ServiceDesc for ClusterPeer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ClusterPeer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shared.ClusterPeer",
+	HandlerType: (*ClusterPeerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Gossip",
+			Handler:    _ClusterPeer_Gossip_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "magicMath/cluster.proto",
+}
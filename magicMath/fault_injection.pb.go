@@ -0,0 +1,564 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: magicMath/fault_injection.proto
+
+package magicMath
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FaultRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FaultRulesRequest) Reset() {
+	*x = FaultRulesRequest{}
+	mi := &file_magicMath_fault_injection_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaultRulesRequest) ProtoMessage() {}
+
+func (x *FaultRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_fault_injection_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaultRulesRequest.ProtoReflect.Descriptor instead.
+func (*FaultRulesRequest) Descriptor() ([]byte, []int) {
+	return file_magicMath_fault_injection_proto_rawDescGZIP(), []int{0}
+}
+
+type FaultRules struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Every rule which matches a call is applied to it in order, until one of them ends the call.
+	Rules         []*FaultRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FaultRules) Reset() {
+	*x = FaultRules{}
+	mi := &file_magicMath_fault_injection_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaultRules) ProtoMessage() {}
+
+func (x *FaultRules) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_fault_injection_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaultRules.ProtoReflect.Descriptor instead.
+func (*FaultRules) Descriptor() ([]byte, []int) {
+	return file_magicMath_fault_injection_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FaultRules) GetRules() []*FaultRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type FaultRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The method name, for example "MagicAdd", and the caller's identity the rule applies to. Empty or "*" matches any.
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Caller string `protobuf:"bytes,2,opt,name=caller,proto3" json:"caller,omitempty"`
+	// The percentage of matching calls the fault is injected into, from 0 to 100.
+	Percentage float64 `protobuf:"fixed64,3,opt,name=percentage,proto3" json:"percentage,omitempty"`
+	// Types that are valid to be assigned to Fault:
+	//
+	//	*FaultRule_Latency
+	//	*FaultRule_Error
+	//	*FaultRule_Drop
+	//	*FaultRule_Corrupt
+	Fault isFaultRule_Fault `protobuf_oneof:"fault"`
+	// How many faults this rule has injected. It is ignored by SetFaults.
+	Injected      int64 `protobuf:"zigzag64,8,opt,name=injected,proto3" json:"injected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FaultRule) Reset() {
+	*x = FaultRule{}
+	mi := &file_magicMath_fault_injection_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FaultRule) ProtoMessage() {}
+
+func (x *FaultRule) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_fault_injection_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FaultRule.ProtoReflect.Descriptor instead.
+func (*FaultRule) Descriptor() ([]byte, []int) {
+	return file_magicMath_fault_injection_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FaultRule) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *FaultRule) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *FaultRule) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+func (x *FaultRule) GetFault() isFaultRule_Fault {
+	if x != nil {
+		return x.Fault
+	}
+	return nil
+}
+
+func (x *FaultRule) GetLatency() *Latency {
+	if x != nil {
+		if x, ok := x.Fault.(*FaultRule_Latency); ok {
+			return x.Latency
+		}
+	}
+	return nil
+}
+
+func (x *FaultRule) GetError() *ErrorFault {
+	if x != nil {
+		if x, ok := x.Fault.(*FaultRule_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *FaultRule) GetDrop() *DropFault {
+	if x != nil {
+		if x, ok := x.Fault.(*FaultRule_Drop); ok {
+			return x.Drop
+		}
+	}
+	return nil
+}
+
+func (x *FaultRule) GetCorrupt() *CorruptFault {
+	if x != nil {
+		if x, ok := x.Fault.(*FaultRule_Corrupt); ok {
+			return x.Corrupt
+		}
+	}
+	return nil
+}
+
+func (x *FaultRule) GetInjected() int64 {
+	if x != nil {
+		return x.Injected
+	}
+	return 0
+}
+
+type isFaultRule_Fault interface {
+	isFaultRule_Fault()
+}
+
+type FaultRule_Latency struct {
+	// Delays the call before it is handled.
+	Latency *Latency `protobuf:"bytes,4,opt,name=latency,proto3,oneof"`
+}
+
+type FaultRule_Error struct {
+	// Fails the call with a status code, without handling it.
+	Error *ErrorFault `protobuf:"bytes,5,opt,name=error,proto3,oneof"`
+}
+
+type FaultRule_Drop struct {
+	// Handles the call, but never sends the response, so the client waits until its deadline, or until the drop's
+	// timeout when that comes first.
+	Drop *DropFault `protobuf:"bytes,6,opt,name=drop,proto3,oneof"`
+}
+
+type FaultRule_Corrupt struct {
+	// Handles the call, and flips a random bit in each number in the response.
+	Corrupt *CorruptFault `protobuf:"bytes,7,opt,name=corrupt,proto3,oneof"`
+}
+
+func (*FaultRule_Latency) isFaultRule_Fault() {}
+
+func (*FaultRule_Error) isFaultRule_Fault() {}
+
+func (*FaultRule_Drop) isFaultRule_Fault() {}
+
+func (*FaultRule_Corrupt) isFaultRule_Fault() {}
+
+type Latency struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The distribution the delay is drawn from: "fixed" waits for base, "uniform" waits between base and base + spread,
+	// and "exponential" waits for base plus an exponentially distributed time with a mean of spread.
+	Distribution  string               `protobuf:"bytes,1,opt,name=distribution,proto3" json:"distribution,omitempty"`
+	Base          *durationpb.Duration `protobuf:"bytes,2,opt,name=base,proto3" json:"base,omitempty"`
+	Spread        *durationpb.Duration `protobuf:"bytes,3,opt,name=spread,proto3" json:"spread,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Latency) Reset() {
+	*x = Latency{}
+	mi := &file_magicMath_fault_injection_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Latency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Latency) ProtoMessage() {}
+
+func (x *Latency) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_fault_injection_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Latency.ProtoReflect.Descriptor instead.
+func (*Latency) Descriptor() ([]byte, []int) {
+	return file_magicMath_fault_injection_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Latency) GetDistribution() string {
+	if x != nil {
+		return x.Distribution
+	}
+	return ""
+}
+
+func (x *Latency) GetBase() *durationpb.Duration {
+	if x != nil {
+		return x.Base
+	}
+	return nil
+}
+
+func (x *Latency) GetSpread() *durationpb.Duration {
+	if x != nil {
+		return x.Spread
+	}
+	return nil
+}
+
+type ErrorFault struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The gRPC status code number, for example 14 for UNAVAILABLE, and the error message.
+	Code          int32  `protobuf:"zigzag32,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorFault) Reset() {
+	*x = ErrorFault{}
+	mi := &file_magicMath_fault_injection_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorFault) ProtoMessage() {}
+
+func (x *ErrorFault) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_fault_injection_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorFault.ProtoReflect.Descriptor instead.
+func (*ErrorFault) Descriptor() ([]byte, []int) {
+	return file_magicMath_fault_injection_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ErrorFault) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ErrorFault) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DropFault struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// How long the response is held back before the call fails with DEADLINE_EXCEEDED, so that a call without a deadline
+	// doesn't hold on to the server forever. It defaults to 30 seconds.
+	Timeout       *durationpb.Duration `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropFault) Reset() {
+	*x = DropFault{}
+	mi := &file_magicMath_fault_injection_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropFault) ProtoMessage() {}
+
+func (x *DropFault) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_fault_injection_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropFault.ProtoReflect.Descriptor instead.
+func (*DropFault) Descriptor() ([]byte, []int) {
+	return file_magicMath_fault_injection_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DropFault) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type CorruptFault struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CorruptFault) Reset() {
+	*x = CorruptFault{}
+	mi := &file_magicMath_fault_injection_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CorruptFault) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CorruptFault) ProtoMessage() {}
+
+func (x *CorruptFault) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_fault_injection_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CorruptFault.ProtoReflect.Descriptor instead.
+func (*CorruptFault) Descriptor() ([]byte, []int) {
+	return file_magicMath_fault_injection_proto_rawDescGZIP(), []int{6}
+}
+
+var File_magicMath_fault_injection_proto protoreflect.FileDescriptor
+
+const file_magicMath_fault_injection_proto_rawDesc = "" +
+	"\n" +
+	"\x1fmagicMath/fault_injection.proto\x12\x06shared\x1a\x1egoogle/protobuf/duration.proto\"\x13\n" +
+	"\x11FaultRulesRequest\"5\n" +
+	"\n" +
+	"FaultRules\x12'\n" +
+	"\x05rules\x18\x01 \x03(\v2\x11.shared.FaultRuleR\x05rules\"\xb4\x02\n" +
+	"\tFaultRule\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x16\n" +
+	"\x06caller\x18\x02 \x01(\tR\x06caller\x12\x1e\n" +
+	"\n" +
+	"percentage\x18\x03 \x01(\x01R\n" +
+	"percentage\x12+\n" +
+	"\alatency\x18\x04 \x01(\v2\x0f.shared.LatencyH\x00R\alatency\x12*\n" +
+	"\x05error\x18\x05 \x01(\v2\x12.shared.ErrorFaultH\x00R\x05error\x12'\n" +
+	"\x04drop\x18\x06 \x01(\v2\x11.shared.DropFaultH\x00R\x04drop\x120\n" +
+	"\acorrupt\x18\a \x01(\v2\x14.shared.CorruptFaultH\x00R\acorrupt\x12\x1a\n" +
+	"\binjected\x18\b \x01(\x12R\binjectedB\a\n" +
+	"\x05fault\"\x8f\x01\n" +
+	"\aLatency\x12\"\n" +
+	"\fdistribution\x18\x01 \x01(\tR\fdistribution\x12-\n" +
+	"\x04base\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x04base\x121\n" +
+	"\x06spread\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06spread\":\n" +
+	"\n" +
+	"ErrorFault\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x11R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"@\n" +
+	"\tDropFault\x123\n" +
+	"\atimeout\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"\x0e\n" +
+	"\fCorruptFault2\x81\x01\n" +
+	"\x0eFaultInjection\x123\n" +
+	"\tSetFaults\x12\x12.shared.FaultRules\x1a\x12.shared.FaultRules\x12:\n" +
+	"\tGetFaults\x12\x19.shared.FaultRulesRequest\x1a\x12.shared.FaultRulesBBZ@github.com/karldmenzel/go-grpc-client-server/magicMath;magicMathb\x06proto3"
+
+var (
+	file_magicMath_fault_injection_proto_rawDescOnce sync.Once
+	file_magicMath_fault_injection_proto_rawDescData []byte
+)
+
+func file_magicMath_fault_injection_proto_rawDescGZIP() []byte {
+	file_magicMath_fault_injection_proto_rawDescOnce.Do(func() {
+		file_magicMath_fault_injection_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_magicMath_fault_injection_proto_rawDesc), len(file_magicMath_fault_injection_proto_rawDesc)))
+	})
+	return file_magicMath_fault_injection_proto_rawDescData
+}
+
+var file_magicMath_fault_injection_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_magicMath_fault_injection_proto_goTypes = []any{
+	(*FaultRulesRequest)(nil),   // 0: shared.FaultRulesRequest
+	(*FaultRules)(nil),          // 1: shared.FaultRules
+	(*FaultRule)(nil),           // 2: shared.FaultRule
+	(*Latency)(nil),             // 3: shared.Latency
+	(*ErrorFault)(nil),          // 4: shared.ErrorFault
+	(*DropFault)(nil),           // 5: shared.DropFault
+	(*CorruptFault)(nil),        // 6: shared.CorruptFault
+	(*durationpb.Duration)(nil), // 7: google.protobuf.Duration
+}
+var file_magicMath_fault_injection_proto_depIdxs = []int32{
+	2,  // 0: shared.FaultRules.rules:type_name -> shared.FaultRule
+	3,  // 1: shared.FaultRule.latency:type_name -> shared.Latency
+	4,  // 2: shared.FaultRule.error:type_name -> shared.ErrorFault
+	5,  // 3: shared.FaultRule.drop:type_name -> shared.DropFault
+	6,  // 4: shared.FaultRule.corrupt:type_name -> shared.CorruptFault
+	7,  // 5: shared.Latency.base:type_name -> google.protobuf.Duration
+	7,  // 6: shared.Latency.spread:type_name -> google.protobuf.Duration
+	7,  // 7: shared.DropFault.timeout:type_name -> google.protobuf.Duration
+	1,  // 8: shared.FaultInjection.SetFaults:input_type -> shared.FaultRules
+	0,  // 9: shared.FaultInjection.GetFaults:input_type -> shared.FaultRulesRequest
+	1,  // 10: shared.FaultInjection.SetFaults:output_type -> shared.FaultRules
+	1,  // 11: shared.FaultInjection.GetFaults:output_type -> shared.FaultRules
+	10, // [10:12] is the sub-list for method output_type
+	8,  // [8:10] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_magicMath_fault_injection_proto_init() }
+func file_magicMath_fault_injection_proto_init() {
+	if File_magicMath_fault_injection_proto != nil {
+		return
+	}
+	file_magicMath_fault_injection_proto_msgTypes[2].OneofWrappers = []any{
+		(*FaultRule_Latency)(nil),
+		(*FaultRule_Error)(nil),
+		(*FaultRule_Drop)(nil),
+		(*FaultRule_Corrupt)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_magicMath_fault_injection_proto_rawDesc), len(file_magicMath_fault_injection_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_magicMath_fault_injection_proto_goTypes,
+		DependencyIndexes: file_magicMath_fault_injection_proto_depIdxs,
+		MessageInfos:      file_magicMath_fault_injection_proto_msgTypes,
+	}.Build()
+	File_magicMath_fault_injection_proto = out.File
+	file_magicMath_fault_injection_proto_goTypes = nil
+	file_magicMath_fault_injection_proto_depIdxs = nil
+}
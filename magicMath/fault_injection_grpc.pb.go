@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.1
+// source: magicMath/fault_injection.proto
+
+package magicMath
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FaultInjection_SetFaults_FullMethodName = "/shared.FaultInjection/SetFaults"
+	FaultInjection_GetFaults_FullMethodName = "/shared.FaultInjection/GetFaults"
+)
+
+// FaultInjectionClient is the client API for FaultInjection service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The FaultInjection service makes the MagicMath service misbehave on purpose while the server is running,
+// so that the clients' handling of slow calls, errors, lost responses and wrong results can be tested.
+// It is only served when the server runs with fault injection enabled.
+type FaultInjectionClient interface {
+	// SetFaults replaces every fault rule, and returns the new rules. An empty list stops injecting faults.
+	SetFaults(ctx context.Context, in *FaultRules, opts ...grpc.CallOption) (*FaultRules, error)
+	// GetFaults returns the current rules, along with how many faults each one has injected.
+	GetFaults(ctx context.Context, in *FaultRulesRequest, opts ...grpc.CallOption) (*FaultRules, error)
+}
+
+type faultInjectionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFaultInjectionClient(cc grpc.ClientConnInterface) FaultInjectionClient {
+	return &faultInjectionClient{cc}
+}
+
+func (c *faultInjectionClient) SetFaults(ctx context.Context, in *FaultRules, opts ...grpc.CallOption) (*FaultRules, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FaultRules)
+	err := c.cc.Invoke(ctx, FaultInjection_SetFaults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *faultInjectionClient) GetFaults(ctx context.Context, in *FaultRulesRequest, opts ...grpc.CallOption) (*FaultRules, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FaultRules)
+	err := c.cc.Invoke(ctx, FaultInjection_GetFaults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FaultInjectionServer is the server API for FaultInjection service.
+// All implementations must embed UnimplementedFaultInjectionServer
+// for forward compatibility.
+//
+// The FaultInjection service makes the MagicMath service misbehave on purpose while the server is running,
+// so that the clients' handling of slow calls, errors, lost responses and wrong results can be tested.
+// It is only served when the server runs with fault injection enabled.
+type FaultInjectionServer interface {
+	// SetFaults replaces every fault rule, and returns the new rules. An empty list stops injecting faults.
+	SetFaults(context.Context, *FaultRules) (*FaultRules, error)
+	// GetFaults returns the current rules, along with how many faults each one has injected.
+	GetFaults(context.Context, *FaultRulesRequest) (*FaultRules, error)
+	mustEmbedUnimplementedFaultInjectionServer()
+}
+
+// UnimplementedFaultInjectionServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFaultInjectionServer struct{}
+
+func (UnimplementedFaultInjectionServer) SetFaults(context.Context, *FaultRules) (*FaultRules, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetFaults not implemented")
+}
+func (UnimplementedFaultInjectionServer) GetFaults(context.Context, *FaultRulesRequest) (*FaultRules, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetFaults not implemented")
+}
+func (UnimplementedFaultInjectionServer) mustEmbedUnimplementedFaultInjectionServer() {}
+func (UnimplementedFaultInjectionServer) testEmbeddedByValue()                        {}
+
+// UnsafeFaultInjectionServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FaultInjectionServer will
+// result in compilation errors.
+type UnsafeFaultInjectionServer interface {
+	mustEmbedUnimplementedFaultInjectionServer()
+}
+
+func RegisterFaultInjectionServer(s grpc.ServiceRegistrar, srv FaultInjectionServer) {
+	// If the following call panics, it indicates UnimplementedFaultInjectionServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FaultInjection_ServiceDesc, srv)
+}
+
+func _FaultInjection_SetFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FaultRules)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FaultInjectionServer).SetFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FaultInjection_SetFaults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FaultInjectionServer).SetFaults(ctx, req.(*FaultRules))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FaultInjection_GetFaults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FaultRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FaultInjectionServer).GetFaults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FaultInjection_GetFaults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FaultInjectionServer).GetFaults(ctx, req.(*FaultRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FaultInjection_ServiceDesc is the grpc.ServiceDesc for FaultInjection service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FaultInjection_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shared.FaultInjection",
+	HandlerType: (*FaultInjectionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetFaults",
+			Handler:    _FaultInjection_SetFaults_Handler,
+		},
+		{
+			MethodName: "GetFaults",
+			Handler:    _FaultInjection_GetFaults_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "magicMath/fault_injection.proto",
+}
@@ -7,8 +7,10 @@
 package magicMath
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +23,8 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// The terms of the version 1 double functions have no validation rules, so infinite terms are allowed, as they always
+// have been. MagicAdd and MagicSubtract follow IEEE-754, and the arithmetic functions check their own terms.
 type DoubleTerms struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TermOne       float64                `protobuf:"fixed64,1,opt,name=termOne,proto3" json:"termOne,omitempty"`
@@ -221,6 +225,890 @@ func (x *IntResult) GetResult() int64 {
 	return 0
 }
 
+type IntPair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TermOne       int64                  `protobuf:"zigzag64,1,opt,name=termOne,proto3" json:"termOne,omitempty"`
+	TermTwo       int64                  `protobuf:"zigzag64,2,opt,name=termTwo,proto3" json:"termTwo,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntPair) Reset() {
+	*x = IntPair{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntPair) ProtoMessage() {}
+
+func (x *IntPair) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntPair.ProtoReflect.Descriptor instead.
+func (*IntPair) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *IntPair) GetTermOne() int64 {
+	if x != nil {
+		return x.TermOne
+	}
+	return 0
+}
+
+func (x *IntPair) GetTermTwo() int64 {
+	if x != nil {
+		return x.TermTwo
+	}
+	return 0
+}
+
+// Two doubles or two integers, the result has the same type as the terms.
+type ArithmeticTerms struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Terms:
+	//
+	//	*ArithmeticTerms_Doubles
+	//	*ArithmeticTerms_Ints
+	Terms         isArithmeticTerms_Terms `protobuf_oneof:"terms"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArithmeticTerms) Reset() {
+	*x = ArithmeticTerms{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArithmeticTerms) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArithmeticTerms) ProtoMessage() {}
+
+func (x *ArithmeticTerms) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArithmeticTerms.ProtoReflect.Descriptor instead.
+func (*ArithmeticTerms) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ArithmeticTerms) GetTerms() isArithmeticTerms_Terms {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+func (x *ArithmeticTerms) GetDoubles() *DoubleTerms {
+	if x != nil {
+		if x, ok := x.Terms.(*ArithmeticTerms_Doubles); ok {
+			return x.Doubles
+		}
+	}
+	return nil
+}
+
+func (x *ArithmeticTerms) GetInts() *IntPair {
+	if x != nil {
+		if x, ok := x.Terms.(*ArithmeticTerms_Ints); ok {
+			return x.Ints
+		}
+	}
+	return nil
+}
+
+type isArithmeticTerms_Terms interface {
+	isArithmeticTerms_Terms()
+}
+
+type ArithmeticTerms_Doubles struct {
+	Doubles *DoubleTerms `protobuf:"bytes,1,opt,name=doubles,proto3,oneof"`
+}
+
+type ArithmeticTerms_Ints struct {
+	Ints *IntPair `protobuf:"bytes,2,opt,name=ints,proto3,oneof"`
+}
+
+func (*ArithmeticTerms_Doubles) isArithmeticTerms_Terms() {}
+
+func (*ArithmeticTerms_Ints) isArithmeticTerms_Terms() {}
+
+type ArithmeticResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*ArithmeticResult_DoubleResult
+	//	*ArithmeticResult_IntResult
+	Result        isArithmeticResult_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArithmeticResult) Reset() {
+	*x = ArithmeticResult{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArithmeticResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArithmeticResult) ProtoMessage() {}
+
+func (x *ArithmeticResult) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArithmeticResult.ProtoReflect.Descriptor instead.
+func (*ArithmeticResult) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ArithmeticResult) GetResult() isArithmeticResult_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ArithmeticResult) GetDoubleResult() float64 {
+	if x != nil {
+		if x, ok := x.Result.(*ArithmeticResult_DoubleResult); ok {
+			return x.DoubleResult
+		}
+	}
+	return 0
+}
+
+func (x *ArithmeticResult) GetIntResult() int64 {
+	if x != nil {
+		if x, ok := x.Result.(*ArithmeticResult_IntResult); ok {
+			return x.IntResult
+		}
+	}
+	return 0
+}
+
+type isArithmeticResult_Result interface {
+	isArithmeticResult_Result()
+}
+
+type ArithmeticResult_DoubleResult struct {
+	DoubleResult float64 `protobuf:"fixed64,1,opt,name=doubleResult,proto3,oneof"`
+}
+
+type ArithmeticResult_IntResult struct {
+	IntResult int64 `protobuf:"zigzag64,2,opt,name=intResult,proto3,oneof"`
+}
+
+func (*ArithmeticResult_DoubleResult) isArithmeticResult_Result() {}
+
+func (*ArithmeticResult_IntResult) isArithmeticResult_Result() {}
+
+// Two numbers written as decimal strings, such as "-12.5" or "1e-30". Rationals may also be written as fractions,
+// such as "1/3". The exponent of a power must be a whole number.
+type BigTerms struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	TermOne string                 `protobuf:"bytes,1,opt,name=termOne,proto3" json:"termOne,omitempty"`
+	TermTwo string                 `protobuf:"bytes,2,opt,name=termTwo,proto3" json:"termTwo,omitempty"`
+	// "int" for integers, "rat" for exact rationals (the default), or "float" for binary floating point numbers.
+	Kind string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	// The number of bits in a float's mantissa, 256 when it is zero.
+	Precision int32 `protobuf:"zigzag32,4,opt,name=precision,proto3" json:"precision,omitempty"`
+	// How floats are rounded after every operation: "ToNearestEven" (the default), "ToNearestAway", "ToZero",
+	// "AwayFromZero", "ToNegativeInf" or "ToPositiveInf".
+	Rounding      string `protobuf:"bytes,5,opt,name=rounding,proto3" json:"rounding,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BigTerms) Reset() {
+	*x = BigTerms{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BigTerms) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BigTerms) ProtoMessage() {}
+
+func (x *BigTerms) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BigTerms.ProtoReflect.Descriptor instead.
+func (*BigTerms) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BigTerms) GetTermOne() string {
+	if x != nil {
+		return x.TermOne
+	}
+	return ""
+}
+
+func (x *BigTerms) GetTermTwo() string {
+	if x != nil {
+		return x.TermTwo
+	}
+	return ""
+}
+
+func (x *BigTerms) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *BigTerms) GetPrecision() int32 {
+	if x != nil {
+		return x.Precision
+	}
+	return 0
+}
+
+func (x *BigTerms) GetRounding() string {
+	if x != nil {
+		return x.Rounding
+	}
+	return ""
+}
+
+// Integers are written in full, rationals as an exact decimal when they have one and otherwise as a fraction,
+// and floats with the fewest digits that read back as the same float.
+type BigResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        string                 `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BigResult) Reset() {
+	*x = BigResult{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BigResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BigResult) ProtoMessage() {}
+
+func (x *BigResult) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BigResult.ProtoReflect.Descriptor instead.
+func (*BigResult) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BigResult) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+// A decimal number, either as a canonical decimal string such as "-12.50", or as an unscaled integer and a scale.
+type Decimal struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Decimal_Text
+	//	*Decimal_Scaled
+	Value         isDecimal_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Decimal) Reset() {
+	*x = Decimal{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Decimal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Decimal) ProtoMessage() {}
+
+func (x *Decimal) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Decimal.ProtoReflect.Descriptor instead.
+func (*Decimal) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Decimal) GetValue() isDecimal_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Decimal) GetText() string {
+	if x != nil {
+		if x, ok := x.Value.(*Decimal_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+func (x *Decimal) GetScaled() *ScaledDecimal {
+	if x != nil {
+		if x, ok := x.Value.(*Decimal_Scaled); ok {
+			return x.Scaled
+		}
+	}
+	return nil
+}
+
+type isDecimal_Value interface {
+	isDecimal_Value()
+}
+
+type Decimal_Text struct {
+	Text string `protobuf:"bytes,1,opt,name=text,proto3,oneof"`
+}
+
+type Decimal_Scaled struct {
+	Scaled *ScaledDecimal `protobuf:"bytes,2,opt,name=scaled,proto3,oneof"`
+}
+
+func (*Decimal_Text) isDecimal_Value() {}
+
+func (*Decimal_Scaled) isDecimal_Value() {}
+
+// The decimal unscaled × 10^-scale, so 12.50 is an unscaled 1250 with a scale of 2.
+// The unscaled integer is written in decimal, as it may not fit in an int64.
+type ScaledDecimal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Unscaled      string                 `protobuf:"bytes,1,opt,name=unscaled,proto3" json:"unscaled,omitempty"`
+	Scale         int32                  `protobuf:"zigzag32,2,opt,name=scale,proto3" json:"scale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScaledDecimal) Reset() {
+	*x = ScaledDecimal{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScaledDecimal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScaledDecimal) ProtoMessage() {}
+
+func (x *ScaledDecimal) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScaledDecimal.ProtoReflect.Descriptor instead.
+func (*ScaledDecimal) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ScaledDecimal) GetUnscaled() string {
+	if x != nil {
+		return x.Unscaled
+	}
+	return ""
+}
+
+func (x *ScaledDecimal) GetScale() int32 {
+	if x != nil {
+		return x.Scale
+	}
+	return 0
+}
+
+type DecimalTerms struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	TermOne *Decimal               `protobuf:"bytes,1,opt,name=termOne,proto3" json:"termOne,omitempty"`
+	TermTwo *Decimal               `protobuf:"bytes,2,opt,name=termTwo,proto3" json:"termTwo,omitempty"`
+	// The number of digits after the point in the result. Without it, sums, differences and products are exact,
+	// and division fails as it needs to know where to stop.
+	Scale *int32 `protobuf:"zigzag32,3,opt,name=scale,proto3,oneof" json:"scale,omitempty"`
+	// How the result is rounded to the scale: "half_even" (the default), "half_up", "down" or "up".
+	Rounding      string `protobuf:"bytes,4,opt,name=rounding,proto3" json:"rounding,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecimalTerms) Reset() {
+	*x = DecimalTerms{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecimalTerms) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecimalTerms) ProtoMessage() {}
+
+func (x *DecimalTerms) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecimalTerms.ProtoReflect.Descriptor instead.
+func (*DecimalTerms) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DecimalTerms) GetTermOne() *Decimal {
+	if x != nil {
+		return x.TermOne
+	}
+	return nil
+}
+
+func (x *DecimalTerms) GetTermTwo() *Decimal {
+	if x != nil {
+		return x.TermTwo
+	}
+	return nil
+}
+
+func (x *DecimalTerms) GetScale() int32 {
+	if x != nil && x.Scale != nil {
+		return *x.Scale
+	}
+	return 0
+}
+
+func (x *DecimalTerms) GetRounding() string {
+	if x != nil {
+		return x.Rounding
+	}
+	return ""
+}
+
+// The result in both forms.
+type DecimalResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Scaled        *ScaledDecimal         `protobuf:"bytes,2,opt,name=scaled,proto3" json:"scaled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecimalResult) Reset() {
+	*x = DecimalResult{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecimalResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecimalResult) ProtoMessage() {}
+
+func (x *DecimalResult) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecimalResult.ProtoReflect.Descriptor instead.
+func (*DecimalResult) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DecimalResult) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *DecimalResult) GetScaled() *ScaledDecimal {
+	if x != nil {
+		return x.Scaled
+	}
+	return nil
+}
+
+// An expression of numbers, variables, + - * /, parentheses, and the min() and max() functions,
+// along with the values of its variables.
+type Expression struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expression    string                 `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	Variables     map[string]float64     `protobuf:"bytes,2,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Expression) Reset() {
+	*x = Expression{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Expression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expression) ProtoMessage() {}
+
+func (x *Expression) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expression.ProtoReflect.Descriptor instead.
+func (*Expression) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Expression) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *Expression) GetVariables() map[string]float64 {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+// Values to add to a dataset, and the quantiles to work out, each between 0 and 1. When the values are streamed,
+// the quantiles are taken from the first message which has any.
+type StatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float64              `protobuf:"fixed64,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	Quantiles     []float64              `protobuf:"fixed64,2,rep,packed,name=quantiles,proto3" json:"quantiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsRequest) Reset() {
+	*x = StatsRequest{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRequest) ProtoMessage() {}
+
+func (x *StatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StatsRequest) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *StatsRequest) GetQuantiles() []float64 {
+	if x != nil {
+		return x.Quantiles
+	}
+	return nil
+}
+
+type Stats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Count int64                  `protobuf:"zigzag64,1,opt,name=count,proto3" json:"count,omitempty"`
+	Sum   float64                `protobuf:"fixed64,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	Mean  float64                `protobuf:"fixed64,3,opt,name=mean,proto3" json:"mean,omitempty"`
+	// The sample variance, which divides by count - 1.
+	Variance  float64          `protobuf:"fixed64,4,opt,name=variance,proto3" json:"variance,omitempty"`
+	Stddev    float64          `protobuf:"fixed64,5,opt,name=stddev,proto3" json:"stddev,omitempty"`
+	Min       float64          `protobuf:"fixed64,6,opt,name=min,proto3" json:"min,omitempty"`
+	Max       float64          `protobuf:"fixed64,7,opt,name=max,proto3" json:"max,omitempty"`
+	Median    float64          `protobuf:"fixed64,8,opt,name=median,proto3" json:"median,omitempty"`
+	Quantiles []*QuantileValue `protobuf:"bytes,9,rep,name=quantiles,proto3" json:"quantiles,omitempty"`
+	// True if the median and quantiles are exact, false if they were estimated by a sketch.
+	Exact         bool `protobuf:"varint,10,opt,name=exact,proto3" json:"exact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Stats) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Stats) GetSum() float64 {
+	if x != nil {
+		return x.Sum
+	}
+	return 0
+}
+
+func (x *Stats) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Stats) GetVariance() float64 {
+	if x != nil {
+		return x.Variance
+	}
+	return 0
+}
+
+func (x *Stats) GetStddev() float64 {
+	if x != nil {
+		return x.Stddev
+	}
+	return 0
+}
+
+func (x *Stats) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Stats) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Stats) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *Stats) GetQuantiles() []*QuantileValue {
+	if x != nil {
+		return x.Quantiles
+	}
+	return nil
+}
+
+func (x *Stats) GetExact() bool {
+	if x != nil {
+		return x.Exact
+	}
+	return false
+}
+
+type QuantileValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quantile      float64                `protobuf:"fixed64,1,opt,name=quantile,proto3" json:"quantile,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuantileValue) Reset() {
+	*x = QuantileValue{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuantileValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuantileValue) ProtoMessage() {}
+
+func (x *QuantileValue) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuantileValue.ProtoReflect.Descriptor instead.
+func (*QuantileValue) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *QuantileValue) GetQuantile() float64 {
+	if x != nil {
+		return x.Quantile
+	}
+	return 0
+}
+
+func (x *QuantileValue) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
 type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -229,7 +1117,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_magicMath_magic_math_proto_msgTypes[4]
+	mi := &file_magicMath_magic_math_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -241,7 +1129,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_magicMath_magic_math_proto_msgTypes[4]
+	mi := &file_magicMath_magic_math_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -254,7 +1142,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{4}
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{17}
 }
 
 type Count struct {
@@ -266,7 +1154,7 @@ type Count struct {
 
 func (x *Count) Reset() {
 	*x = Count{}
-	mi := &file_magicMath_magic_math_proto_msgTypes[5]
+	mi := &file_magicMath_magic_math_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -278,7 +1166,7 @@ func (x *Count) String() string {
 func (*Count) ProtoMessage() {}
 
 func (x *Count) ProtoReflect() protoreflect.Message {
-	mi := &file_magicMath_magic_math_proto_msgTypes[5]
+	mi := &file_magicMath_magic_math_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -291,7 +1179,7 @@ func (x *Count) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Count.ProtoReflect.Descriptor instead.
 func (*Count) Descriptor() ([]byte, []int) {
-	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{5}
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *Count) GetCount() int64 {
@@ -301,11 +1189,173 @@ func (x *Count) GetCount() int64 {
 	return 0
 }
 
+type CacheStats struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Hits   int64                  `protobuf:"zigzag64,1,opt,name=hits,proto3" json:"hits,omitempty"`
+	Misses int64                  `protobuf:"zigzag64,2,opt,name=misses,proto3" json:"misses,omitempty"`
+	// How many results are in the cache, and how many have been evicted to make room for newer ones.
+	Entries       int64 `protobuf:"zigzag64,3,opt,name=entries,proto3" json:"entries,omitempty"`
+	Evictions     int64 `protobuf:"zigzag64,4,opt,name=evictions,proto3" json:"evictions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheStats) Reset() {
+	*x = CacheStats{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStats) ProtoMessage() {}
+
+func (x *CacheStats) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStats.ProtoReflect.Descriptor instead.
+func (*CacheStats) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CacheStats) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CacheStats) GetMisses() int64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+func (x *CacheStats) GetEntries() int64 {
+	if x != nil {
+		return x.Entries
+	}
+	return 0
+}
+
+func (x *CacheStats) GetEvictions() int64 {
+	if x != nil {
+		return x.Evictions
+	}
+	return 0
+}
+
+// A limit of zero means the quota is turned off.
+type Quota struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Caller        string                 `protobuf:"bytes,1,opt,name=caller,proto3" json:"caller,omitempty"`
+	HourlyLimit   int64                  `protobuf:"zigzag64,2,opt,name=hourlyLimit,proto3" json:"hourlyLimit,omitempty"`
+	HourlyUsed    int64                  `protobuf:"zigzag64,3,opt,name=hourlyUsed,proto3" json:"hourlyUsed,omitempty"`
+	HourlyReset   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=hourlyReset,proto3" json:"hourlyReset,omitempty"`
+	DailyLimit    int64                  `protobuf:"zigzag64,5,opt,name=dailyLimit,proto3" json:"dailyLimit,omitempty"`
+	DailyUsed     int64                  `protobuf:"zigzag64,6,opt,name=dailyUsed,proto3" json:"dailyUsed,omitempty"`
+	DailyReset    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=dailyReset,proto3" json:"dailyReset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quota) Reset() {
+	*x = Quota{}
+	mi := &file_magicMath_magic_math_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quota) ProtoMessage() {}
+
+func (x *Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_magic_math_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quota.ProtoReflect.Descriptor instead.
+func (*Quota) Descriptor() ([]byte, []int) {
+	return file_magicMath_magic_math_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Quota) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *Quota) GetHourlyLimit() int64 {
+	if x != nil {
+		return x.HourlyLimit
+	}
+	return 0
+}
+
+func (x *Quota) GetHourlyUsed() int64 {
+	if x != nil {
+		return x.HourlyUsed
+	}
+	return 0
+}
+
+func (x *Quota) GetHourlyReset() *timestamppb.Timestamp {
+	if x != nil {
+		return x.HourlyReset
+	}
+	return nil
+}
+
+func (x *Quota) GetDailyLimit() int64 {
+	if x != nil {
+		return x.DailyLimit
+	}
+	return 0
+}
+
+func (x *Quota) GetDailyUsed() int64 {
+	if x != nil {
+		return x.DailyUsed
+	}
+	return 0
+}
+
+func (x *Quota) GetDailyReset() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DailyReset
+	}
+	return nil
+}
+
 var File_magicMath_magic_math_proto protoreflect.FileDescriptor
 
 const file_magicMath_magic_math_proto_rawDesc = "" +
 	"\n" +
-	"\x1amagicMath/magic_math.proto\x12\x06shared\"A\n" +
+	"\x1amagicMath/magic_math.proto\x12\x06shared\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1amagicMath/validation.proto\"A\n" +
 	"\vDoubleTerms\x12\x18\n" +
 	"\atermOne\x18\x01 \x01(\x01R\atermOne\x12\x18\n" +
 	"\atermTwo\x18\x02 \x01(\x01R\atermTwo\"&\n" +
@@ -316,19 +1366,133 @@ const file_magicMath_magic_math_proto_rawDesc = "" +
 	"\atermTwo\x18\x02 \x01(\x12R\atermTwo\x12\x1c\n" +
 	"\ttermThree\x18\x03 \x01(\x12R\ttermThree\"#\n" +
 	"\tIntResult\x12\x16\n" +
-	"\x06result\x18\x01 \x01(\x12R\x06result\"\a\n" +
+	"\x06result\x18\x01 \x01(\x12R\x06result\"=\n" +
+	"\aIntPair\x12\x18\n" +
+	"\atermOne\x18\x01 \x01(\x12R\atermOne\x12\x18\n" +
+	"\atermTwo\x18\x02 \x01(\x12R\atermTwo\"r\n" +
+	"\x0fArithmeticTerms\x12/\n" +
+	"\adoubles\x18\x01 \x01(\v2\x13.shared.DoubleTermsH\x00R\adoubles\x12%\n" +
+	"\x04ints\x18\x02 \x01(\v2\x0f.shared.IntPairH\x00R\x04intsB\a\n" +
+	"\x05terms\"b\n" +
+	"\x10ArithmeticResult\x12$\n" +
+	"\fdoubleResult\x18\x01 \x01(\x01H\x00R\fdoubleResult\x12\x1e\n" +
+	"\tintResult\x18\x02 \x01(\x12H\x00R\tintResultB\b\n" +
+	"\x06result\"\x9b\x01\n" +
+	"\bBigTerms\x12\x18\n" +
+	"\atermOne\x18\x01 \x01(\tR\atermOne\x12\x18\n" +
+	"\atermTwo\x18\x02 \x01(\tR\atermTwo\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12+\n" +
+	"\tprecision\x18\x04 \x01(\x11B\r\xa2\xbb\x18\t\t\x00\x00\x00\x00\x00\x00\x00\x00R\tprecision\x12\x1a\n" +
+	"\brounding\x18\x05 \x01(\tR\brounding\"#\n" +
+	"\tBigResult\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\tR\x06result\"Y\n" +
+	"\aDecimal\x12\x14\n" +
+	"\x04text\x18\x01 \x01(\tH\x00R\x04text\x12/\n" +
+	"\x06scaled\x18\x02 \x01(\v2\x15.shared.ScaledDecimalH\x00R\x06scaledB\a\n" +
+	"\x05value\"A\n" +
+	"\rScaledDecimal\x12\x1a\n" +
+	"\bunscaled\x18\x01 \x01(\tR\bunscaled\x12\x14\n" +
+	"\x05scale\x18\x02 \x01(\x11R\x05scale\"\xa5\x01\n" +
+	"\fDecimalTerms\x12)\n" +
+	"\atermOne\x18\x01 \x01(\v2\x0f.shared.DecimalR\atermOne\x12)\n" +
+	"\atermTwo\x18\x02 \x01(\v2\x0f.shared.DecimalR\atermTwo\x12\x19\n" +
+	"\x05scale\x18\x03 \x01(\x11H\x00R\x05scale\x88\x01\x01\x12\x1a\n" +
+	"\brounding\x18\x04 \x01(\tR\broundingB\b\n" +
+	"\x06_scale\"R\n" +
+	"\rDecimalResult\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12-\n" +
+	"\x06scaled\x18\x02 \x01(\v2\x15.shared.ScaledDecimalR\x06scaled\"\xbc\x01\n" +
+	"\n" +
+	"Expression\x12'\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tB\a\xa2\xbb\x18\x030\x80 R\n" +
+	"expression\x12G\n" +
+	"\tvariables\x18\x02 \x03(\v2!.shared.Expression.VariablesEntryB\x06\xa2\xbb\x18\x02\x18\x01R\tvariables\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"j\n" +
+	"\fStatsRequest\x12\"\n" +
+	"\x06values\x18\x01 \x03(\x01B\n" +
+	"\xa2\xbb\x18\x06\x18\x01(\x80\x80@R\x06values\x126\n" +
+	"\tquantiles\x18\x02 \x03(\x01B\x18\xa2\xbb\x18\x14\t\x00\x00\x00\x00\x00\x00\x00\x00\x11\x00\x00\x00\x00\x00\x00\xf0?(dR\tquantiles\"\xfe\x01\n" +
+	"\x05Stats\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x12R\x05count\x12\x10\n" +
+	"\x03sum\x18\x02 \x01(\x01R\x03sum\x12\x12\n" +
+	"\x04mean\x18\x03 \x01(\x01R\x04mean\x12\x1a\n" +
+	"\bvariance\x18\x04 \x01(\x01R\bvariance\x12\x16\n" +
+	"\x06stddev\x18\x05 \x01(\x01R\x06stddev\x12\x10\n" +
+	"\x03min\x18\x06 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\a \x01(\x01R\x03max\x12\x16\n" +
+	"\x06median\x18\b \x01(\x01R\x06median\x123\n" +
+	"\tquantiles\x18\t \x03(\v2\x15.shared.QuantileValueR\tquantiles\x12\x14\n" +
+	"\x05exact\x18\n" +
+	" \x01(\bR\x05exact\"A\n" +
+	"\rQuantileValue\x12\x1a\n" +
+	"\bquantile\x18\x01 \x01(\x01R\bquantile\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"\a\n" +
 	"\x05Empty\"\x1d\n" +
 	"\x05Count\x12\x14\n" +
-	"\x05count\x18\x01 \x01(\x12R\x05count2\x9c\x03\n" +
-	"\tMagicMath\x125\n" +
-	"\bMagicAdd\x12\x13.shared.DoubleTerms\x1a\x14.shared.DoubleResult\x12:\n" +
-	"\rMagicSubtract\x12\x13.shared.DoubleTerms\x1a\x14.shared.DoubleResult\x123\n" +
-	"\fMagicFindMin\x12\x10.shared.IntTerms\x1a\x11.shared.IntResult\x123\n" +
-	"\fMagicFindMax\x12\x10.shared.IntTerms\x1a\x11.shared.IntResult\x12+\n" +
-	"\vGetAddCount\x12\r.shared.Empty\x1a\r.shared.Count\x12+\n" +
-	"\vGetSubCount\x12\r.shared.Empty\x1a\r.shared.Count\x12+\n" +
-	"\vGetMinCount\x12\r.shared.Empty\x1a\r.shared.Count\x12+\n" +
-	"\vGetMaxCount\x12\r.shared.Empty\x1a\r.shared.CountB\rZ\v./magicMathb\x06proto3"
+	"\x05count\x18\x01 \x01(\x12R\x05count\"p\n" +
+	"\n" +
+	"CacheStats\x12\x12\n" +
+	"\x04hits\x18\x01 \x01(\x12R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x02 \x01(\x12R\x06misses\x12\x18\n" +
+	"\aentries\x18\x03 \x01(\x12R\aentries\x12\x1c\n" +
+	"\tevictions\x18\x04 \x01(\x12R\tevictions\"\x99\x02\n" +
+	"\x05Quota\x12\x16\n" +
+	"\x06caller\x18\x01 \x01(\tR\x06caller\x12 \n" +
+	"\vhourlyLimit\x18\x02 \x01(\x12R\vhourlyLimit\x12\x1e\n" +
+	"\n" +
+	"hourlyUsed\x18\x03 \x01(\x12R\n" +
+	"hourlyUsed\x12<\n" +
+	"\vhourlyReset\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vhourlyReset\x12\x1e\n" +
+	"\n" +
+	"dailyLimit\x18\x05 \x01(\x12R\n" +
+	"dailyLimit\x12\x1c\n" +
+	"\tdailyUsed\x18\x06 \x01(\x12R\tdailyUsed\x12:\n" +
+	"\n" +
+	"dailyReset\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"dailyReset2\xbd\x15\n" +
+	"\tMagicMath\x12I\n" +
+	"\bMagicAdd\x12\x13.shared.DoubleTerms\x1a\x14.shared.DoubleResult\"\x12\x82\xd3\xe4\x93\x02\f:\x01*\"\a/v1/add\x12S\n" +
+	"\rMagicSubtract\x12\x13.shared.DoubleTerms\x1a\x14.shared.DoubleResult\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/subtract\x12G\n" +
+	"\fMagicFindMin\x12\x10.shared.IntTerms\x1a\x11.shared.IntResult\"\x12\x82\xd3\xe4\x93\x02\f:\x01*\"\a/v1/min\x12G\n" +
+	"\fMagicFindMax\x12\x10.shared.IntTerms\x1a\x11.shared.IntResult\"\x12\x82\xd3\xe4\x93\x02\f:\x01*\"\a/v1/max\x12[\n" +
+	"\rMagicMultiply\x12\x17.shared.ArithmeticTerms\x1a\x18.shared.ArithmeticResult\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/multiply\x12W\n" +
+	"\vMagicDivide\x12\x17.shared.ArithmeticTerms\x1a\x18.shared.ArithmeticResult\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/divide\x12W\n" +
+	"\vMagicModulo\x12\x17.shared.ArithmeticTerms\x1a\x18.shared.ArithmeticResult\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/modulo\x12U\n" +
+	"\n" +
+	"MagicPower\x12\x17.shared.ArithmeticTerms\x1a\x18.shared.ArithmeticResult\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/power\x12J\n" +
+	"\vMagicBigAdd\x12\x10.shared.BigTerms\x1a\x11.shared.BigResult\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/big/add\x12T\n" +
+	"\x10MagicBigSubtract\x12\x10.shared.BigTerms\x1a\x11.shared.BigResult\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/big/subtract\x12T\n" +
+	"\x10MagicBigMultiply\x12\x10.shared.BigTerms\x1a\x11.shared.BigResult\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/big/multiply\x12P\n" +
+	"\x0eMagicBigDivide\x12\x10.shared.BigTerms\x1a\x11.shared.BigResult\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/big/divide\x12N\n" +
+	"\rMagicBigPower\x12\x10.shared.BigTerms\x1a\x11.shared.BigResult\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/big/power\x12Z\n" +
+	"\x0fMagicDecimalAdd\x12\x14.shared.DecimalTerms\x1a\x15.shared.DecimalResult\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/decimal/add\x12d\n" +
+	"\x14MagicDecimalSubtract\x12\x14.shared.DecimalTerms\x1a\x15.shared.DecimalResult\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/decimal/subtract\x12d\n" +
+	"\x14MagicDecimalMultiply\x12\x14.shared.DecimalTerms\x1a\x15.shared.DecimalResult\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/decimal/multiply\x12`\n" +
+	"\x12MagicDecimalDivide\x12\x14.shared.DecimalTerms\x1a\x15.shared.DecimalResult\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/decimal/divide\x12R\n" +
+	"\rMagicEvaluate\x12\x12.shared.Expression\x1a\x14.shared.DoubleResult\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/evaluate\x12G\n" +
+	"\n" +
+	"MagicStats\x12\x14.shared.StatsRequest\x1a\r.shared.Stats\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/stats\x129\n" +
+	"\x10MagicStatsStream\x12\x14.shared.StatsRequest\x1a\r.shared.Stats(\x01\x12C\n" +
+	"\vGetAddCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/add\x12C\n" +
+	"\vGetSubCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/sub\x12C\n" +
+	"\vGetMinCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/min\x12C\n" +
+	"\vGetMaxCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/max\x12C\n" +
+	"\vGetMulCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/mul\x12C\n" +
+	"\vGetDivCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/div\x12C\n" +
+	"\vGetModCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/mod\x12C\n" +
+	"\vGetPowCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/pow\x12G\n" +
+	"\rGetStatsCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/counts/stats\x12C\n" +
+	"\vGetBigCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/counts/big\x12K\n" +
+	"\x0fGetDecimalCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/counts/decimal\x12E\n" +
+	"\fGetEvalCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/counts/eval\x12G\n" +
+	"\rGetPanicCount\x12\r.shared.Empty\x1a\r.shared.Count\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/counts/panic\x12L\n" +
+	"\rGetCacheStats\x12\r.shared.Empty\x1a\x12.shared.CacheStats\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/counts/cache\x12;\n" +
+	"\bGetQuota\x12\r.shared.Empty\x1a\r.shared.Quota\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/quotaBBZ@github.com/karldmenzel/go-grpc-client-server/magicMath;magicMathb\x06proto3"
 
 var (
 	file_magicMath_magic_math_proto_rawDescOnce sync.Once
@@ -342,37 +1506,118 @@ func file_magicMath_magic_math_proto_rawDescGZIP() []byte {
 	return file_magicMath_magic_math_proto_rawDescData
 }
 
-var file_magicMath_magic_math_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_magicMath_magic_math_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_magicMath_magic_math_proto_goTypes = []any{
-	(*DoubleTerms)(nil),  // 0: shared.DoubleTerms
-	(*DoubleResult)(nil), // 1: shared.DoubleResult
-	(*IntTerms)(nil),     // 2: shared.IntTerms
-	(*IntResult)(nil),    // 3: shared.IntResult
-	(*Empty)(nil),        // 4: shared.Empty
-	(*Count)(nil),        // 5: shared.Count
+	(*DoubleTerms)(nil),           // 0: shared.DoubleTerms
+	(*DoubleResult)(nil),          // 1: shared.DoubleResult
+	(*IntTerms)(nil),              // 2: shared.IntTerms
+	(*IntResult)(nil),             // 3: shared.IntResult
+	(*IntPair)(nil),               // 4: shared.IntPair
+	(*ArithmeticTerms)(nil),       // 5: shared.ArithmeticTerms
+	(*ArithmeticResult)(nil),      // 6: shared.ArithmeticResult
+	(*BigTerms)(nil),              // 7: shared.BigTerms
+	(*BigResult)(nil),             // 8: shared.BigResult
+	(*Decimal)(nil),               // 9: shared.Decimal
+	(*ScaledDecimal)(nil),         // 10: shared.ScaledDecimal
+	(*DecimalTerms)(nil),          // 11: shared.DecimalTerms
+	(*DecimalResult)(nil),         // 12: shared.DecimalResult
+	(*Expression)(nil),            // 13: shared.Expression
+	(*StatsRequest)(nil),          // 14: shared.StatsRequest
+	(*Stats)(nil),                 // 15: shared.Stats
+	(*QuantileValue)(nil),         // 16: shared.QuantileValue
+	(*Empty)(nil),                 // 17: shared.Empty
+	(*Count)(nil),                 // 18: shared.Count
+	(*CacheStats)(nil),            // 19: shared.CacheStats
+	(*Quota)(nil),                 // 20: shared.Quota
+	nil,                           // 21: shared.Expression.VariablesEntry
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
 }
 var file_magicMath_magic_math_proto_depIdxs = []int32{
-	0, // 0: shared.MagicMath.MagicAdd:input_type -> shared.DoubleTerms
-	0, // 1: shared.MagicMath.MagicSubtract:input_type -> shared.DoubleTerms
-	2, // 2: shared.MagicMath.MagicFindMin:input_type -> shared.IntTerms
-	2, // 3: shared.MagicMath.MagicFindMax:input_type -> shared.IntTerms
-	4, // 4: shared.MagicMath.GetAddCount:input_type -> shared.Empty
-	4, // 5: shared.MagicMath.GetSubCount:input_type -> shared.Empty
-	4, // 6: shared.MagicMath.GetMinCount:input_type -> shared.Empty
-	4, // 7: shared.MagicMath.GetMaxCount:input_type -> shared.Empty
-	1, // 8: shared.MagicMath.MagicAdd:output_type -> shared.DoubleResult
-	1, // 9: shared.MagicMath.MagicSubtract:output_type -> shared.DoubleResult
-	3, // 10: shared.MagicMath.MagicFindMin:output_type -> shared.IntResult
-	3, // 11: shared.MagicMath.MagicFindMax:output_type -> shared.IntResult
-	5, // 12: shared.MagicMath.GetAddCount:output_type -> shared.Count
-	5, // 13: shared.MagicMath.GetSubCount:output_type -> shared.Count
-	5, // 14: shared.MagicMath.GetMinCount:output_type -> shared.Count
-	5, // 15: shared.MagicMath.GetMaxCount:output_type -> shared.Count
-	8, // [8:16] is the sub-list for method output_type
-	0, // [0:8] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: shared.ArithmeticTerms.doubles:type_name -> shared.DoubleTerms
+	4,  // 1: shared.ArithmeticTerms.ints:type_name -> shared.IntPair
+	10, // 2: shared.Decimal.scaled:type_name -> shared.ScaledDecimal
+	9,  // 3: shared.DecimalTerms.termOne:type_name -> shared.Decimal
+	9,  // 4: shared.DecimalTerms.termTwo:type_name -> shared.Decimal
+	10, // 5: shared.DecimalResult.scaled:type_name -> shared.ScaledDecimal
+	21, // 6: shared.Expression.variables:type_name -> shared.Expression.VariablesEntry
+	16, // 7: shared.Stats.quantiles:type_name -> shared.QuantileValue
+	22, // 8: shared.Quota.hourlyReset:type_name -> google.protobuf.Timestamp
+	22, // 9: shared.Quota.dailyReset:type_name -> google.protobuf.Timestamp
+	0,  // 10: shared.MagicMath.MagicAdd:input_type -> shared.DoubleTerms
+	0,  // 11: shared.MagicMath.MagicSubtract:input_type -> shared.DoubleTerms
+	2,  // 12: shared.MagicMath.MagicFindMin:input_type -> shared.IntTerms
+	2,  // 13: shared.MagicMath.MagicFindMax:input_type -> shared.IntTerms
+	5,  // 14: shared.MagicMath.MagicMultiply:input_type -> shared.ArithmeticTerms
+	5,  // 15: shared.MagicMath.MagicDivide:input_type -> shared.ArithmeticTerms
+	5,  // 16: shared.MagicMath.MagicModulo:input_type -> shared.ArithmeticTerms
+	5,  // 17: shared.MagicMath.MagicPower:input_type -> shared.ArithmeticTerms
+	7,  // 18: shared.MagicMath.MagicBigAdd:input_type -> shared.BigTerms
+	7,  // 19: shared.MagicMath.MagicBigSubtract:input_type -> shared.BigTerms
+	7,  // 20: shared.MagicMath.MagicBigMultiply:input_type -> shared.BigTerms
+	7,  // 21: shared.MagicMath.MagicBigDivide:input_type -> shared.BigTerms
+	7,  // 22: shared.MagicMath.MagicBigPower:input_type -> shared.BigTerms
+	11, // 23: shared.MagicMath.MagicDecimalAdd:input_type -> shared.DecimalTerms
+	11, // 24: shared.MagicMath.MagicDecimalSubtract:input_type -> shared.DecimalTerms
+	11, // 25: shared.MagicMath.MagicDecimalMultiply:input_type -> shared.DecimalTerms
+	11, // 26: shared.MagicMath.MagicDecimalDivide:input_type -> shared.DecimalTerms
+	13, // 27: shared.MagicMath.MagicEvaluate:input_type -> shared.Expression
+	14, // 28: shared.MagicMath.MagicStats:input_type -> shared.StatsRequest
+	14, // 29: shared.MagicMath.MagicStatsStream:input_type -> shared.StatsRequest
+	17, // 30: shared.MagicMath.GetAddCount:input_type -> shared.Empty
+	17, // 31: shared.MagicMath.GetSubCount:input_type -> shared.Empty
+	17, // 32: shared.MagicMath.GetMinCount:input_type -> shared.Empty
+	17, // 33: shared.MagicMath.GetMaxCount:input_type -> shared.Empty
+	17, // 34: shared.MagicMath.GetMulCount:input_type -> shared.Empty
+	17, // 35: shared.MagicMath.GetDivCount:input_type -> shared.Empty
+	17, // 36: shared.MagicMath.GetModCount:input_type -> shared.Empty
+	17, // 37: shared.MagicMath.GetPowCount:input_type -> shared.Empty
+	17, // 38: shared.MagicMath.GetStatsCount:input_type -> shared.Empty
+	17, // 39: shared.MagicMath.GetBigCount:input_type -> shared.Empty
+	17, // 40: shared.MagicMath.GetDecimalCount:input_type -> shared.Empty
+	17, // 41: shared.MagicMath.GetEvalCount:input_type -> shared.Empty
+	17, // 42: shared.MagicMath.GetPanicCount:input_type -> shared.Empty
+	17, // 43: shared.MagicMath.GetCacheStats:input_type -> shared.Empty
+	17, // 44: shared.MagicMath.GetQuota:input_type -> shared.Empty
+	1,  // 45: shared.MagicMath.MagicAdd:output_type -> shared.DoubleResult
+	1,  // 46: shared.MagicMath.MagicSubtract:output_type -> shared.DoubleResult
+	3,  // 47: shared.MagicMath.MagicFindMin:output_type -> shared.IntResult
+	3,  // 48: shared.MagicMath.MagicFindMax:output_type -> shared.IntResult
+	6,  // 49: shared.MagicMath.MagicMultiply:output_type -> shared.ArithmeticResult
+	6,  // 50: shared.MagicMath.MagicDivide:output_type -> shared.ArithmeticResult
+	6,  // 51: shared.MagicMath.MagicModulo:output_type -> shared.ArithmeticResult
+	6,  // 52: shared.MagicMath.MagicPower:output_type -> shared.ArithmeticResult
+	8,  // 53: shared.MagicMath.MagicBigAdd:output_type -> shared.BigResult
+	8,  // 54: shared.MagicMath.MagicBigSubtract:output_type -> shared.BigResult
+	8,  // 55: shared.MagicMath.MagicBigMultiply:output_type -> shared.BigResult
+	8,  // 56: shared.MagicMath.MagicBigDivide:output_type -> shared.BigResult
+	8,  // 57: shared.MagicMath.MagicBigPower:output_type -> shared.BigResult
+	12, // 58: shared.MagicMath.MagicDecimalAdd:output_type -> shared.DecimalResult
+	12, // 59: shared.MagicMath.MagicDecimalSubtract:output_type -> shared.DecimalResult
+	12, // 60: shared.MagicMath.MagicDecimalMultiply:output_type -> shared.DecimalResult
+	12, // 61: shared.MagicMath.MagicDecimalDivide:output_type -> shared.DecimalResult
+	1,  // 62: shared.MagicMath.MagicEvaluate:output_type -> shared.DoubleResult
+	15, // 63: shared.MagicMath.MagicStats:output_type -> shared.Stats
+	15, // 64: shared.MagicMath.MagicStatsStream:output_type -> shared.Stats
+	18, // 65: shared.MagicMath.GetAddCount:output_type -> shared.Count
+	18, // 66: shared.MagicMath.GetSubCount:output_type -> shared.Count
+	18, // 67: shared.MagicMath.GetMinCount:output_type -> shared.Count
+	18, // 68: shared.MagicMath.GetMaxCount:output_type -> shared.Count
+	18, // 69: shared.MagicMath.GetMulCount:output_type -> shared.Count
+	18, // 70: shared.MagicMath.GetDivCount:output_type -> shared.Count
+	18, // 71: shared.MagicMath.GetModCount:output_type -> shared.Count
+	18, // 72: shared.MagicMath.GetPowCount:output_type -> shared.Count
+	18, // 73: shared.MagicMath.GetStatsCount:output_type -> shared.Count
+	18, // 74: shared.MagicMath.GetBigCount:output_type -> shared.Count
+	18, // 75: shared.MagicMath.GetDecimalCount:output_type -> shared.Count
+	18, // 76: shared.MagicMath.GetEvalCount:output_type -> shared.Count
+	18, // 77: shared.MagicMath.GetPanicCount:output_type -> shared.Count
+	19, // 78: shared.MagicMath.GetCacheStats:output_type -> shared.CacheStats
+	20, // 79: shared.MagicMath.GetQuota:output_type -> shared.Quota
+	45, // [45:80] is the sub-list for method output_type
+	10, // [10:45] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_magicMath_magic_math_proto_init() }
@@ -380,13 +1625,27 @@ func file_magicMath_magic_math_proto_init() {
 	if File_magicMath_magic_math_proto != nil {
 		return
 	}
+	file_magicMath_validation_proto_init()
+	file_magicMath_magic_math_proto_msgTypes[5].OneofWrappers = []any{
+		(*ArithmeticTerms_Doubles)(nil),
+		(*ArithmeticTerms_Ints)(nil),
+	}
+	file_magicMath_magic_math_proto_msgTypes[6].OneofWrappers = []any{
+		(*ArithmeticResult_DoubleResult)(nil),
+		(*ArithmeticResult_IntResult)(nil),
+	}
+	file_magicMath_magic_math_proto_msgTypes[9].OneofWrappers = []any{
+		(*Decimal_Text)(nil),
+		(*Decimal_Scaled)(nil),
+	}
+	file_magicMath_magic_math_proto_msgTypes[11].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_magicMath_magic_math_proto_rawDesc), len(file_magicMath_magic_math_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,30 +19,109 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MagicMath_MagicAdd_FullMethodName      = "/shared.MagicMath/MagicAdd"
-	MagicMath_MagicSubtract_FullMethodName = "/shared.MagicMath/MagicSubtract"
-	MagicMath_MagicFindMin_FullMethodName  = "/shared.MagicMath/MagicFindMin"
-	MagicMath_MagicFindMax_FullMethodName  = "/shared.MagicMath/MagicFindMax"
-	MagicMath_GetAddCount_FullMethodName   = "/shared.MagicMath/GetAddCount"
-	MagicMath_GetSubCount_FullMethodName   = "/shared.MagicMath/GetSubCount"
-	MagicMath_GetMinCount_FullMethodName   = "/shared.MagicMath/GetMinCount"
-	MagicMath_GetMaxCount_FullMethodName   = "/shared.MagicMath/GetMaxCount"
+	MagicMath_MagicAdd_FullMethodName             = "/shared.MagicMath/MagicAdd"
+	MagicMath_MagicSubtract_FullMethodName        = "/shared.MagicMath/MagicSubtract"
+	MagicMath_MagicFindMin_FullMethodName         = "/shared.MagicMath/MagicFindMin"
+	MagicMath_MagicFindMax_FullMethodName         = "/shared.MagicMath/MagicFindMax"
+	MagicMath_MagicMultiply_FullMethodName        = "/shared.MagicMath/MagicMultiply"
+	MagicMath_MagicDivide_FullMethodName          = "/shared.MagicMath/MagicDivide"
+	MagicMath_MagicModulo_FullMethodName          = "/shared.MagicMath/MagicModulo"
+	MagicMath_MagicPower_FullMethodName           = "/shared.MagicMath/MagicPower"
+	MagicMath_MagicBigAdd_FullMethodName          = "/shared.MagicMath/MagicBigAdd"
+	MagicMath_MagicBigSubtract_FullMethodName     = "/shared.MagicMath/MagicBigSubtract"
+	MagicMath_MagicBigMultiply_FullMethodName     = "/shared.MagicMath/MagicBigMultiply"
+	MagicMath_MagicBigDivide_FullMethodName       = "/shared.MagicMath/MagicBigDivide"
+	MagicMath_MagicBigPower_FullMethodName        = "/shared.MagicMath/MagicBigPower"
+	MagicMath_MagicDecimalAdd_FullMethodName      = "/shared.MagicMath/MagicDecimalAdd"
+	MagicMath_MagicDecimalSubtract_FullMethodName = "/shared.MagicMath/MagicDecimalSubtract"
+	MagicMath_MagicDecimalMultiply_FullMethodName = "/shared.MagicMath/MagicDecimalMultiply"
+	MagicMath_MagicDecimalDivide_FullMethodName   = "/shared.MagicMath/MagicDecimalDivide"
+	MagicMath_MagicEvaluate_FullMethodName        = "/shared.MagicMath/MagicEvaluate"
+	MagicMath_MagicStats_FullMethodName           = "/shared.MagicMath/MagicStats"
+	MagicMath_MagicStatsStream_FullMethodName     = "/shared.MagicMath/MagicStatsStream"
+	MagicMath_GetAddCount_FullMethodName          = "/shared.MagicMath/GetAddCount"
+	MagicMath_GetSubCount_FullMethodName          = "/shared.MagicMath/GetSubCount"
+	MagicMath_GetMinCount_FullMethodName          = "/shared.MagicMath/GetMinCount"
+	MagicMath_GetMaxCount_FullMethodName          = "/shared.MagicMath/GetMaxCount"
+	MagicMath_GetMulCount_FullMethodName          = "/shared.MagicMath/GetMulCount"
+	MagicMath_GetDivCount_FullMethodName          = "/shared.MagicMath/GetDivCount"
+	MagicMath_GetModCount_FullMethodName          = "/shared.MagicMath/GetModCount"
+	MagicMath_GetPowCount_FullMethodName          = "/shared.MagicMath/GetPowCount"
+	MagicMath_GetStatsCount_FullMethodName        = "/shared.MagicMath/GetStatsCount"
+	MagicMath_GetBigCount_FullMethodName          = "/shared.MagicMath/GetBigCount"
+	MagicMath_GetDecimalCount_FullMethodName      = "/shared.MagicMath/GetDecimalCount"
+	MagicMath_GetEvalCount_FullMethodName         = "/shared.MagicMath/GetEvalCount"
+	MagicMath_GetPanicCount_FullMethodName        = "/shared.MagicMath/GetPanicCount"
+	MagicMath_GetCacheStats_FullMethodName        = "/shared.MagicMath/GetCacheStats"
+	MagicMath_GetQuota_FullMethodName             = "/shared.MagicMath/GetQuota"
 )
 
 // MagicMathClient is the client API for MagicMath service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The google.api.http options map each remote function to an HTTP route, which the REST gateway serves as JSON.
 type MagicMathClient interface {
 	// These four remote functions are what the client will use to call the server to do the math.
 	MagicAdd(ctx context.Context, in *DoubleTerms, opts ...grpc.CallOption) (*DoubleResult, error)
 	MagicSubtract(ctx context.Context, in *DoubleTerms, opts ...grpc.CallOption) (*DoubleResult, error)
 	MagicFindMin(ctx context.Context, in *IntTerms, opts ...grpc.CallOption) (*IntResult, error)
 	MagicFindMax(ctx context.Context, in *IntTerms, opts ...grpc.CallOption) (*IntResult, error)
+	// These four remote functions work on either two doubles or two integers, and return a result of the same type.
+	// Division by zero, results which are not a number, and integer powers with a negative exponent fail with
+	// INVALID_ARGUMENT, and results which overflow fail with OUT_OF_RANGE.
+	MagicMultiply(ctx context.Context, in *ArithmeticTerms, opts ...grpc.CallOption) (*ArithmeticResult, error)
+	MagicDivide(ctx context.Context, in *ArithmeticTerms, opts ...grpc.CallOption) (*ArithmeticResult, error)
+	MagicModulo(ctx context.Context, in *ArithmeticTerms, opts ...grpc.CallOption) (*ArithmeticResult, error)
+	MagicPower(ctx context.Context, in *ArithmeticTerms, opts ...grpc.CallOption) (*ArithmeticResult, error)
+	// These five remote functions do arbitrary precision math on numbers written as decimal strings, as integers,
+	// exact rationals or floats with a chosen precision and rounding mode. Operands, exponents, precisions and results
+	// over the server's limits fail with INVALID_ARGUMENT or OUT_OF_RANGE, like the other arithmetic functions.
+	MagicBigAdd(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error)
+	MagicBigSubtract(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error)
+	MagicBigMultiply(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error)
+	MagicBigDivide(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error)
+	MagicBigPower(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error)
+	// These four remote functions do exact decimal math, so 0.1 plus 0.2 is 0.3. Malformed terms fail with
+	// INVALID_ARGUMENT and a BadRequest detail naming each bad field.
+	MagicDecimalAdd(ctx context.Context, in *DecimalTerms, opts ...grpc.CallOption) (*DecimalResult, error)
+	MagicDecimalSubtract(ctx context.Context, in *DecimalTerms, opts ...grpc.CallOption) (*DecimalResult, error)
+	MagicDecimalMultiply(ctx context.Context, in *DecimalTerms, opts ...grpc.CallOption) (*DecimalResult, error)
+	MagicDecimalDivide(ctx context.Context, in *DecimalTerms, opts ...grpc.CallOption) (*DecimalResult, error)
+	// This remote function works out an arithmetic expression such as "min(price, 100) * (1 + tax)" in one call.
+	// Expressions which don't parse fail with INVALID_ARGUMENT, and expressions without a value, for example because
+	// they divide by zero, fail like MagicDivide does. Both have an ErrorInfo detail with the position of the problem.
+	MagicEvaluate(ctx context.Context, in *Expression, opts ...grpc.CallOption) (*DoubleResult, error)
+	// These two remote functions return summary statistics of a dataset: MagicStats for a dataset sent in one message,
+	// and MagicStatsStream for a large dataset sent in many messages. Quantiles are exact for up to 10000 values, and
+	// estimated with a t-digest sketch for larger datasets.
+	MagicStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*Stats, error)
+	MagicStatsStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StatsRequest, Stats], error)
 	// These four remote functions will be used by the client to get the counters from the server.
 	GetAddCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
 	GetSubCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
 	GetMinCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
 	GetMaxCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// These four remote functions return the counters of the arithmetic functions.
+	GetMulCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	GetDivCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	GetModCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	GetPowCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// This remote function returns how many times MagicStats and MagicStatsStream have been called altogether.
+	GetStatsCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// This remote function returns how many times the five arbitrary precision functions have been called altogether.
+	GetBigCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// This remote function returns how many times the four decimal functions have been called altogether.
+	GetDecimalCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// This remote function returns how many times MagicEvaluate has been called.
+	GetEvalCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// This remote function returns how many calls have failed because the server panicked while handling them.
+	GetPanicCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// This remote function returns how many calls have been answered from the result cache, and how many cacheable calls
+	// had to be worked out. Both are zero if the server doesn't cache results.
+	GetCacheStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CacheStats, error)
+	// This remote function tells the client how much of its hourly and daily call quotas it has used.
+	GetQuota(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Quota, error)
 }
 
 type magicMathClient struct {
@@ -93,6 +172,169 @@ func (c *magicMathClient) MagicFindMax(ctx context.Context, in *IntTerms, opts .
 	return out, nil
 }
 
+func (c *magicMathClient) MagicMultiply(ctx context.Context, in *ArithmeticTerms, opts ...grpc.CallOption) (*ArithmeticResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArithmeticResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicMultiply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicDivide(ctx context.Context, in *ArithmeticTerms, opts ...grpc.CallOption) (*ArithmeticResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArithmeticResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicDivide_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicModulo(ctx context.Context, in *ArithmeticTerms, opts ...grpc.CallOption) (*ArithmeticResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArithmeticResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicModulo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicPower(ctx context.Context, in *ArithmeticTerms, opts ...grpc.CallOption) (*ArithmeticResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArithmeticResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicPower_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicBigAdd(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BigResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicBigAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicBigSubtract(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BigResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicBigSubtract_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicBigMultiply(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BigResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicBigMultiply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicBigDivide(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BigResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicBigDivide_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicBigPower(ctx context.Context, in *BigTerms, opts ...grpc.CallOption) (*BigResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BigResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicBigPower_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicDecimalAdd(ctx context.Context, in *DecimalTerms, opts ...grpc.CallOption) (*DecimalResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecimalResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicDecimalAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicDecimalSubtract(ctx context.Context, in *DecimalTerms, opts ...grpc.CallOption) (*DecimalResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecimalResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicDecimalSubtract_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicDecimalMultiply(ctx context.Context, in *DecimalTerms, opts ...grpc.CallOption) (*DecimalResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecimalResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicDecimalMultiply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicDecimalDivide(ctx context.Context, in *DecimalTerms, opts ...grpc.CallOption) (*DecimalResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecimalResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicDecimalDivide_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicEvaluate(ctx context.Context, in *Expression, opts ...grpc.CallOption) (*DoubleResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DoubleResult)
+	err := c.cc.Invoke(ctx, MagicMath_MagicEvaluate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*Stats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, MagicMath_MagicStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) MagicStatsStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StatsRequest, Stats], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MagicMath_ServiceDesc.Streams[0], MagicMath_MagicStatsStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StatsRequest, Stats]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MagicMath_MagicStatsStreamClient = grpc.ClientStreamingClient[StatsRequest, Stats]
+
 func (c *magicMathClient) GetAddCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Count)
@@ -133,20 +375,182 @@ func (c *magicMathClient) GetMaxCount(ctx context.Context, in *Empty, opts ...gr
 	return out, nil
 }
 
+func (c *magicMathClient) GetMulCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetMulCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetDivCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetDivCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetModCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetModCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetPowCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetPowCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetStatsCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetStatsCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetBigCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetBigCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetDecimalCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetDecimalCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetEvalCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetEvalCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetPanicCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, MagicMath_GetPanicCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetCacheStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CacheStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CacheStats)
+	err := c.cc.Invoke(ctx, MagicMath_GetCacheStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetQuota(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Quota, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Quota)
+	err := c.cc.Invoke(ctx, MagicMath_GetQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MagicMathServer is the server API for MagicMath service.
 // All implementations must embed UnimplementedMagicMathServer
 // for forward compatibility.
+//
+// The google.api.http options map each remote function to an HTTP route, which the REST gateway serves as JSON.
 type MagicMathServer interface {
 	// These four remote functions are what the client will use to call the server to do the math.
 	MagicAdd(context.Context, *DoubleTerms) (*DoubleResult, error)
 	MagicSubtract(context.Context, *DoubleTerms) (*DoubleResult, error)
 	MagicFindMin(context.Context, *IntTerms) (*IntResult, error)
 	MagicFindMax(context.Context, *IntTerms) (*IntResult, error)
+	// These four remote functions work on either two doubles or two integers, and return a result of the same type.
+	// Division by zero, results which are not a number, and integer powers with a negative exponent fail with
+	// INVALID_ARGUMENT, and results which overflow fail with OUT_OF_RANGE.
+	MagicMultiply(context.Context, *ArithmeticTerms) (*ArithmeticResult, error)
+	MagicDivide(context.Context, *ArithmeticTerms) (*ArithmeticResult, error)
+	MagicModulo(context.Context, *ArithmeticTerms) (*ArithmeticResult, error)
+	MagicPower(context.Context, *ArithmeticTerms) (*ArithmeticResult, error)
+	// These five remote functions do arbitrary precision math on numbers written as decimal strings, as integers,
+	// exact rationals or floats with a chosen precision and rounding mode. Operands, exponents, precisions and results
+	// over the server's limits fail with INVALID_ARGUMENT or OUT_OF_RANGE, like the other arithmetic functions.
+	MagicBigAdd(context.Context, *BigTerms) (*BigResult, error)
+	MagicBigSubtract(context.Context, *BigTerms) (*BigResult, error)
+	MagicBigMultiply(context.Context, *BigTerms) (*BigResult, error)
+	MagicBigDivide(context.Context, *BigTerms) (*BigResult, error)
+	MagicBigPower(context.Context, *BigTerms) (*BigResult, error)
+	// These four remote functions do exact decimal math, so 0.1 plus 0.2 is 0.3. Malformed terms fail with
+	// INVALID_ARGUMENT and a BadRequest detail naming each bad field.
+	MagicDecimalAdd(context.Context, *DecimalTerms) (*DecimalResult, error)
+	MagicDecimalSubtract(context.Context, *DecimalTerms) (*DecimalResult, error)
+	MagicDecimalMultiply(context.Context, *DecimalTerms) (*DecimalResult, error)
+	MagicDecimalDivide(context.Context, *DecimalTerms) (*DecimalResult, error)
+	// This remote function works out an arithmetic expression such as "min(price, 100) * (1 + tax)" in one call.
+	// Expressions which don't parse fail with INVALID_ARGUMENT, and expressions without a value, for example because
+	// they divide by zero, fail like MagicDivide does. Both have an ErrorInfo detail with the position of the problem.
+	MagicEvaluate(context.Context, *Expression) (*DoubleResult, error)
+	// These two remote functions return summary statistics of a dataset: MagicStats for a dataset sent in one message,
+	// and MagicStatsStream for a large dataset sent in many messages. Quantiles are exact for up to 10000 values, and
+	// estimated with a t-digest sketch for larger datasets.
+	MagicStats(context.Context, *StatsRequest) (*Stats, error)
+	MagicStatsStream(grpc.ClientStreamingServer[StatsRequest, Stats]) error
 	// These four remote functions will be used by the client to get the counters from the server.
 	GetAddCount(context.Context, *Empty) (*Count, error)
 	GetSubCount(context.Context, *Empty) (*Count, error)
 	GetMinCount(context.Context, *Empty) (*Count, error)
 	GetMaxCount(context.Context, *Empty) (*Count, error)
+	// These four remote functions return the counters of the arithmetic functions.
+	GetMulCount(context.Context, *Empty) (*Count, error)
+	GetDivCount(context.Context, *Empty) (*Count, error)
+	GetModCount(context.Context, *Empty) (*Count, error)
+	GetPowCount(context.Context, *Empty) (*Count, error)
+	// This remote function returns how many times MagicStats and MagicStatsStream have been called altogether.
+	GetStatsCount(context.Context, *Empty) (*Count, error)
+	// This remote function returns how many times the five arbitrary precision functions have been called altogether.
+	GetBigCount(context.Context, *Empty) (*Count, error)
+	// This remote function returns how many times the four decimal functions have been called altogether.
+	GetDecimalCount(context.Context, *Empty) (*Count, error)
+	// This remote function returns how many times MagicEvaluate has been called.
+	GetEvalCount(context.Context, *Empty) (*Count, error)
+	// This remote function returns how many calls have failed because the server panicked while handling them.
+	GetPanicCount(context.Context, *Empty) (*Count, error)
+	// This remote function returns how many calls have been answered from the result cache, and how many cacheable calls
+	// had to be worked out. Both are zero if the server doesn't cache results.
+	GetCacheStats(context.Context, *Empty) (*CacheStats, error)
+	// This remote function tells the client how much of its hourly and daily call quotas it has used.
+	GetQuota(context.Context, *Empty) (*Quota, error)
 	mustEmbedUnimplementedMagicMathServer()
 }
 
@@ -169,6 +573,54 @@ func (UnimplementedMagicMathServer) MagicFindMin(context.Context, *IntTerms) (*I
 func (UnimplementedMagicMathServer) MagicFindMax(context.Context, *IntTerms) (*IntResult, error) {
 	return nil, status.Error(codes.Unimplemented, "method MagicFindMax not implemented")
 }
+func (UnimplementedMagicMathServer) MagicMultiply(context.Context, *ArithmeticTerms) (*ArithmeticResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicMultiply not implemented")
+}
+func (UnimplementedMagicMathServer) MagicDivide(context.Context, *ArithmeticTerms) (*ArithmeticResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicDivide not implemented")
+}
+func (UnimplementedMagicMathServer) MagicModulo(context.Context, *ArithmeticTerms) (*ArithmeticResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicModulo not implemented")
+}
+func (UnimplementedMagicMathServer) MagicPower(context.Context, *ArithmeticTerms) (*ArithmeticResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicPower not implemented")
+}
+func (UnimplementedMagicMathServer) MagicBigAdd(context.Context, *BigTerms) (*BigResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicBigAdd not implemented")
+}
+func (UnimplementedMagicMathServer) MagicBigSubtract(context.Context, *BigTerms) (*BigResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicBigSubtract not implemented")
+}
+func (UnimplementedMagicMathServer) MagicBigMultiply(context.Context, *BigTerms) (*BigResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicBigMultiply not implemented")
+}
+func (UnimplementedMagicMathServer) MagicBigDivide(context.Context, *BigTerms) (*BigResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicBigDivide not implemented")
+}
+func (UnimplementedMagicMathServer) MagicBigPower(context.Context, *BigTerms) (*BigResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicBigPower not implemented")
+}
+func (UnimplementedMagicMathServer) MagicDecimalAdd(context.Context, *DecimalTerms) (*DecimalResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicDecimalAdd not implemented")
+}
+func (UnimplementedMagicMathServer) MagicDecimalSubtract(context.Context, *DecimalTerms) (*DecimalResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicDecimalSubtract not implemented")
+}
+func (UnimplementedMagicMathServer) MagicDecimalMultiply(context.Context, *DecimalTerms) (*DecimalResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicDecimalMultiply not implemented")
+}
+func (UnimplementedMagicMathServer) MagicDecimalDivide(context.Context, *DecimalTerms) (*DecimalResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicDecimalDivide not implemented")
+}
+func (UnimplementedMagicMathServer) MagicEvaluate(context.Context, *Expression) (*DoubleResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicEvaluate not implemented")
+}
+func (UnimplementedMagicMathServer) MagicStats(context.Context, *StatsRequest) (*Stats, error) {
+	return nil, status.Error(codes.Unimplemented, "method MagicStats not implemented")
+}
+func (UnimplementedMagicMathServer) MagicStatsStream(grpc.ClientStreamingServer[StatsRequest, Stats]) error {
+	return status.Error(codes.Unimplemented, "method MagicStatsStream not implemented")
+}
 func (UnimplementedMagicMathServer) GetAddCount(context.Context, *Empty) (*Count, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetAddCount not implemented")
 }
@@ -181,6 +633,39 @@ func (UnimplementedMagicMathServer) GetMinCount(context.Context, *Empty) (*Count
 func (UnimplementedMagicMathServer) GetMaxCount(context.Context, *Empty) (*Count, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetMaxCount not implemented")
 }
+func (UnimplementedMagicMathServer) GetMulCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMulCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetDivCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDivCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetModCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetModCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetPowCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPowCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetStatsCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStatsCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetBigCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBigCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetDecimalCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDecimalCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetEvalCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetEvalCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetPanicCount(context.Context, *Empty) (*Count, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPanicCount not implemented")
+}
+func (UnimplementedMagicMathServer) GetCacheStats(context.Context, *Empty) (*CacheStats, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCacheStats not implemented")
+}
+func (UnimplementedMagicMathServer) GetQuota(context.Context, *Empty) (*Quota, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetQuota not implemented")
+}
 func (UnimplementedMagicMathServer) mustEmbedUnimplementedMagicMathServer() {}
 func (UnimplementedMagicMathServer) testEmbeddedByValue()                   {}
 
@@ -274,86 +759,561 @@ func _MagicMath_MagicFindMax_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MagicMath_GetAddCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _MagicMath_MagicMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArithmeticTerms)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MagicMathServer).GetAddCount(ctx, in)
+		return srv.(MagicMathServer).MagicMultiply(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MagicMath_GetAddCount_FullMethodName,
+		FullMethod: MagicMath_MagicMultiply_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MagicMathServer).GetAddCount(ctx, req.(*Empty))
+		return srv.(MagicMathServer).MagicMultiply(ctx, req.(*ArithmeticTerms))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MagicMath_GetSubCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _MagicMath_MagicDivide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArithmeticTerms)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MagicMathServer).GetSubCount(ctx, in)
+		return srv.(MagicMathServer).MagicDivide(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MagicMath_GetSubCount_FullMethodName,
+		FullMethod: MagicMath_MagicDivide_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MagicMathServer).GetSubCount(ctx, req.(*Empty))
+		return srv.(MagicMathServer).MagicDivide(ctx, req.(*ArithmeticTerms))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MagicMath_GetMinCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _MagicMath_MagicModulo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArithmeticTerms)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MagicMathServer).GetMinCount(ctx, in)
+		return srv.(MagicMathServer).MagicModulo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MagicMath_GetMinCount_FullMethodName,
+		FullMethod: MagicMath_MagicModulo_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MagicMathServer).GetMinCount(ctx, req.(*Empty))
+		return srv.(MagicMathServer).MagicModulo(ctx, req.(*ArithmeticTerms))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MagicMath_GetMaxCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _MagicMath_MagicPower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArithmeticTerms)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MagicMathServer).GetMaxCount(ctx, in)
+		return srv.(MagicMathServer).MagicPower(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MagicMath_GetMaxCount_FullMethodName,
+		FullMethod: MagicMath_MagicPower_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MagicMathServer).GetMaxCount(ctx, req.(*Empty))
+		return srv.(MagicMathServer).MagicPower(ctx, req.(*ArithmeticTerms))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// MagicMath_ServiceDesc is the grpc.ServiceDesc for MagicMath service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var MagicMath_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "shared.MagicMath",
-	HandlerType: (*MagicMathServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
+func _MagicMath_MagicBigAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicBigAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicBigAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicBigAdd(ctx, req.(*BigTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicBigSubtract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicBigSubtract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicBigSubtract_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicBigSubtract(ctx, req.(*BigTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicBigMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicBigMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicBigMultiply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicBigMultiply(ctx, req.(*BigTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicBigDivide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicBigDivide(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicBigDivide_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicBigDivide(ctx, req.(*BigTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicBigPower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicBigPower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicBigPower_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicBigPower(ctx, req.(*BigTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicDecimalAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecimalTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicDecimalAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicDecimalAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicDecimalAdd(ctx, req.(*DecimalTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicDecimalSubtract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecimalTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicDecimalSubtract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicDecimalSubtract_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicDecimalSubtract(ctx, req.(*DecimalTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicDecimalMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecimalTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicDecimalMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicDecimalMultiply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicDecimalMultiply(ctx, req.(*DecimalTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicDecimalDivide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecimalTerms)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicDecimalDivide(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicDecimalDivide_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicDecimalDivide(ctx, req.(*DecimalTerms))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicEvaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Expression)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicEvaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicEvaluate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicEvaluate(ctx, req.(*Expression))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).MagicStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_MagicStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).MagicStats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_MagicStatsStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MagicMathServer).MagicStatsStream(&grpc.GenericServerStream[StatsRequest, Stats]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MagicMath_MagicStatsStreamServer = grpc.ClientStreamingServer[StatsRequest, Stats]
+
+func _MagicMath_GetAddCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetAddCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetAddCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetAddCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetSubCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetSubCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetSubCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetSubCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetMinCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetMinCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetMinCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetMinCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetMaxCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetMaxCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetMaxCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetMaxCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetMulCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetMulCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetMulCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetMulCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetDivCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetDivCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetDivCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetDivCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetModCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetModCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetModCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetModCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetPowCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetPowCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetPowCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetPowCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetStatsCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetStatsCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetStatsCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetStatsCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetBigCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetBigCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetBigCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetBigCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetDecimalCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetDecimalCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetDecimalCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetDecimalCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetEvalCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetEvalCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetEvalCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetEvalCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetPanicCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetPanicCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetPanicCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetPanicCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetCacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetCacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetCacheStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetCacheStats(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetQuota(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MagicMath_ServiceDesc is the grpc.ServiceDesc for MagicMath service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MagicMath_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shared.MagicMath",
+	HandlerType: (*MagicMathServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
 			MethodName: "MagicAdd",
 			Handler:    _MagicMath_MagicAdd_Handler,
 		},
@@ -369,6 +1329,66 @@ var MagicMath_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MagicFindMax",
 			Handler:    _MagicMath_MagicFindMax_Handler,
 		},
+		{
+			MethodName: "MagicMultiply",
+			Handler:    _MagicMath_MagicMultiply_Handler,
+		},
+		{
+			MethodName: "MagicDivide",
+			Handler:    _MagicMath_MagicDivide_Handler,
+		},
+		{
+			MethodName: "MagicModulo",
+			Handler:    _MagicMath_MagicModulo_Handler,
+		},
+		{
+			MethodName: "MagicPower",
+			Handler:    _MagicMath_MagicPower_Handler,
+		},
+		{
+			MethodName: "MagicBigAdd",
+			Handler:    _MagicMath_MagicBigAdd_Handler,
+		},
+		{
+			MethodName: "MagicBigSubtract",
+			Handler:    _MagicMath_MagicBigSubtract_Handler,
+		},
+		{
+			MethodName: "MagicBigMultiply",
+			Handler:    _MagicMath_MagicBigMultiply_Handler,
+		},
+		{
+			MethodName: "MagicBigDivide",
+			Handler:    _MagicMath_MagicBigDivide_Handler,
+		},
+		{
+			MethodName: "MagicBigPower",
+			Handler:    _MagicMath_MagicBigPower_Handler,
+		},
+		{
+			MethodName: "MagicDecimalAdd",
+			Handler:    _MagicMath_MagicDecimalAdd_Handler,
+		},
+		{
+			MethodName: "MagicDecimalSubtract",
+			Handler:    _MagicMath_MagicDecimalSubtract_Handler,
+		},
+		{
+			MethodName: "MagicDecimalMultiply",
+			Handler:    _MagicMath_MagicDecimalMultiply_Handler,
+		},
+		{
+			MethodName: "MagicDecimalDivide",
+			Handler:    _MagicMath_MagicDecimalDivide_Handler,
+		},
+		{
+			MethodName: "MagicEvaluate",
+			Handler:    _MagicMath_MagicEvaluate_Handler,
+		},
+		{
+			MethodName: "MagicStats",
+			Handler:    _MagicMath_MagicStats_Handler,
+		},
 		{
 			MethodName: "GetAddCount",
 			Handler:    _MagicMath_GetAddCount_Handler,
@@ -385,7 +1405,57 @@ var MagicMath_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMaxCount",
 			Handler:    _MagicMath_GetMaxCount_Handler,
 		},
+		{
+			MethodName: "GetMulCount",
+			Handler:    _MagicMath_GetMulCount_Handler,
+		},
+		{
+			MethodName: "GetDivCount",
+			Handler:    _MagicMath_GetDivCount_Handler,
+		},
+		{
+			MethodName: "GetModCount",
+			Handler:    _MagicMath_GetModCount_Handler,
+		},
+		{
+			MethodName: "GetPowCount",
+			Handler:    _MagicMath_GetPowCount_Handler,
+		},
+		{
+			MethodName: "GetStatsCount",
+			Handler:    _MagicMath_GetStatsCount_Handler,
+		},
+		{
+			MethodName: "GetBigCount",
+			Handler:    _MagicMath_GetBigCount_Handler,
+		},
+		{
+			MethodName: "GetDecimalCount",
+			Handler:    _MagicMath_GetDecimalCount_Handler,
+		},
+		{
+			MethodName: "GetEvalCount",
+			Handler:    _MagicMath_GetEvalCount_Handler,
+		},
+		{
+			MethodName: "GetPanicCount",
+			Handler:    _MagicMath_GetPanicCount_Handler,
+		},
+		{
+			MethodName: "GetCacheStats",
+			Handler:    _MagicMath_GetCacheStats_Handler,
+		},
+		{
+			MethodName: "GetQuota",
+			Handler:    _MagicMath_GetQuota_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "MagicStatsStream",
+			Handler:       _MagicMath_MagicStatsStream_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "magicMath/magic_math.proto",
 }
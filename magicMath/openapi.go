@@ -0,0 +1,64 @@
+package magicMath
+
+import (
+	_ "embed"
+	"encoding/json"
+	"slices"
+)
+
+//go:embed magic_math.swagger.json
+var magicMathOpenAPI []byte
+
+//go:embed vector_math.swagger.json
+var vectorMathOpenAPI []byte
+
+//go:embed operations.swagger.json
+var operationsOpenAPI []byte
+
+//go:embed v2/magic_math.swagger.json
+var magicMathV2OpenAPI []byte
+
+// OpenAPI is the OpenAPI (Swagger 2.0) document describing the REST gateway's routes.
+// It is generated from the google.api.http options in the proto files by protoc-gen-openapiv2, which writes one document
+// per file, so the documents are merged into one here.
+var OpenAPI = mergeOpenAPI(magicMathOpenAPI, vectorMathOpenAPI, operationsOpenAPI, magicMathV2OpenAPI)
+
+// This function merges OpenAPI documents into the first one, by adding the tags, paths and definitions of the others
+// to it. The generated documents can't conflict, as protoc would reject two messages or routes with the same name,
+// but both versions of MagicMath are tagged "MagicMath", so each tag is only added once.
+func mergeOpenAPI(first []byte, others ...[]byte) []byte {
+	var merged map[string]any
+	if err := json.Unmarshal(first, &merged); err != nil {
+		panic(err)
+	}
+	merged["info"] = map[string]any{"title": "MagicMath", "version": "v1"}
+
+	for _, other := range others {
+		var document map[string]any
+		if err := json.Unmarshal(other, &document); err != nil {
+			panic(err)
+		}
+		for _, tag := range document["tags"].([]any) {
+			if !slices.ContainsFunc(merged["tags"].([]any), func(existing any) bool {
+				return existing.(map[string]any)["name"] == tag.(map[string]any)["name"]
+			}) {
+				merged["tags"] = append(merged["tags"].([]any), tag)
+			}
+		}
+		for _, section := range []string{"paths", "definitions"} {
+			if merged[section] == nil {
+				merged[section] = map[string]any{}
+			}
+			for name, value := range document[section].(map[string]any) {
+				merged[section].(map[string]any)[name] = value
+			}
+		}
+	}
+
+	document, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	return document
+}
@@ -0,0 +1,540 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: magicMath/operations.proto
+
+package magicMath
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubmitRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The name of the MagicMath method to call, for example "MagicStats" or "MagicBigPower".
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// The method's request message, for example a shared.StatsRequest.
+	Request       *anypb.Any `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitRequest) Reset() {
+	*x = SubmitRequest{}
+	mi := &file_magicMath_operations_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitRequest) ProtoMessage() {}
+
+func (x *SubmitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_operations_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitRequest.ProtoReflect.Descriptor instead.
+func (*SubmitRequest) Descriptor() ([]byte, []int) {
+	return file_magicMath_operations_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubmitRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *SubmitRequest) GetRequest() *anypb.Any {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type Operation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The operation's name, "operations/" followed by its ID.
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// Done is false while the operation is waiting or running, and true once it has a response or an error.
+	Done bool `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*Operation_Error
+	//	*Operation_Response
+	Result     isOperation_Result     `protobuf_oneof:"result"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=createTime,proto3" json:"createTime,omitempty"`
+	// When the operation finished, only set once it is done.
+	EndTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	// Who submitted the operation, as the server identifies callers. Other callers can't see, wait for or cancel it.
+	Caller        string `protobuf:"bytes,8,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_magicMath_operations_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_operations_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_magicMath_operations_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Operation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Operation) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Operation) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *Operation) GetResult() isOperation_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Operation) GetError() *status.Status {
+	if x != nil {
+		if x, ok := x.Result.(*Operation_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *Operation) GetResponse() *anypb.Any {
+	if x != nil {
+		if x, ok := x.Result.(*Operation_Response); ok {
+			return x.Response
+		}
+	}
+	return nil
+}
+
+func (x *Operation) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *Operation) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *Operation) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+type isOperation_Result interface {
+	isOperation_Result()
+}
+
+type Operation_Error struct {
+	// The error of an operation which failed or was cancelled.
+	Error *status.Status `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+type Operation_Response struct {
+	// The response of the method, for example a shared.Stats.
+	Response *anypb.Any `protobuf:"bytes,5,opt,name=response,proto3,oneof"`
+}
+
+func (*Operation_Error) isOperation_Result() {}
+
+func (*Operation_Response) isOperation_Result() {}
+
+type OperationName struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationName) Reset() {
+	*x = OperationName{}
+	mi := &file_magicMath_operations_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationName) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationName) ProtoMessage() {}
+
+func (x *OperationName) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_operations_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationName.ProtoReflect.Descriptor instead.
+func (*OperationName) Descriptor() ([]byte, []int) {
+	return file_magicMath_operations_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OperationName) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type WaitOperationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// How long to wait at most. Defaults to, and can't be longer than, the server's maximum wait.
+	Timeout       *durationpb.Duration `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WaitOperationRequest) Reset() {
+	*x = WaitOperationRequest{}
+	mi := &file_magicMath_operations_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitOperationRequest) ProtoMessage() {}
+
+func (x *WaitOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_operations_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitOperationRequest.ProtoReflect.Descriptor instead.
+func (*WaitOperationRequest) Descriptor() ([]byte, []int) {
+	return file_magicMath_operations_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WaitOperationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WaitOperationRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type ListOperationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The most operations to return. Defaults to, and can't be more than, 100.
+	PageSize int32 `protobuf:"zigzag32,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	// The nextPageToken of the previous page, or empty for the first page.
+	PageToken     string `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOperationsRequest) Reset() {
+	*x = ListOperationsRequest{}
+	mi := &file_magicMath_operations_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOperationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperationsRequest) ProtoMessage() {}
+
+func (x *ListOperationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_operations_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperationsRequest.ProtoReflect.Descriptor instead.
+func (*ListOperationsRequest) Descriptor() ([]byte, []int) {
+	return file_magicMath_operations_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListOperationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOperationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListOperationsResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Operations []*Operation           `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	// Pass this to get the next page. Empty if there are no more operations.
+	NextPageToken string `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOperationsResponse) Reset() {
+	*x = ListOperationsResponse{}
+	mi := &file_magicMath_operations_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOperationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperationsResponse) ProtoMessage() {}
+
+func (x *ListOperationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_operations_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperationsResponse.ProtoReflect.Descriptor instead.
+func (*ListOperationsResponse) Descriptor() ([]byte, []int) {
+	return file_magicMath_operations_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListOperationsResponse) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *ListOperationsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_magicMath_operations_proto protoreflect.FileDescriptor
+
+const file_magicMath_operations_proto_rawDesc = "" +
+	"\n" +
+	"\x1amagicMath/operations.proto\x12\x06shared\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/protobuf/any.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17google/rpc/status.proto\"W\n" +
+	"\rSubmitRequest\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12.\n" +
+	"\arequest\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\arequest\"\xbf\x02\n" +
+	"\tOperation\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x12\n" +
+	"\x04done\x18\x03 \x01(\bR\x04done\x12*\n" +
+	"\x05error\x18\x04 \x01(\v2\x12.google.rpc.StatusH\x00R\x05error\x122\n" +
+	"\bresponse\x18\x05 \x01(\v2\x14.google.protobuf.AnyH\x00R\bresponse\x12:\n" +
+	"\n" +
+	"createTime\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\x124\n" +
+	"\aendTime\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x16\n" +
+	"\x06caller\x18\b \x01(\tR\x06callerB\b\n" +
+	"\x06result\"#\n" +
+	"\rOperationName\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"_\n" +
+	"\x14WaitOperationRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x123\n" +
+	"\atimeout\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"Q\n" +
+	"\x15ListOperationsRequest\x12\x1a\n" +
+	"\bpageSize\x18\x01 \x01(\x11R\bpageSize\x12\x1c\n" +
+	"\tpageToken\x18\x02 \x01(\tR\tpageToken\"q\n" +
+	"\x16ListOperationsResponse\x121\n" +
+	"\n" +
+	"operations\x18\x01 \x03(\v2\x11.shared.OperationR\n" +
+	"operations\x12$\n" +
+	"\rnextPageToken\x18\x02 \x01(\tR\rnextPageToken2\xf2\x03\n" +
+	"\n" +
+	"Operations\x12M\n" +
+	"\x06Submit\x12\x15.shared.SubmitRequest\x1a\x11.shared.Operation\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/operations\x12Y\n" +
+	"\fGetOperation\x12\x15.shared.OperationName\x1a\x11.shared.Operation\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/{name=operations/*}\x12i\n" +
+	"\rWaitOperation\x12\x1c.shared.WaitOperationRequest\x1a\x11.shared.Operation\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/{name=operations/*}:wait\x12f\n" +
+	"\x0fCancelOperation\x12\x15.shared.OperationName\x1a\x11.shared.Operation\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/{name=operations/*}:cancel\x12g\n" +
+	"\x0eListOperations\x12\x1d.shared.ListOperationsRequest\x1a\x1e.shared.ListOperationsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/operationsBBZ@github.com/karldmenzel/go-grpc-client-server/magicMath;magicMathb\x06proto3"
+
+var (
+	file_magicMath_operations_proto_rawDescOnce sync.Once
+	file_magicMath_operations_proto_rawDescData []byte
+)
+
+func file_magicMath_operations_proto_rawDescGZIP() []byte {
+	file_magicMath_operations_proto_rawDescOnce.Do(func() {
+		file_magicMath_operations_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_magicMath_operations_proto_rawDesc), len(file_magicMath_operations_proto_rawDesc)))
+	})
+	return file_magicMath_operations_proto_rawDescData
+}
+
+var file_magicMath_operations_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_magicMath_operations_proto_goTypes = []any{
+	(*SubmitRequest)(nil),          // 0: shared.SubmitRequest
+	(*Operation)(nil),              // 1: shared.Operation
+	(*OperationName)(nil),          // 2: shared.OperationName
+	(*WaitOperationRequest)(nil),   // 3: shared.WaitOperationRequest
+	(*ListOperationsRequest)(nil),  // 4: shared.ListOperationsRequest
+	(*ListOperationsResponse)(nil), // 5: shared.ListOperationsResponse
+	(*anypb.Any)(nil),              // 6: google.protobuf.Any
+	(*status.Status)(nil),          // 7: google.rpc.Status
+	(*timestamppb.Timestamp)(nil),  // 8: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 9: google.protobuf.Duration
+}
+var file_magicMath_operations_proto_depIdxs = []int32{
+	6,  // 0: shared.SubmitRequest.request:type_name -> google.protobuf.Any
+	7,  // 1: shared.Operation.error:type_name -> google.rpc.Status
+	6,  // 2: shared.Operation.response:type_name -> google.protobuf.Any
+	8,  // 3: shared.Operation.createTime:type_name -> google.protobuf.Timestamp
+	8,  // 4: shared.Operation.endTime:type_name -> google.protobuf.Timestamp
+	9,  // 5: shared.WaitOperationRequest.timeout:type_name -> google.protobuf.Duration
+	1,  // 6: shared.ListOperationsResponse.operations:type_name -> shared.Operation
+	0,  // 7: shared.Operations.Submit:input_type -> shared.SubmitRequest
+	2,  // 8: shared.Operations.GetOperation:input_type -> shared.OperationName
+	3,  // 9: shared.Operations.WaitOperation:input_type -> shared.WaitOperationRequest
+	2,  // 10: shared.Operations.CancelOperation:input_type -> shared.OperationName
+	4,  // 11: shared.Operations.ListOperations:input_type -> shared.ListOperationsRequest
+	1,  // 12: shared.Operations.Submit:output_type -> shared.Operation
+	1,  // 13: shared.Operations.GetOperation:output_type -> shared.Operation
+	1,  // 14: shared.Operations.WaitOperation:output_type -> shared.Operation
+	1,  // 15: shared.Operations.CancelOperation:output_type -> shared.Operation
+	5,  // 16: shared.Operations.ListOperations:output_type -> shared.ListOperationsResponse
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_magicMath_operations_proto_init() }
+func file_magicMath_operations_proto_init() {
+	if File_magicMath_operations_proto != nil {
+		return
+	}
+	file_magicMath_operations_proto_msgTypes[1].OneofWrappers = []any{
+		(*Operation_Error)(nil),
+		(*Operation_Response)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_magicMath_operations_proto_rawDesc), len(file_magicMath_operations_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_magicMath_operations_proto_goTypes,
+		DependencyIndexes: file_magicMath_operations_proto_depIdxs,
+		MessageInfos:      file_magicMath_operations_proto_msgTypes,
+	}.Build()
+	File_magicMath_operations_proto = out.File
+	file_magicMath_operations_proto_goTypes = nil
+	file_magicMath_operations_proto_depIdxs = nil
+}
@@ -0,0 +1,556 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: magicMath/v2/magic_math.proto
+
+package magicmathv2
+
+import (
+	_ "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Operation int32
+
+const (
+	Operation_OPERATION_UNSPECIFIED Operation = 0
+	// Add, multiply, min and max take two or more operands.
+	Operation_OPERATION_ADD      Operation = 1
+	Operation_OPERATION_MULTIPLY Operation = 2
+	Operation_OPERATION_MIN      Operation = 3
+	Operation_OPERATION_MAX      Operation = 4
+	// Subtract, divide, modulo and power take exactly two operands. Integer division rounds towards zero, and the
+	// remainder has the same sign as the first operand.
+	Operation_OPERATION_SUBTRACT Operation = 5
+	Operation_OPERATION_DIVIDE   Operation = 6
+	Operation_OPERATION_MODULO   Operation = 7
+	Operation_OPERATION_POWER    Operation = 8
+)
+
+// Enum value maps for Operation.
+var (
+	Operation_name = map[int32]string{
+		0: "OPERATION_UNSPECIFIED",
+		1: "OPERATION_ADD",
+		2: "OPERATION_MULTIPLY",
+		3: "OPERATION_MIN",
+		4: "OPERATION_MAX",
+		5: "OPERATION_SUBTRACT",
+		6: "OPERATION_DIVIDE",
+		7: "OPERATION_MODULO",
+		8: "OPERATION_POWER",
+	}
+	Operation_value = map[string]int32{
+		"OPERATION_UNSPECIFIED": 0,
+		"OPERATION_ADD":         1,
+		"OPERATION_MULTIPLY":    2,
+		"OPERATION_MIN":         3,
+		"OPERATION_MAX":         4,
+		"OPERATION_SUBTRACT":    5,
+		"OPERATION_DIVIDE":      6,
+		"OPERATION_MODULO":      7,
+		"OPERATION_POWER":       8,
+	}
+)
+
+func (x Operation) Enum() *Operation {
+	p := new(Operation)
+	*p = x
+	return p
+}
+
+func (x Operation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Operation) Descriptor() protoreflect.EnumDescriptor {
+	return file_magicMath_v2_magic_math_proto_enumTypes[0].Descriptor()
+}
+
+func (Operation) Type() protoreflect.EnumType {
+	return &file_magicMath_v2_magic_math_proto_enumTypes[0]
+}
+
+func (x Operation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Operation.Descriptor instead.
+func (Operation) EnumDescriptor() ([]byte, []int) {
+	return file_magicMath_v2_magic_math_proto_rawDescGZIP(), []int{0}
+}
+
+type CalculateRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Operation Operation              `protobuf:"varint,1,opt,name=operation,proto3,enum=magicmath.v2.Operation" json:"operation,omitempty"`
+	// Types that are valid to be assigned to Operands:
+	//
+	//	*CalculateRequest_Doubles
+	//	*CalculateRequest_Integers
+	Operands      isCalculateRequest_Operands `protobuf_oneof:"operands"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateRequest) Reset() {
+	*x = CalculateRequest{}
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateRequest) ProtoMessage() {}
+
+func (x *CalculateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateRequest.ProtoReflect.Descriptor instead.
+func (*CalculateRequest) Descriptor() ([]byte, []int) {
+	return file_magicMath_v2_magic_math_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CalculateRequest) GetOperation() Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return Operation_OPERATION_UNSPECIFIED
+}
+
+func (x *CalculateRequest) GetOperands() isCalculateRequest_Operands {
+	if x != nil {
+		return x.Operands
+	}
+	return nil
+}
+
+func (x *CalculateRequest) GetDoubles() *DoubleOperands {
+	if x != nil {
+		if x, ok := x.Operands.(*CalculateRequest_Doubles); ok {
+			return x.Doubles
+		}
+	}
+	return nil
+}
+
+func (x *CalculateRequest) GetIntegers() *IntegerOperands {
+	if x != nil {
+		if x, ok := x.Operands.(*CalculateRequest_Integers); ok {
+			return x.Integers
+		}
+	}
+	return nil
+}
+
+type isCalculateRequest_Operands interface {
+	isCalculateRequest_Operands()
+}
+
+type CalculateRequest_Doubles struct {
+	Doubles *DoubleOperands `protobuf:"bytes,2,opt,name=doubles,proto3,oneof"`
+}
+
+type CalculateRequest_Integers struct {
+	Integers *IntegerOperands `protobuf:"bytes,3,opt,name=integers,proto3,oneof"`
+}
+
+func (*CalculateRequest_Doubles) isCalculateRequest_Operands() {}
+
+func (*CalculateRequest_Integers) isCalculateRequest_Operands() {}
+
+type DoubleOperands struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float64              `protobuf:"fixed64,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DoubleOperands) Reset() {
+	*x = DoubleOperands{}
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DoubleOperands) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DoubleOperands) ProtoMessage() {}
+
+func (x *DoubleOperands) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DoubleOperands.ProtoReflect.Descriptor instead.
+func (*DoubleOperands) Descriptor() ([]byte, []int) {
+	return file_magicMath_v2_magic_math_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DoubleOperands) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type IntegerOperands struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []int64                `protobuf:"zigzag64,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntegerOperands) Reset() {
+	*x = IntegerOperands{}
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntegerOperands) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntegerOperands) ProtoMessage() {}
+
+func (x *IntegerOperands) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntegerOperands.ProtoReflect.Descriptor instead.
+func (*IntegerOperands) Descriptor() ([]byte, []int) {
+	return file_magicMath_v2_magic_math_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *IntegerOperands) GetValues() []int64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type CalculateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*CalculateResponse_DoubleResult
+	//	*CalculateResponse_IntegerResult
+	Result        isCalculateResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateResponse) Reset() {
+	*x = CalculateResponse{}
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateResponse) ProtoMessage() {}
+
+func (x *CalculateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateResponse.ProtoReflect.Descriptor instead.
+func (*CalculateResponse) Descriptor() ([]byte, []int) {
+	return file_magicMath_v2_magic_math_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CalculateResponse) GetResult() isCalculateResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *CalculateResponse) GetDoubleResult() float64 {
+	if x != nil {
+		if x, ok := x.Result.(*CalculateResponse_DoubleResult); ok {
+			return x.DoubleResult
+		}
+	}
+	return 0
+}
+
+func (x *CalculateResponse) GetIntegerResult() int64 {
+	if x != nil {
+		if x, ok := x.Result.(*CalculateResponse_IntegerResult); ok {
+			return x.IntegerResult
+		}
+	}
+	return 0
+}
+
+type isCalculateResponse_Result interface {
+	isCalculateResponse_Result()
+}
+
+type CalculateResponse_DoubleResult struct {
+	DoubleResult float64 `protobuf:"fixed64,1,opt,name=double_result,json=doubleResult,proto3,oneof"`
+}
+
+type CalculateResponse_IntegerResult struct {
+	IntegerResult int64 `protobuf:"zigzag64,2,opt,name=integer_result,json=integerResult,proto3,oneof"`
+}
+
+func (*CalculateResponse_DoubleResult) isCalculateResponse_Result() {}
+
+func (*CalculateResponse_IntegerResult) isCalculateResponse_Result() {}
+
+type GetCountersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The names of the counters to return, such as "add" or "cache_hit". Empty means every counter.
+	Names         []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCountersRequest) Reset() {
+	*x = GetCountersRequest{}
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCountersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCountersRequest) ProtoMessage() {}
+
+func (x *GetCountersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCountersRequest.ProtoReflect.Descriptor instead.
+func (*GetCountersRequest) Descriptor() ([]byte, []int) {
+	return file_magicMath_v2_magic_math_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCountersRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type GetCountersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Counters      map[string]int64       `protobuf:"bytes,1,rep,name=counters,proto3" json:"counters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"zigzag64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCountersResponse) Reset() {
+	*x = GetCountersResponse{}
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCountersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCountersResponse) ProtoMessage() {}
+
+func (x *GetCountersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_v2_magic_math_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCountersResponse.ProtoReflect.Descriptor instead.
+func (*GetCountersResponse) Descriptor() ([]byte, []int) {
+	return file_magicMath_v2_magic_math_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetCountersResponse) GetCounters() map[string]int64 {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
+var File_magicMath_v2_magic_math_proto protoreflect.FileDescriptor
+
+const file_magicMath_v2_magic_math_proto_rawDesc = "" +
+	"\n" +
+	"\x1dmagicMath/v2/magic_math.proto\x12\fmagicmath.v2\x1a\x1cgoogle/api/annotations.proto\x1a\x1amagicMath/validation.proto\"\xcc\x01\n" +
+	"\x10CalculateRequest\x125\n" +
+	"\toperation\x18\x01 \x01(\x0e2\x17.magicmath.v2.OperationR\toperation\x128\n" +
+	"\adoubles\x18\x02 \x01(\v2\x1c.magicmath.v2.DoubleOperandsH\x00R\adoubles\x12;\n" +
+	"\bintegers\x18\x03 \x01(\v2\x1d.magicmath.v2.IntegerOperandsH\x00R\bintegersB\n" +
+	"\n" +
+	"\boperands\"5\n" +
+	"\x0eDoubleOperands\x12#\n" +
+	"\x06values\x18\x01 \x03(\x01B\v\xa2\xbb\x18\a\x18\x01 \x02(\xe8\aR\x06values\"4\n" +
+	"\x0fIntegerOperands\x12!\n" +
+	"\x06values\x18\x01 \x03(\x12B\t\xa2\xbb\x18\x05 \x02(\xe8\aR\x06values\"m\n" +
+	"\x11CalculateResponse\x12%\n" +
+	"\rdouble_result\x18\x01 \x01(\x01H\x00R\fdoubleResult\x12'\n" +
+	"\x0einteger_result\x18\x02 \x01(\x12H\x00R\rintegerResultB\b\n" +
+	"\x06result\"2\n" +
+	"\x12GetCountersRequest\x12\x1c\n" +
+	"\x05names\x18\x01 \x03(\tB\x06\xa2\xbb\x18\x02(dR\x05names\"\x9f\x01\n" +
+	"\x13GetCountersResponse\x12K\n" +
+	"\bcounters\x18\x01 \x03(\v2/.magicmath.v2.GetCountersResponse.CountersEntryR\bcounters\x1a;\n" +
+	"\rCountersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x12R\x05value:\x028\x01*\xd0\x01\n" +
+	"\tOperation\x12\x19\n" +
+	"\x15OPERATION_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rOPERATION_ADD\x10\x01\x12\x16\n" +
+	"\x12OPERATION_MULTIPLY\x10\x02\x12\x11\n" +
+	"\rOPERATION_MIN\x10\x03\x12\x11\n" +
+	"\rOPERATION_MAX\x10\x04\x12\x16\n" +
+	"\x12OPERATION_SUBTRACT\x10\x05\x12\x14\n" +
+	"\x10OPERATION_DIVIDE\x10\x06\x12\x14\n" +
+	"\x10OPERATION_MODULO\x10\a\x12\x13\n" +
+	"\x0fOPERATION_POWER\x10\b2\xdd\x01\n" +
+	"\tMagicMath\x12f\n" +
+	"\tCalculate\x12\x1e.magicmath.v2.CalculateRequest\x1a\x1f.magicmath.v2.CalculateResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v2/calculate\x12h\n" +
+	"\vGetCounters\x12 .magicmath.v2.GetCountersRequest\x1a!.magicmath.v2.GetCountersResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v2/countersBGZEgithub.com/karldmenzel/go-grpc-client-server/magicMath/v2;magicmathv2b\x06proto3"
+
+var (
+	file_magicMath_v2_magic_math_proto_rawDescOnce sync.Once
+	file_magicMath_v2_magic_math_proto_rawDescData []byte
+)
+
+func file_magicMath_v2_magic_math_proto_rawDescGZIP() []byte {
+	file_magicMath_v2_magic_math_proto_rawDescOnce.Do(func() {
+		file_magicMath_v2_magic_math_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_magicMath_v2_magic_math_proto_rawDesc), len(file_magicMath_v2_magic_math_proto_rawDesc)))
+	})
+	return file_magicMath_v2_magic_math_proto_rawDescData
+}
+
+var file_magicMath_v2_magic_math_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_magicMath_v2_magic_math_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_magicMath_v2_magic_math_proto_goTypes = []any{
+	(Operation)(0),              // 0: magicmath.v2.Operation
+	(*CalculateRequest)(nil),    // 1: magicmath.v2.CalculateRequest
+	(*DoubleOperands)(nil),      // 2: magicmath.v2.DoubleOperands
+	(*IntegerOperands)(nil),     // 3: magicmath.v2.IntegerOperands
+	(*CalculateResponse)(nil),   // 4: magicmath.v2.CalculateResponse
+	(*GetCountersRequest)(nil),  // 5: magicmath.v2.GetCountersRequest
+	(*GetCountersResponse)(nil), // 6: magicmath.v2.GetCountersResponse
+	nil,                         // 7: magicmath.v2.GetCountersResponse.CountersEntry
+}
+var file_magicMath_v2_magic_math_proto_depIdxs = []int32{
+	0, // 0: magicmath.v2.CalculateRequest.operation:type_name -> magicmath.v2.Operation
+	2, // 1: magicmath.v2.CalculateRequest.doubles:type_name -> magicmath.v2.DoubleOperands
+	3, // 2: magicmath.v2.CalculateRequest.integers:type_name -> magicmath.v2.IntegerOperands
+	7, // 3: magicmath.v2.GetCountersResponse.counters:type_name -> magicmath.v2.GetCountersResponse.CountersEntry
+	1, // 4: magicmath.v2.MagicMath.Calculate:input_type -> magicmath.v2.CalculateRequest
+	5, // 5: magicmath.v2.MagicMath.GetCounters:input_type -> magicmath.v2.GetCountersRequest
+	4, // 6: magicmath.v2.MagicMath.Calculate:output_type -> magicmath.v2.CalculateResponse
+	6, // 7: magicmath.v2.MagicMath.GetCounters:output_type -> magicmath.v2.GetCountersResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_magicMath_v2_magic_math_proto_init() }
+func file_magicMath_v2_magic_math_proto_init() {
+	if File_magicMath_v2_magic_math_proto != nil {
+		return
+	}
+	file_magicMath_v2_magic_math_proto_msgTypes[0].OneofWrappers = []any{
+		(*CalculateRequest_Doubles)(nil),
+		(*CalculateRequest_Integers)(nil),
+	}
+	file_magicMath_v2_magic_math_proto_msgTypes[3].OneofWrappers = []any{
+		(*CalculateResponse_DoubleResult)(nil),
+		(*CalculateResponse_IntegerResult)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_magicMath_v2_magic_math_proto_rawDesc), len(file_magicMath_v2_magic_math_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_magicMath_v2_magic_math_proto_goTypes,
+		DependencyIndexes: file_magicMath_v2_magic_math_proto_depIdxs,
+		EnumInfos:         file_magicMath_v2_magic_math_proto_enumTypes,
+		MessageInfos:      file_magicMath_v2_magic_math_proto_msgTypes,
+	}.Build()
+	File_magicMath_v2_magic_math_proto = out.File
+	file_magicMath_v2_magic_math_proto_goTypes = nil
+	file_magicMath_v2_magic_math_proto_depIdxs = nil
+}
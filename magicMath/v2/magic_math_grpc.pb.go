@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.1
+// source: magicMath/v2/magic_math.proto
+
+package magicmathv2
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MagicMath_Calculate_FullMethodName   = "/magicmath.v2.MagicMath/Calculate"
+	MagicMath_GetCounters_FullMethodName = "/magicmath.v2.MagicMath/GetCounters"
+)
+
+// MagicMathClient is the client API for MagicMath service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Version 2 of MagicMath, served alongside version 1 by the same server. Instead of a remote function and a message
+// for each operation, every operation is one Calculate call, and every counter is returned by one GetCounters call.
+// Both versions share their counters, so a v2 addition counts towards the v1 GetAddCount too.
+type MagicMathClient interface {
+	// Calculate applies an operation to a list of doubles or a list of integers, and returns a result of the same kind.
+	// Results too large for their kind fail with OUT_OF_RANGE, and every other problem with INVALID_ARGUMENT.
+	Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error)
+	// GetCounters returns how many times each function has been called, through either version.
+	GetCounters(ctx context.Context, in *GetCountersRequest, opts ...grpc.CallOption) (*GetCountersResponse, error)
+}
+
+type magicMathClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMagicMathClient(cc grpc.ClientConnInterface) MagicMathClient {
+	return &magicMathClient{cc}
+}
+
+func (c *magicMathClient) Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalculateResponse)
+	err := c.cc.Invoke(ctx, MagicMath_Calculate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *magicMathClient) GetCounters(ctx context.Context, in *GetCountersRequest, opts ...grpc.CallOption) (*GetCountersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCountersResponse)
+	err := c.cc.Invoke(ctx, MagicMath_GetCounters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MagicMathServer is the server API for MagicMath service.
+// All implementations must embed UnimplementedMagicMathServer
+// for forward compatibility.
+//
+// Version 2 of MagicMath, served alongside version 1 by the same server. Instead of a remote function and a message
+// for each operation, every operation is one Calculate call, and every counter is returned by one GetCounters call.
+// Both versions share their counters, so a v2 addition counts towards the v1 GetAddCount too.
+type MagicMathServer interface {
+	// Calculate applies an operation to a list of doubles or a list of integers, and returns a result of the same kind.
+	// Results too large for their kind fail with OUT_OF_RANGE, and every other problem with INVALID_ARGUMENT.
+	Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error)
+	// GetCounters returns how many times each function has been called, through either version.
+	GetCounters(context.Context, *GetCountersRequest) (*GetCountersResponse, error)
+	mustEmbedUnimplementedMagicMathServer()
+}
+
+// UnimplementedMagicMathServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMagicMathServer struct{}
+
+func (UnimplementedMagicMathServer) Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Calculate not implemented")
+}
+func (UnimplementedMagicMathServer) GetCounters(context.Context, *GetCountersRequest) (*GetCountersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCounters not implemented")
+}
+func (UnimplementedMagicMathServer) mustEmbedUnimplementedMagicMathServer() {}
+func (UnimplementedMagicMathServer) testEmbeddedByValue()                   {}
+
+// UnsafeMagicMathServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MagicMathServer will
+// result in compilation errors.
+type UnsafeMagicMathServer interface {
+	mustEmbedUnimplementedMagicMathServer()
+}
+
+func RegisterMagicMathServer(s grpc.ServiceRegistrar, srv MagicMathServer) {
+	// If the following call panics, it indicates UnimplementedMagicMathServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MagicMath_ServiceDesc, srv)
+}
+
+func _MagicMath_Calculate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).Calculate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_Calculate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).Calculate(ctx, req.(*CalculateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MagicMath_GetCounters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCountersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MagicMathServer).GetCounters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MagicMath_GetCounters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MagicMathServer).GetCounters(ctx, req.(*GetCountersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MagicMath_ServiceDesc is the grpc.ServiceDesc for MagicMath service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MagicMath_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "magicmath.v2.MagicMath",
+	HandlerType: (*MagicMathServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Calculate",
+			Handler:    _MagicMath_Calculate_Handler,
+		},
+		{
+			MethodName: "GetCounters",
+			Handler:    _MagicMath_GetCounters_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "magicMath/v2/magic_math.proto",
+}
@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: magicMath/validation.proto
+
+package magicMath
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FieldRules are the validation rules of a field, which the server checks before a call reaches its handler, so that
+// handlers never see a request which breaks them. Rules on a repeated field apply to each of its elements, apart from
+// minItems and maxItems, which limit how many elements there are.
+type FieldRules struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Numbers must be at least min and at most max. 64 bit integers are compared as doubles.
+	Min *float64 `protobuf:"fixed64,1,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max *float64 `protobuf:"fixed64,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	// Doubles must not be NaN or infinite.
+	Finite bool `protobuf:"varint,3,opt,name=finite,proto3" json:"finite,omitempty"`
+	// Repeated fields must have at least minItems and at most maxItems elements.
+	MinItems *uint32 `protobuf:"varint,4,opt,name=minItems,proto3,oneof" json:"minItems,omitempty"`
+	MaxItems *uint32 `protobuf:"varint,5,opt,name=maxItems,proto3,oneof" json:"maxItems,omitempty"`
+	// Strings must be at most maxLength bytes long.
+	MaxLength *uint32 `protobuf:"varint,6,opt,name=maxLength,proto3,oneof" json:"maxLength,omitempty"`
+	// Message fields must be set.
+	Required      bool `protobuf:"varint,7,opt,name=required,proto3" json:"required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldRules) Reset() {
+	*x = FieldRules{}
+	mi := &file_magicMath_validation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldRules) ProtoMessage() {}
+
+func (x *FieldRules) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_validation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldRules.ProtoReflect.Descriptor instead.
+func (*FieldRules) Descriptor() ([]byte, []int) {
+	return file_magicMath_validation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FieldRules) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *FieldRules) GetFinite() bool {
+	if x != nil {
+		return x.Finite
+	}
+	return false
+}
+
+func (x *FieldRules) GetMinItems() uint32 {
+	if x != nil && x.MinItems != nil {
+		return *x.MinItems
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMaxItems() uint32 {
+	if x != nil && x.MaxItems != nil {
+		return *x.MaxItems
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMaxLength() uint32 {
+	if x != nil && x.MaxLength != nil {
+		return *x.MaxLength
+	}
+	return 0
+}
+
+func (x *FieldRules) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+var file_magicMath_validation_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*FieldRules)(nil),
+		Field:         50100,
+		Name:          "shared.rules",
+		Tag:           "bytes,50100,opt,name=rules",
+		Filename:      "magicMath/validation.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// For example: double termOne = 1 [(rules) = {finite: true}];
+	//
+	// optional shared.FieldRules rules = 50100;
+	E_Rules = &file_magicMath_validation_proto_extTypes[0]
+)
+
+var File_magicMath_validation_proto protoreflect.FileDescriptor
+
+const file_magicMath_validation_proto_rawDesc = "" +
+	"\n" +
+	"\x1amagicMath/validation.proto\x12\x06shared\x1a google/protobuf/descriptor.proto\"\x8b\x02\n" +
+	"\n" +
+	"FieldRules\x12\x15\n" +
+	"\x03min\x18\x01 \x01(\x01H\x00R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\x01H\x01R\x03max\x88\x01\x01\x12\x16\n" +
+	"\x06finite\x18\x03 \x01(\bR\x06finite\x12\x1f\n" +
+	"\bminItems\x18\x04 \x01(\rH\x02R\bminItems\x88\x01\x01\x12\x1f\n" +
+	"\bmaxItems\x18\x05 \x01(\rH\x03R\bmaxItems\x88\x01\x01\x12!\n" +
+	"\tmaxLength\x18\x06 \x01(\rH\x04R\tmaxLength\x88\x01\x01\x12\x1a\n" +
+	"\brequired\x18\a \x01(\bR\brequiredB\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_maxB\v\n" +
+	"\t_minItemsB\v\n" +
+	"\t_maxItemsB\f\n" +
+	"\n" +
+	"_maxLength:I\n" +
+	"\x05rules\x12\x1d.google.protobuf.FieldOptions\x18\xb4\x87\x03 \x01(\v2\x12.shared.FieldRulesR\x05rulesBBZ@github.com/karldmenzel/go-grpc-client-server/magicMath;magicMathb\x06proto3"
+
+var (
+	file_magicMath_validation_proto_rawDescOnce sync.Once
+	file_magicMath_validation_proto_rawDescData []byte
+)
+
+func file_magicMath_validation_proto_rawDescGZIP() []byte {
+	file_magicMath_validation_proto_rawDescOnce.Do(func() {
+		file_magicMath_validation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_magicMath_validation_proto_rawDesc), len(file_magicMath_validation_proto_rawDesc)))
+	})
+	return file_magicMath_validation_proto_rawDescData
+}
+
+var file_magicMath_validation_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_magicMath_validation_proto_goTypes = []any{
+	(*FieldRules)(nil),                // 0: shared.FieldRules
+	(*descriptorpb.FieldOptions)(nil), // 1: google.protobuf.FieldOptions
+}
+var file_magicMath_validation_proto_depIdxs = []int32{
+	1, // 0: shared.rules:extendee -> google.protobuf.FieldOptions
+	0, // 1: shared.rules:type_name -> shared.FieldRules
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_magicMath_validation_proto_init() }
+func file_magicMath_validation_proto_init() {
+	if File_magicMath_validation_proto != nil {
+		return
+	}
+	file_magicMath_validation_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_magicMath_validation_proto_rawDesc), len(file_magicMath_validation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_magicMath_validation_proto_goTypes,
+		DependencyIndexes: file_magicMath_validation_proto_depIdxs,
+		MessageInfos:      file_magicMath_validation_proto_msgTypes,
+		ExtensionInfos:    file_magicMath_validation_proto_extTypes,
+	}.Build()
+	File_magicMath_validation_proto = out.File
+	file_magicMath_validation_proto_goTypes = nil
+	file_magicMath_validation_proto_depIdxs = nil
+}
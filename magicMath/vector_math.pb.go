@@ -0,0 +1,436 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.1
+// source: magicMath/vector_math.proto
+
+package magicMath
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Repeated doubles are packed, so each element takes exactly eight bytes on the wire.
+type Vector struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float64              `protobuf:"fixed64,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vector) Reset() {
+	*x = Vector{}
+	mi := &file_magicMath_vector_math_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vector) ProtoMessage() {}
+
+func (x *Vector) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_vector_math_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vector.ProtoReflect.Descriptor instead.
+func (*Vector) Descriptor() ([]byte, []int) {
+	return file_magicMath_vector_math_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Vector) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type VectorPair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	A             *Vector                `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B             *Vector                `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VectorPair) Reset() {
+	*x = VectorPair{}
+	mi := &file_magicMath_vector_math_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VectorPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VectorPair) ProtoMessage() {}
+
+func (x *VectorPair) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_vector_math_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VectorPair.ProtoReflect.Descriptor instead.
+func (*VectorPair) Descriptor() ([]byte, []int) {
+	return file_magicMath_vector_math_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VectorPair) GetA() *Vector {
+	if x != nil {
+		return x.A
+	}
+	return nil
+}
+
+func (x *VectorPair) GetB() *Vector {
+	if x != nil {
+		return x.B
+	}
+	return nil
+}
+
+type NormRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Vector *Vector                `protobuf:"bytes,1,opt,name=vector,proto3" json:"vector,omitempty"`
+	// One of "l1", "l2" or "max". Empty means "l2".
+	Norm          string `protobuf:"bytes,2,opt,name=norm,proto3" json:"norm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NormRequest) Reset() {
+	*x = NormRequest{}
+	mi := &file_magicMath_vector_math_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NormRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NormRequest) ProtoMessage() {}
+
+func (x *NormRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_vector_math_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NormRequest.ProtoReflect.Descriptor instead.
+func (*NormRequest) Descriptor() ([]byte, []int) {
+	return file_magicMath_vector_math_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NormRequest) GetVector() *Vector {
+	if x != nil {
+		return x.Vector
+	}
+	return nil
+}
+
+func (x *NormRequest) GetNorm() string {
+	if x != nil {
+		return x.Norm
+	}
+	return ""
+}
+
+type Scalar struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Scalar) Reset() {
+	*x = Scalar{}
+	mi := &file_magicMath_vector_math_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Scalar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scalar) ProtoMessage() {}
+
+func (x *Scalar) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_vector_math_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scalar.ProtoReflect.Descriptor instead.
+func (*Scalar) Descriptor() ([]byte, []int) {
+	return file_magicMath_vector_math_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Scalar) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+// A matrix stored row by row, so the element in row i and column j is values[i * columns + j].
+type Matrix struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          int32                  `protobuf:"zigzag32,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Columns       int32                  `protobuf:"zigzag32,2,opt,name=columns,proto3" json:"columns,omitempty"`
+	Values        []float64              `protobuf:"fixed64,3,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Matrix) Reset() {
+	*x = Matrix{}
+	mi := &file_magicMath_vector_math_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Matrix) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Matrix) ProtoMessage() {}
+
+func (x *Matrix) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_vector_math_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Matrix.ProtoReflect.Descriptor instead.
+func (*Matrix) Descriptor() ([]byte, []int) {
+	return file_magicMath_vector_math_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Matrix) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *Matrix) GetColumns() int32 {
+	if x != nil {
+		return x.Columns
+	}
+	return 0
+}
+
+func (x *Matrix) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type MatrixPair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	A             *Matrix                `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B             *Matrix                `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatrixPair) Reset() {
+	*x = MatrixPair{}
+	mi := &file_magicMath_vector_math_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatrixPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatrixPair) ProtoMessage() {}
+
+func (x *MatrixPair) ProtoReflect() protoreflect.Message {
+	mi := &file_magicMath_vector_math_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatrixPair.ProtoReflect.Descriptor instead.
+func (*MatrixPair) Descriptor() ([]byte, []int) {
+	return file_magicMath_vector_math_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MatrixPair) GetA() *Matrix {
+	if x != nil {
+		return x.A
+	}
+	return nil
+}
+
+func (x *MatrixPair) GetB() *Matrix {
+	if x != nil {
+		return x.B
+	}
+	return nil
+}
+
+var File_magicMath_vector_math_proto protoreflect.FileDescriptor
+
+const file_magicMath_vector_math_proto_rawDesc = "" +
+	"\n" +
+	"\x1bmagicMath/vector_math.proto\x12\x06shared\x1a\x1cgoogle/api/annotations.proto\x1a\x1amagicMath/validation.proto\",\n" +
+	"\x06Vector\x12\"\n" +
+	"\x06values\x18\x01 \x03(\x01B\n" +
+	"\xa2\xbb\x18\x06\x18\x01(\x80\x80@R\x06values\"H\n" +
+	"\n" +
+	"VectorPair\x12\x1c\n" +
+	"\x01a\x18\x01 \x01(\v2\x0e.shared.VectorR\x01a\x12\x1c\n" +
+	"\x01b\x18\x02 \x01(\v2\x0e.shared.VectorR\x01b\"I\n" +
+	"\vNormRequest\x12&\n" +
+	"\x06vector\x18\x01 \x01(\v2\x0e.shared.VectorR\x06vector\x12\x12\n" +
+	"\x04norm\x18\x02 \x01(\tR\x04norm\"\x1e\n" +
+	"\x06Scalar\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x01R\x05value\"x\n" +
+	"\x06Matrix\x12!\n" +
+	"\x04rows\x18\x01 \x01(\x11B\r\xa2\xbb\x18\t\t\x00\x00\x00\x00\x00\x00\x00\x00R\x04rows\x12'\n" +
+	"\acolumns\x18\x02 \x01(\x11B\r\xa2\xbb\x18\t\t\x00\x00\x00\x00\x00\x00\x00\x00R\acolumns\x12\"\n" +
+	"\x06values\x18\x03 \x03(\x01B\n" +
+	"\xa2\xbb\x18\x06\x18\x01(\x80\x80@R\x06values\"H\n" +
+	"\n" +
+	"MatrixPair\x12\x1c\n" +
+	"\x01a\x18\x01 \x01(\v2\x0e.shared.MatrixR\x01a\x12\x1c\n" +
+	"\x01b\x18\x02 \x01(\v2\x0e.shared.MatrixR\x01b2\xd5\x03\n" +
+	"\n" +
+	"VectorMath\x12D\n" +
+	"\x03Add\x12\x12.shared.VectorPair\x1a\x0e.shared.Vector\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/vector/add\x12N\n" +
+	"\bSubtract\x12\x12.shared.VectorPair\x1a\x0e.shared.Vector\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/vector/subtract\x12D\n" +
+	"\x03Dot\x12\x12.shared.VectorPair\x1a\x0e.shared.Scalar\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/vector/dot\x12G\n" +
+	"\x04Norm\x12\x13.shared.NormRequest\x1a\x0e.shared.Scalar\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/vector/norm\x12T\n" +
+	"\x0eMatrixMultiply\x12\x12.shared.MatrixPair\x1a\x0e.shared.Matrix\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/matrix/multiply\x12L\n" +
+	"\tTranspose\x12\x0e.shared.Matrix\x1a\x0e.shared.Matrix\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/matrix/transposeBBZ@github.com/karldmenzel/go-grpc-client-server/magicMath;magicMathb\x06proto3"
+
+var (
+	file_magicMath_vector_math_proto_rawDescOnce sync.Once
+	file_magicMath_vector_math_proto_rawDescData []byte
+)
+
+func file_magicMath_vector_math_proto_rawDescGZIP() []byte {
+	file_magicMath_vector_math_proto_rawDescOnce.Do(func() {
+		file_magicMath_vector_math_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_magicMath_vector_math_proto_rawDesc), len(file_magicMath_vector_math_proto_rawDesc)))
+	})
+	return file_magicMath_vector_math_proto_rawDescData
+}
+
+var file_magicMath_vector_math_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_magicMath_vector_math_proto_goTypes = []any{
+	(*Vector)(nil),      // 0: shared.Vector
+	(*VectorPair)(nil),  // 1: shared.VectorPair
+	(*NormRequest)(nil), // 2: shared.NormRequest
+	(*Scalar)(nil),      // 3: shared.Scalar
+	(*Matrix)(nil),      // 4: shared.Matrix
+	(*MatrixPair)(nil),  // 5: shared.MatrixPair
+}
+var file_magicMath_vector_math_proto_depIdxs = []int32{
+	0,  // 0: shared.VectorPair.a:type_name -> shared.Vector
+	0,  // 1: shared.VectorPair.b:type_name -> shared.Vector
+	0,  // 2: shared.NormRequest.vector:type_name -> shared.Vector
+	4,  // 3: shared.MatrixPair.a:type_name -> shared.Matrix
+	4,  // 4: shared.MatrixPair.b:type_name -> shared.Matrix
+	1,  // 5: shared.VectorMath.Add:input_type -> shared.VectorPair
+	1,  // 6: shared.VectorMath.Subtract:input_type -> shared.VectorPair
+	1,  // 7: shared.VectorMath.Dot:input_type -> shared.VectorPair
+	2,  // 8: shared.VectorMath.Norm:input_type -> shared.NormRequest
+	5,  // 9: shared.VectorMath.MatrixMultiply:input_type -> shared.MatrixPair
+	4,  // 10: shared.VectorMath.Transpose:input_type -> shared.Matrix
+	0,  // 11: shared.VectorMath.Add:output_type -> shared.Vector
+	0,  // 12: shared.VectorMath.Subtract:output_type -> shared.Vector
+	3,  // 13: shared.VectorMath.Dot:output_type -> shared.Scalar
+	3,  // 14: shared.VectorMath.Norm:output_type -> shared.Scalar
+	4,  // 15: shared.VectorMath.MatrixMultiply:output_type -> shared.Matrix
+	4,  // 16: shared.VectorMath.Transpose:output_type -> shared.Matrix
+	11, // [11:17] is the sub-list for method output_type
+	5,  // [5:11] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_magicMath_vector_math_proto_init() }
+func file_magicMath_vector_math_proto_init() {
+	if File_magicMath_vector_math_proto != nil {
+		return
+	}
+	file_magicMath_validation_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_magicMath_vector_math_proto_rawDesc), len(file_magicMath_vector_math_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_magicMath_vector_math_proto_goTypes,
+		DependencyIndexes: file_magicMath_vector_math_proto_depIdxs,
+		MessageInfos:      file_magicMath_vector_math_proto_msgTypes,
+	}.Build()
+	File_magicMath_vector_math_proto = out.File
+	file_magicMath_vector_math_proto_goTypes = nil
+	file_magicMath_vector_math_proto_depIdxs = nil
+}
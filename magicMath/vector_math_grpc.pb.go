@@ -0,0 +1,327 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.1
+// source: magicMath/vector_math.proto
+
+package magicMath
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VectorMath_Add_FullMethodName            = "/shared.VectorMath/Add"
+	VectorMath_Subtract_FullMethodName       = "/shared.VectorMath/Subtract"
+	VectorMath_Dot_FullMethodName            = "/shared.VectorMath/Dot"
+	VectorMath_Norm_FullMethodName           = "/shared.VectorMath/Norm"
+	VectorMath_MatrixMultiply_FullMethodName = "/shared.VectorMath/MatrixMultiply"
+	VectorMath_Transpose_FullMethodName      = "/shared.VectorMath/Transpose"
+)
+
+// VectorMathClient is the client API for VectorMath service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VectorMath works on vectors and matrices of doubles rather than on single terms.
+// Shapes that don't fit together, for example adding vectors of different lengths, are rejected with
+// INVALID_ARGUMENT, and so are operands or results with more elements than the server allows.
+type VectorMathClient interface {
+	// Add and Subtract work element by element on two vectors of the same length.
+	Add(ctx context.Context, in *VectorPair, opts ...grpc.CallOption) (*Vector, error)
+	Subtract(ctx context.Context, in *VectorPair, opts ...grpc.CallOption) (*Vector, error)
+	// Dot returns the sum of the products of the elements of two vectors of the same length.
+	Dot(ctx context.Context, in *VectorPair, opts ...grpc.CallOption) (*Scalar, error)
+	// Norm returns the length of a vector, measured by the named norm.
+	Norm(ctx context.Context, in *NormRequest, opts ...grpc.CallOption) (*Scalar, error)
+	// MatrixMultiply returns the matrix product a times b. The number of columns of a must equal the number of rows of b.
+	MatrixMultiply(ctx context.Context, in *MatrixPair, opts ...grpc.CallOption) (*Matrix, error)
+	Transpose(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error)
+}
+
+type vectorMathClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVectorMathClient(cc grpc.ClientConnInterface) VectorMathClient {
+	return &vectorMathClient{cc}
+}
+
+func (c *vectorMathClient) Add(ctx context.Context, in *VectorPair, opts ...grpc.CallOption) (*Vector, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vector)
+	err := c.cc.Invoke(ctx, VectorMath_Add_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorMathClient) Subtract(ctx context.Context, in *VectorPair, opts ...grpc.CallOption) (*Vector, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vector)
+	err := c.cc.Invoke(ctx, VectorMath_Subtract_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorMathClient) Dot(ctx context.Context, in *VectorPair, opts ...grpc.CallOption) (*Scalar, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Scalar)
+	err := c.cc.Invoke(ctx, VectorMath_Dot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorMathClient) Norm(ctx context.Context, in *NormRequest, opts ...grpc.CallOption) (*Scalar, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Scalar)
+	err := c.cc.Invoke(ctx, VectorMath_Norm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorMathClient) MatrixMultiply(ctx context.Context, in *MatrixPair, opts ...grpc.CallOption) (*Matrix, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Matrix)
+	err := c.cc.Invoke(ctx, VectorMath_MatrixMultiply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vectorMathClient) Transpose(ctx context.Context, in *Matrix, opts ...grpc.CallOption) (*Matrix, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Matrix)
+	err := c.cc.Invoke(ctx, VectorMath_Transpose_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VectorMathServer is the server API for VectorMath service.
+// All implementations must embed UnimplementedVectorMathServer
+// for forward compatibility.
+//
+// VectorMath works on vectors and matrices of doubles rather than on single terms.
+// Shapes that don't fit together, for example adding vectors of different lengths, are rejected with
+// INVALID_ARGUMENT, and so are operands or results with more elements than the server allows.
+type VectorMathServer interface {
+	// Add and Subtract work element by element on two vectors of the same length.
+	Add(context.Context, *VectorPair) (*Vector, error)
+	Subtract(context.Context, *VectorPair) (*Vector, error)
+	// Dot returns the sum of the products of the elements of two vectors of the same length.
+	Dot(context.Context, *VectorPair) (*Scalar, error)
+	// Norm returns the length of a vector, measured by the named norm.
+	Norm(context.Context, *NormRequest) (*Scalar, error)
+	// MatrixMultiply returns the matrix product a times b. The number of columns of a must equal the number of rows of b.
+	MatrixMultiply(context.Context, *MatrixPair) (*Matrix, error)
+	Transpose(context.Context, *Matrix) (*Matrix, error)
+	mustEmbedUnimplementedVectorMathServer()
+}
+
+// UnimplementedVectorMathServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVectorMathServer struct{}
+
+func (UnimplementedVectorMathServer) Add(context.Context, *VectorPair) (*Vector, error) {
+	return nil, status.Error(codes.Unimplemented, "method Add not implemented")
+}
+func (UnimplementedVectorMathServer) Subtract(context.Context, *VectorPair) (*Vector, error) {
+	return nil, status.Error(codes.Unimplemented, "method Subtract not implemented")
+}
+func (UnimplementedVectorMathServer) Dot(context.Context, *VectorPair) (*Scalar, error) {
+	return nil, status.Error(codes.Unimplemented, "method Dot not implemented")
+}
+func (UnimplementedVectorMathServer) Norm(context.Context, *NormRequest) (*Scalar, error) {
+	return nil, status.Error(codes.Unimplemented, "method Norm not implemented")
+}
+func (UnimplementedVectorMathServer) MatrixMultiply(context.Context, *MatrixPair) (*Matrix, error) {
+	return nil, status.Error(codes.Unimplemented, "method MatrixMultiply not implemented")
+}
+func (UnimplementedVectorMathServer) Transpose(context.Context, *Matrix) (*Matrix, error) {
+	return nil, status.Error(codes.Unimplemented, "method Transpose not implemented")
+}
+func (UnimplementedVectorMathServer) mustEmbedUnimplementedVectorMathServer() {}
+func (UnimplementedVectorMathServer) testEmbeddedByValue()                    {}
+
+// UnsafeVectorMathServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VectorMathServer will
+// result in compilation errors.
+type UnsafeVectorMathServer interface {
+	mustEmbedUnimplementedVectorMathServer()
+}
+
+func RegisterVectorMathServer(s grpc.ServiceRegistrar, srv VectorMathServer) {
+	// If the following call panics, it indicates UnimplementedVectorMathServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VectorMath_ServiceDesc, srv)
+}
+
+func _VectorMath_Add_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VectorPair)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorMathServer).Add(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorMath_Add_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorMathServer).Add(ctx, req.(*VectorPair))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorMath_Subtract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VectorPair)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorMathServer).Subtract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorMath_Subtract_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorMathServer).Subtract(ctx, req.(*VectorPair))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorMath_Dot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VectorPair)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorMathServer).Dot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorMath_Dot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorMathServer).Dot(ctx, req.(*VectorPair))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorMath_Norm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NormRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorMathServer).Norm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorMath_Norm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorMathServer).Norm(ctx, req.(*NormRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorMath_MatrixMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixPair)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorMathServer).MatrixMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorMath_MatrixMultiply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorMathServer).MatrixMultiply(ctx, req.(*MatrixPair))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VectorMath_Transpose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Matrix)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VectorMathServer).Transpose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VectorMath_Transpose_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VectorMathServer).Transpose(ctx, req.(*Matrix))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VectorMath_ServiceDesc is the grpc.ServiceDesc for VectorMath service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VectorMath_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shared.VectorMath",
+	HandlerType: (*VectorMathServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Add",
+			Handler:    _VectorMath_Add_Handler,
+		},
+		{
+			MethodName: "Subtract",
+			Handler:    _VectorMath_Subtract_Handler,
+		},
+		{
+			MethodName: "Dot",
+			Handler:    _VectorMath_Dot_Handler,
+		},
+		{
+			MethodName: "Norm",
+			Handler:    _VectorMath_Norm_Handler,
+		},
+		{
+			MethodName: "MatrixMultiply",
+			Handler:    _VectorMath_MatrixMultiply_Handler,
+		},
+		{
+			MethodName: "Transpose",
+			Handler:    _VectorMath_Transpose_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "magicMath/vector_math.proto",
+}
@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"slices"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	pbv2 "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+	"github.com/karldmenzel/go-grpc-client-server/server/cache"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/faults"
+	"github.com/karldmenzel/go-grpc-client-server/server/idempotency"
+	"github.com/karldmenzel/go-grpc-client-server/server/loadshed"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/bignum"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/decimal"
+	"github.com/karldmenzel/go-grpc-client-server/server/operations"
+	"github.com/karldmenzel/go-grpc-client-server/server/ratelimit"
+	"github.com/karldmenzel/go-grpc-client-server/server/recovery"
+	"github.com/karldmenzel/go-grpc-client-server/server/service"
+	"github.com/karldmenzel/go-grpc-client-server/server/validation"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Config holds everything needed to build a MagicMath gRPC server.
+// The server binary fills it in from its flags, and tests fill it in through the servertest package.
+type Config struct {
+	// Counters stores how many times each function has been called. Defaults to an in-memory store.
+	Counters counter.Store
+
+	// BigLimits limit the size of the numbers used by the arbitrary precision functions, so that callers can't
+	// exhaust the server's CPU and memory. Zero limits take their values from bignum.DefaultLimits.
+	BigLimits bignum.Limits
+
+	// DecimalLimits limit the size of the decimals used by the exact decimal functions, for the same reason.
+	// Zero limits take their values from decimal.DefaultLimits.
+	DecimalLimits decimal.Limits
+
+	// Operations turns on the Operations service, which runs MagicMath calls in the background, with these options.
+	// Defaults to no Operations service.
+	Operations *operations.Options
+
+	// UnaryInterceptors run around every unary call, the first one is the outermost.
+	UnaryInterceptors []grpc.UnaryServerInterceptor
+
+	// Deduplicator answers duplicate MagicMath calls, with the same idempotency key, with the response of the first one.
+	// It runs outside the rate limiter, so that retried calls don't use up the caller's quota or the function counters.
+	// Defaults to handling every call, whatever its idempotency key.
+	Deduplicator *idempotency.Deduplicator
+
+	// RateLimiter enforces rate limits and quotas on MagicMath calls. It runs inside the other interceptors,
+	// so that they also see the calls it rejects. Defaults to no limits.
+	RateLimiter *ratelimit.Limiter
+
+	// ConcurrencyLimiter limits how many MagicMath calls run at once, and sheds the calls the server has no room for.
+	// It runs inside the rate limiter, so that calls over a rate limit never wait for a slot. Defaults to no limit.
+	ConcurrencyLimiter *loadshed.Limiter
+
+	// StreamInterceptors run around every streaming call, the first one is the outermost.
+	StreamInterceptors []grpc.StreamServerInterceptor
+
+	// FaultInjector injects faults into MagicMath calls, it runs inside every other interceptor so that injected
+	// latency and errors look like they came from the handlers. Its FaultInjection service is registered alongside
+	// MagicMath, so that the faults can be changed at runtime. Defaults to no fault injection.
+	FaultInjector *faults.Injector
+
+	// Cache turns on a cache with these options, which memoizes the results of the MagicMath math functions. It counts
+	// its hits and misses in Counters, and runs inside every other interceptor, so that rate limits and injected faults
+	// apply to calls answered from the cache too. Defaults to no cache.
+	Cache *cache.Options
+
+	// Credentials secure the server's connections, for example with TLS. Defaults to no security.
+	Credentials credentials.TransportCredentials
+
+	// Health is the standard gRPC health service, registered so that load balancers and orchestrators can check
+	// the server. Defaults to a health service which reports the server as serving.
+	Health *health.Server
+
+	// Recoverer turns panics in any handler or interceptor into codes.Internal errors, so it runs outside every other
+	// interceptor. Defaults to a recoverer which counts panics in Counters, and only logs its crash reports.
+	Recoverer *recovery.Recoverer
+}
+
+// New creates a gRPC server with every MagicMath service bound to it, ready to serve on any listener.
+func New(config Config) *grpc.Server {
+	if config.Counters == nil {
+		config.Counters = counter.NewMemoryStore()
+	}
+	if config.Health == nil {
+		config.Health = health.NewServer()
+	}
+	if config.Recoverer == nil {
+		config.Recoverer = recovery.New(recovery.Options{Counters: config.Counters})
+	}
+	// The cache counts the calls it answers in the same store as the service, so that the counters include them.
+	var resultCache *cache.Cache
+	if config.Cache != nil {
+		resultCache = cache.New(*config.Cache, config.Counters)
+	}
+
+	// Requests which break the validation rules in the proto files are rejected before any of the limits or the cache
+	// see them, so they never use up a caller's quota.
+	outerInterceptors := append([]grpc.UnaryServerInterceptor{config.Recoverer.UnaryServerInterceptor()},
+		config.UnaryInterceptors...)
+	outerInterceptors = append(outerInterceptors, validation.UnaryServerInterceptor())
+	if config.Deduplicator != nil {
+		outerInterceptors = append(outerInterceptors, config.Deduplicator.UnaryServerInterceptor())
+	}
+	var innerInterceptors []grpc.UnaryServerInterceptor
+	if config.ConcurrencyLimiter != nil {
+		innerInterceptors = append(innerInterceptors, config.ConcurrencyLimiter.UnaryServerInterceptor())
+	}
+	if config.FaultInjector != nil {
+		innerInterceptors = append(innerInterceptors, config.FaultInjector.UnaryServerInterceptor())
+	}
+	if resultCache != nil {
+		innerInterceptors = append(innerInterceptors, resultCache.UnaryServerInterceptor())
+	}
+
+	// The rate limiter sits between the outer and inner interceptors.
+	unaryInterceptors := slices.Clone(outerInterceptors)
+	if config.RateLimiter != nil {
+		unaryInterceptors = append(unaryInterceptors, config.RateLimiter.UnaryServerInterceptor())
+	}
+	unaryInterceptors = append(unaryInterceptors, innerInterceptors...)
+
+	streamInterceptors := append([]grpc.StreamServerInterceptor{config.Recoverer.StreamServerInterceptor()},
+		config.StreamInterceptors...)
+	streamInterceptors = append(streamInterceptors, validation.StreamServerInterceptor())
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	if config.Credentials != nil {
+		options = append(options, grpc.Creds(config.Credentials))
+	}
+
+	// Create a new unbound gRPC server.
+	s := grpc.NewServer(options...)
+	// Bind the magic interface to the gRPC server.
+	magicMath := service.New(config.Counters, config.RateLimiter, bignum.New(config.BigLimits),
+		decimal.NewCalculator(config.DecimalLimits), resultCache)
+	pb.RegisterMagicMathServer(s, magicMath)
+	// Bind version 2 of the magic interface to the gRPC server, alongside version 1 and sharing its counters.
+	pbv2.RegisterMagicMathServer(s, service.NewV2(config.Counters))
+	// Bind the vector interface to the gRPC server, alongside the magic interface.
+	pb.RegisterVectorMathServer(s, service.NewVectorMath())
+
+	// Bind the health interface to the gRPC server, which reports both versions of the MagicMath service and the
+	// VectorMath service as serving.
+	healthpb.RegisterHealthServer(s, config.Health)
+	config.Health.SetServingStatus(pb.MagicMath_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	config.Health.SetServingStatus(pbv2.MagicMath_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	config.Health.SetServingStatus(pb.VectorMath_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	if config.Operations != nil {
+		// Bind the operations interface to the gRPC server, which runs calls to the magic interface in the background.
+		// The operations' calls go through the same interceptors as any other call, apart from the rate limiter, which
+		// charges for them when they are submitted instead.
+		options := *config.Operations
+		options.Interceptor = chainUnaryInterceptors(append(slices.Clone(outerInterceptors), innerInterceptors...))
+		if config.RateLimiter != nil {
+			options.Admit = config.RateLimiter.Allow
+		}
+		pb.RegisterOperationsServer(s, operations.New(magicMath, options))
+		config.Health.SetServingStatus(pb.Operations_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	if config.FaultInjector != nil {
+		// Bind the fault injection interface to the gRPC server so that faults can be changed while it runs.
+		pb.RegisterFaultInjectionServer(s, config.FaultInjector)
+	}
+
+	return s
+}
+
+// This function chains interceptors into one, the first one is the outermost, just like grpc.ChainUnaryInterceptor.
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+
+		return chained(ctx, req)
+	}
+}
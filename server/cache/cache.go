@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// A method whose results are cached.
+type method struct {
+	// counter is the name of the method's invocation counter, which the handler would have incremented.
+	counter string
+	// commutative is true if the order of the terms doesn't change the result, so that calls with the terms in any
+	// order share one cache entry.
+	commutative bool
+}
+
+// These are the methods whose results are cached. They are pure functions of their terms, so a cached result is
+// always the one the handler would have returned.
+var methods = map[string]method{
+	pb.MagicMath_MagicAdd_FullMethodName:      {counter: counter.Add, commutative: true},
+	pb.MagicMath_MagicSubtract_FullMethodName: {counter: counter.Subtract},
+	pb.MagicMath_MagicFindMin_FullMethodName:  {counter: counter.FindMin, commutative: true},
+	pb.MagicMath_MagicFindMax_FullMethodName:  {counter: counter.FindMax, commutative: true},
+	pb.MagicMath_MagicMultiply_FullMethodName: {counter: counter.Multiply, commutative: true},
+	pb.MagicMath_MagicDivide_FullMethodName:   {counter: counter.Divide},
+	pb.MagicMath_MagicModulo_FullMethodName:   {counter: counter.Modulo},
+	pb.MagicMath_MagicPower_FullMethodName:    {counter: counter.Power},
+}
+
+// Options tunes the cache. A zero value means the default given for each option.
+type Options struct {
+	// MaxEntries is how many results the cache holds, once it is full the least recently used result is evicted.
+	// It defaults to 10000.
+	MaxEntries int
+	// TTL is how long a result stays in the cache after it was worked out. It defaults to five minutes.
+	TTL time.Duration
+}
+
+// Stats is a snapshot of the cache's state.
+type Stats struct {
+	// Entries is how many results are in the cache.
+	Entries int
+	// Evictions is how many results have been evicted to make room for newer ones.
+	Evictions int64
+}
+
+// Cache memoizes the results of the MagicMath math functions, keyed by the method and its terms, so that repeated
+// calls with the same terms don't run the handler again. Only successful results are cached.
+type Cache struct {
+	options Options
+	// counters counts the hits and misses, and the invocations of the methods whose calls are answered from the cache.
+	counters counter.Store
+	// now returns the current time, tests replace it to control the clock.
+	now func() time.Time
+
+	// This mutex is used to protect all of the fields below.
+	mutex sync.Mutex
+	// entries holds the cached results, most recently used first, and index finds them by their key.
+	entries   *list.List
+	index     map[string]*list.Element
+	evictions int64
+}
+
+// A cached result.
+type entry struct {
+	key      string
+	response proto.Message
+	expires  time.Time
+}
+
+// New creates an empty cache with the given options, which counts its hits and misses, and the calls it answers, in the
+// given store. This must be the store the MagicMath service counts its calls in, so that the counters include the
+// calls answered from the cache.
+func New(options Options, counters counter.Store) *Cache {
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = 10000
+	}
+	if options.TTL <= 0 {
+		options.TTL = 5 * time.Minute
+	}
+
+	return &Cache{
+		options:  options,
+		counters: counters,
+		now:      time.Now,
+		entries:  list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+// UnaryServerInterceptor answers calls to the cached methods from the cache when it can, and otherwise runs the
+// handler and caches its result. The method's invocation counter is incremented for calls answered from the cache,
+// so that the counters count every call whether it was cached or not.
+func (c *Cache) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		m, ok := methods[info.FullMethod]
+		request, isMessage := req.(proto.Message)
+		if !ok || !isMessage {
+			return handler(ctx, req)
+		}
+
+		key, err := cacheKey(info.FullMethod, request, m.commutative)
+		if err != nil {
+			return handler(ctx, req)
+		}
+
+		if response := c.get(key); response != nil {
+			c.counters.Increment(counter.CacheHit)
+			c.counters.Increment(m.counter)
+			return response, nil
+		}
+
+		c.counters.Increment(counter.CacheMiss)
+		resp, err := handler(ctx, req)
+		if response, ok := resp.(proto.Message); ok && err == nil {
+			c.put(key, response)
+		}
+
+		return resp, err
+	}
+}
+
+// Stats returns a snapshot of the cache's state.
+func (c *Cache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return Stats{Entries: c.entries.Len(), Evictions: c.evictions}
+}
+
+// This function returns a copy of the cached result for the key, or nil if there is none or it has expired.
+// The copy stops the interceptors and the gRPC library from changing the cached result.
+func (c *Cache) get(key string) proto.Message {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.index[key]
+	if !ok {
+		return nil
+	}
+	cached := element.Value.(*entry)
+	if !c.now().Before(cached.expires) {
+		c.entries.Remove(element)
+		delete(c.index, key)
+		return nil
+	}
+	c.entries.MoveToFront(element)
+
+	return proto.Clone(cached.response)
+}
+
+// This function caches a copy of the result for the key, evicting the least recently used results if the cache is full.
+func (c *Cache) put(key string, response proto.Message) {
+	cached := &entry{key: key, response: proto.Clone(response), expires: c.now().Add(c.options.TTL)}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.index[key]; ok {
+		element.Value = cached
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.index[key] = c.entries.PushFront(cached)
+	for c.entries.Len() > c.options.MaxEntries {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.index, oldest.Value.(*entry).key)
+		c.evictions++
+	}
+}
+
+// This function returns the cache key of a call, which is the method followed by the encoded terms. The terms of
+// commutative methods are sorted first, so that for example MagicFindMin(3, 1, 2) and MagicFindMin(1, 2, 3) share a key.
+func cacheKey(fullMethod string, request proto.Message, commutative bool) (string, error) {
+	if commutative {
+		request = canonicalize(request)
+	}
+
+	terms, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	return fullMethod + "\x00" + string(terms), nil
+}
+
+// This function returns a copy of the request with its terms sorted.
+func canonicalize(request proto.Message) proto.Message {
+	switch terms := request.(type) {
+	case *pb.DoubleTerms:
+		sorted := sortTerms(terms.TermOne, terms.TermTwo)
+		return &pb.DoubleTerms{TermOne: sorted[0], TermTwo: sorted[1]}
+	case *pb.IntTerms:
+		sorted := sortTerms(terms.TermOne, terms.TermTwo, terms.TermThree)
+		return &pb.IntTerms{TermOne: sorted[0], TermTwo: sorted[1], TermThree: sorted[2]}
+	case *pb.ArithmeticTerms:
+		switch pair := terms.Terms.(type) {
+		case *pb.ArithmeticTerms_Doubles:
+			return &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Doubles{
+				Doubles: canonicalize(pair.Doubles).(*pb.DoubleTerms),
+			}}
+		case *pb.ArithmeticTerms_Ints:
+			sorted := sortTerms(pair.Ints.TermOne, pair.Ints.TermTwo)
+			return &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{
+				Ints: &pb.IntPair{TermOne: sorted[0], TermTwo: sorted[1]},
+			}}
+		}
+	}
+
+	return request
+}
+
+// This function returns the terms in ascending order.
+func sortTerms[T cmp.Ordered](terms ...T) []T {
+	slices.Sort(terms)
+	return terms
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	minInfo      = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicFindMin_FullMethodName}
+	subtractInfo = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicSubtract_FullMethodName}
+	quotaInfo    = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_GetQuota_FullMethodName}
+)
+
+// This object is a handler which counts how many times it runs, and increments the invocation counter like the
+// MagicMath handlers do.
+type countingHandler struct {
+	counters counter.Store
+	calls    int
+}
+
+func (h *countingHandler) handle(_ context.Context, req any) (any, error) {
+	h.calls++
+	switch terms := req.(type) {
+	case *pb.IntTerms:
+		h.counters.Increment(counter.FindMin)
+		return &pb.IntResult{Result: math.LocalFindMin(terms.TermOne, terms.TermTwo, terms.TermThree)}, nil
+	case *pb.DoubleTerms:
+		h.counters.Increment(counter.Subtract)
+		return &pb.DoubleResult{Result: math.LocalSubtract(terms.TermOne, terms.TermTwo)}, nil
+	}
+
+	return &pb.Quota{}, nil
+}
+
+func TestCacheCountsEveryCall(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	cache := New(Options{}, counters)
+	handler := &countingHandler{counters: counters}
+	interceptor := cache.UnaryServerInterceptor()
+
+	// The terms in any order share an entry, as the minimum doesn't depend on their order.
+	for _, terms := range []*pb.IntTerms{
+		{TermOne: 3, TermTwo: 1, TermThree: 2},
+		{TermOne: 1, TermTwo: 2, TermThree: 3},
+		{TermOne: 2, TermTwo: 3, TermThree: 1},
+	} {
+		resp, err := interceptor(context.Background(), terms, minInfo, handler.handle)
+		if err != nil || resp.(*pb.IntResult).Result != 1 {
+			t.Errorf("MagicFindMin(%v) = %v, %v; want 1", terms, resp, err)
+		}
+	}
+
+	if handler.calls != 1 {
+		t.Errorf("handler ran %d times; want 1", handler.calls)
+	}
+	if got := counters.Count(counter.FindMin); got != 3 {
+		t.Errorf("min count = %d; want 3", got)
+	}
+	if hits, misses := counters.Count(counter.CacheHit), counters.Count(counter.CacheMiss); hits != 2 || misses != 1 {
+		t.Errorf("hits, misses = %d, %d; want 2, 1", hits, misses)
+	}
+}
+
+func TestCacheKeepsTheOrderOfNonCommutativeTerms(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	handler := &countingHandler{counters: counters}
+	interceptor := New(Options{}, counters).UnaryServerInterceptor()
+
+	var tests = []struct {
+		terms *pb.DoubleTerms
+		want  float64
+	}{
+		{&pb.DoubleTerms{TermOne: 5, TermTwo: 2}, 3},
+		{&pb.DoubleTerms{TermOne: 2, TermTwo: 5}, -3},
+		{&pb.DoubleTerms{TermOne: 5, TermTwo: 2}, 3},
+	}
+
+	for _, test := range tests {
+		resp, err := interceptor(context.Background(), test.terms, subtractInfo, handler.handle)
+		if err != nil || resp.(*pb.DoubleResult).Result != test.want {
+			t.Errorf("MagicSubtract(%v) = %v, %v; want %v", test.terms, resp, err, test.want)
+		}
+	}
+	if handler.calls != 2 {
+		t.Errorf("handler ran %d times; want 2", handler.calls)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	cache := New(Options{MaxEntries: 2}, counters)
+	handler := &countingHandler{counters: counters}
+	interceptor := cache.UnaryServerInterceptor()
+	call := func(term int64) {
+		interceptor(context.Background(), &pb.IntTerms{TermOne: term}, minInfo, handler.handle)
+	}
+
+	call(1)
+	call(2)
+	call(1) // 1 is now more recently used than 2.
+	call(3) // This evicts 2.
+	call(1)
+	call(2)
+
+	if handler.calls != 4 {
+		t.Errorf("handler ran %d times; want 4", handler.calls)
+	}
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Evictions != 2 {
+		t.Errorf("Stats() = %+v; want 2 entries and 2 evictions", stats)
+	}
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	cache := New(Options{TTL: time.Minute}, counters)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	handler := &countingHandler{counters: counters}
+	interceptor := cache.UnaryServerInterceptor()
+	terms := &pb.IntTerms{TermOne: 1}
+
+	interceptor(context.Background(), terms, minInfo, handler.handle)
+	now = now.Add(59 * time.Second)
+	interceptor(context.Background(), terms, minInfo, handler.handle)
+	if handler.calls != 1 {
+		t.Errorf("handler ran %d times before the TTL; want 1", handler.calls)
+	}
+
+	now = now.Add(time.Second)
+	interceptor(context.Background(), terms, minInfo, handler.handle)
+	if handler.calls != 2 {
+		t.Errorf("handler ran %d times after the TTL; want 2", handler.calls)
+	}
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	handler := &countingHandler{counters: counters}
+	interceptor := New(Options{}, counters).UnaryServerInterceptor()
+	terms := &pb.IntTerms{TermOne: 1}
+
+	first, _ := interceptor(context.Background(), terms, minInfo, handler.handle)
+	first.(*pb.IntResult).Result = 42
+	second, _ := interceptor(context.Background(), terms, minInfo, handler.handle)
+	if want := (&pb.IntResult{Result: 1}); !proto.Equal(second.(*pb.IntResult), want) {
+		t.Errorf("cached result = %v after changing a returned result; want %v", second, want)
+	}
+}
+
+func TestCacheSkipsErrorsAndOtherMethods(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	cache := New(Options{}, counters)
+	interceptor := cache.UnaryServerInterceptor()
+
+	failures := 0
+	failing := func(context.Context, any) (any, error) {
+		failures++
+		return nil, errors.New("failed")
+	}
+	for range 2 {
+		interceptor(context.Background(), &pb.IntTerms{}, minInfo, failing)
+	}
+	if failures != 2 {
+		t.Errorf("failing handler ran %d times; want 2", failures)
+	}
+
+	handler := &countingHandler{counters: counters}
+	for range 2 {
+		interceptor(context.Background(), &pb.Empty{}, quotaInfo, handler.handle)
+	}
+	if handler.calls != 2 || cache.Stats().Entries != 0 {
+		t.Errorf("GetQuota ran %d times and left %d entries; want 2 and 0", handler.calls, cache.Stats().Entries)
+	}
+}
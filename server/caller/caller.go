@@ -0,0 +1,144 @@
+package caller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// MetadataKey is the metadata header a client sends to identify itself, for example "x-caller-id: reporting-job".
+const MetadataKey = "x-caller-id"
+
+// The header the REST gateway and the web handler use to pass on the address of the client they are calling for.
+const forwardedForKey = "x-forwarded-for"
+
+// TrustedNetworks are the networks of the clients which are trusted to identify themselves with the x-caller-id header.
+// Clients on this machine, and clients which connected with a verified TLS certificate, are always trusted. The server
+// sets it from its flags before it starts serving, it must not be changed afterwards.
+var TrustedNetworks []netip.Prefix
+
+// ParseNetworks parses a comma separated list of networks, each written as an IP address or a CIDR range,
+// for example "10.0.0.0/8,192.0.2.7".
+func ParseNetworks(spec string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	if spec == "" {
+		return networks, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if address, err := netip.ParseAddr(entry); err == nil {
+			networks = append(networks, netip.PrefixFrom(address, address.BitLen()))
+			continue
+		}
+		network, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q, expected an IP address or a CIDR range", entry)
+		}
+		networks = append(networks, network.Masked())
+	}
+
+	return networks, nil
+}
+
+// ID returns the identity of the client making a call. This is the client's x-caller-id header if the client is
+// trusted to send one, otherwise the client's IP address. Calls forwarded by the REST gateway or the web handler on the
+// same machine are identified by the address in their x-forwarded-for header, and trusted by that address.
+func ID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	address, forwarded := clientAddress(ctx, md)
+
+	// The TLS certificate of a forwarded call belongs to the proxy, not to the client.
+	if Trusted(address) || (!forwarded && authenticated(ctx)) {
+		if values := md.Get(MetadataKey); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	return address
+}
+
+// This function returns the address of the client making a call, and whether the call was forwarded by a proxy on
+// this machine. Proxies add the address of their client to the end of the x-forwarded-for header, any addresses
+// before it were sent by the client itself, so only the last one is used.
+func clientAddress(ctx context.Context, md metadata.MD) (string, bool) {
+	address := peerAddress(ctx)
+	if !isLocal(address) {
+		return address, false
+	}
+
+	values := md.Get(forwardedForKey)
+	if len(values) == 0 {
+		return address, false
+	}
+	entries := strings.Split(values[len(values)-1], ",")
+	if forwarded := strings.TrimSpace(entries[len(entries)-1]); forwarded != "" {
+		return forwarded, true
+	}
+
+	return address, false
+}
+
+// This function returns the IP address of the connection the call arrived on,
+// or the whole address if it has no port, such as a Unix domain socket.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// This function reports whether the connection the call arrived on was secured with TLS, and the client sent a
+// certificate which the server verified.
+func authenticated(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+
+	return ok && len(info.State.VerifiedChains) > 0
+}
+
+// This function reports whether an address belongs to this machine: a loopback IP address, or anything which isn't
+// an IP address at all, such as a Unix domain socket or an in-memory connection.
+func isLocal(address string) bool {
+	ip := net.ParseIP(address)
+	return ip == nil || ip.IsLoopback()
+}
+
+// Trusted reports whether a client at the address, an IP address or the address of a Unix domain socket, is trusted to
+// identify itself with the x-caller-id header: it is on this machine, or in one of the TrustedNetworks. Proxies use it
+// to decide whether to pass on the identity headers their clients send.
+func Trusted(address string) bool {
+	return isLocal(address) || isTrusted(address)
+}
+
+// This function reports whether an IP address is in one of the trusted networks.
+func isTrusted(address string) bool {
+	ip, err := netip.ParseAddr(address)
+	if err != nil {
+		return false
+	}
+
+	for _, network := range TrustedNetworks {
+		if network.Contains(ip.Unmap()) {
+			return true
+		}
+	}
+
+	return false
+}
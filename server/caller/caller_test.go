@@ -0,0 +1,74 @@
+package caller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestID(t *testing.T) {
+	TrustedNetworks = []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")}
+	defer func() { TrustedNetworks = nil }()
+
+	remote := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}
+	local := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}
+	trusted := &net.TCPAddr{IP: net.ParseIP("203.0.113.9"), Port: 1234}
+	verified := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}}}}
+
+	tests := []struct {
+		name     string
+		address  net.Addr
+		authInfo credentials.AuthInfo
+		md       metadata.MD
+		want     string
+	}{
+		{"caller header from local client", local, nil, metadata.Pairs(MetadataKey, "alice"), "alice"},
+		{"caller header from trusted network", trusted, nil, metadata.Pairs(MetadataKey, "alice"), "alice"},
+		{"caller header from verified client", remote, verified, metadata.Pairs(MetadataKey, "alice"), "alice"},
+		{"caller header from remote client is ignored", remote, nil, metadata.Pairs(MetadataKey, "alice"), "192.0.2.1"},
+		{"remote address", remote, nil, metadata.MD{}, "192.0.2.1"},
+		{"forwarded by local gateway", local, nil, metadata.Pairs(forwardedForKey, "198.51.100.7"), "198.51.100.7"},
+		{"forwarded header sent by the client is ignored", local, nil,
+			metadata.Pairs(forwardedForKey, "10.0.0.1, 198.51.100.7"), "198.51.100.7"},
+		{"caller header forwarded for remote client is ignored", local, verified,
+			metadata.Pairs(forwardedForKey, "198.51.100.7", MetadataKey, "alice"), "198.51.100.7"},
+		{"caller header forwarded for trusted client", local, nil,
+			metadata.Pairs(forwardedForKey, "203.0.113.9", MetadataKey, "alice"), "alice"},
+		{"forwarded header from remote client is ignored", remote, nil, metadata.Pairs(forwardedForKey, "198.51.100.7"), "192.0.2.1"},
+		{"unix socket", &net.UnixAddr{Name: "/tmp/magic.sock", Net: "unix"}, nil, metadata.MD{}, "/tmp/magic.sock"},
+	}
+
+	for _, tt := range tests {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tt.address, AuthInfo: tt.authInfo})
+		ctx = metadata.NewIncomingContext(ctx, tt.md)
+		if got := ID(ctx); got != tt.want {
+			t.Errorf("%s: ID() = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks("10.1.2.3/8, 192.0.2.7,2001:db8::/32")
+	want := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.0.2.7/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+	if err != nil || !slices.Equal(networks, want) {
+		t.Errorf("ParseNetworks() = %v, %v; want %v", networks, err, want)
+	}
+
+	for _, spec := range []string{"10.0.0.0/33", "example.com", "10.0.0.1,"} {
+		if _, err := ParseNetworks(spec); err == nil {
+			t.Errorf("ParseNetworks(%q) returned no error", spec)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package cluster
+
+import "math"
+
+// GCounter is a grow-only counter CRDT (conflict-free replicated data type).
+// Every replica only ever increments its own share, and merging two counters keeps the newest share seen for each
+// replica. Because merging can be done in any order and any number of times, replicas which exchange their counters in
+// any order eventually agree on the same total.
+type GCounter map[string]Share
+
+// Share is one replica's share of a counter.
+//
+// A replica keeps its name when it restarts, but starts with none of its counts while its peers still hold the counts
+// of its last run. So each run counts under a higher incarnation, and the share it made in its earlier runs becomes the
+// base of the new one. That way a restart adds neither an entry nor counts which would be lost when merging.
+type Share struct {
+	Incarnation int64
+	// Base is the replica's share from its earlier incarnations.
+	Base int64
+	// Count is the replica's share from its current incarnation.
+	Count int64
+}
+
+// This function returns the replica's whole share, the counts of its earlier incarnations and of its current one.
+func (s Share) total() int64 {
+	return saturatingAdd(s.Base, s.Count)
+}
+
+// Increment adds one to the given replica's share of the counter, for its given incarnation.
+// If the counter holds the share of an earlier incarnation, that share becomes the base of the new one.
+func (g GCounter) Increment(replicaID string, incarnation int64) {
+	share := g[replicaID]
+	if share.Incarnation < incarnation {
+		share = Share{Incarnation: incarnation, Base: share.total()}
+	}
+	share.Count = saturatingAdd(share.Count, 1)
+	g[replicaID] = share
+}
+
+// Value returns the counter's total across all replicas. It stops at the largest int64 rather than overflowing.
+func (g GCounter) Value() int64 {
+	var total int64
+	for _, share := range g {
+		total = saturatingAdd(total, share.total())
+	}
+
+	return total
+}
+
+// Merge updates the counter with every replica's share in the other counter.
+// The share of the later incarnation wins, and the share of the earlier one is kept in its base. Between shares of the
+// same incarnation, the higher base and the higher count win.
+//
+// A replica which restarts twice without exchanging its counters with any peer may start from a base which misses the
+// counts of its first run; merging keeps the higher base, so those counts are lost rather than counted twice.
+func (g GCounter) Merge(other GCounter) {
+	for replicaID, theirs := range other {
+		ours, found := g[replicaID]
+		switch {
+		case !found:
+			g[replicaID] = theirs
+		case ours.Incarnation < theirs.Incarnation:
+			theirs.Base = max(theirs.Base, ours.total())
+			g[replicaID] = theirs
+		case ours.Incarnation > theirs.Incarnation:
+			ours.Base = max(ours.Base, theirs.total())
+			g[replicaID] = ours
+		default:
+			g[replicaID] = Share{
+				Incarnation: ours.Incarnation,
+				Base:        max(ours.Base, theirs.Base),
+				Count:       max(ours.Count, theirs.Count),
+			}
+		}
+	}
+}
+
+// This function adds two counts, stopping at the largest int64 rather than overflowing.
+func saturatingAdd(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+
+	return a + b
+}
@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGCounterValue(t *testing.T) {
+	g := GCounter{}
+	g.Increment("a", 1)
+	g.Increment("a", 1)
+	g.Increment("b", 1)
+
+	if got := g.Value(); got != 3 {
+		t.Errorf("Value() = %d; want 3", got)
+	}
+
+	// A later incarnation of a replica keeps counting from the share of the earlier one.
+	g.Increment("a", 2)
+	if got, share := g.Value(), g["a"]; got != 4 || share != (Share{Incarnation: 2, Base: 2, Count: 1}) {
+		t.Errorf("Value() after a new incarnation = %d, with share %+v; want 4, with base 2 and count 1", got, share)
+	}
+
+	// The total stops at the largest int64 rather than overflowing.
+	g["c"] = Share{Count: math.MaxInt64}
+	if got := g.Value(); got != math.MaxInt64 {
+		t.Errorf("Value() with a huge share = %d; want %d", got, int64(math.MaxInt64))
+	}
+}
+
+func TestGCounterMerge(t *testing.T) {
+	tests := []struct {
+		left, right GCounter
+		want        int64
+	}{
+		{GCounter{"a": {Count: 2}}, GCounter{"b": {Count: 3}}, 5},                  // disjoint replicas are added
+		{GCounter{"a": {Count: 2}}, GCounter{"a": {Count: 5}}, 5},                  // the higher share of a replica wins
+		{GCounter{"a": {Count: 5}, "b": {Count: 1}}, GCounter{"a": {Count: 2}}, 6}, // a stale share never lowers the counter
+		// A later incarnation which hasn't heard of the earlier one adds to it.
+		{GCounter{"a": {Incarnation: 1, Count: 3}}, GCounter{"a": {Incarnation: 2, Count: 1}}, 4},
+		// A later incarnation which has heard of the earlier one doesn't count it twice.
+		{GCounter{"a": {Incarnation: 1, Count: 3}}, GCounter{"a": {Incarnation: 2, Base: 3, Count: 1}}, 4},
+		{GCounter{"a": {Incarnation: 2, Base: 3, Count: 1}}, GCounter{"a": {Incarnation: 2, Count: 4}}, 7},
+		{GCounter{}, GCounter{}, 0},
+	}
+
+	for _, tt := range tests {
+		// Merging must give the same answer in either order, and merging twice must change nothing.
+		left, right := copyGCounter(tt.left), copyGCounter(tt.right)
+		left.Merge(tt.right)
+		right.Merge(tt.left)
+		left.Merge(tt.right)
+
+		if left.Value() != tt.want || right.Value() != tt.want {
+			t.Errorf("Merge(%v, %v) = %d and %d; want %d", tt.left, tt.right, left.Value(), right.Value(), tt.want)
+		}
+		if len(left) != len(right) {
+			t.Errorf("Merge(%v, %v) has %d and %d replicas; want the same number", tt.left, tt.right, len(left), len(right))
+		}
+	}
+}
+
+func copyGCounter(g GCounter) GCounter {
+	c := GCounter{}
+	for replicaID, share := range g {
+		c[replicaID] = share
+	}
+
+	return c
+}
@@ -0,0 +1,166 @@
+package cluster
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+)
+
+// The cluster node must be usable anywhere a counter store is expected, and able to prune its counters.
+var (
+	_ counter.Store  = (*Node)(nil)
+	_ counter.Pruner = (*Node)(nil)
+)
+
+// Node is one server in a cluster. It is a counter store whose counters are replicated to every other node,
+// so Count returns the cluster-wide total rather than just this node's share.
+// It also implements the ClusterPeer service, which the other nodes call to exchange counters, NewPeerServer serves it.
+type Node struct {
+	pb.UnsafeClusterPeerServer
+
+	// The node's name, which it keeps when it restarts, and the incarnation it counts under in this run.
+	name        string
+	incarnation int64
+
+	// This mutex is used to protect read and write access to the counters and peers.
+	mutex    sync.Mutex
+	counters map[string]GCounter
+	peers    []peer
+}
+
+// A peer is another node of the cluster that this node gossips with.
+type peer struct {
+	address string
+	client  pb.ClusterPeerClient
+}
+
+// NewNode creates a cluster node with the given name, which must be unique within the cluster. A restarted node keeps
+// its name, and counts under a new incarnation, made from the time it starts.
+func NewNode(name string) *Node {
+	return &Node{name: name, incarnation: time.Now().UnixNano(), counters: make(map[string]GCounter)}
+}
+
+// Name returns the node's name.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// AddPeer adds another node of the cluster that this node will gossip with.
+func (n *Node) AddPeer(address string, client pb.ClusterPeerClient) {
+	n.mutex.Lock()
+	n.peers = append(n.peers, peer{address: address, client: client})
+	n.mutex.Unlock()
+}
+
+// Increment adds one to this node's share of the named counter.
+func (n *Node) Increment(name string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.counters[name] == nil {
+		n.counters[name] = make(GCounter)
+	}
+	n.counters[name].Increment(n.name, n.incarnation)
+}
+
+// Count returns the total of the named counter across every node this node has heard from.
+func (n *Node) Count(name string) int64 {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.counters[name].Value()
+}
+
+// Prune drops every counter whose name starts with the prefix, and for which expired returns true. A peer which hasn't
+// pruned the counter yet gossips it back, so every node must prune the same counters, which is the case when expired
+// only depends on the name, such as the window of time a counter is named after.
+func (n *Node) Prune(prefix string, expired func(name string) bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for name := range n.counters {
+		if strings.HasPrefix(name, prefix) && expired(name) {
+			delete(n.counters, name)
+		}
+	}
+}
+
+// Gossip is called by another node. It merges the caller's counters, and replies with this node's counters.
+func (n *Node) Gossip(_ context.Context, in *pb.CounterState) (*pb.CounterState, error) {
+	n.merge(in)
+
+	return n.state(), nil
+}
+
+// Run gossips with every peer once per interval, until the context is cancelled.
+func (n *Node) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.GossipOnce(ctx, interval)
+		}
+	}
+}
+
+// GossipOnce exchanges counters with every peer. Each exchange gives up after the timeout.
+// Peers which can't be reached are logged and skipped, they will catch up on a later round.
+func (n *Node) GossipOnce(ctx context.Context, timeout time.Duration) {
+	n.mutex.Lock()
+	peers := append([]peer(nil), n.peers...)
+	n.mutex.Unlock()
+
+	for _, p := range peers {
+		requestContext, cancel := context.WithTimeout(ctx, timeout)
+		reply, err := p.client.Gossip(requestContext, n.state())
+		cancel()
+		if err != nil {
+			log.Printf("failed to gossip with peer %s: %v", p.address, err)
+			continue
+		}
+		n.merge(reply)
+	}
+}
+
+// This function copies the node's counters into a message which can be sent to a peer.
+func (n *Node) state() *pb.CounterState {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	state := &pb.CounterState{ReplicaId: n.name, Counters: make(map[string]*pb.ReplicaCounts, len(n.counters))}
+	for name, gCounter := range n.counters {
+		shares := make(map[string]*pb.ReplicaShare, len(gCounter))
+		for replicaID, share := range gCounter {
+			shares[replicaID] = &pb.ReplicaShare{Incarnation: share.Incarnation, Base: share.Base, Count: share.Count}
+		}
+		state.Counters[name] = &pb.ReplicaCounts{Shares: shares}
+	}
+
+	return state
+}
+
+// This function merges counters received from a peer into the node's counters.
+func (n *Node) merge(in *pb.CounterState) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for name, replicaCounts := range in.GetCounters() {
+		if n.counters[name] == nil {
+			n.counters[name] = make(GCounter)
+		}
+		shares := make(GCounter, len(replicaCounts.GetShares()))
+		for replicaID, share := range replicaCounts.GetShares() {
+			shares[replicaID] = Share{Incarnation: share.GetIncarnation(), Base: share.GetBase(), Count: share.GetCount()}
+		}
+		n.counters[name].Merge(shares)
+	}
+}
@@ -0,0 +1,241 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testNetwork connects in-process cluster nodes, and lets a test cut and restore the links between them.
+type testNetwork struct {
+	mutex sync.Mutex
+	cut   map[[2]string]bool
+}
+
+func (n *testNetwork) isCut(from, to string) bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.cut[[2]string{from, to}] || n.cut[[2]string{to, from}]
+}
+
+// partition cuts every link between the two groups of nodes.
+func (n *testNetwork) partition(groupOne, groupTwo []string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for _, from := range groupOne {
+		for _, to := range groupTwo {
+			n.cut[[2]string{from, to}] = true
+		}
+	}
+}
+
+// heal restores every link.
+func (n *testNetwork) heal() {
+	n.mutex.Lock()
+	n.cut = make(map[[2]string]bool)
+	n.mutex.Unlock()
+}
+
+// startCluster starts one gRPC server per ID, each serving MagicMath backed by a cluster node, and a peer server for
+// each node, with every node peered with every other node through the test network.
+func startCluster(t *testing.T, ids ...string) (*testNetwork, map[string]*Node, map[string]pb.MagicMathClient) {
+	t.Helper()
+
+	network := &testNetwork{cut: make(map[[2]string]bool)}
+	nodes := make(map[string]*Node)
+	clients := make(map[string]pb.MagicMathClient)
+	peerListeners := make(map[string]*bufconn.Listener)
+
+	for _, id := range ids {
+		node := NewNode(id)
+		nodes[id] = node
+		clients[id] = servertest.Start(t, servertest.WithCounterStore(node)).Client
+		peerListeners[id] = startPeerServer(t, node, nil)
+	}
+
+	for _, from := range ids {
+		for _, to := range ids {
+			if from == to {
+				continue
+			}
+			// Calls over a cut link fail as if the peer was unreachable.
+			linkInterceptor := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				if network.isCut(from, to) {
+					return status.Errorf(codes.Unavailable, "link from %s to %s is cut", from, to)
+				}
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}
+			connection := dialPeer(t, peerListeners[to], insecure.NewCredentials(), grpc.WithUnaryInterceptor(linkInterceptor))
+			nodes[from].AddPeer(to, pb.NewClusterPeerClient(connection))
+		}
+	}
+
+	return network, nodes, clients
+}
+
+// gossip runs enough gossip rounds for counters to spread across the whole cluster.
+func gossip(nodes map[string]*Node) {
+	for range 2 {
+		for _, node := range nodes {
+			node.GossipOnce(context.Background(), time.Second)
+		}
+	}
+}
+
+// addN calls MagicAdd n times on the given server.
+func addN(t *testing.T, client pb.MagicMathClient, n int) {
+	t.Helper()
+
+	for range n {
+		if _, err := client.MagicAdd(context.Background(), &pb.DoubleTerms{TermOne: 1, TermTwo: 2}); err != nil {
+			t.Fatalf("MagicAdd() returned error: %v", err)
+		}
+	}
+}
+
+// addCounts returns what GetAddCount answers on each server.
+func addCounts(t *testing.T, clients map[string]pb.MagicMathClient) map[string]int64 {
+	t.Helper()
+
+	counts := make(map[string]int64)
+	for id, client := range clients {
+		count, err := client.GetAddCount(context.Background(), &pb.Empty{})
+		if err != nil {
+			t.Fatalf("GetAddCount() on %s returned error: %v", id, err)
+		}
+		counts[id] = count.Count
+	}
+
+	return counts
+}
+
+func TestClusterConverges(t *testing.T) {
+	_, nodes, clients := startCluster(t, "a", "b", "c")
+
+	addN(t, clients["a"], 3)
+	addN(t, clients["b"], 5)
+	addN(t, clients["c"], 7)
+	gossip(nodes)
+
+	for id, count := range addCounts(t, clients) {
+		if count != 15 {
+			t.Errorf("GetAddCount() on %s = %d; want 15", id, count)
+		}
+	}
+}
+
+func TestClusterPartitionAndHeal(t *testing.T) {
+	network, nodes, clients := startCluster(t, "a", "b", "c")
+
+	addN(t, clients["a"], 1)
+	gossip(nodes)
+
+	// Cut node a off from the rest of the cluster, both sides keep serving requests.
+	network.partition([]string{"a"}, []string{"b", "c"})
+	addN(t, clients["a"], 2)
+	addN(t, clients["b"], 4)
+	addN(t, clients["c"], 8)
+	gossip(nodes)
+
+	want := map[string]int64{"a": 3, "b": 13, "c": 13}
+	for id, count := range addCounts(t, clients) {
+		if count != want[id] {
+			t.Errorf("while partitioned, GetAddCount() on %s = %d; want %d", id, count, want[id])
+		}
+	}
+
+	// Once the partition heals, every node agrees on the cluster-wide total.
+	network.heal()
+	gossip(nodes)
+
+	for id, count := range addCounts(t, clients) {
+		if count != 15 {
+			t.Errorf("after healing, GetAddCount() on %s = %d; want 15", id, count)
+		}
+	}
+}
+
+func TestNodeRunGossipsInBackground(t *testing.T) {
+	_, nodes, clients := startCluster(t, "a", "b")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, node := range nodes {
+		go node.Run(ctx, 10*time.Millisecond)
+	}
+
+	addN(t, clients["a"], 2)
+	addN(t, clients["b"], 3)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		counts := addCounts(t, clients)
+		if counts["a"] == 5 && counts["b"] == 5 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("counters did not converge: %v", counts)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClusterPrune(t *testing.T) {
+	_, nodes, _ := startCluster(t, "a", "b")
+	nodes["a"].Increment("quota/old")
+	nodes["b"].Increment("quota/new")
+	gossip(nodes)
+
+	expired := func(name string) bool { return name == "quota/old" }
+	nodes["a"].Prune("quota/", expired)
+	if got := nodes["a"].Count("quota/old"); got != 0 {
+		t.Errorf("Count(quota/old) after pruning = %d; want 0", got)
+	}
+
+	// Once every node has pruned the counter, gossip no longer brings it back.
+	nodes["b"].Prune("quota/", expired)
+	gossip(nodes)
+	for id, node := range nodes {
+		if old, kept := node.Count("quota/old"), node.Count("quota/new"); old != 0 || kept != 1 {
+			t.Errorf("counts on %s after pruning = %d, %d; want quota/old 0 and quota/new 1", id, old, kept)
+		}
+	}
+}
+
+func TestRestartedNodeKeepsItsCounts(t *testing.T) {
+	peer := NewNode("b")
+	node := NewNode("a")
+	for range 3 {
+		node.Increment("add")
+	}
+	peer.Gossip(context.Background(), node.state())
+
+	// The node restarts several times, and each time counts a call before it hears from its peer again.
+	for restart := 1; restart <= 3; restart++ {
+		restarted := NewNode("a")
+		restarted.incarnation = node.incarnation + int64(restart)
+		restarted.Increment("add")
+		restarted.merge(peer.state())
+		peer.Gossip(context.Background(), restarted.state())
+
+		if got, want := restarted.Count("add"), int64(3+restart); got != want {
+			t.Errorf("Count(%q) after restart %d = %d; want %d", "add", restart, got, want)
+		}
+		// The peer keeps a single entry for the node, however often it restarts.
+		if replicas := len(peer.state().GetCounters()["add"].GetShares()); replicas != 1 {
+			t.Errorf("peer state after restart %d has %d replicas of %q; want 1", restart, replicas, "add")
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// NewPeerServer creates a gRPC server which serves the node's ClusterPeer service, and nothing else.
+//
+// Any caller of Gossip can change the node's counters, including every caller's quota, and reads all of them back. So
+// the service is served on a listener of its own, which only the other servers of the cluster can reach: either one on
+// a private network, or one secured with PeerCredentials, so that only servers with a certificate from the cluster's
+// certificate authority are answered. The credentials may be nil for a listener on a private network.
+func NewPeerServer(node *Node, creds credentials.TransportCredentials) *grpc.Server {
+	var options []grpc.ServerOption
+	if creds != nil {
+		options = append(options, grpc.Creds(creds))
+	}
+
+	s := grpc.NewServer(options...)
+	pb.RegisterClusterPeerServer(s, node)
+
+	return s
+}
+
+// PeerCredentials loads the certificate and key which a server presents to its peers, and the certificate authority
+// which issued the certificates of every server in the cluster, all of them PEM encoded files.
+// The server credentials only accept calls from peers with a certificate from the authority, and the client
+// credentials only connect to peers with one.
+func PeerCredentials(certFile, keyFile, caFile string) (credentials.TransportCredentials, credentials.TransportCredentials, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load the peer certificate: %v", err)
+	}
+
+	authority, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the peer certificate authority: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(authority) {
+		return nil, nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    roots,
+		MinVersion:   tls.VersionTLS12,
+	}
+	clientConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      roots,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	return credentials.NewTLS(serverConfig), credentials.NewTLS(clientConfig), nil
+}
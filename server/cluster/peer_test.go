@@ -0,0 +1,173 @@
+package cluster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startPeerServer serves the node's peer service over an in-memory connection until the test finishes.
+func startPeerServer(t *testing.T, node *Node, creds credentials.TransportCredentials) *bufconn.Listener {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewPeerServer(node, creds)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener
+}
+
+// dialPeer connects to a peer server started by startPeerServer. The connection is closed when the test finishes.
+func dialPeer(
+	t *testing.T,
+	listener *bufconn.Listener,
+	creds credentials.TransportCredentials,
+	options ...grpc.DialOption,
+) *grpc.ClientConn {
+	t.Helper()
+
+	options = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(creds),
+	}, options...)
+	connection, err := grpc.NewClient("passthrough:///bufnet", options...)
+	if err != nil {
+		t.Fatalf("failed to connect to the peer server: %v", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+
+	return connection
+}
+
+// writePeerCertificates writes a certificate authority, and a certificate it issued for "bufnet" with its key, to PEM
+// files in a temporary directory, and returns their paths.
+func writePeerCertificates(t *testing.T) (certFile, keyFile, caFile string) {
+	t.Helper()
+
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	writePEM := func(name, blockType string, bytes []byte) string {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	caKey := newKey()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cluster authority"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peerKey := newKey()
+	peerTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "bufnet"},
+		DNSNames:     []string{"bufnet"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	peerDER, err := x509.CreateCertificate(rand.Reader, peerTemplate, caTemplate, &peerKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(peerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return writePEM("peer.pem", "CERTIFICATE", peerDER), writePEM("peer-key.pem", "PRIVATE KEY", keyDER),
+		writePEM("ca.pem", "CERTIFICATE", caDER)
+}
+
+func TestMainServerDoesNotServePeers(t *testing.T) {
+	node := NewNode("a")
+	server := servertest.Start(t, servertest.WithCounterStore(node))
+
+	forged := &pb.CounterState{ReplicaId: "forged", Counters: map[string]*pb.ReplicaCounts{
+		"add": {Shares: map[string]*pb.ReplicaShare{"forged": {Count: 1 << 40}}},
+	}}
+	_, err := pb.NewClusterPeerClient(server.Conn).Gossip(context.Background(), forged)
+	if status.Code(err) != codes.Unimplemented || node.Count("add") != 0 {
+		t.Errorf("Gossip() on the main server returned error %v, add count %d; want code %v and 0",
+			err, node.Count("add"), codes.Unimplemented)
+	}
+}
+
+func TestPeerCredentials(t *testing.T) {
+	certFile, keyFile, caFile := writePeerCertificates(t)
+	serverCredentials, clientCredentials, err := PeerCredentials(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("PeerCredentials() returned error: %v", err)
+	}
+
+	node := NewNode("a")
+	node.Increment("add")
+	listener := startPeerServer(t, node, serverCredentials)
+
+	// A peer with a certificate from the authority is answered.
+	peer := pb.NewClusterPeerClient(dialPeer(t, listener, clientCredentials))
+	reply, err := peer.Gossip(context.Background(), &pb.CounterState{ReplicaId: "b"})
+	if err != nil || reply.GetCounters()["add"].GetShares()["a"].GetCount() != 1 {
+		t.Errorf("Gossip() from a peer = %v, %v; want the add count of a", reply, err)
+	}
+
+	// A client which trusts the server, but has no certificate of its own, is turned away.
+	roots := x509.NewCertPool()
+	authority, _ := os.ReadFile(caFile)
+	roots.AppendCertsFromPEM(authority)
+	stranger := credentials.NewTLS(&tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12})
+	_, err = pb.NewClusterPeerClient(dialPeer(t, listener, stranger)).Gossip(context.Background(), &pb.CounterState{})
+	if err == nil {
+		t.Errorf("Gossip() from a client without a certificate succeeded; want an error")
+	}
+
+	// So is a client without TLS.
+	plain := pb.NewClusterPeerClient(dialPeer(t, listener, insecure.NewCredentials()))
+	if _, err := plain.Gossip(context.Background(), &pb.CounterState{}); err == nil {
+		t.Errorf("Gossip() from a client without TLS succeeded; want an error")
+	}
+
+	if _, _, err := PeerCredentials(certFile, keyFile, caFile+".missing"); err == nil {
+		t.Errorf("PeerCredentials() with a missing authority file returned no error")
+	}
+}
@@ -0,0 +1,99 @@
+package counter
+
+import (
+	"strings"
+	"sync"
+)
+
+// These are the names of the counters kept for each math function.
+const (
+	Add      = "add"
+	Subtract = "sub"
+	FindMin  = "min"
+	FindMax  = "max"
+	Multiply = "mul"
+	Divide   = "div"
+	Modulo   = "mod"
+	Power    = "pow"
+	Evaluate = "eval"
+)
+
+// Stats is the name of the counter shared by the two statistics functions.
+const Stats = "stats"
+
+// Big is the name of the counter shared by the five arbitrary precision math functions.
+const Big = "big"
+
+// Decimal is the name of the counter shared by the four decimal math functions.
+const Decimal = "decimal"
+
+// These are the names of the counters of calls answered from the result cache, and of cacheable calls which weren't.
+const (
+	CacheHit  = "cache_hit"
+	CacheMiss = "cache_miss"
+)
+
+// Panic is the name of the counter of calls which failed because the server panicked while handling them.
+const Panic = "panic"
+
+// Names lists the name of every counter.
+var Names = []string{
+	Add, Subtract, FindMin, FindMax, Multiply, Divide, Modulo, Power, Evaluate,
+	Stats, Big, Decimal, CacheHit, CacheMiss, Panic,
+}
+
+// Store keeps track of how many times each function has been called.
+// Implementations must be safe to use from many go routines at once.
+type Store interface {
+	// Increment adds one to the named counter.
+	Increment(name string)
+	// Count returns the current value of the named counter, or zero if it has never been incremented.
+	Count(name string) int64
+}
+
+// Pruner is a Store which can drop counters that are no longer needed, such as counters named after a window of time
+// which has ended, so that they don't pile up forever.
+type Pruner interface {
+	// Prune drops every counter whose name starts with the prefix, and for which expired returns true.
+	Prune(prefix string, expired func(name string) bool)
+}
+
+// MemoryStore is a Store which keeps the counters in memory, they are lost when the server stops.
+type MemoryStore struct {
+	// This mutex is used to protect read and write access to the counters map.
+	mutex    sync.Mutex
+	counters map[string]int64
+}
+
+// NewMemoryStore creates an empty in-memory counter store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: make(map[string]int64)}
+}
+
+// Increment adds one to the named counter.
+func (s *MemoryStore) Increment(name string) {
+	s.mutex.Lock()
+	s.counters[name]++
+	s.mutex.Unlock()
+}
+
+// Count returns the current value of the named counter.
+func (s *MemoryStore) Count(name string) int64 {
+	s.mutex.Lock()
+	count := s.counters[name]
+	s.mutex.Unlock()
+
+	return count
+}
+
+// Prune drops every counter whose name starts with the prefix, and for which expired returns true.
+func (s *MemoryStore) Prune(prefix string, expired func(name string) bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for name := range s.counters {
+		if strings.HasPrefix(name, prefix) && expired(name) {
+			delete(s.counters, name)
+		}
+	}
+}
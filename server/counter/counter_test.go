@@ -0,0 +1,56 @@
+package counter
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+
+	var waitGroup sync.WaitGroup
+	for range 100 {
+		waitGroup.Go(func() { store.Increment(Add) })
+	}
+	waitGroup.Wait()
+	store.Increment(FindMax)
+
+	tests := []struct {
+		name string
+		want int64
+	}{
+		{Add, 100},
+		{FindMax, 1},
+		{Subtract, 0}, // never incremented
+	}
+
+	for _, tt := range tests {
+		if got := store.Count(tt.name); got != tt.want {
+			t.Errorf("Count(%q) = %d; want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryStorePrune(t *testing.T) {
+	store := NewMemoryStore()
+	for _, name := range []string{"quota/1", "quota/2", "add"} {
+		store.Increment(name)
+	}
+
+	store.Prune("quota/", func(name string) bool { return name != "quota/2" })
+
+	tests := []struct {
+		name string
+		want int64
+	}{
+		{"quota/1", 0},
+		{"quota/2", 1},
+		{"add", 1}, // doesn't start with the prefix
+	}
+
+	for _, tt := range tests {
+		if got := store.Count(tt.name); got != tt.want {
+			t.Errorf("Count(%q) after pruning = %d; want %d", tt.name, got, tt.want)
+		}
+	}
+}
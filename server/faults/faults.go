@@ -0,0 +1,283 @@
+package faults
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/methods"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Any matches every method, or every caller, in a rule.
+const Any = "*"
+
+// DefaultDropTimeout is how long a dropped response is held back when the rule doesn't give a timeout.
+const DefaultDropTimeout = 30 * time.Second
+
+// A rule is a fault rule, along with how many faults it has injected.
+type rule struct {
+	config   *pb.FaultRule
+	injected atomic.Int64
+}
+
+// Injector injects faults into MagicMath and VectorMath calls following its rules, which can be changed at any time
+// through the FaultInjection service it implements. Every injected fault is logged, and counted against its rule.
+type Injector struct {
+	pb.UnimplementedFaultInjectionServer
+
+	// This mutex is used to protect the rules, and the random number generator.
+	mutex sync.Mutex
+	rules []*rule
+	rng   *rand.Rand
+}
+
+// New creates an injector with no rules, which doesn't inject any faults until it is given some.
+func New() *Injector {
+	return &Injector{rng: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))}
+}
+
+// SetRules validates and replaces every rule.
+func (i *Injector) SetRules(rules *pb.FaultRules) error {
+	newRules := make([]*rule, 0, len(rules.GetRules()))
+	for index, config := range rules.GetRules() {
+		if err := validate(config); err != nil {
+			return fmt.Errorf("rule %d: %v", index, err)
+		}
+
+		config = proto.CloneOf(config)
+		config.Injected = 0
+		newRules = append(newRules, &rule{config: config})
+	}
+
+	i.mutex.Lock()
+	i.rules = newRules
+	i.mutex.Unlock()
+
+	log.Printf("fault injection rules set to %d rules", len(newRules))
+
+	return nil
+}
+
+// Rules returns the current rules, along with how many faults each one has injected.
+func (i *Injector) Rules() *pb.FaultRules {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	rules := &pb.FaultRules{}
+	for _, r := range i.rules {
+		config := proto.CloneOf(r.config)
+		config.Injected = r.injected.Load()
+		rules.Rules = append(rules.Rules, config)
+	}
+
+	return rules
+}
+
+// SetFaults replaces every fault rule, and returns the new rules.
+func (i *Injector) SetFaults(_ context.Context, in *pb.FaultRules) (*pb.FaultRules, error) {
+	if err := i.SetRules(in); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return i.Rules(), nil
+}
+
+// GetFaults returns the current rules, along with how many faults each one has injected.
+func (i *Injector) GetFaults(_ context.Context, _ *pb.FaultRulesRequest) (*pb.FaultRules, error) {
+	return i.Rules(), nil
+}
+
+// UnaryServerInterceptor injects faults into MagicMath and VectorMath calls, following the rules which match each call.
+func (i *Injector) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !methods.IsMath(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		method := path.Base(info.FullMethod)
+		id := caller.ID(ctx)
+		var drop *pb.DropFault
+		corrupt := false
+		for _, fault := range i.choose(method, id) {
+			log.Printf("fault injection: %s into %s called by %s", describe(fault.config), method, id)
+			fault.injected.Add(1)
+
+			switch kind := fault.config.Fault.(type) {
+			case *pb.FaultRule_Latency:
+				timer := time.NewTimer(i.delay(kind.Latency))
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, status.FromContextError(ctx.Err()).Err()
+				}
+			case *pb.FaultRule_Error:
+				return nil, status.Error(codes.Code(kind.Error.Code), kind.Error.Message)
+			case *pb.FaultRule_Drop:
+				drop = kind.Drop
+			case *pb.FaultRule_Corrupt:
+				corrupt = true
+			}
+		}
+
+		resp, err := handler(ctx, req)
+
+		if drop != nil {
+			// The call has been handled, but the client never hears back.
+			timeout := DefaultDropTimeout
+			if drop.Timeout != nil {
+				timeout = drop.Timeout.AsDuration()
+			}
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return nil, status.Error(codes.DeadlineExceeded, "response dropped")
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+		}
+		if corrupt && err == nil {
+			if message, ok := resp.(proto.Message); ok {
+				resp = i.corrupt(message)
+			}
+		}
+
+		return resp, err
+	}
+}
+
+// This function rolls the dice for every rule which matches the call, and returns the ones to inject.
+func (i *Injector) choose(method, id string) []*rule {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	var chosen []*rule
+	for _, r := range i.rules {
+		if matches(r.config.Method, method) && matches(r.config.Caller, id) && i.rng.Float64()*100 < r.config.Percentage {
+			chosen = append(chosen, r)
+		}
+	}
+
+	return chosen
+}
+
+// This function draws how long to delay a call from the latency's distribution.
+func (i *Injector) delay(latency *pb.Latency) time.Duration {
+	base, spread := latency.Base.AsDuration(), latency.Spread.AsDuration()
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	switch latency.Distribution {
+	case "uniform":
+		return base + time.Duration(i.rng.Float64()*float64(spread))
+	case "exponential":
+		return base + time.Duration(i.rng.ExpFloat64()*float64(spread))
+	default:
+		return base
+	}
+}
+
+// This function returns a copy of the message with a random bit flipped in each of its populated number fields.
+// Only populated fields are changed, so that setting one member of a oneof doesn't replace the one which was set.
+// The bits of a double are only flipped in its fraction, so that it stays a finite number.
+func (i *Injector) corrupt(message proto.Message) proto.Message {
+	corrupted := proto.Clone(message)
+	reflection := corrupted.ProtoReflect()
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	// Fields can't be set while ranging over them, so the new values are collected first.
+	changed := make(map[protoreflect.FieldDescriptor]protoreflect.Value)
+	reflection.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.IsList() || field.IsMap() {
+			return true
+		}
+
+		switch field.Kind() {
+		case protoreflect.DoubleKind:
+			bits := math.Float64bits(value.Float()) ^ (1 << i.rng.IntN(52))
+			changed[field] = protoreflect.ValueOfFloat64(math.Float64frombits(bits))
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			changed[field] = protoreflect.ValueOfInt64(value.Int() ^ (1 << i.rng.IntN(63)))
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+			changed[field] = protoreflect.ValueOfInt32(int32(value.Int()) ^ (1 << i.rng.IntN(31)))
+		}
+		return true
+	})
+	for field, value := range changed {
+		reflection.Set(field, value)
+	}
+
+	return corrupted
+}
+
+// This function reports whether a rule's method or caller pattern matches the name.
+func matches(pattern, name string) bool {
+	return pattern == "" || pattern == Any || pattern == name
+}
+
+// This function checks that a rule can be applied.
+func validate(config *pb.FaultRule) error {
+	if !(config.Percentage >= 0 && config.Percentage <= 100) {
+		return fmt.Errorf("percentage %v must be between 0 and 100", config.Percentage)
+	}
+
+	switch kind := config.Fault.(type) {
+	case *pb.FaultRule_Latency:
+		switch kind.Latency.Distribution {
+		case "", "fixed", "uniform", "exponential":
+		default:
+			return fmt.Errorf("unknown latency distribution %q, expected fixed, uniform or exponential", kind.Latency.Distribution)
+		}
+		if kind.Latency.Base.AsDuration() < 0 || kind.Latency.Spread.AsDuration() < 0 {
+			return fmt.Errorf("latency must not be negative")
+		}
+	case *pb.FaultRule_Error:
+		if code := codes.Code(kind.Error.Code); code == codes.OK || code > codes.Unauthenticated {
+			return fmt.Errorf("invalid error code %d", kind.Error.Code)
+		}
+	case *pb.FaultRule_Drop:
+		if kind.Drop.Timeout != nil && kind.Drop.Timeout.AsDuration() <= 0 {
+			return fmt.Errorf("drop timeout must be positive")
+		}
+	case *pb.FaultRule_Corrupt:
+	default:
+		return fmt.Errorf("no fault given")
+	}
+
+	return nil
+}
+
+// This function describes a rule's fault, for the log.
+func describe(config *pb.FaultRule) string {
+	switch kind := config.Fault.(type) {
+	case *pb.FaultRule_Latency:
+		if kind.Latency.Distribution == "" {
+			return "fixed latency"
+		}
+		return kind.Latency.Distribution + " latency"
+	case *pb.FaultRule_Error:
+		return codes.Code(kind.Error.Code).String() + " error"
+	case *pb.FaultRule_Drop:
+		return "dropped response"
+	default:
+		return "corrupted response"
+	}
+}
@@ -0,0 +1,179 @@
+package faults
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var (
+	addInfo      = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicAdd_FullMethodName}
+	minInfo      = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicFindMin_FullMethodName}
+	multiplyInfo = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicMultiply_FullMethodName}
+)
+
+// This function makes a call from the caller through the injector, to a handler which returns the result.
+func call(ctx context.Context, injector *Injector, info *grpc.UnaryServerInfo, id string, result any) (any, error) {
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(caller.MetadataKey, id))
+	return injector.UnaryServerInterceptor()(ctx, nil, info, func(context.Context, any) (any, error) {
+		return result, nil
+	})
+}
+
+func TestErrorsAreTargeted(t *testing.T) {
+	injector := New()
+	err := injector.SetRules(&pb.FaultRules{Rules: []*pb.FaultRule{{
+		Method:     "MagicAdd",
+		Caller:     "alice",
+		Percentage: 100,
+		Fault:      &pb.FaultRule_Error{Error: &pb.ErrorFault{Code: int32(codes.Unavailable), Message: "injected"}},
+	}}})
+	if err != nil {
+		t.Fatalf("SetRules() returned error: %v", err)
+	}
+
+	tests := []struct {
+		info   *grpc.UnaryServerInfo
+		caller string
+		want   codes.Code
+	}{
+		{addInfo, "alice", codes.Unavailable},
+		{addInfo, "bob", codes.OK},
+		{minInfo, "alice", codes.OK},
+	}
+
+	for _, tt := range tests {
+		if _, err := call(context.Background(), injector, tt.info, tt.caller, &pb.DoubleResult{}); status.Code(err) != tt.want {
+			t.Errorf("%s called by %s returned %v; want %v", tt.info.FullMethod, tt.caller, err, tt.want)
+		}
+	}
+
+	if got := injector.Rules().Rules[0].Injected; got != 1 {
+		t.Errorf("injected = %d; want 1", got)
+	}
+}
+
+func TestPercentage(t *testing.T) {
+	injector := New()
+	injector.SetRules(&pb.FaultRules{Rules: []*pb.FaultRule{{
+		Percentage: 25,
+		Fault:      &pb.FaultRule_Error{Error: &pb.ErrorFault{Code: int32(codes.Internal)}},
+	}}})
+
+	failed := 0
+	for range 4000 {
+		if _, err := call(context.Background(), injector, addInfo, "alice", &pb.DoubleResult{}); err != nil {
+			failed++
+		}
+	}
+	if failed < 800 || failed > 1200 {
+		t.Errorf("%d of 4000 calls failed; want about 1000", failed)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	injector := New()
+	injector.SetRules(&pb.FaultRules{Rules: []*pb.FaultRule{{
+		Percentage: 100,
+		Fault: &pb.FaultRule_Latency{Latency: &pb.Latency{
+			Distribution: "uniform", Base: durationpb.New(20 * time.Millisecond), Spread: durationpb.New(10 * time.Millisecond),
+		}},
+	}}})
+
+	start := time.Now()
+	if _, err := call(context.Background(), injector, addInfo, "alice", &pb.DoubleResult{}); err != nil {
+		t.Errorf("delayed call returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("delayed call took %v; want at least 20ms", elapsed)
+	}
+
+	// A call whose deadline passes while it is delayed fails.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := call(ctx, injector, addInfo, "alice", &pb.DoubleResult{}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("delayed call past its deadline returned %v; want DeadlineExceeded", err)
+	}
+}
+
+func TestDrop(t *testing.T) {
+	injector := New()
+	injector.SetRules(&pb.FaultRules{Rules: []*pb.FaultRule{{Percentage: 100, Fault: &pb.FaultRule_Drop{Drop: &pb.DropFault{}}}}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	handled := false
+	_, err := injector.UnaryServerInterceptor()(ctx, nil, addInfo, func(context.Context, any) (any, error) {
+		handled = true
+		return &pb.DoubleResult{}, nil
+	})
+	if !handled || status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("dropped call returned %v, handled %v; want DeadlineExceeded after the call was handled", err, handled)
+	}
+
+	// A call without a deadline is held back until the drop's timeout, rather than forever.
+	timeout := durationpb.New(10 * time.Millisecond)
+	injector.SetRules(&pb.FaultRules{Rules: []*pb.FaultRule{
+		{Percentage: 100, Fault: &pb.FaultRule_Drop{Drop: &pb.DropFault{Timeout: timeout}}},
+	}})
+	if _, err := call(context.Background(), injector, addInfo, "alice", &pb.DoubleResult{}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("dropped call without a deadline returned %v; want DeadlineExceeded", err)
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	injector := New()
+	injector.SetRules(&pb.FaultRules{Rules: []*pb.FaultRule{{Percentage: 100, Fault: &pb.FaultRule_Corrupt{Corrupt: &pb.CorruptFault{}}}}})
+
+	original := &pb.DoubleResult{Result: 1.5}
+	resp, err := call(context.Background(), injector, addInfo, "alice", original)
+	if err != nil {
+		t.Fatalf("corrupted call returned error: %v", err)
+	}
+	got := resp.(*pb.DoubleResult).Result
+	if got == 1.5 || math.IsNaN(got) || math.IsInf(got, 0) || original.Result != 1.5 {
+		t.Errorf("corrupted result = %v, original now %v; want a different finite number, with the original unchanged", got, original.Result)
+	}
+
+	resp, _ = call(context.Background(), injector, minInfo, "alice", &pb.IntResult{Result: 7})
+	if got := resp.(*pb.IntResult).Result; got == 7 {
+		t.Errorf("corrupted result = %d; want a number other than 7", got)
+	}
+
+	// Only the member of a oneof which is set is corrupted, so the result stays a double.
+	arithmetic := &pb.ArithmeticResult{Result: &pb.ArithmeticResult_DoubleResult{DoubleResult: 1.5}}
+	resp, _ = call(context.Background(), injector, multiplyInfo, "alice", arithmetic)
+	if got, isDouble := resp.(*pb.ArithmeticResult).Result.(*pb.ArithmeticResult_DoubleResult); !isDouble || got.DoubleResult == 1.5 {
+		t.Errorf("corrupted result = %v; want a double other than 1.5", resp)
+	}
+}
+
+func TestSetRulesValidates(t *testing.T) {
+	invalid := []*pb.FaultRule{
+		{Percentage: 101, Fault: &pb.FaultRule_Drop{Drop: &pb.DropFault{}}},
+		{Percentage: 50},
+		{Percentage: 50, Fault: &pb.FaultRule_Error{Error: &pb.ErrorFault{Code: 0}}},
+		{Percentage: 50, Fault: &pb.FaultRule_Error{Error: &pb.ErrorFault{Code: 99}}},
+		{Percentage: 50, Fault: &pb.FaultRule_Latency{Latency: &pb.Latency{Distribution: "pareto"}}},
+		{Percentage: 50, Fault: &pb.FaultRule_Latency{Latency: &pb.Latency{Base: durationpb.New(-time.Second)}}},
+		{Percentage: 50, Fault: &pb.FaultRule_Drop{Drop: &pb.DropFault{Timeout: durationpb.New(0)}}},
+	}
+
+	injector := New()
+	for _, rule := range invalid {
+		if _, err := injector.SetFaults(context.Background(), &pb.FaultRules{Rules: []*pb.FaultRule{rule}}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("SetFaults(%v) returned %v; want InvalidArgument", rule, err)
+		}
+	}
+}
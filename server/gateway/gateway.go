@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	pbv2 "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+)
+
+// OpenAPIPath is the path the gateway serves the OpenAPI document at.
+const OpenAPIPath = "/openapi.json"
+
+// New creates an HTTP handler which translates JSON requests into calls to the MagicMath (both versions), VectorMath
+// and Operations remote functions. The routes are the ones given by the google.api.http options in the proto files,
+// for example "POST /v1/add", "GET /v1/counts/add", "POST /v1/vector/dot" and "POST /v2/calculate". The Operations
+// routes only work if the server serves the Operations service. Each request is forwarded to the gRPC
+// server through the connection, so it goes through exactly the same path as a request from a gRPC client would.
+func New(ctx context.Context, connection grpc.ClientConnInterface) (http.Handler, error) {
+	gatewayMux := runtime.NewServeMux()
+	if err := pb.RegisterMagicMathHandlerClient(ctx, gatewayMux, pb.NewMagicMathClient(connection)); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterVectorMathHandlerClient(ctx, gatewayMux, pb.NewVectorMathClient(connection)); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterOperationsHandlerClient(ctx, gatewayMux, pb.NewOperationsClient(connection)); err != nil {
+		return nil, err
+	}
+	if err := pbv2.RegisterMagicMathHandlerClient(ctx, gatewayMux, pbv2.NewMagicMathClient(connection)); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+OpenAPIPath, serveOpenAPI)
+	mux.Handle("/", gatewayMux)
+
+	return mux, nil
+}
+
+// This function serves the OpenAPI document generated from the proto files.
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(pb.OpenAPI)
+}
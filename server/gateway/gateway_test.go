@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/operations"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+)
+
+// startGateway starts an in-process gRPC server, and an HTTP test server running the gateway in front of it.
+func startGateway(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	handler, err := New(context.Background(), servertest.Start(t).Conn)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+
+	return httpServer
+}
+
+// request sends an HTTP request to the gateway, and decodes the JSON response.
+func request(t *testing.T, method, url, body string) (int, map[string]any) {
+	t.Helper()
+
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s returned error: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	decoded := map[string]any{}
+	contents, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(contents, &decoded); err != nil {
+		t.Fatalf("%s %s returned invalid JSON %q: %v", method, url, contents, err)
+	}
+
+	return resp.StatusCode, decoded
+}
+
+func TestGatewayRoutes(t *testing.T) {
+	httpServer := startGateway(t)
+
+	tests := []struct {
+		method, path, body string
+		want               map[string]any
+	}{
+		{"POST", "/v1/add", `{"termOne": 1.5, "termTwo": 2}`, map[string]any{"result": 3.5}},
+		{"POST", "/v1/subtract", `{"termOne": 10, "termTwo": 3}`, map[string]any{"result": 7.0}},
+		// 64 bit integers are written as strings in JSON, so that JavaScript clients don't lose precision.
+		{"POST", "/v1/min", `{"termOne": "3", "termTwo": -1, "termThree": 7}`, map[string]any{"result": "-1"}},
+		{"POST", "/v1/max", `{"termOne": 3, "termTwo": -1, "termThree": 7}`, map[string]any{"result": "7"}},
+		{"GET", "/v1/counts/add", "", map[string]any{"count": "1"}},
+		{"GET", "/v1/counts/sub", "", map[string]any{"count": "1"}},
+		{"GET", "/v1/counts/min", "", map[string]any{"count": "1"}},
+		{"GET", "/v1/counts/max", "", map[string]any{"count": "1"}},
+		{"POST", "/v1/vector/dot", `{"a": {"values": [1, 2, 3]}, "b": {"values": [4, 5, 6]}}`, map[string]any{"value": 32.0}},
+		{"POST", "/v1/vector/norm", `{"vector": {"values": [3, -4]}, "norm": "l1"}`, map[string]any{"value": 7.0}},
+		{"POST", "/v2/calculate", `{"operation": "OPERATION_MAX", "integers": {"values": [3, "-1", 7]}}`,
+			map[string]any{"integerResult": "7"}},
+		// Both versions share their counters, so the max count includes the v1 call above.
+		{"GET", "/v2/counters?names=max&names=add", "", map[string]any{"counters": map[string]any{"max": "2", "add": "1"}}},
+	}
+
+	for _, tt := range tests {
+		code, got := request(t, tt.method, httpServer.URL+tt.path, tt.body)
+		if code != http.StatusOK {
+			t.Errorf("%s %s status = %d; want %d", tt.method, tt.path, code, http.StatusOK)
+			continue
+		}
+		for key, value := range tt.want {
+			if !reflect.DeepEqual(got[key], value) {
+				t.Errorf("%s %s = %v; want %s: %v", tt.method, tt.path, got, key, value)
+			}
+		}
+	}
+}
+
+func TestGatewayOperations(t *testing.T) {
+	server := servertest.Start(t, servertest.WithOperations(operations.Options{}))
+	handler, err := New(context.Background(), server.Conn)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+
+	code, submitted := request(t, "POST", httpServer.URL+"/v1/operations", `{"method": "MagicAdd",
+		"request": {"@type": "type.googleapis.com/shared.DoubleTerms", "termOne": 1.5, "termTwo": 2}}`)
+	name, _ := submitted["name"].(string)
+	if code != http.StatusOK || name == "" {
+		t.Fatalf("POST /v1/operations = %d %v; want an operation", code, submitted)
+	}
+
+	code, waited := request(t, "POST", httpServer.URL+"/v1/"+name+":wait", `{"timeout": "5s"}`)
+	response, _ := waited["response"].(map[string]any)
+	if code != http.StatusOK || waited["done"] != true || response["result"] != 3.5 {
+		t.Errorf("POST /v1/%s:wait = %d %v; want a done operation with the result 3.5", name, code, waited)
+	}
+}
+
+func TestGatewayRejectsMalformedJSON(t *testing.T) {
+	httpServer := startGateway(t)
+
+	code, _ := request(t, "POST", httpServer.URL+"/v1/add", `{"termOne": "not a number"}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("POST /v1/add with malformed body status = %d; want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestGatewayServesOpenAPI(t *testing.T) {
+	httpServer := startGateway(t)
+
+	code, document := request(t, "GET", httpServer.URL+OpenAPIPath, "")
+	if code != http.StatusOK {
+		t.Fatalf("GET %s status = %d; want %d", OpenAPIPath, code, http.StatusOK)
+	}
+
+	paths, _ := document["paths"].(map[string]any)
+	for _, path := range []string{"/v1/add", "/v1/subtract", "/v1/min", "/v1/max", "/v1/counts/add", "/v1/vector/dot", "/v1/matrix/multiply",
+		"/v2/calculate", "/v2/counters"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("OpenAPI document is missing path %s", path)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/methods"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// MetadataKey is the header a client sets to make a call idempotent. Calls from the same caller to the same method with
+// the same key are the same call, however many times they are sent.
+const MetadataKey = "idempotency-key"
+
+// MaxKeyLength is the longest idempotency key the server accepts.
+const MaxKeyLength = 256
+
+// Response is the stored response of a call, which is returned to the call's duplicates.
+type Response struct {
+	// RequestHash is the SHA-256 hash of the call's encoded request, so that reusing a key for a different request can
+	// be rejected.
+	RequestHash []byte `json:"requestHash"`
+	// Type is the full name of the response message, for example "shared.DoubleResult", and Message its encoding.
+	Type    string `json:"type"`
+	Message []byte `json:"message"`
+	// Caller is the identity of the caller who made the call, so that the store can limit how many responses each
+	// caller has stored.
+	Caller string `json:"caller"`
+	// Expires is when the response is forgotten, after which the key can be used again.
+	Expires time.Time `json:"expires"`
+}
+
+// Store keeps the responses of idempotent calls. Implementations must be safe to use from many go routines at once.
+type Store interface {
+	// Get returns the response stored under the key, and false if there is none or it has expired.
+	Get(key string) (Response, bool, error)
+	// Put stores the response under the key, replacing any response already stored under it.
+	Put(key string, response Response) error
+}
+
+// Options tunes the deduplicator. A zero value means the default given for each option.
+type Options struct {
+	// Window is how long a response is stored, and so how long duplicates of a call are recognised. It defaults to a day.
+	Window time.Duration
+	// Store keeps the responses. Defaults to an in-memory store with the default StoreOptions.
+	Store Store
+}
+
+// Deduplicator answers duplicate calls with the response of the first one, so that a retried call is only handled
+// once, and the function counters count it once.
+//
+// Only successful responses are stored, so a call which failed can be retried with the same key. A duplicate which
+// arrives while the first call is still running waits for it to finish.
+//
+// The responses are kept by each server on its own, they aren't shared with the rest of a cluster. A retry which a load
+// balancer sends to a different server than the first call is handled, and counted, again.
+type Deduplicator struct {
+	options Options
+	// now returns the current time, tests replace it to control the clock.
+	now func() time.Time
+
+	// This mutex is used to protect the running map.
+	mutex sync.Mutex
+	// running holds a channel for each key whose first call is being handled, which is closed once it has finished.
+	running map[string]chan struct{}
+}
+
+// New creates a deduplicator with the given options.
+func New(options Options) *Deduplicator {
+	if options.Window <= 0 {
+		options.Window = 24 * time.Hour
+	}
+	if options.Store == nil {
+		options.Store = NewMemoryStore(StoreOptions{})
+	}
+
+	return &Deduplicator{options: options, now: time.Now, running: make(map[string]chan struct{})}
+}
+
+// UnaryServerInterceptor handles the first MagicMath or VectorMath call with each idempotency key, and answers its
+// duplicates with the stored response without calling the handler. Calls without an idempotency key are handled as
+// usual. A key reused for a different request is rejected with codes.InvalidArgument.
+func (d *Deduplicator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		idempotencyKey := keyOf(ctx)
+		request, ok := req.(proto.Message)
+		if idempotencyKey == "" || !ok || !methods.IsMath(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if len(idempotencyKey) > MaxKeyLength {
+			return nil, status.Errorf(codes.InvalidArgument, "the %s header is longer than %d characters",
+				MetadataKey, MaxKeyLength)
+		}
+
+		requestHash, err := hash(request)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode the request: %v", err)
+		}
+
+		// Keys are scoped to the caller and the method, so that callers can't see each other's responses.
+		id := caller.ID(ctx)
+		key := id + " " + info.FullMethod + " " + idempotencyKey
+		for {
+			if resp, found, err := d.stored(key, requestHash); found || err != nil {
+				return resp, err
+			}
+
+			done, first := d.start(key)
+			if first {
+				defer d.finish(key, done)
+				break
+			}
+
+			// Wait for the first call to finish, and then look for its response again. If it failed there is none,
+			// and this call becomes the first.
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+		}
+
+		resp, err := handler(ctx, req)
+		if response, ok := resp.(proto.Message); ok && err == nil {
+			d.store(key, id, requestHash, response)
+		}
+
+		return resp, err
+	}
+}
+
+// This function returns the stored response for the key, if there is one. It fails if the response is for a different
+// request, and treats a store which can't be read as having no response, so that calls are still served.
+func (d *Deduplicator) stored(key string, requestHash []byte) (proto.Message, bool, error) {
+	response, found, err := d.options.Store.Get(key)
+	if err != nil {
+		log.Printf("failed to read the idempotency store: %v", err)
+		return nil, false, nil
+	}
+	if !found || !d.now().Before(response.Expires) {
+		return nil, false, nil
+	}
+	if string(response.RequestHash) != string(requestHash) {
+		return nil, true, status.Errorf(codes.InvalidArgument,
+			"the %s header was already used for a different request", MetadataKey)
+	}
+
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(response.Type))
+	if err != nil {
+		return nil, true, status.Errorf(codes.Internal, "stored response has unknown type %q", response.Type)
+	}
+	message := messageType.New().Interface()
+	if err := proto.Unmarshal(response.Message, message); err != nil {
+		return nil, true, status.Errorf(codes.Internal, "failed to decode the stored response: %v", err)
+	}
+
+	return message, true, nil
+}
+
+// This function stores the response of the first call with the key, logging any failure, as the call itself succeeded.
+func (d *Deduplicator) store(key, id string, requestHash []byte, response proto.Message) {
+	encoded, err := proto.Marshal(response)
+	if err == nil {
+		err = d.options.Store.Put(key, Response{
+			RequestHash: requestHash,
+			Type:        string(proto.MessageName(response)),
+			Message:     encoded,
+			Expires:     d.now().Add(d.options.Window),
+			Caller:      id,
+		})
+	}
+	if err != nil {
+		log.Printf("failed to store the response of an idempotent call: %v", err)
+	}
+}
+
+// This function marks a call with the key as running. It returns true if no other call with the key was running,
+// otherwise it returns the channel which is closed once the running call finishes.
+func (d *Deduplicator) start(key string) (chan struct{}, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if done, ok := d.running[key]; ok {
+		return done, false
+	}
+	done := make(chan struct{})
+	d.running[key] = done
+
+	return done, true
+}
+
+// This function marks the call with the key as finished, waking up its duplicates.
+func (d *Deduplicator) finish(key string, done chan struct{}) {
+	d.mutex.Lock()
+	delete(d.running, key)
+	d.mutex.Unlock()
+
+	close(done)
+}
+
+// This function returns the call's idempotency key, or an empty string if it doesn't have one.
+func keyOf(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(MetadataKey); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// This function returns the SHA-256 hash of the encoded request.
+func hash(request proto.Message) ([]byte, error) {
+	encoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(encoded)
+
+	return sum[:], nil
+}
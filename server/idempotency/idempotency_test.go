@@ -0,0 +1,178 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	addInfo      = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicAdd_FullMethodName}
+	subtractInfo = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicSubtract_FullMethodName}
+)
+
+// This function returns a context for a call with the given caller ID and idempotency key.
+func withKey(callerID, key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-caller-id", callerID, MetadataKey, key))
+}
+
+// This object is a handler which adds the terms, and counts how many times it runs.
+type addingHandler struct {
+	calls atomic.Int64
+}
+
+func (h *addingHandler) handle(_ context.Context, req any) (any, error) {
+	h.calls.Add(1)
+	terms := req.(*pb.DoubleTerms)
+	return &pb.DoubleResult{Result: terms.TermOne + terms.TermTwo}, nil
+}
+
+func TestDuplicatesReturnTheFirstResponse(t *testing.T) {
+	handler := &addingHandler{}
+	interceptor := New(Options{}).UnaryServerInterceptor()
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+
+	for range 3 {
+		resp, err := interceptor(withKey("alice", "key-1"), terms, addInfo, handler.handle)
+		if want := (&pb.DoubleResult{Result: 3}); err != nil || !proto.Equal(resp.(proto.Message), want) {
+			t.Errorf("MagicAdd() = %v, %v; want %v", resp, err, want)
+		}
+	}
+	if calls := handler.calls.Load(); calls != 1 {
+		t.Errorf("handler ran %d times; want 1", calls)
+	}
+}
+
+func TestKeysAreScoped(t *testing.T) {
+	handler := &addingHandler{}
+	interceptor := New(Options{}).UnaryServerInterceptor()
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+
+	// The same key from a different caller, to a different method, or a different key, are all different calls.
+	interceptor(withKey("alice", "key-1"), terms, addInfo, handler.handle)
+	interceptor(withKey("bob", "key-1"), terms, addInfo, handler.handle)
+	interceptor(withKey("alice", "key-1"), terms, subtractInfo, handler.handle)
+	interceptor(withKey("alice", "key-2"), terms, addInfo, handler.handle)
+	// Calls without a key are never deduplicated.
+	interceptor(context.Background(), terms, addInfo, handler.handle)
+	interceptor(context.Background(), terms, addInfo, handler.handle)
+
+	if calls := handler.calls.Load(); calls != 6 {
+		t.Errorf("handler ran %d times; want 6", calls)
+	}
+}
+
+func TestKeyReusedForDifferentRequest(t *testing.T) {
+	handler := &addingHandler{}
+	interceptor := New(Options{}).UnaryServerInterceptor()
+
+	interceptor(withKey("alice", "key-1"), &pb.DoubleTerms{TermOne: 1, TermTwo: 2}, addInfo, handler.handle)
+	_, err := interceptor(withKey("alice", "key-1"), &pb.DoubleTerms{TermOne: 5, TermTwo: 2}, addInfo, handler.handle)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("MagicAdd() with a reused key returned %v; want %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestFailedCallsCanBeRetried(t *testing.T) {
+	interceptor := New(Options{}).UnaryServerInterceptor()
+	ctx := withKey("alice", "key-1")
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+
+	failing := func(context.Context, any) (any, error) { return nil, errors.New("failed") }
+	if _, err := interceptor(ctx, terms, addInfo, failing); err == nil {
+		t.Fatalf("MagicAdd() with a failing handler succeeded")
+	}
+
+	handler := &addingHandler{}
+	if resp, err := interceptor(ctx, terms, addInfo, handler.handle); err != nil || handler.calls.Load() != 1 {
+		t.Errorf("retried MagicAdd() = %v, %v after %d calls; want the handler to run", resp, err, handler.calls.Load())
+	}
+}
+
+func TestResponsesExpire(t *testing.T) {
+	store := NewMemoryStore(StoreOptions{})
+	deduplicator := New(Options{Window: time.Hour, Store: store})
+	now := time.Now()
+	deduplicator.now = func() time.Time { return now }
+	store.now = deduplicator.now
+	handler := &addingHandler{}
+	interceptor := deduplicator.UnaryServerInterceptor()
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+
+	interceptor(withKey("alice", "key-1"), terms, addInfo, handler.handle)
+	now = now.Add(time.Hour)
+	interceptor(withKey("alice", "key-1"), terms, addInfo, handler.handle)
+
+	if calls := handler.calls.Load(); calls != 2 {
+		t.Errorf("handler ran %d times; want 2", calls)
+	}
+}
+
+func TestConcurrentDuplicates(t *testing.T) {
+	handler := &addingHandler{}
+	release := make(chan struct{})
+	slowHandler := func(ctx context.Context, req any) (any, error) {
+		<-release
+		return handler.handle(ctx, req)
+	}
+	interceptor := New(Options{}).UnaryServerInterceptor()
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+
+	// Every duplicate arrives while the first call is still running.
+	var waitGroup sync.WaitGroup
+	results := make([]*pb.DoubleResult, 20)
+	for i := range results {
+		waitGroup.Go(func() {
+			resp, err := interceptor(withKey("alice", "key-1"), terms, addInfo, slowHandler)
+			if err != nil {
+				t.Errorf("MagicAdd() returned error: %v", err)
+				return
+			}
+			results[i] = resp.(*pb.DoubleResult)
+		})
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	waitGroup.Wait()
+
+	if calls := handler.calls.Load(); calls != 1 {
+		t.Errorf("handler ran %d times; want 1", calls)
+	}
+	for i, result := range results {
+		if result.GetResult() != 3 {
+			t.Errorf("call %d = %v; want 3", i, result)
+		}
+	}
+}
+
+func TestWaitingDuplicateGivesUpWithItsContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slowHandler := func(context.Context, any) (any, error) {
+		<-release
+		return &pb.DoubleResult{}, nil
+	}
+	interceptor := New(Options{}).UnaryServerInterceptor()
+	terms := &pb.DoubleTerms{}
+
+	go interceptor(withKey("alice", "key-1"), terms, addInfo, slowHandler)
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(withKey("alice", "key-1"), 10*time.Millisecond)
+	defer cancel()
+	_, err := interceptor(ctx, terms, addInfo, slowHandler)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("waiting duplicate returned %v; want %v", err, codes.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,363 @@
+package idempotency
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// StoreOptions limits how many responses a store holds. A zero value means the default given for each option.
+type StoreOptions struct {
+	// MaxResponses is how many responses the store holds, once it is full the oldest response is evicted.
+	// It defaults to 100000.
+	MaxResponses int
+	// MaxPerCaller is how many responses one caller can have stored, once a caller has that many their oldest response
+	// is evicted, so that one caller can't fill the store and evict everyone else's responses. It defaults to 1000.
+	MaxPerCaller int
+}
+
+// MemoryStore is a Store which keeps the responses in memory, they are lost when the server stops.
+type MemoryStore struct {
+	options StoreOptions
+
+	// This mutex is used to protect read and write access to all of the fields below.
+	mutex sync.Mutex
+	// responses finds the stored responses by their key. order holds them oldest first, and callers holds each
+	// caller's responses oldest first, so that the oldest response can be evicted when the store is full.
+	responses map[string]*storedResponse
+	order     *list.List
+	callers   map[string]*list.List
+	// nextPurge is when the expired responses are next forgotten.
+	nextPurge time.Time
+	// now returns the current time, tests replace it to control the clock.
+	now func() time.Time
+}
+
+// A stored response, along with its place in the store's order and its caller's order.
+type storedResponse struct {
+	key           string
+	response      Response
+	inOrder       *list.Element
+	inCallerOrder *list.Element
+}
+
+// How often a MemoryStore forgets the responses which have expired.
+const purgeInterval = time.Minute
+
+// NewMemoryStore creates an empty in-memory store with the given options.
+func NewMemoryStore(options StoreOptions) *MemoryStore {
+	if options.MaxResponses <= 0 {
+		options.MaxResponses = 100000
+	}
+	if options.MaxPerCaller <= 0 {
+		options.MaxPerCaller = 1000
+	}
+
+	return &MemoryStore{
+		options:   options,
+		responses: make(map[string]*storedResponse),
+		order:     list.New(),
+		callers:   make(map[string]*list.List),
+		now:       time.Now,
+	}
+}
+
+// Get returns the response stored under the key.
+func (s *MemoryStore) Get(key string) (Response, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, ok := s.responses[key]
+	if !ok || !s.now().Before(stored.response.Expires) {
+		return Response{}, false, nil
+	}
+
+	return stored.response, true, nil
+}
+
+// Put stores the response under the key. Every so often it also forgets the responses which have expired, and it
+// evicts the oldest responses when the store, or the response's caller, holds too many.
+func (s *MemoryStore) Put(key string, response Response) error {
+	s.put(key, response)
+	return nil
+}
+
+// This function stores the response under the key, and reports whether it forgot the expired responses first.
+func (s *MemoryStore) put(key string, response Response) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	purged := false
+	if now := s.now(); !now.Before(s.nextPurge) {
+		for _, stored := range s.responses {
+			if !now.Before(stored.response.Expires) {
+				s.remove(stored)
+			}
+		}
+		s.nextPurge = now.Add(purgeInterval)
+		purged = true
+	}
+	s.add(key, response)
+
+	return purged
+}
+
+// This function stores the response under the key, replacing any response stored under it, and then evicts the
+// oldest responses until the store and the caller are within their limits. The mutex must be held.
+func (s *MemoryStore) add(key string, response Response) {
+	if stored, ok := s.responses[key]; ok {
+		s.remove(stored)
+	}
+
+	callerOrder := s.callers[response.Caller]
+	if callerOrder == nil {
+		callerOrder = list.New()
+		s.callers[response.Caller] = callerOrder
+	}
+	stored := &storedResponse{key: key, response: response}
+	stored.inOrder = s.order.PushBack(stored)
+	stored.inCallerOrder = callerOrder.PushBack(stored)
+	s.responses[key] = stored
+
+	for callerOrder.Len() > s.options.MaxPerCaller {
+		s.remove(callerOrder.Front().Value.(*storedResponse))
+	}
+	for s.order.Len() > s.options.MaxResponses {
+		s.remove(s.order.Front().Value.(*storedResponse))
+	}
+}
+
+// This function forgets a stored response. The mutex must be held.
+func (s *MemoryStore) remove(stored *storedResponse) {
+	delete(s.responses, stored.key)
+	s.order.Remove(stored.inOrder)
+
+	callerOrder := s.callers[stored.response.Caller]
+	callerOrder.Remove(stored.inCallerOrder)
+	if callerOrder.Len() == 0 {
+		delete(s.callers, stored.response.Caller)
+	}
+}
+
+// This function returns how many responses the store holds, including expired ones which haven't been forgotten yet.
+func (s *MemoryStore) len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.responses)
+}
+
+// This function returns a copy of the responses the store holds, oldest first.
+func (s *MemoryStore) snapshot() []storedResponse {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	responses := make([]storedResponse, 0, s.order.Len())
+	for element := s.order.Front(); element != nil; element = element.Next() {
+		stored := element.Value.(*storedResponse)
+		responses = append(responses, storedResponse{key: stored.key, response: stored.response})
+	}
+
+	return responses
+}
+
+// FileStore is a Store which also appends every response to a file, so that duplicates are still recognised after the
+// server restarts. The responses are held in memory too, and the file is only read when the store is opened. Whenever
+// the memory store forgets the expired responses, the file is rewritten without them, so that it doesn't grow forever.
+type FileStore struct {
+	memory *MemoryStore
+	path   string
+
+	// This mutex is used to protect writes to the file.
+	mutex sync.Mutex
+	file  *os.File
+	// lines is how many responses the file holds, including expired and replaced ones.
+	lines int
+}
+
+// A line of the file, holding one response.
+type fileEntry struct {
+	Key      string   `json:"key"`
+	Response Response `json:"response"`
+}
+
+// OpenFileStore opens the store kept in the file, creating the file if it doesn't exist. The responses which haven't
+// expired are loaded, and the file is rewritten with only those. The options limit the responses held in memory, and so
+// the responses kept in the file when it is rewritten.
+func OpenFileStore(path string, options StoreOptions) (*FileStore, error) {
+	memory := NewMemoryStore(options)
+	if err := load(path, memory); err != nil {
+		return nil, err
+	}
+
+	store := &FileStore{memory: memory, path: path}
+	if err := store.compact(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Get returns the response stored under the key.
+func (s *FileStore) Get(key string) (Response, bool, error) {
+	return s.memory.Get(key)
+}
+
+// Put stores the response under the key, and appends it to the file.
+func (s *FileStore) Put(key string, response Response) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := appendEntry(s.file, key, response); err != nil {
+		return err
+	}
+	s.lines++
+
+	// Rewriting the file is only worth it when some of its lines are no longer needed.
+	if s.memory.put(key, response) && s.lines > s.memory.len() {
+		if err := s.compact(); err != nil {
+			// The response is stored, the file just stays longer than it needs to be until the next try.
+			log.Printf("failed to compact %s: %v", s.path, err)
+		}
+	}
+
+	return nil
+}
+
+// Close closes the file.
+func (s *FileStore) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.file.Close()
+}
+
+// This function rewrites the file with only the responses held in memory, which are the ones that haven't expired.
+// The responses are written to a new file, which replaces the old one in one step, so that a crash can't lose them.
+// The mutex must be held, or the store not yet shared, when it is called.
+func (s *FileStore) compact() error {
+	compacted := s.path + ".tmp"
+	file, err := os.OpenFile(compacted, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	// The responses are written oldest first, so that loading the file evicts the same responses as the store did.
+	responses := s.memory.snapshot()
+	for _, stored := range responses {
+		if err := appendEntry(file, stored.key, stored.response); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := os.Rename(compacted, s.path); err != nil {
+		file.Close()
+		return err
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file, s.lines = file, len(responses)
+
+	return nil
+}
+
+// This function appends a response to a file, as a line of JSON.
+func appendEntry(file *os.File, key string, response Response) error {
+	line, err := json.Marshal(fileEntry{Key: key, Response: response})
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// The longest line load reads. Longer lines can't be a response the store wrote, so they are skipped.
+const maxLineLength = 1 << 20
+
+// errLineTooLong is returned by readLine for a line longer than maxLineLength.
+var errLineTooLong = errors.New("line is too long")
+
+// This function loads the responses which haven't expired from the file into the memory store.
+// Later lines replace earlier ones with the same key. A missing file has no responses, and lines which can't be read are
+// skipped.
+func load(path string, memory *MemoryStore) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	now := memory.now()
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		text, err := readLine(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && err != errLineTooLong {
+			return err
+		}
+
+		var entry fileEntry
+		if err == nil {
+			err = json.Unmarshal(text, &entry)
+		}
+		if err != nil {
+			// The server may have stopped halfway through writing a line, which loses only that response.
+			log.Printf("skipping line %d of %s: %v", line, path, err)
+			continue
+		}
+		if now.Before(entry.Response.Expires) {
+			memory.add(entry.Key, entry.Response)
+		}
+	}
+}
+
+// This function reads the next line of the reader, without its newline. A line longer than maxLineLength is read to
+// its end and dropped, and errLineTooLong is returned in its place, so that the lines after it can still be read.
+// It returns io.EOF once there are no more lines.
+func readLine(reader *bufio.Reader) ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !tooLong && len(line)+len(chunk) > maxLineLength+1 {
+			tooLong, line = true, nil
+		}
+		if !tooLong {
+			line = append(line, chunk...)
+		}
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && (len(line) > 0 || tooLong):
+			// The last line has no newline.
+		case err != nil:
+			return nil, err
+		}
+		if tooLong {
+			return nil, errLineTooLong
+		}
+
+		return bytes.TrimSuffix(line, []byte("\n")), nil
+	}
+}
@@ -0,0 +1,150 @@
+package idempotency
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore(StoreOptions{})
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Put("a", Response{Type: "shared.DoubleResult", Expires: now.Add(time.Minute)})
+	if response, ok, err := store.Get("a"); !ok || err != nil || response.Type != "shared.DoubleResult" {
+		t.Errorf("Get(%q) = %v, %v, %v; want the stored response", "a", response, ok, err)
+	}
+	if _, ok, _ := store.Get("b"); ok {
+		t.Errorf("Get(%q) found a response; want none", "b")
+	}
+
+	// Expired responses can't be read, and are forgotten by a later Put.
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := store.Get("a"); ok {
+		t.Errorf("Get(%q) found an expired response", "a")
+	}
+	store.Put("c", Response{Expires: now.Add(time.Minute)})
+	if len(store.responses) != 1 {
+		t.Errorf("store holds %d responses after expiry; want 1", len(store.responses))
+	}
+}
+
+func TestMemoryStoreLimits(t *testing.T) {
+	store := NewMemoryStore(StoreOptions{MaxResponses: 4, MaxPerCaller: 2})
+	expires := time.Now().Add(time.Hour)
+
+	// A caller with too many responses loses their oldest one, but not anyone else's.
+	store.Put("alice 1", Response{Caller: "alice", Expires: expires})
+	store.Put("bob 1", Response{Caller: "bob", Expires: expires})
+	store.Put("alice 2", Response{Caller: "alice", Expires: expires})
+	store.Put("alice 3", Response{Caller: "alice", Expires: expires})
+	// Replacing a response doesn't count twice against the caller.
+	store.Put("alice 3", Response{Caller: "alice", Expires: expires})
+	// A full store loses its oldest response.
+	store.Put("carol 1", Response{Caller: "carol", Expires: expires})
+	store.Put("dave 1", Response{Caller: "dave", Expires: expires})
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"alice 1", false},
+		{"bob 1", false},
+		{"alice 2", true},
+		{"alice 3", true},
+		{"carol 1", true},
+		{"dave 1", true},
+	}
+	for _, tt := range tests {
+		if _, ok, _ := store.Get(tt.key); ok != tt.want {
+			t.Errorf("Get(%q) found a response = %v; want %v", tt.key, ok, tt.want)
+		}
+	}
+	if store.len() != 4 || len(store.callers) != 3 {
+		t.Errorf("store holds %d responses of %d callers; want 4 of 3", store.len(), len(store.callers))
+	}
+}
+
+func TestFileStoreSurvivesReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.jsonl")
+	expires := time.Now().Add(time.Hour)
+
+	store, err := OpenFileStore(path, StoreOptions{})
+	if err != nil {
+		t.Fatalf("OpenFileStore() returned error: %v", err)
+	}
+	store.Put("live", Response{RequestHash: []byte{1, 2}, Type: "shared.IntResult", Message: []byte{8, 6}, Expires: expires})
+	store.Put("expired", Response{Expires: time.Now().Add(-time.Hour)})
+	store.Put("replaced", Response{Type: "old", Expires: expires})
+	store.Put("replaced", Response{Type: "new", Expires: expires})
+	store.Close()
+
+	// Simulate a line too long to be a response, and the server stopping halfway through writing a line.
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	file.WriteString(`{"key": "long", "response": {"type": "` + strings.Repeat("x", 2<<20) + "\"}}\n")
+	file.WriteString(`{"key": "after", "response": {"expires": "` + expires.Format(time.RFC3339Nano) + "\"}}\n")
+	file.WriteString(`{"key": "torn", "respo`)
+	file.Close()
+
+	reopened, err := OpenFileStore(path, StoreOptions{})
+	if err != nil {
+		t.Fatalf("OpenFileStore() of an existing file returned error: %v", err)
+	}
+	defer reopened.Close()
+
+	response, ok, err := reopened.Get("live")
+	if !ok || err != nil || response.Type != "shared.IntResult" || string(response.Message) != "\x08\x06" ||
+		string(response.RequestHash) != "\x01\x02" || !response.Expires.Equal(expires) {
+		t.Errorf("Get(%q) = %v, %v, %v; want the stored response", "live", response, ok, err)
+	}
+	if response, _, _ := reopened.Get("replaced"); response.Type != "new" {
+		t.Errorf("Get(%q) = %v; want the last response stored", "replaced", response)
+	}
+	if _, ok, _ := reopened.Get("after"); !ok {
+		t.Errorf("Get(%q) found no response; want the one stored after the long line", "after")
+	}
+	for _, key := range []string{"expired", "long", "torn"} {
+		if _, ok, _ := reopened.Get(key); ok {
+			t.Errorf("Get(%q) found a response; want none", key)
+		}
+	}
+	if len(reopened.memory.responses) != 3 {
+		t.Errorf("reopened store holds %d responses; want 3", len(reopened.memory.responses))
+	}
+}
+
+func TestFileStoreIsCompactedWhenPurged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.jsonl")
+	store, err := OpenFileStore(path, StoreOptions{})
+	if err != nil {
+		t.Fatalf("OpenFileStore() returned error: %v", err)
+	}
+	defer store.Close()
+	now := time.Now()
+	store.memory.now = func() time.Time { return now }
+
+	for _, key := range []string{"a", "b", "b", "c"} {
+		store.Put(key, Response{Expires: now.Add(time.Minute)})
+	}
+	if store.lines != 4 {
+		t.Errorf("file holds %d lines before the purge; want 4", store.lines)
+	}
+
+	// The next Put after the purge interval forgets a, b and c, and rewrites the file with only d.
+	now = now.Add(2 * time.Minute)
+	store.Put("d", Response{Expires: now.Add(time.Minute)})
+
+	contents, _ := os.ReadFile(path)
+	if lines := strings.Count(string(contents), "\n"); lines != 1 || store.lines != 1 || !strings.Contains(string(contents), `"key":"d"`) {
+		t.Errorf("file holds %d lines after the purge:\n%s\nwant only d", lines, contents)
+	}
+
+	// Later responses are appended to the rewritten file.
+	store.Put("e", Response{Expires: now.Add(time.Minute)})
+	if contents, _ := os.ReadFile(path); strings.Count(string(contents), "\n") != 2 {
+		t.Errorf("file holds:\n%s\nwant d and e", contents)
+	}
+}
@@ -0,0 +1,59 @@
+package listen
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The first file descriptor passed by systemd style socket activation, after stdin, stdout and stderr.
+const firstActivationFD = 3
+
+// Inherited returns the listeners passed to the server by systemd style socket activation.
+// The service manager opens the sockets and passes them as file descriptors, starting at 3, and describes them
+// with the LISTEN_PID, LISTEN_FDS and (optionally) LISTEN_FDNAMES environment variables.
+// If the server was not started by socket activation it returns no listeners.
+// The environment variables are cleared, so that child processes don't mistake the sockets for their own.
+func Inherited() ([]net.Listener, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	var listeners []net.Listener
+	for i := range count {
+		fd := firstActivationFD + i
+		name := fmt.Sprintf("LISTEN_FD_%d", fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		file := os.NewFile(uintptr(fd), name)
+		listener, err := net.FileListener(file)
+		// FileListener duplicates the descriptor, so the original is always closed, which also stops it from
+		// leaking into processes the server starts.
+		file.Close()
+		if err != nil {
+			closeAll(listeners)
+			return nil, fmt.Errorf("inherited file descriptor %d (%s) is not a listening socket: %v", fd, name, err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
@@ -0,0 +1,163 @@
+package listen
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// UnixPrefix marks an address as a Unix domain socket path, for example "unix:///run/magic/magic.sock".
+const UnixPrefix = "unix://"
+
+// The longest path a Unix domain socket can be created at, which is the size of the path in a socket address, less the
+// zero byte which ends it.
+var maxSocketPathLength = len(syscall.RawSockaddrUnix{}.Path) - 1
+
+// Listen opens a listener for each address. An address is either a TCP address such as ":50051" or
+// "localhost:50051" (optionally written "tcp://localhost:50051"), or a Unix domain socket such as
+// "unix:///run/magic/magic.sock". Unix domain sockets are given the file permissions in socketMode.
+// If any address can't be listened on, the listeners opened so far are closed and an error is returned.
+func Listen(addresses []string, socketMode os.FileMode) ([]net.Listener, error) {
+	var listeners []net.Listener
+	for _, address := range addresses {
+		listener, err := listenOne(address, socketMode)
+		if err != nil {
+			closeAll(listeners)
+			return nil, fmt.Errorf("failed to listen on %s: %v", address, err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// This function opens a single TCP or Unix domain socket listener.
+func listenOne(address string, socketMode os.FileMode) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(address, UnixPrefix); ok {
+		return listenUnix(path, socketMode)
+	}
+
+	return net.Listen("tcp", strings.TrimPrefix(address, "tcp://"))
+}
+
+// This function opens a Unix domain socket listener, removing a stale socket file left behind by a server
+// which didn't shut down cleanly. The socket file is removed again when the listener is closed.
+//
+// The socket is created in a new directory which only this user can enter, given its permissions there, and then moved
+// into place. Creating it at its path and changing its permissions afterwards would let anyone connect in between.
+// The directory and the socket in it have short names, as the path the socket is created at is limited in length.
+func listenUnix(path string, socketMode os.FileMode) (net.Listener, error) {
+	if len(path) > maxSocketPathLength {
+		return nil, fmt.Errorf("socket path %s is %d bytes long, the limit is %d", path, len(path), maxSocketPathLength)
+	}
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".s")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(dir)
+
+	private := filepath.Join(dir, "s")
+	if len(private) > maxSocketPathLength {
+		return nil, fmt.Errorf("socket path %s is too long to create the socket in a private directory next to it, "+
+			"the limit is %d bytes, use a shorter path", path, maxSocketPathLength-len(private)+len(path))
+	}
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: private, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// The socket file is moved, so the listener can't remove it by its old name.
+	listener.SetUnlinkOnClose(false)
+
+	if err := os.Chmod(private, socketMode); err != nil {
+		listener.Close()
+		os.Remove(private)
+		return nil, err
+	}
+	if err := os.Rename(private, path); err != nil {
+		listener.Close()
+		os.Remove(private)
+		return nil, err
+	}
+
+	return &movedListener{UnixListener: listener, address: &net.UnixAddr{Name: path, Net: "unix"}}, nil
+}
+
+// A movedListener is a Unix domain socket listener whose socket file was moved after it was created.
+// It reports the socket's new path as its address, and removes the socket file there when it is closed.
+type movedListener struct {
+	*net.UnixListener
+	address *net.UnixAddr
+}
+
+func (l *movedListener) Addr() net.Addr {
+	return l.address
+}
+
+func (l *movedListener) Close() error {
+	err := l.UnixListener.Close()
+	if err == nil {
+		os.Remove(l.address.Name)
+	}
+
+	return err
+}
+
+// This function removes the file at path if it is a socket nobody is listening on.
+// It refuses to remove a socket which is still in use, or a file which isn't a socket.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	connection, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		connection.Close()
+		return fmt.Errorf("socket %s is already in use", path)
+	}
+
+	return os.Remove(path)
+}
+
+// DialTarget returns a gRPC target which connects to the listener from the same machine.
+// The path of a Unix domain socket is made absolute, as gRPC reads "unix://" followed by a relative path as a host name.
+func DialTarget(listener net.Listener) string {
+	if listener.Addr().Network() == "unix" {
+		path := listener.Addr().String()
+		// Paths starting with @ are abstract sockets, which have no file, and so no directory they are relative to.
+		if !strings.HasPrefix(path, "@") {
+			if absolute, err := filepath.Abs(path); err == nil {
+				path = absolute
+			}
+		}
+		return UnixPrefix + path
+	}
+
+	if tcpAddress, ok := listener.Addr().(*net.TCPAddr); ok && tcpAddress.IP.IsUnspecified() {
+		return fmt.Sprintf("localhost:%d", tcpAddress.Port)
+	}
+
+	return listener.Addr().String()
+}
+
+func closeAll(listeners []net.Listener) {
+	for _, listener := range listeners {
+		listener.Close()
+	}
+}
@@ -0,0 +1,166 @@
+package listen
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListenTCPAndUnix(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "magic.sock")
+
+	listeners, err := Listen([]string{"localhost:0", "tcp://127.0.0.1:0", UnixPrefix + socketPath}, 0o660)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+	defer closeAll(listeners)
+
+	wantNetworks := []string{"tcp", "tcp", "unix"}
+	for i, listener := range listeners {
+		if listener.Addr().Network() != wantNetworks[i] {
+			t.Errorf("listener %d network = %s; want %s", i, listener.Addr().Network(), wantNetworks[i])
+		}
+	}
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatalf("socket file was not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o660 {
+		t.Errorf("socket file mode = %v; want %v", info.Mode().Perm(), os.FileMode(0o660))
+	}
+
+	if got := DialTarget(listeners[2]); got != UnixPrefix+socketPath {
+		t.Errorf("DialTarget() = %s; want %s", got, UnixPrefix+socketPath)
+	}
+	if got := DialTarget(listeners[0]); !strings.HasPrefix(got, "127.0.0.1:") {
+		t.Errorf("DialTarget() = %s; want 127.0.0.1:<port>", got)
+	}
+}
+
+func TestListenUnixLeavesOnlyTheSocket(t *testing.T) {
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "magic.sock")
+
+	listeners, err := Listen([]string{UnixPrefix + socketPath}, 0o600)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+
+	// The socket is served at its path, and the directory it was created in is gone.
+	connection, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Errorf("Dial(%s) returned error: %v", socketPath, err)
+	} else {
+		connection.Close()
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 || entries[0].Name() != "magic.sock" {
+		t.Errorf("directory holds %v; want only magic.sock", entries)
+	}
+
+	closeAll(listeners)
+	if _, err := os.Lstat(socketPath); !os.IsNotExist(err) {
+		t.Errorf("socket file still exists after Close(): %v", err)
+	}
+}
+
+func TestListenUnixPathLength(t *testing.T) {
+	// A directory whose path leaves room for a socket name of exactly 24 bytes.
+	dir := t.TempDir()
+	padding := maxSocketPathLength - len(dir) - 2 - 24
+	if padding < 1 {
+		t.Skipf("temporary directory %s is too long to test with", dir)
+	}
+	dir = filepath.Join(dir, strings.Repeat("d", padding))
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	// A socket at the longest path there can be is created, even though it is created in a directory next to it.
+	longest := filepath.Join(dir, strings.Repeat("s", 24))
+	listeners, err := Listen([]string{UnixPrefix + longest}, 0o600)
+	if err != nil {
+		t.Fatalf("Listen() of a %d byte path returned error: %v", len(longest), err)
+	}
+	closeAll(listeners)
+
+	// A longer path is refused, rather than failing with an unclear error from the system.
+	tooLong := longest + "s"
+	if _, err := Listen([]string{UnixPrefix + tooLong}, 0o600); err == nil || !strings.Contains(err.Error(), "limit") {
+		t.Errorf("Listen() of a %d byte path returned error %v; want one about the limit", len(tooLong), err)
+	}
+}
+
+func TestDialTargetOfRelativeSocket(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	listeners, err := Listen([]string{UnixPrefix + "magic.sock"}, 0o600)
+	if err != nil {
+		t.Fatalf("Listen() returned error: %v", err)
+	}
+	defer closeAll(listeners)
+
+	want := UnixPrefix + filepath.Join(dir, "magic.sock")
+	if got := DialTarget(listeners[0]); got != want {
+		t.Errorf("DialTarget() = %s; want %s", got, want)
+	}
+}
+
+func TestListenRemovesStaleSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "magic.sock")
+
+	// Leave a socket file behind, the way a crashed server would.
+	stale, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listeners, err := Listen([]string{UnixPrefix + socketPath}, 0o600)
+	if err != nil {
+		t.Fatalf("Listen() over a stale socket returned error: %v", err)
+	}
+	closeAll(listeners)
+}
+
+func TestListenRefusesSocketInUse(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "magic.sock")
+
+	active, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer active.Close()
+
+	if _, err := Listen([]string{UnixPrefix + socketPath}, 0o600); err == nil {
+		t.Errorf("Listen() on a socket in use returned no error")
+	}
+}
+
+func TestListenRefusesRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-socket")
+	if err := os.WriteFile(path, []byte("keep me"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Listen([]string{"localhost:0", UnixPrefix + path}, 0o600); err == nil {
+		t.Errorf("Listen() over a regular file returned no error")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Listen() removed a regular file: %v", err)
+	}
+}
+
+func TestInheritedWithoutActivation(t *testing.T) {
+	t.Setenv("LISTEN_PID", "")
+	t.Setenv("LISTEN_FDS", "")
+
+	listeners, err := Inherited()
+	if err != nil || len(listeners) != 0 {
+		t.Errorf("Inherited() = %v, %v; want no listeners and no error", listeners, err)
+	}
+}
@@ -0,0 +1,118 @@
+package loadshed
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The simulated server can work on this many calls at once, each taking serviceTime, so it handles about
+// 2000 calls per second. Any more calls at once wait for a worker, which is what makes an overloaded server slow.
+const (
+	workers     = 4
+	serviceTime = 2 * time.Millisecond
+)
+
+// loadResult summarizes a load test run.
+type loadResult struct {
+	served, shed       int
+	servedP99, shedP99 time.Duration
+}
+
+// This function overloads a simulated server with many clients, each making calls one after another for the
+// duration, and measures the latency of the calls the server served and those it shed.
+func runLoad(limiter *Limiter, clients int, duration time.Duration) loadResult {
+	capacity := make(chan struct{}, workers)
+	handle := func() {
+		capacity <- struct{}{}
+		time.Sleep(serviceTime)
+		<-capacity
+	}
+
+	var mutex sync.Mutex
+	var served, shed []time.Duration
+	var waitGroup sync.WaitGroup
+	deadline := time.Now().Add(duration)
+	for range clients {
+		waitGroup.Go(func() {
+			for time.Now().Before(deadline) {
+				start := time.Now()
+				var err error
+				if limiter == nil {
+					handle()
+				} else {
+					err = call(limiter, Normal, handle)
+				}
+				latency := time.Since(start)
+
+				mutex.Lock()
+				if status.Code(err) == codes.Unavailable {
+					shed = append(shed, latency)
+				} else {
+					served = append(served, latency)
+				}
+				mutex.Unlock()
+
+				if err != nil {
+					// A real client would back off before trying again.
+					time.Sleep(serviceTime)
+				}
+			}
+		})
+	}
+	waitGroup.Wait()
+
+	return loadResult{served: len(served), shed: len(shed), servedP99: percentile(served, 0.99), shedP99: percentile(shed, 0.99)}
+}
+
+// This function returns the latency which the given fraction of the latencies are below.
+func percentile(latencies []time.Duration, fraction float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	slices.Sort(latencies)
+
+	return latencies[int(float64(len(latencies)-1)*fraction)]
+}
+
+// This load test sends about ten times more calls than the simulated server can handle. Without a limiter every call
+// is served, but they queue up for the workers and become slow. With the limiter the calls it serves stay fast,
+// and the calls it has no room for are shed quickly.
+func TestStableLatencyUnderOverload(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping load test in short mode")
+	}
+
+	const clients = 100
+	const duration = time.Second
+
+	unlimited := runLoad(nil, clients, duration)
+	limited := runLoad(New(Options{
+		MinLimit:      workers,
+		MaxLimit:      clients,
+		LatencyTarget: 4 * serviceTime,
+		QueueSize:     workers,
+		MaxQueueWait:  4 * serviceTime,
+	}), clients, duration)
+
+	t.Logf("without limiter: %d calls served, p99 latency %v", unlimited.served, unlimited.servedP99)
+	t.Logf("with limiter: %d calls served, p99 latency %v; %d calls shed, p99 latency %v",
+		limited.served, limited.servedP99, limited.shed, limited.shedP99)
+
+	if limited.shed == 0 {
+		t.Errorf("limiter shed no calls; want calls shed under overload")
+	}
+	if limited.servedP99 > unlimited.servedP99/2 {
+		t.Errorf("p99 latency with limiter = %v; want less than half of %v without it", limited.servedP99, unlimited.servedP99)
+	}
+	if limited.shedP99 > 100*time.Millisecond {
+		t.Errorf("p99 latency of shed calls = %v; want them rejected quickly", limited.shedP99)
+	}
+	if limited.served < unlimited.served/4 {
+		t.Errorf("limiter served %d calls; want at least a quarter of the %d served without it", limited.served, unlimited.served)
+	}
+}
@@ -0,0 +1,302 @@
+package loadshed
+
+import (
+	"context"
+	"math"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/methods"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Options tunes the limiter. A zero value means the default given for each option.
+type Options struct {
+	// MinLimit and MaxLimit bound how many calls may run at once. They default to 1 and 1000.
+	MinLimit int
+	MaxLimit int
+	// InitialLimit is how many calls may run at once before the limiter has adapted. It defaults to a tenth of MaxLimit.
+	InitialLimit int
+	// LatencyTarget is how long a call should take. While calls are faster, and at least half the limit is in use,
+	// the limit grows by one for each limit's worth of calls which finish. When a call is slower the limit shrinks by
+	// Backoff.
+	// They default to 50ms and 0.9.
+	LatencyTarget time.Duration
+	Backoff       float64
+	// QueueSize is how many calls may wait for a free slot, and MaxQueueWait how long each may wait before it is shed.
+	// They default to 100 calls and 100ms.
+	QueueSize    int
+	MaxQueueWait time.Duration
+	// Priorities sets the priority of calls to each method, keyed by the method's name, for example "GetAddCount".
+	// The priority under Any applies to every method without its own, and the default is Normal.
+	// A client can override the priority of a call with the x-priority header.
+	Priorities map[string]Priority
+}
+
+// Stats is a snapshot of the limiter's state.
+type Stats struct {
+	// Limit is how many calls may currently run at once.
+	Limit int
+	// InFlight is how many calls are running, and Queued how many are waiting for a slot.
+	InFlight int
+	Queued   int
+	// Shed is how many calls have been rejected, for each priority.
+	Shed map[Priority]int64
+}
+
+// Limiter limits how many MagicMath and VectorMath calls run at once, adapting the limit to the latency of the calls
+// using additive increase and multiplicative decrease (AIMD). Calls over the limit wait in a bounded queue, highest
+// priority first, and calls which can't be queued, or wait too long, are shed with codes.Unavailable straight away.
+type Limiter struct {
+	options Options
+	// now returns the current time, tests replace it to control the clock.
+	now func() time.Time
+
+	// This mutex is used to protect all of the fields below.
+	mutex        sync.Mutex
+	limit        float64
+	inFlight     int
+	queue        [High + 1][]*waiter
+	queued       int
+	shed         map[Priority]int64
+	lastDecrease time.Time
+}
+
+// A waiter is a call waiting in the queue. Its ready channel is closed once it has been given a slot or shed.
+type waiter struct {
+	priority Priority
+	ready    chan struct{}
+	granted  bool
+}
+
+// New creates a limiter with the given options.
+func New(options Options) *Limiter {
+	if options.MinLimit <= 0 {
+		options.MinLimit = 1
+	}
+	if options.MaxLimit <= 0 {
+		options.MaxLimit = 1000
+	}
+	options.MaxLimit = max(options.MaxLimit, options.MinLimit)
+	if options.InitialLimit <= 0 {
+		options.InitialLimit = options.MaxLimit / 10
+	}
+	options.InitialLimit = min(max(options.InitialLimit, options.MinLimit), options.MaxLimit)
+	if options.LatencyTarget <= 0 {
+		options.LatencyTarget = 50 * time.Millisecond
+	}
+	if options.Backoff <= 0 || options.Backoff >= 1 {
+		options.Backoff = 0.9
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = 100
+	}
+	if options.MaxQueueWait <= 0 {
+		options.MaxQueueWait = 100 * time.Millisecond
+	}
+
+	return &Limiter{
+		options: options,
+		now:     time.Now,
+		limit:   float64(options.InitialLimit),
+		shed:    make(map[Priority]int64),
+	}
+}
+
+// UnaryServerInterceptor runs MagicMath and VectorMath calls once a slot is free, and sheds the calls the server has no
+// room for with codes.Unavailable.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !methods.IsMath(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		priority := l.priority(ctx, path.Base(info.FullMethod))
+		if err := l.acquire(ctx, priority); err != nil {
+			return nil, err
+		}
+
+		// The slot is released even if the handler panics, so that a panic doesn't leak it.
+		start := l.now()
+		defer func() { l.release(l.now().Sub(start)) }()
+
+		return handler(ctx, req)
+	}
+}
+
+// Stats returns a snapshot of the limiter's state.
+func (l *Limiter) Stats() Stats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	shed := make(map[Priority]int64, len(l.shed))
+	for priority, count := range l.shed {
+		shed[priority] = count
+	}
+
+	return Stats{Limit: l.currentLimit(), InFlight: l.inFlight, Queued: l.queued, Shed: shed}
+}
+
+// This function returns the priority of a call: the one in its x-priority header if it is valid,
+// otherwise the method's priority.
+func (l *Limiter) priority(ctx context.Context, method string) Priority {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(MetadataKey); len(values) > 0 {
+		if priority, err := ParsePriority(values[0]); err == nil {
+			return priority
+		}
+	}
+
+	if priority, ok := l.options.Priorities[method]; ok {
+		return priority
+	}
+	if priority, ok := l.options.Priorities[Any]; ok {
+		return priority
+	}
+
+	return Normal
+}
+
+// This function waits for a slot to run the call in, or returns the error the call fails with if it is shed
+// or its context ends first.
+func (l *Limiter) acquire(ctx context.Context, priority Priority) error {
+	l.mutex.Lock()
+
+	if l.inFlight < l.currentLimit() && l.queued == 0 {
+		l.inFlight++
+		l.mutex.Unlock()
+		return nil
+	}
+
+	if l.queued >= l.options.QueueSize {
+		// Make room by shedding the newest call with a lower priority, or shed this call if there isn't one.
+		lowest := l.newestBelow(priority)
+		if lowest == nil {
+			l.shed[priority]++
+			l.mutex.Unlock()
+			return overloaded(priority)
+		}
+		l.remove(lowest)
+		l.shed[lowest.priority]++
+		close(lowest.ready)
+	}
+
+	w := &waiter{priority: priority, ready: make(chan struct{})}
+	l.queue[priority] = append(l.queue[priority], w)
+	l.queued++
+	l.mutex.Unlock()
+
+	timer := time.NewTimer(l.options.MaxQueueWait)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-w.ready:
+	case <-timer.C:
+		err = overloaded(priority)
+	case <-ctx.Done():
+		err = status.FromContextError(ctx.Err()).Err()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if w.granted {
+		// The slot was given to the call just as it stopped waiting, so it runs anyway.
+		return nil
+	}
+	select {
+	case <-w.ready:
+		// The call was shed to make room for a call with a higher priority.
+		return overloaded(priority)
+	default:
+	}
+
+	l.remove(w)
+	if status.Code(err) == codes.Unavailable {
+		l.shed[priority]++
+	}
+
+	return err
+}
+
+// This function frees the slot of a call which has finished, adapts the limit to how long the call took,
+// and hands the slot to the next queued call.
+func (l *Limiter) release(latency time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	if latency > l.options.LatencyTarget {
+		// Only shrink the limit once per latency target, so that a burst of slow calls doesn't collapse it.
+		if now.Sub(l.lastDecrease) >= l.options.LatencyTarget {
+			l.limit = math.Max(float64(l.options.MinLimit), l.limit*l.options.Backoff)
+			l.lastDecrease = now
+		}
+	} else if 2*l.inFlight >= l.currentLimit() {
+		// Only grow the limit while much of it is in use, otherwise it would grow without bound when idle.
+		l.limit = math.Min(float64(l.options.MaxLimit), l.limit+1/l.limit)
+	}
+
+	l.inFlight--
+	for l.inFlight < l.currentLimit() {
+		next := l.oldestHighest()
+		if next == nil {
+			break
+		}
+		l.remove(next)
+		next.granted = true
+		l.inFlight++
+		close(next.ready)
+	}
+}
+
+// This function returns the limit as a whole number of calls.
+func (l *Limiter) currentLimit() int {
+	return int(l.limit)
+}
+
+// This function returns the call which has waited longest among those with the highest priority, or nil if the
+// queue is empty.
+func (l *Limiter) oldestHighest() *waiter {
+	for priority := High; priority >= Low; priority-- {
+		if len(l.queue[priority]) > 0 {
+			return l.queue[priority][0]
+		}
+	}
+
+	return nil
+}
+
+// This function returns the newest queued call with the lowest priority below the given one, or nil if there isn't one.
+func (l *Limiter) newestBelow(priority Priority) *waiter {
+	for lower := Low; lower < priority; lower++ {
+		if queue := l.queue[lower]; len(queue) > 0 {
+			return queue[len(queue)-1]
+		}
+	}
+
+	return nil
+}
+
+// This function takes a call out of the queue.
+func (l *Limiter) remove(w *waiter) {
+	queue := l.queue[w.priority]
+	for i, queued := range queue {
+		if queued == w {
+			l.queue[w.priority] = append(queue[:i], queue[i+1:]...)
+			l.queued--
+			return
+		}
+	}
+}
+
+// This function creates the error a shed call fails with.
+func overloaded(priority Priority) error {
+	return status.Errorf(codes.Unavailable, "server overloaded, %s priority call shed", priority)
+}
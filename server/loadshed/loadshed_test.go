@@ -0,0 +1,200 @@
+package loadshed
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var addInfo = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicAdd_FullMethodName}
+
+// This function makes a call through the limiter with the given priority, to a handler which runs the function.
+func call(limiter *Limiter, priority Priority, handle func()) error {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, priority.String()))
+	_, err := limiter.UnaryServerInterceptor()(ctx, nil, addInfo, func(context.Context, any) (any, error) {
+		handle()
+		return nil, nil
+	})
+
+	return err
+}
+
+// This function waits until the limiter has the given number of calls queued.
+func waitForQueued(t *testing.T, limiter *Limiter, queued int) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); limiter.Stats().Queued != queued; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("queued = %d; want %d", limiter.Stats().Queued, queued)
+		}
+	}
+}
+
+func TestQueueShedsLowestPriorityFirst(t *testing.T) {
+	limiter := New(Options{MinLimit: 1, MaxLimit: 1, QueueSize: 2, MaxQueueWait: time.Minute})
+
+	// The first call takes the only slot, until it is released.
+	release := make(chan struct{})
+	var waitGroup sync.WaitGroup
+	waitGroup.Go(func() { call(limiter, Normal, func() { <-release }) })
+	for limiter.Stats().InFlight != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	var mutex sync.Mutex
+	var ran []string
+	results := make(map[string]error)
+	start := func(name string, priority Priority) {
+		waitGroup.Go(func() {
+			err := call(limiter, priority, func() {
+				mutex.Lock()
+				ran = append(ran, name)
+				mutex.Unlock()
+			})
+			mutex.Lock()
+			results[name] = err
+			mutex.Unlock()
+		})
+	}
+
+	start("low", Low)
+	waitForQueued(t, limiter, 1)
+	start("normal", Normal)
+	waitForQueued(t, limiter, 2)
+
+	// The queue is full, so the high priority call takes the low priority call's place.
+	start("high", High)
+	for limiter.Stats().Shed[Low] != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	// There's nothing lower than another low priority call, so it is shed at once.
+	if err := call(limiter, Low, func() {}); status.Code(err) != codes.Unavailable {
+		t.Errorf("low priority call to a full queue returned %v; want Unavailable", err)
+	}
+
+	close(release)
+	waitGroup.Wait()
+
+	if status.Code(results["low"]) != codes.Unavailable || results["normal"] != nil || results["high"] != nil {
+		t.Errorf("results = %v; want only the low priority call shed", results)
+	}
+	if want := []string{"high", "normal"}; !slices.Equal(ran, want) {
+		t.Errorf("calls ran in order %v; want %v", ran, want)
+	}
+	if stats := limiter.Stats(); stats.Shed[Low] != 2 || stats.InFlight != 0 || stats.Queued != 0 {
+		t.Errorf("Stats() = %+v; want 2 low priority calls shed and nothing left running or queued", stats)
+	}
+}
+
+func TestQueueWaitIsBounded(t *testing.T) {
+	limiter := New(Options{MinLimit: 1, MaxLimit: 1, MaxQueueWait: 10 * time.Millisecond})
+
+	release := make(chan struct{})
+	go call(limiter, Normal, func() { <-release })
+	defer close(release)
+	for limiter.Stats().InFlight != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	err := call(limiter, High, func() {})
+	if status.Code(err) != codes.Unavailable || time.Since(start) > time.Second {
+		t.Errorf("queued call returned %v after %v; want Unavailable after about 10ms", err, time.Since(start))
+	}
+	if stats := limiter.Stats(); stats.Shed[High] != 1 || stats.Queued != 0 {
+		t.Errorf("Stats() = %+v; want one high priority call shed and none queued", stats)
+	}
+}
+
+func TestPanicReleasesSlot(t *testing.T) {
+	limiter := New(Options{MinLimit: 1, MaxLimit: 1, MaxQueueWait: 10 * time.Millisecond})
+
+	func() {
+		defer func() { recover() }()
+		call(limiter, Normal, func() { panic("handler panicked") })
+	}()
+
+	if stats := limiter.Stats(); stats.InFlight != 0 {
+		t.Errorf("Stats() = %+v after a panic; want nothing in flight", stats)
+	}
+	if err := call(limiter, Normal, func() {}); err != nil {
+		t.Errorf("call after a panic returned %v; want the slot to be free", err)
+	}
+}
+
+func TestPriorityOfCall(t *testing.T) {
+	limiter := New(Options{Priorities: map[string]Priority{"GetAddCount": Low, Any: High}})
+
+	tests := []struct {
+		method string
+		header string
+		want   Priority
+	}{
+		{"GetAddCount", "", Low},
+		{"MagicAdd", "", High},
+		{"GetAddCount", "high", High},
+		{"GetAddCount", "urgent", Low}, // an invalid header is ignored
+	}
+
+	for _, tt := range tests {
+		md := metadata.MD{}
+		if tt.header != "" {
+			md = metadata.Pairs(MetadataKey, tt.header)
+		}
+		if got := limiter.priority(metadata.NewIncomingContext(context.Background(), md), tt.method); got != tt.want {
+			t.Errorf("priority(%q, header %q) = %v; want %v", tt.method, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLimitAdaptsToLatency(t *testing.T) {
+	limiter := New(Options{MinLimit: 2, MaxLimit: 20, InitialLimit: 10, LatencyTarget: 10 * time.Millisecond})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	// Slow calls shrink the limit by the backoff, at most once per latency target, down to the minimum.
+	for range 3 {
+		limiter.acquire(context.Background(), Normal)
+		limiter.release(20 * time.Millisecond)
+	}
+	if got := limiter.Stats().Limit; got != 9 {
+		t.Errorf("limit after slow calls at the same time = %d; want 9", got)
+	}
+	for range 50 {
+		now = now.Add(10 * time.Millisecond)
+		limiter.acquire(context.Background(), Normal)
+		limiter.release(20 * time.Millisecond)
+	}
+	if got := limiter.Stats().Limit; got != 2 {
+		t.Errorf("limit after many slow calls = %d; want the minimum 2", got)
+	}
+
+	// Fast calls grow the limit, but only while at least half of it is in use.
+	limiter.limit = 4
+	limiter.acquire(context.Background(), Normal)
+	limiter.release(time.Millisecond)
+	if got := limiter.Stats().Limit; got != 4 {
+		t.Errorf("limit after a fast call with most slots free = %d; want 4", got)
+	}
+	limiter.limit = 2
+	for range 5 {
+		for range limiter.Stats().Limit {
+			limiter.acquire(context.Background(), Normal)
+		}
+		for range limiter.Stats().Limit {
+			limiter.release(time.Millisecond)
+		}
+	}
+	if got := limiter.Stats().Limit; got < 4 || got > 7 {
+		t.Errorf("limit after fast calls = %d; want between 4 and 7", got)
+	}
+}
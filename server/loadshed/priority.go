@@ -0,0 +1,70 @@
+package loadshed
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority decides which calls are shed first when the server is overloaded: lower priorities are shed first.
+type Priority int
+
+const (
+	Low Priority = iota
+	Normal
+	High
+)
+
+// MetadataKey is the metadata header a client sends to set a call's priority, for example "x-priority: low".
+const MetadataKey = "x-priority"
+
+// Any is the key of the priority which applies to every method without a priority of its own.
+const Any = "*"
+
+// String returns the priority's name.
+func (p Priority) String() string {
+	switch p {
+	case Low:
+		return "low"
+	case Normal:
+		return "normal"
+	case High:
+		return "high"
+	default:
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+}
+
+// ParsePriority parses a priority from its name: "low", "normal" or "high".
+func ParsePriority(name string) (Priority, error) {
+	for p := Low; p <= High; p++ {
+		if strings.EqualFold(name, p.String()) {
+			return p, nil
+		}
+	}
+
+	return Normal, fmt.Errorf("invalid priority %q, expected low, normal or high", name)
+}
+
+// ParsePriorities parses a comma separated list of method priorities, each written as "method=priority",
+// for example "GetAddCount=low,*=normal".
+func ParsePriorities(spec string) (map[string]Priority, error) {
+	priorities := make(map[string]Priority)
+	if spec == "" {
+		return priorities, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		method, name, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid priority %q, expected method=priority", entry)
+		}
+
+		priority, err := ParsePriority(name)
+		if err != nil {
+			return nil, err
+		}
+		priorities[method] = priority
+	}
+
+	return priorities, nil
+}
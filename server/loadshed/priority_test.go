@@ -0,0 +1,23 @@
+package loadshed
+
+import "testing"
+
+func TestParsePriorities(t *testing.T) {
+	priorities, err := ParsePriorities("GetAddCount=low, MagicAdd=HIGH,*=normal")
+	if err != nil {
+		t.Fatalf("ParsePriorities() returned error: %v", err)
+	}
+
+	want := map[string]Priority{"GetAddCount": Low, "MagicAdd": High, Any: Normal}
+	for method, priority := range want {
+		if priorities[method] != priority {
+			t.Errorf("priorities[%q] = %v; want %v", method, priorities[method], priority)
+		}
+	}
+
+	for _, spec := range []string{"GetAddCount", "=low", "MagicAdd=urgent"} {
+		if _, err := ParsePriorities(spec); err == nil {
+			t.Errorf("ParsePriorities(%q) returned no error", spec)
+		}
+	}
+}
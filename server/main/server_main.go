@@ -2,156 +2,555 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/karldmenzel/go-grpc-client-server/capture"
 	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
-	"github.com/karldmenzel/go-grpc-client-server/server/math"
+	"github.com/karldmenzel/go-grpc-client-server/server/app"
+	"github.com/karldmenzel/go-grpc-client-server/server/cache"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/cluster"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/faults"
+	"github.com/karldmenzel/go-grpc-client-server/server/gateway"
+	"github.com/karldmenzel/go-grpc-client-server/server/idempotency"
+	"github.com/karldmenzel/go-grpc-client-server/server/listen"
+	"github.com/karldmenzel/go-grpc-client-server/server/loadshed"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/bignum"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/decimal"
+	"github.com/karldmenzel/go-grpc-client-server/server/operations"
+	"github.com/karldmenzel/go-grpc-client-server/server/ratelimit"
+	"github.com/karldmenzel/go-grpc-client-server/server/recorder"
+	"github.com/karldmenzel/go-grpc-client-server/server/recovery"
+	"github.com/karldmenzel/go-grpc-client-server/server/web"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// This is the server object that we will bind to in order to expose the remote methods.
-type server struct {
-	pb.UnsafeMagicMathServer
-}
+// The port can be changed so that several servers can be run on one machine, for example to test load balancing.
+// It defaults to port 50051 (common gRPC development port).
+var port = flag.Int("port", defaultPort, "The TCP port the server listens on.")
+
+// These are the default ports of the server and of its REST gateway.
+const (
+	defaultPort     = 50051
+	defaultHTTPPort = 8080
+)
+
+// These flags let the server listen on several TCP addresses and Unix domain sockets at once, for example
+// "-listen :50051,unix:///run/magic/magic.sock". Sockets passed by systemd socket activation are always used.
+var (
+	listenAddresses = flag.String("listen", "",
+		"Comma separated TCP addresses and unix:// socket paths to listen on. Defaults to the -port flag.")
+	socketMode = flag.String("socket-mode", "0660", "The file permissions given to Unix domain sockets, in octal.")
+)
+
+// The REST gateway lets tools which can only speak HTTP and JSON call the server. By default its port follows the
+// -port flag, so that several servers can be run on one machine without their gateways clashing, and it only listens on
+// the loopback interface, so that it isn't reachable from other machines unless that is asked for.
+var (
+	httpPort = flag.Int("http-port", -1,
+		"The TCP port the REST gateway listens on. Set to 0 to disable the gateway. "+
+			"Defaults to 8080 plus how far -port is from 50051, so -port 50052 gives 8081.")
+	httpHost = flag.String("http-host", "localhost",
+		"The host name or IP address the REST gateway listens on. Set to an empty string to listen on every interface.")
+)
+
+// These flags let browsers and curl call the server directly, using gRPC-Web or the Connect protocol on the gRPC port.
+var (
+	webProtocols = flag.Bool("web", false,
+		"Also accept gRPC-Web and Connect protocol requests (HTTP/1.1 or HTTP/2, JSON or binary) on the gRPC port.")
+	corsOrigins = flag.String("cors-origins", "",
+		"Comma separated origins which browsers may call the server from when -web is set, or * for any origin.")
+)
+
+// These flags record every MagicMath call to a capture file, which the client's -replay flag can replay.
+var (
+	recordFile     = flag.String("record", "", "Record every MagicMath call to this capture file.")
+	recordMaxBytes = flag.Int64("record-max-bytes", 100<<20, "Rotate the capture file once it reaches this size.")
+	recordMaxFiles = flag.Int("record-max-files", 5, "How many rotated capture files to keep.")
+)
+
+// These flags limit how fast each caller may call the server, and how many calls it may make per hour and per day.
+// Callers on this machine or in the trusted networks identify themselves with the x-caller-id header, every other
+// caller is identified by its IP address.
+var (
+	methodRateLimits = flag.String("rate-limits", "",
+		"Comma separated per caller limits for each method, as method=rate[:burst] in calls per second, for example "+
+			"\"MagicAdd=100:200,*=1000\". * applies to every other method.")
+	callerRateLimits = flag.String("caller-rate-limits", "",
+		"Comma separated limits on each caller's calls to all methods together, as caller=rate[:burst], "+
+			"for example \"batch-job=10,*=500\". * applies to every other caller.")
+	trustedCallers = flag.String("trusted-callers", "",
+		"Comma separated IP addresses or CIDR ranges of the clients trusted to identify themselves with x-caller-id, "+
+			"for example \"10.0.0.0/8\". Clients on this machine are always trusted.")
+	hourlyQuota = flag.Int64("hourly-quota", 0, "How many calls each caller may make per hour. 0 means unlimited.")
+	dailyQuota  = flag.Int64("daily-quota", 0, "How many calls each caller may make per day. 0 means unlimited.")
+)
+
+// These flags limit how many calls the server runs at once. The limit adapts to how long calls take, and calls the
+// server has no room for are rejected straight away, lowest priority first, so that the calls it serves stay fast.
+var (
+	maxConcurrency = flag.Int("max-concurrency", 0,
+		"The most MagicMath calls the server runs at once, the actual limit adapts below this. 0 means unlimited.")
+	latencyTarget = flag.Duration("latency-target", 50*time.Millisecond,
+		"How long a call should take, slower calls make the server lower its concurrency limit.")
+	queueSize    = flag.Int("queue-size", 100, "How many calls may wait for the concurrency limit before calls are shed.")
+	maxQueueWait = flag.Duration("max-queue-wait", 100*time.Millisecond, "How long a call may wait before it is shed.")
+	priorities   = flag.String("priorities", "",
+		"Comma separated priorities of each method, as method=low|normal|high, for example \"GetAddCount=low,*=normal\". "+
+			"Low priority calls are shed first. Clients can set a call's priority with the x-priority header.")
+)
+
+// These flags choose where crash reports are written when a handler panics, and when the server should report itself
+// as unhealthy because of panics.
+var (
+	crashReports = flag.String("crash-reports", "",
+		"Append a crash report to this file each time a call panics. If empty, crash reports are only logged.")
+	unhealthyAfterPanics = flag.Int64("unhealthy-after-panics", 0,
+		"Fail the health check after this many panics, so that the server gets restarted. 0 means never.")
+)
+
+// These flags make the server misbehave on purpose, to test how its clients cope. The faults can be changed while the
+// server runs through the FaultInjection service, which is only served when fault injection is enabled.
+var (
+	faultInjection = flag.Bool("fault-injection", false,
+		"Enable fault injection, and serve the FaultInjection service to control it. Starts with no faults unless -faults is set.")
+	faultsFile = flag.String("faults", "",
+		"A JSON file of fault rules, in the format of the FaultInjection service's FaultRules message. Enables fault injection.")
+)
+
+// These flags make calls with an idempotency-key header idempotent: duplicates of a call are answered with the first
+// call's response, without handling them again or counting them.
+var (
+	idempotencyWindow = flag.Duration("idempotency-window", 24*time.Hour,
+		"How long the response of a call with an idempotency key is kept for its duplicates. 0 turns idempotency keys off.")
+	idempotencyFile = flag.String("idempotency-file", "",
+		"Keep the responses of idempotent calls in this file, so that they survive a restart. If empty, they are kept in memory.")
+	idempotencyMaxResponses = flag.Int("idempotency-max-responses", 100000,
+		"The most responses of idempotent calls kept, once there are that many the oldest is forgotten.")
+	idempotencyMaxPerCaller = flag.Int("idempotency-max-per-caller", 1000,
+		"The most responses of idempotent calls kept for one caller, once a caller has that many their oldest is forgotten.")
+)
+
+// These flags serve the Operations service, which runs calls in the background so that clients don't have to hold a
+// call open while an expensive computation runs.
+var (
+	operationWorkers = flag.Int("operation-workers", 4,
+		"How many operations run at once. 0 turns the Operations service off.")
+	operationQueue  = flag.Int("operation-queue", 100, "How many operations may wait for a worker.")
+	operationExpiry = flag.Duration("operation-expiry", time.Hour, "How long an operation is kept once it is done.")
+	operationsFile  = flag.String("operations-file", "",
+		"Save the operations which are done to this file, so that their results survive a restart.")
+)
+
+// These flags cache the results of the math functions, so that calls repeating the same terms are answered without
+// working them out again. The function counters still count every call.
+var (
+	cacheSize = flag.Int("cache-size", 0, "How many results to cache. 0 turns the cache off.")
+	cacheTTL  = flag.Duration("cache-ttl", 5*time.Minute, "How long a result stays in the cache.")
+)
 
-// These counters store how many times each function has been called.
+// These flags limit the size of the numbers used by the arbitrary precision functions, so that a caller can't tie up
+// the server with a huge calculation.
 var (
-	addFuncCounter int64 = 0
-	subFuncCounter int64 = 0
-	minFuncCounter int64 = 0
-	maxFuncCounter int64 = 0
+	bigMaxDigits = flag.Int("big-max-digits", bignum.DefaultLimits.MaxDigits,
+		"The most characters in an arbitrary precision operand, and the most digits in an exponent or a result.")
+	bigMaxPrecision = flag.Uint("big-max-precision", bignum.DefaultLimits.MaxPrecision,
+		"The most bits a caller may ask for in the mantissa of an arbitrary precision float.")
+	decimalMaxDigits = flag.Int("decimal-max-digits", decimal.DefaultLimits.MaxDigits,
+		"The most digits in the unscaled integer of an exact decimal, whether it is a term or a result.")
+	decimalMaxScale = flag.Int("decimal-max-scale", int(decimal.DefaultLimits.MaxScale),
+		"The largest scale, either way, of an exact decimal, whether it is a term, a result or a scale a caller asks for.")
 )
 
-// These mutexes are used to protect read and write access to the function counters above.
+// These flags join the server to a cluster, whose members share their function counters with each other.
 var (
-	addCounterMutex sync.Mutex
-	subCounterMutex sync.Mutex
-	minCounterMutex sync.Mutex
-	maxCounterMutex sync.Mutex
+	nodeID = flag.String("node-id", "",
+		"The unique name of this server within the cluster, which it keeps when it restarts. "+
+			"Defaults to the hostname and port.")
+	peers = flag.String("peers", "",
+		"Comma separated peer addresses (see -peer-listen) of the other servers in the cluster. If empty, the server runs on its own.")
+	peerListen = flag.String("peer-listen", "",
+		"The address the other servers in the cluster gossip with this one on, such as 10.0.0.5:7946. Required with -peers. "+
+			"Keep it on a private network, or secure it with -peer-cert, -peer-key and -peer-ca.")
+	peerCert = flag.String("peer-cert", "",
+		"The certificate this server presents to its peers, as a PEM file. With -peer-key and -peer-ca, peers use mutual TLS.")
+	peerKey = flag.String("peer-key", "", "The private key of -peer-cert, as a PEM file.")
+	peerCA  = flag.String("peer-ca", "",
+		"The certificate authority, as a PEM file, which issued the -peer-cert of every server in the cluster.")
+	gossipInterval = flag.Duration("gossip-interval", time.Second,
+		"How often the server shares its counters with its peers.")
 )
 
 func main() {
+	flag.Parse()
+
 	fmt.Println("The magic math server is running!")
 
-	// Listen to incoming TCP connections on port 50051 (common gRPC development port).
-	port := 50051
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	// Listen to incoming connections on every requested address.
+	listeners := createListeners()
+	// Create the gRPC server with the magic interface bound to it.
+	config := app.Config{}
+	caller.TrustedNetworks = createTrustedNetworks()
+	config.Counters = createCounterStore()
+	config.UnaryInterceptors = createInterceptors()
+	config.Deduplicator = createDeduplicator()
+	config.RateLimiter = createRateLimiter(config.Counters)
+	config.ConcurrencyLimiter = createConcurrencyLimiter()
+	config.FaultInjector = createFaultInjector()
+	config.Cache = createCacheOptions()
+	config.Operations = createOperationsOptions()
+	config.BigLimits = bignum.Limits{MaxDigits: *bigMaxDigits, MaxPrecision: *bigMaxPrecision}
+	config.DecimalLimits = decimal.Limits{MaxDigits: *decimalMaxDigits, MaxScale: int32(*decimalMaxScale)}
+	config.Health = health.NewServer()
+	config.Recoverer = recovery.New(recovery.Options{
+		Counters:       config.Counters,
+		ReportFile:     *crashReports,
+		Health:         config.Health,
+		UnhealthyAfter: *unhealthyAfterPanics,
+	})
+	s := app.New(config)
+
+	for _, lis := range listeners {
+		fmt.Printf("The server is listening at %v\n", lis.Addr())
+	}
+
+	if gatewayPort := createGatewayPort(); gatewayPort != 0 {
+		go startGateway(listen.DialTarget(listeners[0]), net.JoinHostPort(*httpHost, strconv.Itoa(gatewayPort)))
+	}
+
+	if *webProtocols {
+		serveWeb(s, listeners)
+		return
+	}
+
+	// Begin serving requests on every listener, s.Serve will run in a loop until the server terminates.
+	serveAll(listeners, s.Serve)
+}
+
+// This function opens the listeners the server serves requests on: the sockets passed by systemd socket activation,
+// plus every address in the -listen flag. If neither gives a listener, it listens on the -port flag's TCP port.
+func createListeners() []net.Listener {
+	listeners, err := listen.Inherited()
+	if err != nil {
+		panic(err)
+	}
+
+	addresses := []string{}
+	if *listenAddresses != "" {
+		addresses = strings.Split(*listenAddresses, ",")
+	} else if len(listeners) == 0 {
+		addresses = []string{fmt.Sprintf(":%d", *port)}
+	}
+
+	mode, err := strconv.ParseUint(*socketMode, 8, 32)
+	if err != nil {
+		panic(fmt.Errorf("invalid socket mode %q: %v", *socketMode, err))
+	}
+
+	opened, err := listen.Listen(addresses, os.FileMode(mode))
+	if err != nil {
+		panic(err)
+	}
+
+	return append(listeners, opened...)
+}
+
+// This function runs the serve function on every listener at once, and returns when they have all stopped.
+// If any of them fails, the server terminates.
+func serveAll(listeners []net.Listener, serve func(net.Listener) error) {
+	var waitGroup sync.WaitGroup
+	for _, lis := range listeners {
+		waitGroup.Go(func() {
+			if err := serve(lis); err != nil {
+				log.Printf("failed to serve at %v: %v", lis.Addr(), err)
+				panic(err)
+			}
+		})
+	}
+	waitGroup.Wait()
+}
+
+// This function serves native gRPC, gRPC-Web and the Connect protocol on the same listeners.
+// It runs in a loop until the server terminates.
+func serveWeb(s *grpc.Server, listeners []net.Listener) {
+	options := web.Options{}
+	if *corsOrigins != "" {
+		options.AllowedOrigins = strings.Split(*corsOrigins, ",")
+	}
+
+	handler, err := web.New(s, options)
+	if err != nil {
+		panic(fmt.Errorf("failed to create the web handler: %v", err))
+	}
+	defer handler.Close()
+
+	fmt.Println("The server also accepts gRPC-Web and Connect protocol requests")
+
+	serveAll(listeners, web.NewServer(handler).Serve)
+}
+
+// This function creates the interceptors which run around every call, as chosen by the flags.
+func createInterceptors() []grpc.UnaryServerInterceptor {
+	var interceptors []grpc.UnaryServerInterceptor
+
+	if *recordFile != "" {
+		writer, err := capture.NewWriter(*recordFile, *recordMaxBytes, *recordMaxFiles)
+		if err != nil {
+			panic(fmt.Errorf("failed to open capture file: %v", err))
+		}
+		interceptors = append(interceptors, recorder.UnaryServerInterceptor(writer))
+		fmt.Printf("Recording every call to %s\n", *recordFile)
+	}
+
+	return interceptors
+}
+
+// This function returns the networks of the clients which may identify themselves with x-caller-id, from the flags.
+func createTrustedNetworks() []netip.Prefix {
+	networks, err := caller.ParseNetworks(*trustedCallers)
 	if err != nil {
-		fmt.Printf("failed to listen to port %d: %v", port, err)
 		panic(err)
 	}
-	// Create a new unbound gRPC server.
-	s := grpc.NewServer()
-	// Bind the magic interface to the gRPC server.
-	pb.RegisterMagicMathServer(s, &server{})
 
-	fmt.Printf("The server is listening at %v\n", lis.Addr())
+	return networks
+}
+
+// This function creates the rate limiter from the flags, or returns nil if no limits are set.
+// The quotas are counted in the counter store, so a cluster enforces them across all of its servers.
+func createRateLimiter(counters counter.Store) *ratelimit.Limiter {
+	config := ratelimit.Config{HourlyQuota: *hourlyQuota, DailyQuota: *dailyQuota}
 
-	// Begin serving requests at the listening port, s.Server will run in a loop until the server terminates.
-	if err := s.Serve(lis); err != nil {
-		log.Printf("failed to serve: %v", err)
+	var err error
+	if config.Methods, err = ratelimit.ParseLimits(*methodRateLimits); err != nil {
 		panic(err)
 	}
+	if config.Callers, err = ratelimit.ParseLimits(*callerRateLimits); err != nil {
+		panic(err)
+	}
+
+	if len(config.Methods) == 0 && len(config.Callers) == 0 && config.HourlyQuota == 0 && config.DailyQuota == 0 {
+		return nil
+	}
+
+	fmt.Println("Rate limits and quotas are enabled")
+
+	return ratelimit.New(config, counters)
 }
 
-// ========================================== Math Functions ==========================================
+// This function creates the concurrency limiter from the flags, or returns nil if the concurrency is unlimited.
+func createConcurrencyLimiter() *loadshed.Limiter {
+	if *maxConcurrency <= 0 {
+		return nil
+	}
 
-// MagicAdd takes a request context (which is ignored) and two doubles, and returns their sum.
-func (s *server) MagicAdd(_ context.Context, in *pb.DoubleTerms) (*pb.DoubleResult, error) {
-	addCounterMutex.Lock()
-	addFuncCounter++
-	addCounterMutex.Unlock()
+	methodPriorities, err := loadshed.ParsePriorities(*priorities)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Running at most %d calls at once\n", *maxConcurrency)
+
+	return loadshed.New(loadshed.Options{
+		MaxLimit:      *maxConcurrency,
+		LatencyTarget: *latencyTarget,
+		QueueSize:     *queueSize,
+		MaxQueueWait:  *maxQueueWait,
+		Priorities:    methodPriorities,
+	})
+}
+
+// This function creates the deduplicator from the flags, or returns nil if idempotency keys are turned off.
+func createDeduplicator() *idempotency.Deduplicator {
+	if *idempotencyWindow <= 0 {
+		return nil
+	}
+	storeOptions := idempotency.StoreOptions{MaxResponses: *idempotencyMaxResponses, MaxPerCaller: *idempotencyMaxPerCaller}
+	if *idempotencyFile == "" {
+		store := idempotency.NewMemoryStore(storeOptions)
+		return idempotency.New(idempotency.Options{Window: *idempotencyWindow, Store: store})
+	}
 
-	sum := math.LocalAdd(in.TermOne, in.TermTwo)
-	responseObject := &pb.DoubleResult{Result: sum}
+	store, err := idempotency.OpenFileStore(*idempotencyFile, storeOptions)
+	if err != nil {
+		panic(fmt.Errorf("failed to open the idempotency file: %v", err))
+	}
+	fmt.Printf("Keeping the responses of idempotent calls in %s\n", *idempotencyFile)
 
-	return responseObject, nil
+	return idempotency.New(idempotency.Options{Window: *idempotencyWindow, Store: store})
 }
 
-// MagicSubtract takes a request context (which is ignored) and two doubles, and returns their difference.
-func (s *server) MagicSubtract(_ context.Context, in *pb.DoubleTerms) (*pb.DoubleResult, error) {
-	subCounterMutex.Lock()
-	subFuncCounter++
-	subCounterMutex.Unlock()
+// This function returns the Operations service's options from the flags, or nil if the service is turned off.
+func createOperationsOptions() *operations.Options {
+	if *operationWorkers <= 0 {
+		return nil
+	}
 
-	difference := math.LocalSubtract(in.TermOne, in.TermTwo)
-	responseObject := &pb.DoubleResult{Result: difference}
+	options := &operations.Options{Workers: *operationWorkers, QueueSize: *operationQueue, Expiry: *operationExpiry}
+	if *operationsFile != "" {
+		store, err := operations.OpenFileStore(*operationsFile)
+		if err != nil {
+			panic(fmt.Errorf("failed to open the operations file: %v", err))
+		}
+		options.Store = store
+	}
 
-	return responseObject, nil
+	return options
 }
 
-// MagicFindMin takes a request context (which is ignored) and three integers, and returns the lowest value.
-// If all three values are equal it returns the first value.
-func (s *server) MagicFindMin(_ context.Context, in *pb.IntTerms) (*pb.IntResult, error) {
-	minCounterMutex.Lock()
-	minFuncCounter++
-	minCounterMutex.Unlock()
+// This function returns the result cache's options from the flags, or nil if the cache is turned off.
+func createCacheOptions() *cache.Options {
+	if *cacheSize <= 0 {
+		return nil
+	}
 
-	minimum := math.LocalFindMin(in.TermOne, in.TermTwo, in.TermThree)
-	responseObject := &pb.IntResult{Result: minimum}
+	fmt.Printf("Caching up to %d results for %v\n", *cacheSize, *cacheTTL)
 
-	return responseObject, nil
+	return &cache.Options{MaxEntries: *cacheSize, TTL: *cacheTTL}
 }
 
-// MagicFindMax takes a request context (which is ignored) and three integers, and returns the highest value.
-// If all three values are equal it returns the first value.
-func (s *server) MagicFindMax(_ context.Context, in *pb.IntTerms) (*pb.IntResult, error) {
-	maxCounterMutex.Lock()
-	maxFuncCounter++
-	maxCounterMutex.Unlock()
+// This function creates the fault injector, with the rules in the faults file if there is one,
+// or returns nil if fault injection isn't enabled.
+func createFaultInjector() *faults.Injector {
+	if !*faultInjection && *faultsFile == "" {
+		return nil
+	}
+
+	injector := faults.New()
+	if *faultsFile != "" {
+		contents, err := os.ReadFile(*faultsFile)
+		if err != nil {
+			panic(fmt.Errorf("failed to read faults file: %v", err))
+		}
+		rules := &pb.FaultRules{}
+		if err := protojson.Unmarshal(contents, rules); err != nil {
+			panic(fmt.Errorf("failed to parse faults file: %v", err))
+		}
+		if err := injector.SetRules(rules); err != nil {
+			panic(fmt.Errorf("invalid faults file: %v", err))
+		}
+	}
 
-	maximum := math.LocalFindMax(in.TermOne, in.TermTwo, in.TermThree)
-	responseObject := &pb.IntResult{Result: maximum}
+	fmt.Println("Fault injection is enabled, DO NOT use this in production")
 
-	return responseObject, nil
+	return injector
 }
 
-// ========================================== Counter Functions ==========================================
+// This function creates the store for the function counters.
+// A server on its own keeps them in memory, a server in a cluster replicates them to its peers. A server in a cluster
+// also serves the peer service, which the other servers gossip with, on the -peer-listen address.
+func createCounterStore() counter.Store {
+	if *peers == "" {
+		return counter.NewMemoryStore()
+	}
+	if *peerListen == "" {
+		panic(fmt.Errorf("-peers needs -peer-listen, the address the peers gossip with this server on"))
+	}
+
+	name := *nodeID
+	if name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "localhost"
+		}
+		name = fmt.Sprintf("%s:%d", hostname, *port)
+	}
 
-// GetAddCount returns the total number of times MagicAdd has been called.
-func (s *server) GetAddCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
-	addCounterMutex.Lock()
-	count := addFuncCounter
-	addCounterMutex.Unlock()
+	serverCredentials, clientCredentials := createPeerCredentials()
+	node := cluster.NewNode(name)
+	for _, address := range strings.Split(*peers, ",") {
+		connection, err := grpc.NewClient(strings.TrimSpace(address), grpc.WithTransportCredentials(clientCredentials))
+		if err != nil {
+			panic(fmt.Errorf("failed to connect to peer %s: %v", address, err))
+		}
+		node.AddPeer(address, pb.NewClusterPeerClient(connection))
+	}
+
+	listener, err := net.Listen("tcp", *peerListen)
+	if err != nil {
+		panic(fmt.Errorf("failed to listen for peers on %s: %v", *peerListen, err))
+	}
+	peerServer := cluster.NewPeerServer(node, serverCredentials)
+	go func() {
+		if err := peerServer.Serve(listener); err != nil {
+			panic(fmt.Errorf("failed to serve peers at %v: %v", listener.Addr(), err))
+		}
+	}()
 
-	responseObject := &pb.Count{Count: count}
+	go node.Run(context.Background(), *gossipInterval)
 
-	return responseObject, nil
+	fmt.Printf("Server %s is gossiping with peers %s on %v\n", name, *peers, listener.Addr())
+
+	return node
 }
 
-// GetSubCount returns the total number of times MagicSubtract has been called.
-func (s *server) GetSubCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
-	subCounterMutex.Lock()
-	count := subFuncCounter
-	subCounterMutex.Unlock()
+// This function returns the credentials the peer service is served with, and the peers are dialled with.
+// Without -peer-cert, -peer-key and -peer-ca the peers talk in plain text, so the peer listener must be private.
+func createPeerCredentials() (credentials.TransportCredentials, credentials.TransportCredentials) {
+	if *peerCert == "" && *peerKey == "" && *peerCA == "" {
+		fmt.Println("Peers gossip without TLS, keep -peer-listen on a private network")
+		return nil, insecure.NewCredentials()
+	}
+	if *peerCert == "" || *peerKey == "" || *peerCA == "" {
+		panic(fmt.Errorf("mutual TLS between peers needs all of -peer-cert, -peer-key and -peer-ca"))
+	}
 
-	responseObject := &pb.Count{Count: count}
+	serverCredentials, clientCredentials, err := cluster.PeerCredentials(*peerCert, *peerKey, *peerCA)
+	if err != nil {
+		panic(err)
+	}
 
-	return responseObject, nil
+	return serverCredentials, clientCredentials
 }
 
-// GetMinCount returns the total number of times MagicFindMin has been called.
-func (s *server) GetMinCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
-	minCounterMutex.Lock()
-	count := minFuncCounter
-	minCounterMutex.Unlock()
+// This function returns the port of the REST gateway from the flags, or 0 if the gateway is turned off.
+// Unless -http-port is set, it is as far from 8080 as -port is from 50051.
+func createGatewayPort() int {
+	if *httpPort >= 0 {
+		return *httpPort
+	}
 
-	responseObject := &pb.Count{Count: count}
+	gatewayPort := defaultHTTPPort + *port - defaultPort
+	if gatewayPort < 1 || gatewayPort > 65535 {
+		fmt.Println("The REST gateway is turned off, as no port follows from -port, set -http-port to turn it on")
+		return 0
+	}
 
-	return responseObject, nil
+	return gatewayPort
 }
 
-// GetMaxCount returns the total number of times MagicFindMax has been called.
-func (s *server) GetMaxCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
-	maxCounterMutex.Lock()
-	count := maxFuncCounter
-	maxCounterMutex.Unlock()
+// This function serves the REST gateway on its own address. The gateway calls this server at the target,
+// so it runs in its own go routine alongside the gRPC server.
+func startGateway(target, address string) {
+	connection, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(fmt.Errorf("failed to connect the gateway to the server: %v", err))
+	}
 
-	responseObject := &pb.Count{Count: count}
+	handler, err := gateway.New(context.Background(), connection)
+	if err != nil {
+		panic(fmt.Errorf("failed to create the gateway: %v", err))
+	}
 
-	return responseObject, nil
+	fmt.Printf("The REST gateway is listening at %s, with its OpenAPI document at %s\n", address, gateway.OpenAPIPath)
+
+	if err := http.ListenAndServe(address, handler); err != nil {
+		log.Printf("failed to serve the gateway: %v", err)
+		panic(err)
+	}
 }
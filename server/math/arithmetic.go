@@ -0,0 +1,170 @@
+package math
+
+import (
+	"errors"
+	"math"
+)
+
+// These errors explain why an arithmetic function has no result. The server turns them into gRPC errors.
+var (
+	// ErrDivisionByZero is returned when dividing by zero, taking the modulo of zero,
+	// or raising zero to a negative power.
+	ErrDivisionByZero = errors.New("division by zero")
+	// ErrOverflow is returned when the result is too large for its type.
+	ErrOverflow = errors.New("result overflows")
+	// ErrUndefined is returned when the result is not a number, for example for infinity minus infinity,
+	// or when an operand is not a number.
+	ErrUndefined = errors.New("result is undefined")
+	// ErrNegativeExponent is returned when raising an integer to a negative power, which gives a fraction.
+	ErrNegativeExponent = errors.New("negative exponent, use doubles for fractional results")
+)
+
+// ========================================== Doubles ==========================================
+
+// The double functions follow IEEE-754, except that instead of returning NaN they return ErrUndefined,
+// and instead of overflowing to an infinity they return ErrOverflow. Infinite operands are allowed,
+// so multiplying infinity by two still gives infinity.
+
+// LocalCheckedAdd is LocalAdd, but with the errors of the other double functions.
+func LocalCheckedAdd(a, b float64) (float64, error) {
+	return checkDouble(a+b, a, b)
+}
+
+// LocalCheckedSubtract is LocalSubtract, but with the errors of the other double functions.
+func LocalCheckedSubtract(a, b float64) (float64, error) {
+	return checkDouble(a-b, a, b)
+}
+
+func LocalMultiply(a, b float64) (float64, error) {
+	return checkDouble(a*b, a, b)
+}
+
+func LocalDivide(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, ErrDivisionByZero
+	}
+
+	return checkDouble(a/b, a, b)
+}
+
+// LocalModulo returns the remainder of a divided by b, which has the same sign as a.
+func LocalModulo(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, ErrDivisionByZero
+	}
+
+	return checkDouble(math.Mod(a, b), a, b)
+}
+
+func LocalPower(a, b float64) (float64, error) {
+	if a == 0 && b < 0 {
+		return 0, ErrDivisionByZero
+	}
+
+	return checkDouble(math.Pow(a, b), a, b)
+}
+
+// This function checks the result of a double function with operands a and b.
+func checkDouble(result, a, b float64) (float64, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(result) {
+		return 0, ErrUndefined
+	}
+	if math.IsInf(result, 0) && !math.IsInf(a, 0) && !math.IsInf(b, 0) {
+		return 0, ErrOverflow
+	}
+
+	return result, nil
+}
+
+// ========================================== Integers ==========================================
+
+func LocalAddInt(a, b int64) (int64, error) {
+	sum := a + b
+	// The sum overflowed if both terms have the same sign, and the sum has the other sign.
+	if (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+		return 0, ErrOverflow
+	}
+
+	return sum, nil
+}
+
+func LocalSubtractInt(a, b int64) (int64, error) {
+	difference := a - b
+	// The difference overflowed if the terms have different signs, and the difference has the sign of b.
+	if (a >= 0) != (b >= 0) && (difference >= 0) != (a >= 0) {
+		return 0, ErrOverflow
+	}
+
+	return difference, nil
+}
+
+func LocalMultiplyInt(a, b int64) (int64, error) {
+	product, ok := multiplyInt(a, b)
+	if !ok {
+		return 0, ErrOverflow
+	}
+
+	return product, nil
+}
+
+// LocalDivideInt returns a divided by b, rounded towards zero.
+func LocalDivideInt(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, ErrDivisionByZero
+	}
+	if a == math.MinInt64 && b == -1 {
+		return 0, ErrOverflow
+	}
+
+	return a / b, nil
+}
+
+// LocalModuloInt returns the remainder of a divided by b, which has the same sign as a.
+func LocalModuloInt(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, ErrDivisionByZero
+	}
+
+	return a % b, nil
+}
+
+// LocalPowerInt returns a raised to the power b. Zero to the power zero is one.
+func LocalPowerInt(a, b int64) (int64, error) {
+	if b < 0 {
+		if a == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return 0, ErrNegativeExponent
+	}
+
+	// Raise by repeated squaring, which takes at most 63 steps.
+	result := int64(1)
+	for ok := true; b > 0; b >>= 1 {
+		if b&1 == 1 {
+			if result, ok = multiplyInt(result, a); !ok {
+				return 0, ErrOverflow
+			}
+		}
+		if b > 1 {
+			if a, ok = multiplyInt(a, a); !ok {
+				return 0, ErrOverflow
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// This function multiplies two integers, and reports whether the product fits in an int64.
+func multiplyInt(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	return product, true
+}
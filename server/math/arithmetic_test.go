@@ -0,0 +1,113 @@
+package math
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDoubleArithmetic(t *testing.T) {
+	inf := math.Inf(1)
+	nan := math.NaN()
+
+	tests := []struct {
+		name    string
+		f       func(a, b float64) (float64, error)
+		a, b    float64
+		want    float64
+		wantErr error
+	}{
+		{"LocalCheckedAdd", LocalCheckedAdd, 2.5, 4, 6.5, nil},
+		{"LocalCheckedAdd", LocalCheckedAdd, inf, 1, inf, nil},
+		{"LocalCheckedAdd", LocalCheckedAdd, math.MaxFloat64, math.MaxFloat64, 0, ErrOverflow},
+		{"LocalCheckedAdd", LocalCheckedAdd, inf, -inf, 0, ErrUndefined},
+		{"LocalCheckedSubtract", LocalCheckedSubtract, 2.5, 4, -1.5, nil},
+		{"LocalCheckedSubtract", LocalCheckedSubtract, -math.MaxFloat64, math.MaxFloat64, 0, ErrOverflow},
+		{"LocalCheckedSubtract", LocalCheckedSubtract, inf, inf, 0, ErrUndefined},
+		{"LocalMultiply", LocalMultiply, 2.5, 4, 10, nil},
+		{"LocalMultiply", LocalMultiply, inf, 2, inf, nil},
+		{"LocalMultiply", LocalMultiply, math.MaxFloat64, 2, 0, ErrOverflow},
+		{"LocalMultiply", LocalMultiply, inf, 0, 0, ErrUndefined},
+		{"LocalMultiply", LocalMultiply, nan, 1, 0, ErrUndefined},
+		{"LocalDivide", LocalDivide, 7, 2, 3.5, nil},
+		{"LocalDivide", LocalDivide, 1, 0, 0, ErrDivisionByZero},
+		{"LocalDivide", LocalDivide, 0, 0, 0, ErrDivisionByZero},
+		{"LocalDivide", LocalDivide, 1, inf, 0, nil},
+		{"LocalDivide", LocalDivide, inf, inf, 0, ErrUndefined},
+		{"LocalDivide", LocalDivide, math.MaxFloat64, 0.5, 0, ErrOverflow},
+		{"LocalModulo", LocalModulo, 7, 3, 1, nil},
+		{"LocalModulo", LocalModulo, -7, 3, -1, nil},
+		{"LocalModulo", LocalModulo, 7.5, 2, 1.5, nil},
+		{"LocalModulo", LocalModulo, 7, 0, 0, ErrDivisionByZero},
+		{"LocalModulo", LocalModulo, inf, 3, 0, ErrUndefined},
+		{"LocalPower", LocalPower, 2, 10, 1024, nil},
+		{"LocalPower", LocalPower, 4, 0.5, 2, nil},
+		{"LocalPower", LocalPower, 2, -1, 0.5, nil},
+		{"LocalPower", LocalPower, 0, 0, 1, nil},
+		{"LocalPower", LocalPower, 0, -1, 0, ErrDivisionByZero},
+		{"LocalPower", LocalPower, -8, 1.0 / 3, 0, ErrUndefined},
+		{"LocalPower", LocalPower, 10, 400, 0, ErrOverflow},
+		{"LocalPower", LocalPower, 10, -400, 0, nil}, // underflows to zero
+	}
+
+	for _, tt := range tests {
+		got, err := tt.f(tt.a, tt.b)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%s(%v, %v) = %v, %v; want %v, %v", tt.name, tt.a, tt.b, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestIntArithmetic(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       func(a, b int64) (int64, error)
+		a, b    int64
+		want    int64
+		wantErr error
+	}{
+		{"LocalAddInt", LocalAddInt, 6, -7, -1, nil},
+		{"LocalAddInt", LocalAddInt, math.MaxInt64, math.MinInt64, -1, nil},
+		{"LocalAddInt", LocalAddInt, math.MaxInt64, 1, 0, ErrOverflow},
+		{"LocalAddInt", LocalAddInt, math.MinInt64, -1, 0, ErrOverflow},
+		{"LocalAddInt", LocalAddInt, -1, 0, -1, nil},
+		{"LocalSubtractInt", LocalSubtractInt, 6, -7, 13, nil},
+		{"LocalSubtractInt", LocalSubtractInt, -1, math.MaxInt64, math.MinInt64, nil},
+		{"LocalSubtractInt", LocalSubtractInt, 0, math.MinInt64, 0, ErrOverflow},
+		{"LocalSubtractInt", LocalSubtractInt, math.MaxInt64, -1, 0, ErrOverflow},
+		{"LocalSubtractInt", LocalSubtractInt, math.MinInt64, 1, 0, ErrOverflow},
+		{"LocalMultiplyInt", LocalMultiplyInt, 6, -7, -42, nil},
+		{"LocalMultiplyInt", LocalMultiplyInt, math.MaxInt64, 0, 0, nil},
+		{"LocalMultiplyInt", LocalMultiplyInt, math.MaxInt64, 2, 0, ErrOverflow},
+		{"LocalMultiplyInt", LocalMultiplyInt, math.MinInt64, -1, 0, ErrOverflow},
+		{"LocalMultiplyInt", LocalMultiplyInt, -1, math.MinInt64, 0, ErrOverflow},
+		{"LocalMultiplyInt", LocalMultiplyInt, math.MinInt64, 1, math.MinInt64, nil},
+		{"LocalMultiplyInt", LocalMultiplyInt, 1 << 32, 1 << 31, 0, ErrOverflow},
+		{"LocalDivideInt", LocalDivideInt, 7, 2, 3, nil},
+		{"LocalDivideInt", LocalDivideInt, -7, 2, -3, nil}, // rounds towards zero
+		{"LocalDivideInt", LocalDivideInt, 7, 0, 0, ErrDivisionByZero},
+		{"LocalDivideInt", LocalDivideInt, math.MinInt64, -1, 0, ErrOverflow},
+		{"LocalModuloInt", LocalModuloInt, 7, 3, 1, nil},
+		{"LocalModuloInt", LocalModuloInt, -7, 3, -1, nil},
+		{"LocalModuloInt", LocalModuloInt, 7, 0, 0, ErrDivisionByZero},
+		{"LocalModuloInt", LocalModuloInt, math.MinInt64, -1, 0, nil},
+		{"LocalPowerInt", LocalPowerInt, 2, 10, 1024, nil},
+		{"LocalPowerInt", LocalPowerInt, -3, 3, -27, nil},
+		{"LocalPowerInt", LocalPowerInt, 0, 0, 1, nil},
+		{"LocalPowerInt", LocalPowerInt, 2, 62, 1 << 62, nil},
+		{"LocalPowerInt", LocalPowerInt, 2, 63, 0, ErrOverflow},
+		{"LocalPowerInt", LocalPowerInt, -2, 63, math.MinInt64, nil},
+		{"LocalPowerInt", LocalPowerInt, -1, math.MaxInt64, -1, nil},
+		{"LocalPowerInt", LocalPowerInt, 1, math.MaxInt64, 1, nil},
+		{"LocalPowerInt", LocalPowerInt, 3, 40, 0, ErrOverflow},
+		{"LocalPowerInt", LocalPowerInt, 2, -1, 0, ErrNegativeExponent},
+		{"LocalPowerInt", LocalPowerInt, 0, -1, 0, ErrDivisionByZero},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.f(tt.a, tt.b)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%s(%d, %d) = %d, %v; want %d, %v", tt.name, tt.a, tt.b, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
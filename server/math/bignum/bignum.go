@@ -0,0 +1,452 @@
+package bignum
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	magicmath "github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+// These are the kinds of numbers the calculator works with.
+const (
+	// Integer numbers are exact, and integer division rounds towards zero.
+	Integer = "int"
+	// Rational numbers are exact fractions, so dividing one by three gives "1/3". This is the default kind.
+	Rational = "rat"
+	// Float numbers are binary floating point numbers with a mantissa of the chosen precision, rounded with the
+	// chosen rounding mode after every operation.
+	Float = "float"
+)
+
+// DefaultPrecision is the number of bits in a float's mantissa when the caller doesn't choose one,
+// which is about 77 decimal digits.
+const DefaultPrecision = 256
+
+// ErrTooLarge is returned when an operand, a precision or an exponent is larger than the calculator's limits allow.
+// Results which are too large fail with the math package's ErrOverflow instead.
+var ErrTooLarge = errors.New("number is larger than the server allows")
+
+// ErrInvalidNumber is returned when an operand is not a decimal number of the right kind.
+var ErrInvalidNumber = errors.New("not a valid number")
+
+// Limits stop callers from exhausting the server's CPU and memory with huge numbers.
+type Limits struct {
+	// MaxDigits is the largest number of characters in an operand, and of decimal digits in a decimal exponent or
+	// in the numerator or denominator of a result. Defaults to 1000.
+	MaxDigits int
+	// MaxPrecision is the largest float precision a caller may choose, in bits. Defaults to 4096.
+	MaxPrecision uint
+}
+
+// DefaultLimits are the limits of a calculator created with zero limits.
+var DefaultLimits = Limits{MaxDigits: 1000, MaxPrecision: 4096}
+
+// Mode chooses how the calculator works out a result.
+type Mode struct {
+	// Kind is Integer, Rational or Float. Defaults to Rational.
+	Kind string
+	// Precision is the number of bits in a float's mantissa. Defaults to DefaultPrecision, or the calculator's
+	// limit if that is lower.
+	Precision uint
+	// Rounding is how floats are rounded, it is ignored by the other kinds.
+	Rounding big.RoundingMode
+}
+
+// Calculator does arbitrary precision arithmetic on numbers written as decimal strings, within its limits.
+// It is safe to use from many go routines at once.
+type Calculator struct {
+	limits Limits
+	// The largest number of bits in an integer, numerator or denominator, worked out from limits.MaxDigits.
+	maxBits int
+}
+
+// New creates a calculator with the given limits. Zero limits take their values from DefaultLimits.
+func New(limits Limits) *Calculator {
+	if limits.MaxDigits <= 0 {
+		limits.MaxDigits = DefaultLimits.MaxDigits
+	}
+	if limits.MaxPrecision == 0 {
+		limits.MaxPrecision = DefaultLimits.MaxPrecision
+	}
+
+	return &Calculator{limits: limits, maxBits: int(math.Ceil(float64(limits.MaxDigits) * math.Log2(10)))}
+}
+
+// ParseRounding parses the name of a rounding mode, such as "ToNearestEven" or "to_zero". The name is not case
+// sensitive, and may leave out the underscores. An empty name means ToNearestEven.
+func ParseRounding(name string) (big.RoundingMode, error) {
+	if name == "" {
+		return big.ToNearestEven, nil
+	}
+
+	normalized := strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	for mode := big.ToNearestEven; mode <= big.ToPositiveInf; mode++ {
+		if strings.ToLower(mode.String()) == normalized {
+			return mode, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown rounding mode %q", name)
+}
+
+// These are the operations the calculator can do.
+type operation int
+
+const (
+	add operation = iota
+	subtract
+	multiply
+	divide
+	power
+)
+
+// Add returns a plus b.
+func (c *Calculator) Add(a, b string, mode Mode) (string, error) {
+	return c.calculate(add, a, b, mode)
+}
+
+// Subtract returns a minus b.
+func (c *Calculator) Subtract(a, b string, mode Mode) (string, error) {
+	return c.calculate(subtract, a, b, mode)
+}
+
+// Multiply returns a times b.
+func (c *Calculator) Multiply(a, b string, mode Mode) (string, error) {
+	return c.calculate(multiply, a, b, mode)
+}
+
+// Divide returns a divided by b. Integer division rounds towards zero.
+func (c *Calculator) Divide(a, b string, mode Mode) (string, error) {
+	return c.calculate(divide, a, b, mode)
+}
+
+// Power returns a raised to the power b, which must be an integer. Integers can't have a negative exponent.
+func (c *Calculator) Power(a, b string, mode Mode) (string, error) {
+	return c.calculate(power, a, b, mode)
+}
+
+// This function checks the operands against the limits, and then works out the operation with the mode's kind.
+func (c *Calculator) calculate(op operation, a, b string, mode Mode) (string, error) {
+	if err := c.checkOperand(a); err != nil {
+		return "", err
+	}
+	if err := c.checkOperand(b); err != nil {
+		return "", err
+	}
+
+	switch mode.Kind {
+	case Integer:
+		return c.calculateInt(op, a, b)
+	case Rational, "":
+		return c.calculateRat(op, a, b)
+	case Float:
+		return c.calculateFloat(op, a, b, mode)
+	default:
+		return "", fmt.Errorf("unknown kind of number %q, use %q, %q or %q", mode.Kind, Integer, Rational, Float)
+	}
+}
+
+// This function checks that an operand only has the characters of a decimal number, and that neither it nor its
+// exponent are too long. Parsing "1e1000000000" as a rational would otherwise take a very long time.
+func (c *Calculator) checkOperand(operand string) error {
+	if len(operand) > c.limits.MaxDigits {
+		return fmt.Errorf("%w: operand has %d characters, the limit is %d", ErrTooLarge, len(operand), c.limits.MaxDigits)
+	}
+	if strings.Trim(operand, "0123456789+-./eE") != "" || operand == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidNumber, operand)
+	}
+
+	if index := strings.IndexAny(operand, "eE"); index >= 0 {
+		exponent, err := strconv.Atoi(operand[index+1:])
+		if err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidNumber, operand)
+		}
+		if exponent > c.limits.MaxDigits || exponent < -c.limits.MaxDigits {
+			return fmt.Errorf("%w: exponent %d is beyond ±%d", ErrTooLarge, exponent, c.limits.MaxDigits)
+		}
+	}
+
+	return nil
+}
+
+// This function checks that a result has no more bits than the limit.
+func (c *Calculator) checkBits(bits int) error {
+	if bits > c.maxBits {
+		return fmt.Errorf("%w: the result would have more than %d digits", magicmath.ErrOverflow, c.limits.MaxDigits)
+	}
+
+	return nil
+}
+
+// This function parses the exponent of a power, which must be an integer that fits in an int64.
+func parseExponent(operand string) (int64, error) {
+	exponent, ok := new(big.Rat).SetString(operand)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNumber, operand)
+	}
+	if !exponent.IsInt() || !exponent.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: the exponent %q is not a whole number", ErrInvalidNumber, operand)
+	}
+
+	return exponent.Num().Int64(), nil
+}
+
+// ========================================== Integers ==========================================
+
+func (c *Calculator) calculateInt(op operation, a, b string) (string, error) {
+	x, ok := new(big.Int).SetString(a, 10)
+	if !ok {
+		return "", fmt.Errorf("%w: %q is not an integer", ErrInvalidNumber, a)
+	}
+
+	if op == power {
+		exponent, err := parseExponent(b)
+		if err != nil {
+			return "", err
+		}
+		return c.powerInt(x, exponent)
+	}
+
+	y, ok := new(big.Int).SetString(b, 10)
+	if !ok {
+		return "", fmt.Errorf("%w: %q is not an integer", ErrInvalidNumber, b)
+	}
+
+	z := new(big.Int)
+	switch op {
+	case add:
+		z.Add(x, y)
+	case subtract:
+		z.Sub(x, y)
+	case multiply:
+		z.Mul(x, y)
+	case divide:
+		if y.Sign() == 0 {
+			return "", magicmath.ErrDivisionByZero
+		}
+		z.Quo(x, y)
+	}
+	if err := c.checkBits(z.BitLen()); err != nil {
+		return "", err
+	}
+
+	return z.String(), nil
+}
+
+// This function raises an integer to a power, after checking that the result will fit within the limits.
+func (c *Calculator) powerInt(x *big.Int, exponent int64) (string, error) {
+	if exponent < 0 {
+		if x.Sign() == 0 {
+			return "", magicmath.ErrDivisionByZero
+		}
+		return "", magicmath.ErrNegativeExponent
+	}
+
+	// Zero, one and minus one stay small whatever the exponent.
+	if x.CmpAbs(big.NewInt(1)) > 0 {
+		if err := c.checkBits(powerBits(x.BitLen(), exponent)); err != nil {
+			return "", err
+		}
+	}
+
+	return new(big.Int).Exp(x, big.NewInt(exponent), nil).String(), nil
+}
+
+// This function returns an upper bound on the number of bits in a number of the given length raised to a power,
+// without overflowing.
+func powerBits(bits int, exponent int64) int {
+	if exponent > int64(math.MaxInt/max(bits, 1)) {
+		return math.MaxInt
+	}
+
+	return bits * int(exponent)
+}
+
+// ========================================== Rationals ==========================================
+
+func (c *Calculator) calculateRat(op operation, a, b string) (string, error) {
+	x, ok := new(big.Rat).SetString(a)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidNumber, a)
+	}
+
+	if op == power {
+		exponent, err := parseExponent(b)
+		if err != nil {
+			return "", err
+		}
+		return c.powerRat(x, exponent)
+	}
+
+	y, ok := new(big.Rat).SetString(b)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidNumber, b)
+	}
+
+	z := new(big.Rat)
+	switch op {
+	case add:
+		z.Add(x, y)
+	case subtract:
+		z.Sub(x, y)
+	case multiply:
+		z.Mul(x, y)
+	case divide:
+		if y.Sign() == 0 {
+			return "", magicmath.ErrDivisionByZero
+		}
+		z.Quo(x, y)
+	}
+	if err := c.checkBits(max(z.Num().BitLen(), z.Denom().BitLen())); err != nil {
+		return "", err
+	}
+
+	return ratString(z), nil
+}
+
+// This function raises a rational to a power, after checking that the result will fit within the limits.
+func (c *Calculator) powerRat(x *big.Rat, exponent int64) (string, error) {
+	if exponent < 0 {
+		if x.Sign() == 0 {
+			return "", magicmath.ErrDivisionByZero
+		}
+		x.Inv(x)
+		if exponent == math.MinInt64 {
+			return "", fmt.Errorf("%w: exponent %d", ErrTooLarge, exponent)
+		}
+		exponent = -exponent
+	}
+
+	bits := max(x.Num().BitLen(), x.Denom().BitLen())
+	if x.Num().CmpAbs(big.NewInt(1)) > 0 || !x.IsInt() {
+		if err := c.checkBits(powerBits(bits, exponent)); err != nil {
+			return "", err
+		}
+	}
+
+	power := big.NewInt(exponent)
+	numerator := new(big.Int).Exp(x.Num(), power, nil)
+	denominator := new(big.Int).Exp(x.Denom(), power, nil)
+
+	return ratString(new(big.Rat).SetFrac(numerator, denominator)), nil
+}
+
+// This function writes a rational as an exact decimal, such as "0.125", when it has one, and as a fraction,
+// such as "1/3", when it doesn't.
+func ratString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	// A fraction in its lowest terms has an exact decimal if its denominator only has the factors two and five,
+	// and it needs as many decimal places as the larger of the two powers.
+	denominator := new(big.Int).Set(r.Denom())
+	twos := denominator.TrailingZeroBits()
+	denominator.Rsh(denominator, twos)
+
+	fives := uint(0)
+	five, remainder := big.NewInt(5), new(big.Int)
+	for {
+		quotient, _ := new(big.Int).QuoRem(denominator, five, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		denominator = quotient
+		fives++
+	}
+	if denominator.Cmp(big.NewInt(1)) != 0 {
+		return r.String()
+	}
+
+	return r.FloatString(int(max(twos, fives)))
+}
+
+// ========================================== Floats ==========================================
+
+func (c *Calculator) calculateFloat(op operation, a, b string, mode Mode) (string, error) {
+	precision := mode.Precision
+	if precision == 0 {
+		precision = min(DefaultPrecision, c.limits.MaxPrecision)
+	}
+	if precision > c.limits.MaxPrecision {
+		return "", fmt.Errorf("%w: precision %d is over the limit of %d bits", ErrTooLarge, precision, c.limits.MaxPrecision)
+	}
+
+	x, _, err := big.ParseFloat(a, 10, precision, mode.Rounding)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidNumber, a)
+	}
+
+	if op == power {
+		exponent, err := parseExponent(b)
+		if err != nil {
+			return "", err
+		}
+		return powerFloat(x, exponent)
+	}
+
+	y, _, err := big.ParseFloat(b, 10, precision, mode.Rounding)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidNumber, b)
+	}
+
+	z := new(big.Float).SetPrec(precision).SetMode(mode.Rounding)
+	switch op {
+	case add:
+		z.Add(x, y)
+	case subtract:
+		z.Sub(x, y)
+	case multiply:
+		z.Mul(x, y)
+	case divide:
+		if y.Sign() == 0 {
+			return "", magicmath.ErrDivisionByZero
+		}
+		z.Quo(x, y)
+	}
+
+	return floatString(z)
+}
+
+// This function raises a float to a power by repeated squaring, rounding after every step.
+// A negative exponent raises the float's inverse instead.
+func powerFloat(x *big.Float, exponent int64) (string, error) {
+	base := new(big.Float).Copy(x)
+	magnitude := uint64(exponent)
+	if exponent < 0 {
+		if x.Sign() == 0 {
+			return "", magicmath.ErrDivisionByZero
+		}
+		base.Quo(new(big.Float).SetPrec(x.Prec()).SetInt64(1), x)
+		// This also works for the most negative int64, whose magnitude doesn't fit in an int64.
+		magnitude = -magnitude
+	}
+
+	result := new(big.Float).SetPrec(x.Prec()).SetMode(x.Mode()).SetInt64(1)
+	for ; magnitude > 0; magnitude >>= 1 {
+		if magnitude&1 == 1 {
+			result.Mul(result, base)
+		}
+		if magnitude > 1 {
+			base.Mul(base, base)
+		}
+		if result.IsInf() {
+			return "", magicmath.ErrOverflow
+		}
+	}
+
+	return floatString(result)
+}
+
+// This function writes a float with the fewest decimal digits that still read back as the same float.
+func floatString(f *big.Float) (string, error) {
+	if f.IsInf() {
+		return "", magicmath.ErrOverflow
+	}
+
+	return f.Text('g', -1), nil
+}
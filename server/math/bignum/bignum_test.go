@@ -0,0 +1,119 @@
+package bignum
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	magicmath "github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+func TestCalculator(t *testing.T) {
+	calculator := New(Limits{})
+	integer := Mode{Kind: Integer}
+	rational := Mode{Kind: Rational}
+
+	var tests = []struct {
+		name      string
+		operation func(a, b string, mode Mode) (string, error)
+		a, b      string
+		mode      Mode
+		want      string
+	}{
+		{"Add", calculator.Add, "9223372036854775807", "1", integer, "9223372036854775808"},
+		{"Subtract", calculator.Subtract, "-9223372036854775808", "1", integer, "-9223372036854775809"},
+		{"Multiply", calculator.Multiply, "123456789012345678901234567890", "10", integer, "1234567890123456789012345678900"},
+		{"Divide", calculator.Divide, "-7", "2", integer, "-3"},
+		{"Power", calculator.Power, "2", "100", integer, "1267650600228229401496703205376"},
+		{"Power", calculator.Power, "-1", "9223372036854775807", integer, "-1"},
+		{"Add", calculator.Add, "0.1", "0.2", rational, "0.3"},
+		{"Add", calculator.Add, "0.1", "0.2", Mode{}, "0.3"},
+		{"Subtract", calculator.Subtract, "1/3", "1/6", rational, "1/6"},
+		{"Multiply", calculator.Multiply, "1.5e-3", "2", rational, "0.003"},
+		{"Divide", calculator.Divide, "1", "3", rational, "1/3"},
+		{"Divide", calculator.Divide, "1", "8", rational, "0.125"},
+		{"Divide", calculator.Divide, "10", "4", rational, "2.5"},
+		{"Power", calculator.Power, "2", "-3", rational, "0.125"},
+		{"Power", calculator.Power, "2/3", "2", rational, "4/9"},
+		{"Divide", calculator.Divide, "1", "3", Mode{Kind: Float, Precision: 24}, "0.33333334"},
+		{"Divide", calculator.Divide, "1", "3", Mode{Kind: Float, Precision: 24, Rounding: big.ToZero}, "0.3333333"},
+		{"Add", calculator.Add, "0.1", "0.2", Mode{Kind: Float, Precision: 53}, "0.30000000000000004"},
+		{"Power", calculator.Power, "1.5", "2", Mode{Kind: Float}, "2.25"},
+		{"Power", calculator.Power, "2", "-2", Mode{Kind: Float}, "0.25"},
+	}
+
+	for _, test := range tests {
+		got, err := test.operation(test.a, test.b, test.mode)
+		if err != nil || got != test.want {
+			t.Errorf("%s(%q, %q, %+v) = %q, %v; want %q", test.name, test.a, test.b, test.mode, got, err, test.want)
+		}
+	}
+}
+
+func TestCalculatorErrors(t *testing.T) {
+	calculator := New(Limits{MaxDigits: 50, MaxPrecision: 128})
+	integer := Mode{Kind: Integer}
+
+	var tests = []struct {
+		name      string
+		operation func(a, b string, mode Mode) (string, error)
+		a, b      string
+		mode      Mode
+		want      error
+	}{
+		{"Divide", calculator.Divide, "1", "0", integer, magicmath.ErrDivisionByZero},
+		{"Divide", calculator.Divide, "1", "0.0", Mode{}, magicmath.ErrDivisionByZero},
+		{"Divide", calculator.Divide, "1", "0", Mode{Kind: Float}, magicmath.ErrDivisionByZero},
+		{"Power", calculator.Power, "0", "-1", integer, magicmath.ErrDivisionByZero},
+		{"Power", calculator.Power, "0", "-1", Mode{}, magicmath.ErrDivisionByZero},
+		{"Power", calculator.Power, "2", "-1", integer, magicmath.ErrNegativeExponent},
+		{"Power", calculator.Power, "2", "0.5", Mode{}, ErrInvalidNumber},
+		{"Add", calculator.Add, "1.5", "1", integer, ErrInvalidNumber},
+		{"Add", calculator.Add, "0x10", "1", Mode{}, ErrInvalidNumber},
+		{"Add", calculator.Add, "Inf", "1", Mode{Kind: Float}, ErrInvalidNumber},
+		{"Add", calculator.Add, "", "1", Mode{}, ErrInvalidNumber},
+		{"Add", calculator.Add, strings.Repeat("9", 51), "1", integer, ErrTooLarge},
+		{"Add", calculator.Add, "1e1000000000", "1", Mode{}, ErrTooLarge},
+		{"Add", calculator.Add, "1", "1", Mode{Kind: Float, Precision: 129}, ErrTooLarge},
+		{"Multiply", calculator.Multiply, strings.Repeat("9", 40), strings.Repeat("9", 40), integer, magicmath.ErrOverflow},
+		{"Power", calculator.Power, "10", "51", integer, magicmath.ErrOverflow},
+		{"Power", calculator.Power, "10", "9223372036854775807", Mode{}, magicmath.ErrOverflow},
+		{"Power", calculator.Power, "1e50", "9223372036854775807", Mode{Kind: Float}, magicmath.ErrOverflow},
+	}
+
+	for _, test := range tests {
+		got, err := test.operation(test.a, test.b, test.mode)
+		if !errors.Is(err, test.want) {
+			t.Errorf("%s(%q, %q, %+v) = %q, %v; want %v", test.name, test.a, test.b, test.mode, got, err, test.want)
+		}
+	}
+
+	if _, err := calculator.Add("1", "1", Mode{Kind: "complex"}); err == nil {
+		t.Errorf("Add with an unknown kind succeeded; want an error")
+	}
+}
+
+func TestParseRounding(t *testing.T) {
+	var tests = []struct {
+		name string
+		want big.RoundingMode
+	}{
+		{"", big.ToNearestEven},
+		{"ToNearestAway", big.ToNearestAway},
+		{"to_zero", big.ToZero},
+		{"AWAY_FROM_ZERO", big.AwayFromZero},
+		{"toNegativeInf", big.ToNegativeInf},
+		{"ToPositiveInf", big.ToPositiveInf},
+	}
+
+	for _, test := range tests {
+		if got, err := ParseRounding(test.name); err != nil || got != test.want {
+			t.Errorf("ParseRounding(%q) = %v, %v; want %v", test.name, got, err, test.want)
+		}
+	}
+
+	if _, err := ParseRounding("up"); err == nil {
+		t.Errorf("ParseRounding(%q) succeeded; want an error", "up")
+	}
+}
@@ -0,0 +1,236 @@
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	magicmath "github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+// ErrMalformed is returned when a decimal string or an unscaled integer can't be parsed.
+var ErrMalformed = errors.New("malformed decimal")
+
+// ErrTooLarge is returned when a decimal, or a scale a caller asks for, is beyond the calculator's limits.
+// Results which are too large fail with the math package's ErrOverflow instead.
+var ErrTooLarge = errors.New("decimal is too large")
+
+// Limits stop callers from making the server work with numbers with millions of digits or zeros.
+type Limits struct {
+	// MaxScale is the largest scale a decimal may have, either way. Defaults to 1000.
+	MaxScale int32
+	// MaxDigits is the largest number of digits in the unscaled integer of a decimal. Defaults to 1000.
+	MaxDigits int
+}
+
+// DefaultLimits are the limits of a calculator created with zero limits.
+var DefaultLimits = Limits{MaxScale: 1000, MaxDigits: 1000}
+
+// Calculator creates decimals and does arithmetic with them, within its limits.
+// It is safe to use from many go routines at once.
+type Calculator struct {
+	limits Limits
+}
+
+// NewCalculator creates a calculator with the given limits. Zero limits take their values from DefaultLimits.
+func NewCalculator(limits Limits) *Calculator {
+	if limits.MaxScale <= 0 {
+		limits.MaxScale = DefaultLimits.MaxScale
+	}
+	if limits.MaxDigits <= 0 {
+		limits.MaxDigits = DefaultLimits.MaxDigits
+	}
+
+	return &Calculator{limits: limits}
+}
+
+// Decimal is an exact decimal number, an unscaled integer times ten to the power of minus the scale.
+// So 12.50 is 1250 with a scale of 2, and 1200 written as 12 with a scale of -2 is 12e2.
+// The zero value is zero, and decimals are never changed once created.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// This matches a canonical decimal string, an optional sign, digits, and optionally a point followed by more digits.
+var canonical = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+
+// Parse reads a canonical decimal string, such as "12", "-0.5" or "12.50". The scale is the number of digits after
+// the point, so "12.50" keeps its trailing zero.
+func (c *Calculator) Parse(text string) (Decimal, error) {
+	if len(text) > c.limits.MaxDigits+2 {
+		return Decimal{}, fmt.Errorf("%w: more than %d digits", ErrTooLarge, c.limits.MaxDigits)
+	}
+	if !canonical.MatchString(text) {
+		return Decimal{}, fmt.Errorf("%w: %q is not a decimal such as \"-12.50\"", ErrMalformed, text)
+	}
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	unscaled, _ := new(big.Int).SetString(whole+fraction, 10)
+
+	return c.New(unscaled, int32(len(fraction)))
+}
+
+// New creates the decimal unscaled × 10^-scale.
+func (c *Calculator) New(unscaled *big.Int, scale int32) (Decimal, error) {
+	d := Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+	if err := c.check(d, ErrTooLarge); err != nil {
+		return Decimal{}, err
+	}
+
+	return d, nil
+}
+
+// ParseUnscaled creates the decimal from an unscaled integer written in decimal, and a scale.
+func (c *Calculator) ParseUnscaled(unscaled string, scale int32) (Decimal, error) {
+	if len(unscaled) > c.limits.MaxDigits+1 {
+		return Decimal{}, fmt.Errorf("%w: more than %d digits", ErrTooLarge, c.limits.MaxDigits)
+	}
+	integer, ok := new(big.Int).SetString(unscaled, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q is not an integer", ErrMalformed, unscaled)
+	}
+
+	return c.New(integer, scale)
+}
+
+// Unscaled returns the decimal's unscaled integer.
+func (d Decimal) Unscaled() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(d.unscaled)
+}
+
+// Scale returns the decimal's scale, the number of digits after the point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// String writes the decimal with exactly Scale digits after the point, and never with an exponent.
+func (d Decimal) String() string {
+	unscaled := d.Unscaled()
+	digits := new(big.Int).Abs(unscaled).String()
+
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign = "-"
+	}
+
+	if d.scale <= 0 {
+		if unscaled.Sign() == 0 {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", int(-d.scale))
+	}
+
+	if len(digits) <= int(d.scale) {
+		digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(d.scale)
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Add returns d plus e, exactly. Its scale is the larger of their scales.
+func (c *Calculator) Add(d, e Decimal) (Decimal, error) {
+	x, y, scale := align(d, e)
+	return c.result(Decimal{unscaled: x.Add(x, y), scale: scale})
+}
+
+// Sub returns d minus e, exactly. Its scale is the larger of their scales.
+func (c *Calculator) Sub(d, e Decimal) (Decimal, error) {
+	x, y, scale := align(d, e)
+	return c.result(Decimal{unscaled: x.Sub(x, y), scale: scale})
+}
+
+// Mul returns d times e, exactly. Its scale is the sum of their scales, so it fails if that is beyond the limits.
+func (c *Calculator) Mul(d, e Decimal) (Decimal, error) {
+	return c.result(Decimal{unscaled: new(big.Int).Mul(d.Unscaled(), e.Unscaled()), scale: d.scale + e.scale})
+}
+
+// Quo returns d divided by e, rounded to the given scale with the given rounding mode.
+func (c *Calculator) Quo(d, e Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if e.Unscaled().Sign() == 0 {
+		return Decimal{}, magicmath.ErrDivisionByZero
+	}
+	if err := c.checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	// d / e at the scale is (d.unscaled × 10^-d.scale) / (e.unscaled × 10^-e.scale) × 10^scale, so the numerator
+	// is shifted by scale - d.scale + e.scale digits. A negative shift multiplies the denominator instead.
+	numerator, denominator := d.Unscaled(), e.Unscaled()
+	if shift := int64(scale) - int64(d.scale) + int64(e.scale); shift >= 0 {
+		numerator.Mul(numerator, pow10(shift))
+	} else {
+		denominator.Mul(denominator, pow10(-shift))
+	}
+
+	return c.result(Decimal{unscaled: roundQuo(numerator, denominator, mode), scale: scale})
+}
+
+// Round returns d with the given scale, rounded with the given rounding mode if it has fewer digits after the point.
+func (c *Calculator) Round(d Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if err := c.checkScale(scale); err != nil {
+		return Decimal{}, err
+	}
+
+	unscaled := d.Unscaled()
+	if scale >= d.scale {
+		return c.result(Decimal{unscaled: unscaled.Mul(unscaled, pow10(int64(scale)-int64(d.scale))), scale: scale})
+	}
+
+	return c.result(Decimal{unscaled: roundQuo(unscaled, pow10(int64(d.scale)-int64(scale)), mode), scale: scale})
+}
+
+// This function checks that a scale a caller asked for is within the limits.
+func (c *Calculator) checkScale(scale int32) error {
+	if scale > c.limits.MaxScale || scale < -c.limits.MaxScale {
+		return fmt.Errorf("%w: scale %d is beyond ±%d", ErrTooLarge, scale, c.limits.MaxScale)
+	}
+
+	return nil
+}
+
+// This function returns a result, or an ErrOverflow if its scale or its number of digits is beyond the limits.
+func (c *Calculator) result(d Decimal) (Decimal, error) {
+	if err := c.check(d, magicmath.ErrOverflow); err != nil {
+		return Decimal{}, err
+	}
+
+	return d, nil
+}
+
+// This function checks that a decimal's scale and its number of digits are within the limits, and returns the kind
+// of error given if they aren't.
+func (c *Calculator) check(d Decimal, kind error) error {
+	if d.scale > c.limits.MaxScale || d.scale < -c.limits.MaxScale {
+		return fmt.Errorf("%w: scale %d is beyond ±%d", kind, d.scale, c.limits.MaxScale)
+	}
+	if digits := len(new(big.Int).Abs(d.Unscaled()).String()); digits > c.limits.MaxDigits {
+		return fmt.Errorf("%w: more than %d digits", kind, c.limits.MaxDigits)
+	}
+
+	return nil
+}
+
+// This function returns the unscaled integers of two decimals at the same scale, the larger of the two.
+func align(d, e Decimal) (*big.Int, *big.Int, int32) {
+	x, y := d.Unscaled(), e.Unscaled()
+	if d.scale < e.scale {
+		x.Mul(x, pow10(int64(e.scale-d.scale)))
+		return x, y, e.scale
+	}
+	y.Mul(y, pow10(int64(d.scale-e.scale)))
+
+	return x, y, d.scale
+}
+
+// This function returns ten to the power n.
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
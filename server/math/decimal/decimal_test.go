@@ -0,0 +1,255 @@
+package decimal
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	magicmath "github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+// The calculator the tests use, with the default limits.
+var calculator = NewCalculator(Limits{})
+
+// This function parses a decimal, and fails the test if it can't.
+func mustParse(t *testing.T, text string) Decimal {
+	t.Helper()
+
+	d, err := calculator.Parse(text)
+	if err != nil {
+		t.Fatalf("Parse(%q) returned error: %v", text, err)
+	}
+
+	return d
+}
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		text     string
+		unscaled string
+		scale    int32
+		want     string
+	}{
+		{"12", "12", 0, "12"},
+		{"12.50", "1250", 2, "12.50"},
+		{"-0.05", "-5", 2, "-0.05"},
+		{"+7.0", "70", 1, "7.0"},
+		{"0.000", "0", 3, "0.000"},
+	}
+
+	for _, test := range tests {
+		d, err := calculator.Parse(test.text)
+		if err != nil || d.Unscaled().String() != test.unscaled || d.Scale() != test.scale || d.String() != test.want {
+			t.Errorf("Parse(%q) = %s × 10^-%d (%q), %v; want %s × 10^-%d (%q)", test.text,
+				d.Unscaled(), d.Scale(), d.String(), err, test.unscaled, test.scale, test.want)
+		}
+	}
+
+	for _, text := range []string{"", "1.", ".5", "1e3", "0x10", "1,000", "--1", " 1"} {
+		if _, err := calculator.Parse(text); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Parse(%q) returned error %v; want %v", text, err, ErrMalformed)
+		}
+	}
+	maxDigits := DefaultLimits.MaxDigits
+	if _, err := calculator.Parse(strings.Repeat("9", maxDigits+1)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Parse(%d digits) returned error %v; want %v", maxDigits+1, err, ErrTooLarge)
+	}
+}
+
+func TestParseUnscaled(t *testing.T) {
+	var tests = []struct {
+		unscaled string
+		scale    int32
+		want     string
+	}{
+		{"1250", 2, "12.50"},
+		{"-5", 3, "-0.005"},
+		{"12", -2, "1200"},
+		{"0", -2, "0"},
+	}
+
+	for _, test := range tests {
+		if d, err := calculator.ParseUnscaled(test.unscaled, test.scale); err != nil || d.String() != test.want {
+			t.Errorf("ParseUnscaled(%q, %d) = %q, %v; want %q", test.unscaled, test.scale, d.String(), err, test.want)
+		}
+	}
+
+	if _, err := calculator.ParseUnscaled("1.5", 0); !errors.Is(err, ErrMalformed) {
+		t.Errorf("ParseUnscaled(%q, 0) returned error %v; want %v", "1.5", err, ErrMalformed)
+	}
+	maxScale := DefaultLimits.MaxScale
+	if _, err := calculator.ParseUnscaled("1", maxScale+1); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ParseUnscaled(%q, %d) returned error %v; want %v", "1", maxScale+1, err, ErrTooLarge)
+	}
+}
+
+func TestExactArithmetic(t *testing.T) {
+	var tests = []struct {
+		name      string
+		operation func(c *Calculator, d, e Decimal) (Decimal, error)
+		a, b      string
+		want      string
+	}{
+		{"Add", (*Calculator).Add, "0.1", "0.2", "0.3"},
+		{"Add", (*Calculator).Add, "12.50", "0.005", "12.505"},
+		{"Add", (*Calculator).Add, "-1", "1", "0"},
+		{"Sub", (*Calculator).Sub, "0.3", "0.1", "0.2"},
+		{"Sub", (*Calculator).Sub, "1", "1.25", "-0.25"},
+		{"Mul", (*Calculator).Mul, "19.99", "3", "59.97"},
+		{"Mul", (*Calculator).Mul, "1.10", "1.10", "1.2100"},
+		{"Mul", (*Calculator).Mul, "-0.5", "0.5", "-0.25"},
+	}
+
+	for _, test := range tests {
+		got, err := test.operation(calculator, mustParse(t, test.a), mustParse(t, test.b))
+		if err != nil || got.String() != test.want {
+			t.Errorf("%s(%s, %s) = %s, %v; want %s", test.name, test.a, test.b, got, err, test.want)
+		}
+	}
+
+	var zero Decimal
+	if got, err := calculator.Add(zero, mustParse(t, "1.5")); err != nil || got.String() != "1.5" {
+		t.Errorf("Add(Decimal{}, 1.5) = %s, %v; want 1.5", got, err)
+	}
+}
+
+func TestRound(t *testing.T) {
+	var tests = []struct {
+		value string
+		scale int32
+		mode  RoundingMode
+		want  string
+	}{
+		{"2.5", 0, HalfEven, "2"},
+		{"3.5", 0, HalfEven, "4"},
+		{"-2.5", 0, HalfEven, "-2"},
+		{"2.51", 0, HalfEven, "3"},
+		{"2.5", 0, HalfUp, "3"},
+		{"-2.5", 0, HalfUp, "-3"},
+		{"2.49", 0, HalfUp, "2"},
+		{"2.9", 0, Down, "2"},
+		{"-2.9", 0, Down, "-2"},
+		{"2.1", 0, Up, "3"},
+		{"-2.1", 0, Up, "-3"},
+		{"2.0", 0, Up, "2"},
+		{"1.005", 2, HalfUp, "1.01"},
+		{"1.5", 3, Down, "1.500"},
+		{"1250", -2, HalfEven, "1200"},
+	}
+
+	for _, test := range tests {
+		got, err := calculator.Round(mustParse(t, test.value), test.scale, test.mode)
+		if err != nil || got.String() != test.want {
+			t.Errorf("Round(%s, %d, %v) = %s, %v; want %s", test.value, test.scale, test.mode, got, err, test.want)
+		}
+	}
+}
+
+func TestQuo(t *testing.T) {
+	var tests = []struct {
+		a, b  string
+		scale int32
+		mode  RoundingMode
+		want  string
+	}{
+		{"1", "3", 4, HalfEven, "0.3333"},
+		{"2", "3", 4, HalfEven, "0.6667"},
+		{"2", "3", 4, Down, "0.6666"},
+		{"-2", "3", 2, HalfUp, "-0.67"},
+		{"10.00", "4", 1, HalfEven, "2.5"},
+		{"10.00", "4", 0, HalfEven, "2"},
+		{"10", "0.25", 0, HalfEven, "40"},
+		{"100", "3", -1, Up, "40"},
+		{"0.1", "-3", 3, Up, "-0.034"},
+	}
+
+	for _, test := range tests {
+		got, err := calculator.Quo(mustParse(t, test.a), mustParse(t, test.b), test.scale, test.mode)
+		if err != nil || got.String() != test.want {
+			t.Errorf("Quo(%s, %s, %d, %v) = %s, %v; want %s", test.a, test.b, test.scale, test.mode, got, err, test.want)
+		}
+	}
+
+	_, err := calculator.Quo(mustParse(t, "1"), mustParse(t, "0.00"), 2, HalfEven)
+	if !errors.Is(err, magicmath.ErrDivisionByZero) {
+		t.Errorf("Quo(1, 0.00) returned error %v; want %v", err, magicmath.ErrDivisionByZero)
+	}
+	maxScale := DefaultLimits.MaxScale
+	if _, err := calculator.Quo(mustParse(t, "1"), mustParse(t, "3"), maxScale+1, HalfEven); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Quo(1, 3, %d) returned error %v; want %v", maxScale+1, err, ErrTooLarge)
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	var tests = []struct {
+		name string
+		want RoundingMode
+	}{
+		{"", HalfEven},
+		{"half_even", HalfEven},
+		{"HALF_UP", HalfUp},
+		{"half-up", HalfUp},
+		{"down", Down},
+		{"Up", Up},
+	}
+
+	for _, test := range tests {
+		if got, err := ParseRoundingMode(test.name); err != nil || got != test.want {
+			t.Errorf("ParseRoundingMode(%q) = %v, %v; want %v", test.name, got, err, test.want)
+		}
+	}
+
+	if _, err := ParseRoundingMode("ceiling"); err == nil {
+		t.Errorf("ParseRoundingMode(%q) succeeded; want an error", "ceiling")
+	}
+}
+
+func TestNewCopiesUnscaled(t *testing.T) {
+	unscaled := big.NewInt(125)
+	d, err := calculator.New(unscaled, 2)
+	if err != nil {
+		t.Fatalf("New(125, 2) returned error: %v", err)
+	}
+
+	unscaled.SetInt64(999)
+	if d.String() != "1.25" {
+		t.Errorf("New(125, 2) changed to %s when its integer was changed; want 1.25", d)
+	}
+}
+
+func TestResultsWithinLimits(t *testing.T) {
+	small := NewCalculator(Limits{MaxScale: 4, MaxDigits: 6})
+	overflow := magicmath.ErrOverflow
+	parse := func(text string) Decimal {
+		d, err := small.Parse(text)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", text, err)
+		}
+		return d
+	}
+
+	var tests = []struct {
+		name    string
+		result  func() (Decimal, error)
+		want    string
+		wantErr error
+	}{
+		{"Mul(0.01, 0.01)", func() (Decimal, error) { return small.Mul(parse("0.01"), parse("0.01")) }, "0.0001", nil},
+		{"Mul(0.001, 0.01)", func() (Decimal, error) { return small.Mul(parse("0.001"), parse("0.01")) }, "", overflow},
+		{"Mul(999, 1000)", func() (Decimal, error) { return small.Mul(parse("999"), parse("1000")) }, "999000", nil},
+		{"Mul(9999, 1000)", func() (Decimal, error) { return small.Mul(parse("9999"), parse("1000")) }, "", overflow},
+		{"Add(999999, 1)", func() (Decimal, error) { return small.Add(parse("999999"), parse("1")) }, "", overflow},
+		{"Round(999.99, 4)", func() (Decimal, error) { return small.Round(parse("999.99"), 4, HalfEven) }, "", overflow},
+		{"Round(1, 5)", func() (Decimal, error) { return small.Round(parse("1"), 5, HalfEven) }, "", ErrTooLarge},
+		{"Parse(0.12345)", func() (Decimal, error) { return small.Parse("0.12345") }, "", ErrTooLarge},
+		{"Parse(1234567)", func() (Decimal, error) { return small.Parse("1234567") }, "", ErrTooLarge},
+	}
+
+	for _, test := range tests {
+		got, err := test.result()
+		if !errors.Is(err, test.wantErr) || (err == nil && got.String() != test.want) {
+			t.Errorf("%s = %s, %v; want %q, error %v", test.name, got, err, test.want, test.wantErr)
+		}
+	}
+}
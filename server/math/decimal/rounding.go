@@ -0,0 +1,88 @@
+package decimal
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RoundingMode says which way to round a result which has more digits than its scale allows.
+type RoundingMode int
+
+// These are the rounding modes. The zero value is HalfEven, which is what banks use since it doesn't drift up.
+const (
+	// HalfEven rounds to the nearest decimal, and ties to the one whose last digit is even, so 2.5 rounds to 2.
+	HalfEven RoundingMode = iota
+	// HalfUp rounds to the nearest decimal, and ties away from zero, so 2.5 rounds to 3 and -2.5 to -3.
+	HalfUp
+	// Down rounds towards zero, so it drops the extra digits.
+	Down
+	// Up rounds away from zero.
+	Up
+)
+
+// These are the names of the rounding modes, as used by String and ParseRoundingMode.
+var roundingModeNames = map[RoundingMode]string{
+	HalfEven: "half_even",
+	HalfUp:   "half_up",
+	Down:     "down",
+	Up:       "up",
+}
+
+func (m RoundingMode) String() string {
+	if name, ok := roundingModeNames[m]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("RoundingMode(%d)", int(m))
+}
+
+// ParseRoundingMode parses the name of a rounding mode: "half_even", "half_up", "down" or "up".
+// The name is not case sensitive, and may use a dash instead of the underscore. An empty name means HalfEven.
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	if name == "" {
+		return HalfEven, nil
+	}
+
+	normalized := strings.ReplaceAll(strings.ToLower(name), "-", "_")
+	for mode, modeName := range roundingModeNames {
+		if modeName == normalized {
+			return mode, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown rounding mode %q, use half_even, half_up, down or up", name)
+}
+
+// This function divides n by d, and rounds the quotient to an integer with the rounding mode.
+func roundQuo(n, d *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(n, d, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	// Work out whether the remainder is below, at, or above half of the divisor.
+	half := new(big.Int).Abs(remainder)
+	half.Lsh(half, 1)
+	comparison := half.CmpAbs(d)
+
+	awayFromZero := false
+	switch mode {
+	case HalfEven:
+		awayFromZero = comparison > 0 || (comparison == 0 && quotient.Bit(0) == 1)
+	case HalfUp:
+		awayFromZero = comparison >= 0
+	case Up:
+		awayFromZero = true
+	}
+
+	if awayFromZero {
+		if (n.Sign() < 0) != (d.Sign() < 0) {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient
+}
@@ -0,0 +1,170 @@
+package expression
+
+import (
+	"fmt"
+	gomath "math"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+// Node is a node of an expression's syntax tree.
+type Node interface {
+	// Evaluate works out the value of the node, with the given values for its variables.
+	Evaluate(variables map[string]float64) (float64, error)
+	// Position is the byte offset in the expression where the node starts, or where its operator is.
+	Position() int
+}
+
+// EvaluationError is returned when an expression parses, but has no value, for example because it divides by zero
+// or uses a variable which has no value. It says where in the expression the problem is.
+type EvaluationError struct {
+	// Position is the byte offset of the problem in the expression, starting from zero.
+	Position int
+	// Err is what went wrong, often one of the math package's errors such as math.ErrDivisionByZero.
+	Err error
+}
+
+func (e *EvaluationError) Error() string {
+	return fmt.Sprintf("evaluation error at position %d: %v", e.Position, e.Err)
+}
+
+func (e *EvaluationError) Unwrap() error {
+	return e.Err
+}
+
+// Evaluate parses an expression and works out its value, with the given values for its variables.
+// It returns a *SyntaxError if the expression doesn't parse, and an *EvaluationError if it has no value.
+func Evaluate(source string, variables map[string]float64) (float64, error) {
+	node, err := Parse(source)
+	if err != nil {
+		return 0, err
+	}
+
+	return node.Evaluate(variables)
+}
+
+// A number written in the expression.
+type numberNode struct {
+	value    float64
+	position int
+}
+
+func (n *numberNode) Evaluate(map[string]float64) (float64, error) {
+	return n.value, nil
+}
+
+func (n *numberNode) Position() int {
+	return n.position
+}
+
+// A variable, whose value is given when the expression is evaluated.
+type variableNode struct {
+	name     string
+	position int
+}
+
+func (n *variableNode) Evaluate(variables map[string]float64) (float64, error) {
+	value, ok := variables[n.name]
+	if !ok {
+		return 0, &EvaluationError{Position: n.position, Err: fmt.Errorf("variable %q has no value", n.name)}
+	}
+	if gomath.IsNaN(value) {
+		return 0, &EvaluationError{Position: n.position, Err: math.ErrUndefined}
+	}
+
+	return value, nil
+}
+
+func (n *variableNode) Position() int {
+	return n.position
+}
+
+// A minus sign in front of an operand.
+type negateNode struct {
+	operand  Node
+	position int
+}
+
+func (n *negateNode) Evaluate(variables map[string]float64) (float64, error) {
+	value, err := n.operand.Evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	return -value, nil
+}
+
+func (n *negateNode) Position() int {
+	return n.position
+}
+
+// Two operands with an operator between them, one of + - * or /.
+type binaryNode struct {
+	operator    byte
+	left, right Node
+	position    int
+}
+
+func (n *binaryNode) Evaluate(variables map[string]float64) (float64, error) {
+	left, err := n.left.Evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.Evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	var result float64
+	switch n.operator {
+	case '+':
+		result, err = math.LocalCheckedAdd(left, right)
+	case '-':
+		result, err = math.LocalCheckedSubtract(left, right)
+	case '*':
+		result, err = math.LocalMultiply(left, right)
+	case '/':
+		result, err = math.LocalDivide(left, right)
+	}
+	if err != nil {
+		return 0, &EvaluationError{Position: n.position, Err: err}
+	}
+
+	return result, nil
+}
+
+func (n *binaryNode) Position() int {
+	return n.position
+}
+
+// A call to min or max.
+type callNode struct {
+	function  string
+	arguments []Node
+	position  int
+}
+
+func (n *callNode) Evaluate(variables map[string]float64) (float64, error) {
+	result, err := n.arguments[0].Evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, argument := range n.arguments[1:] {
+		value, err := argument.Evaluate(variables)
+		if err != nil {
+			return 0, err
+		}
+		if n.function == "min" {
+			result = gomath.Min(result, value)
+		} else {
+			result = gomath.Max(result, value)
+		}
+	}
+
+	return result, nil
+}
+
+func (n *callNode) Position() int {
+	return n.position
+}
@@ -0,0 +1,88 @@
+package expression
+
+import (
+	"errors"
+	gomath "math"
+	"testing"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+func TestEvaluate(t *testing.T) {
+	variables := map[string]float64{"x": 3, "rate": 0.5, "big": gomath.MaxFloat64}
+
+	var tests = []struct {
+		source string
+		want   float64
+	}{
+		{"1", 1},
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"12 / 4 / 3", 1},
+		{"-x", -3},
+		{"--x", 3},
+		{"+x * -2", -6},
+		{"2 * -(x + 1)", -8},
+		{"x * rate", 1.5},
+		{"1.5e2 + .5", 150.5},
+		{"min(4, x, 7)", 3},
+		{"max(4, x, 7)", 7},
+		{"max(min(1, 2), -x)", 1},
+		{"min(x)", 3},
+		{"  1\t+\n2 ", 3},
+	}
+
+	for _, test := range tests {
+		got, err := Evaluate(test.source, variables)
+		if err != nil || got != test.want {
+			t.Errorf("Evaluate(%q) = %v, %v; want %v", test.source, got, err, test.want)
+		}
+	}
+}
+
+func TestEvaluationErrors(t *testing.T) {
+	variables := map[string]float64{"big": gomath.MaxFloat64, "nan": gomath.NaN()}
+
+	var tests = []struct {
+		source       string
+		want         error
+		wantPosition int
+	}{
+		{"1 / 0", math.ErrDivisionByZero, 2},
+		{"1 + 1 / (2 - 2)", math.ErrDivisionByZero, 6},
+		{"big * 2", math.ErrOverflow, 4},
+		{"big + big", math.ErrOverflow, 4},
+		{"-big - big", math.ErrOverflow, 5},
+		{"nan + 1", math.ErrUndefined, 0},
+		{"1 + y", nil, 4},
+	}
+
+	for _, test := range tests {
+		_, err := Evaluate(test.source, variables)
+
+		var evaluationError *EvaluationError
+		if !errors.As(err, &evaluationError) {
+			t.Errorf("Evaluate(%q) returned error %v; want an EvaluationError", test.source, err)
+			continue
+		}
+		if evaluationError.Position != test.wantPosition || (test.want != nil && !errors.Is(err, test.want)) {
+			t.Errorf("Evaluate(%q) returned error %v; want %v at position %d",
+				test.source, err, test.want, test.wantPosition)
+		}
+	}
+}
+
+func TestParseOnceEvaluateMany(t *testing.T) {
+	node, err := Parse("price * (1 + tax)")
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	for _, price := range []float64{10, 20, 40} {
+		got, err := node.Evaluate(map[string]float64{"price": price, "tax": 0.25})
+		if want := price * 1.25; err != nil || got != want {
+			t.Errorf("Evaluate(price=%v) = %v, %v; want %v", price, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package expression
+
+import (
+	"fmt"
+)
+
+// MaxLength is the longest expression, in bytes, that Parse accepts.
+const MaxLength = 4096
+
+// MaxDepth is how deeply Parse lets parentheses, function calls and signs nest, so that a caller can't exhaust the
+// server's stack with an expression such as "((((((((1))))))))".
+const MaxDepth = 64
+
+// SyntaxError is returned when an expression can't be parsed. It says where in the expression the problem is.
+type SyntaxError struct {
+	// Position is the byte offset of the problem in the expression, starting from zero.
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Position, e.Message)
+}
+
+// These are the functions an expression may call, and the fewest arguments each takes.
+var functions = map[string]int{
+	"min": 1,
+	"max": 1,
+}
+
+// Parse turns an expression into its syntax tree, which can then be evaluated any number of times.
+//
+// The grammar, from the lowest precedence to the highest, is:
+//
+//	expression = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/") unary }
+//	unary      = ("+" | "-") unary | primary
+//	primary    = number | name | name "(" expression { "," expression } ")" | "(" expression ")"
+//
+// Numbers are decimal, with an optional point and exponent, such as "12", "0.5" or "1e-3". A name on its own is a
+// variable, and the functions are min and max, which take one or more arguments.
+func Parse(source string) (Node, error) {
+	if len(source) > MaxLength {
+		return nil, &SyntaxError{Position: MaxLength, Message: fmt.Sprintf("expression is longer than %d bytes", MaxLength)}
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	node, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != endToken {
+		return nil, &SyntaxError{Position: next.position, Message: fmt.Sprintf("unexpected %v", next)}
+	}
+
+	return node, nil
+}
+
+// The parser is a recursive descent parser, with one function for each rule of the grammar.
+type parser struct {
+	tokens []token
+	next   int
+	depth  int
+}
+
+// This function returns the next token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// This function consumes the next token and returns it.
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != endToken {
+		p.next++
+	}
+
+	return t
+}
+
+// This function consumes the next token if it is the given operator.
+func (p *parser) accept(operator string) bool {
+	if t := p.peek(); t.kind == operatorToken && t.text == operator {
+		p.next++
+		return true
+	}
+
+	return false
+}
+
+// This function consumes the next token, which must be the given operator.
+func (p *parser) expect(operator string) error {
+	if !p.accept(operator) {
+		next := p.peek()
+		return &SyntaxError{Position: next.position, Message: fmt.Sprintf("expected %q but found %v", operator, next)}
+	}
+
+	return nil
+}
+
+// This function is called on the way into a nested rule, and fails once the nesting is too deep.
+// Every call must be matched by a call to leave.
+func (p *parser) enter(position int) error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return &SyntaxError{Position: position, Message: fmt.Sprintf("expression is nested more than %d deep", MaxDepth)}
+	}
+
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) parseExpression() (Node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator := p.peek()
+		if !p.accept("+") && !p.accept("-") {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator.text[0], left: left, right: right, position: operator.position}
+	}
+}
+
+func (p *parser) parseTerm() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator := p.peek()
+		if !p.accept("*") && !p.accept("/") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator.text[0], left: left, right: right, position: operator.position}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	operator := p.peek()
+	if !p.accept("+") && !p.accept("-") {
+		return p.parsePrimary()
+	}
+
+	if err := p.enter(operator.position); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if operator.text == "+" {
+		return operand, nil
+	}
+
+	return &negateNode{operand: operand, position: operator.position}, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.advance()
+
+	switch {
+	case t.kind == numberToken:
+		return &numberNode{value: t.number, position: t.position}, nil
+
+	case t.kind == nameToken && p.peek().text == "(":
+		return p.parseCall(t)
+
+	case t.kind == nameToken:
+		return &variableNode{name: t.text, position: t.position}, nil
+
+	case t.kind == operatorToken && t.text == "(":
+		if err := p.enter(t.position); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+
+	default:
+		return nil, &SyntaxError{Position: t.position, Message: fmt.Sprintf("expected a number, a name or \"(\" but found %v", t)}
+	}
+}
+
+// This function parses the arguments of a call to the named function, whose opening parenthesis is the next token.
+func (p *parser) parseCall(name token) (Node, error) {
+	minArguments, ok := functions[name.text]
+	if !ok {
+		return nil, &SyntaxError{Position: name.position, Message: fmt.Sprintf("unknown function %q", name.text)}
+	}
+
+	if err := p.enter(name.position); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	p.advance()
+	call := &callNode{function: name.text, position: name.position}
+	if !p.accept(")") {
+		for {
+			argument, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			call.arguments = append(call.arguments, argument)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(call.arguments) < minArguments {
+		return nil, &SyntaxError{Position: name.position,
+			Message: fmt.Sprintf("%s needs at least %d argument, it has %d", name.text, minArguments, len(call.arguments))}
+	}
+
+	return call, nil
+}
@@ -0,0 +1,57 @@
+package expression
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSyntaxErrors(t *testing.T) {
+	var tests = []struct {
+		source       string
+		wantPosition int
+		wantMessage  string
+	}{
+		{"", 0, "expected a number"},
+		{"1 +", 3, "expected a number"},
+		{"1 + * 2", 4, `found "*"`},
+		{"(1 + 2", 6, `expected ")"`},
+		{"1 + 2)", 5, `unexpected ")"`},
+		{"1 2", 2, `unexpected "2"`},
+		{"2 $ 3", 2, "unexpected character '$'"},
+		{"1.2.3", 0, `invalid number "1.2.3"`},
+		{"1e999", 0, "too large"},
+		{"sqrt(4)", 0, `unknown function "sqrt"`},
+		{"min()", 0, "at least 1 argument"},
+		{"max(1,)", 6, "expected a number"},
+		{"max(1 2)", 6, `expected ")"`},
+		{strings.Repeat("(", MaxDepth+1) + "1" + strings.Repeat(")", MaxDepth+1), MaxDepth, "nested more than"},
+		{strings.Repeat("-", MaxDepth+1) + "1", MaxDepth, "nested more than"},
+		{strings.Repeat("1+", MaxLength/2) + "1", MaxLength, "longer than"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.source)
+
+		var syntaxError *SyntaxError
+		if !errors.As(err, &syntaxError) {
+			t.Errorf("Parse(%.20q) returned error %v; want a SyntaxError", test.source, err)
+			continue
+		}
+		if syntaxError.Position != test.wantPosition || !strings.Contains(syntaxError.Message, test.wantMessage) {
+			t.Errorf("Parse(%.20q) returned error %v; want %q at position %d",
+				test.source, err, test.wantMessage, test.wantPosition)
+		}
+	}
+}
+
+func TestLimitsAllowTheLargestExpressions(t *testing.T) {
+	for _, source := range []string{
+		strings.Repeat("(", MaxDepth) + "1" + strings.Repeat(")", MaxDepth),
+		strings.Repeat("1+", MaxLength/2-1) + "1",
+	} {
+		if _, err := Parse(source); err != nil {
+			t.Errorf("Parse(%.20q) returned error: %v", source, err)
+		}
+	}
+}
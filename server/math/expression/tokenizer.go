@@ -0,0 +1,100 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// These are the kinds of tokens an expression is made of.
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	numberToken
+	nameToken
+	operatorToken
+)
+
+// A token is one number, name, operator or parenthesis of an expression, along with where it starts.
+type token struct {
+	kind     tokenKind
+	text     string
+	number   float64
+	position int
+}
+
+func (t token) String() string {
+	if t.kind == endToken {
+		return "end of expression"
+	}
+
+	return strconv.Quote(t.text)
+}
+
+// This function splits an expression into tokens, ending with an endToken.
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+
+	for position := 0; position < len(source); {
+		c := source[position]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			position++
+		case isDigit(c) || c == '.':
+			end := scanNumber(source, position)
+			number, err := strconv.ParseFloat(source[position:end], 64)
+			if errors.Is(err, strconv.ErrRange) {
+				return nil, &SyntaxError{Position: position, Message: fmt.Sprintf("number %q is too large", source[position:end])}
+			} else if err != nil {
+				return nil, &SyntaxError{Position: position, Message: fmt.Sprintf("invalid number %q", source[position:end])}
+			}
+			tokens = append(tokens, token{kind: numberToken, text: source[position:end], number: number, position: position})
+			position = end
+		case isLetter(c):
+			end := position + 1
+			for end < len(source) && (isLetter(source[end]) || isDigit(source[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: nameToken, text: source[position:end], position: position})
+			position = end
+		case c == '+' || c == '-' || c == '*' || c == '/' || c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, token{kind: operatorToken, text: string(c), position: position})
+			position++
+		default:
+			return nil, &SyntaxError{Position: position, Message: fmt.Sprintf("unexpected character %q", rune(c))}
+		}
+	}
+
+	return append(tokens, token{kind: endToken, position: len(source)}), nil
+}
+
+// This function returns where the number starting at the position ends: digits with an optional decimal point,
+// followed by an optional exponent such as "e-5".
+func scanNumber(source string, position int) int {
+	end := position
+	for end < len(source) && (isDigit(source[end]) || source[end] == '.') {
+		end++
+	}
+
+	if end < len(source) && (source[end] == 'e' || source[end] == 'E') {
+		exponent := end + 1
+		if exponent < len(source) && (source[exponent] == '+' || source[exponent] == '-') {
+			exponent++
+		}
+		if exponent < len(source) && isDigit(source[exponent]) {
+			for end = exponent; end < len(source) && isDigit(source[end]); end++ {
+			}
+		}
+	}
+
+	return end
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_'
+}
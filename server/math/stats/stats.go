@@ -0,0 +1,171 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	magicmath "github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+// DefaultExactLimit is how many values an Accumulator keeps, to work out exact quantiles, when no limit is given.
+const DefaultExactLimit = 10000
+
+// ErrEmpty is returned when asking for the statistics of no values.
+var ErrEmpty = errors.New("no values")
+
+// ErrNotFinite is returned when adding a value which is NaN or infinite, as it would make every statistic meaningless.
+var ErrNotFinite = errors.New("value is not a finite number")
+
+// Quantile is the value below which the fraction Q of the values fall.
+type Quantile struct {
+	Q     float64
+	Value float64
+}
+
+// Summary holds the statistics of a dataset.
+type Summary struct {
+	Count int64
+	Sum   float64
+	Mean  float64
+	// Variance is the sample variance, which divides by Count - 1. It is zero for a single value.
+	Variance float64
+	// StdDev is the square root of Variance.
+	StdDev float64
+	Min    float64
+	Max    float64
+	Median float64
+	// Quantiles are the quantiles asked for, in the same order.
+	Quantiles []Quantile
+	// Exact is true if Median and Quantiles were worked out from every value, rather than estimated by a t-digest.
+	Exact bool
+}
+
+// Accumulator works out the statistics of a dataset one value at a time, so that a dataset never has to be held in
+// memory all at once. The mean and variance use Welford's online algorithm, which doesn't lose precision the way
+// summing the squares does. The first values are kept to work out exact quantiles, and once there are too many of
+// them the quantiles are estimated by a t-digest instead.
+type Accumulator struct {
+	exactLimit int
+
+	count    int64
+	sum      float64
+	mean     float64
+	squares  float64
+	min, max float64
+
+	// values holds every value while there are no more than exactLimit of them, after which it is nil and
+	// digest holds the sketch of the values instead.
+	values []float64
+	digest *TDigest
+}
+
+// NewAccumulator creates an empty accumulator which keeps up to exactLimit values for exact quantiles.
+// A limit of zero means DefaultExactLimit, and a negative limit always estimates the quantiles.
+func NewAccumulator(exactLimit int) *Accumulator {
+	if exactLimit == 0 {
+		exactLimit = DefaultExactLimit
+	}
+
+	a := &Accumulator{exactLimit: max(exactLimit, 0), min: math.Inf(1), max: math.Inf(-1)}
+	if exactLimit < 0 {
+		a.digest = NewTDigest(DefaultCompression)
+	}
+
+	return a
+}
+
+// Add adds a value to the dataset.
+func (a *Accumulator) Add(value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("%w: %v", ErrNotFinite, value)
+	}
+
+	a.count++
+	a.sum += value
+	delta := value - a.mean
+	a.mean += delta / float64(a.count)
+	a.squares += delta * (value - a.mean)
+	a.min = math.Min(a.min, value)
+	a.max = math.Max(a.max, value)
+
+	if a.digest != nil {
+		a.digest.Add(value)
+		return nil
+	}
+
+	a.values = append(a.values, value)
+	if len(a.values) > a.exactLimit {
+		// Too many values to keep, so move them into a sketch.
+		a.digest = NewTDigest(DefaultCompression)
+		for _, kept := range a.values {
+			a.digest.Add(kept)
+		}
+		a.values = nil
+	}
+
+	return nil
+}
+
+// Summarize returns the statistics of the values added so far, with the given quantiles, which must be between
+// 0 and 1. It fails with ErrEmpty if no values have been added, and with the math package's ErrOverflow if the sum or
+// the variance are too large for a double.
+func (a *Accumulator) Summarize(quantiles []float64) (Summary, error) {
+	if a.count == 0 {
+		return Summary{}, ErrEmpty
+	}
+	for _, q := range quantiles {
+		if !(q >= 0 && q <= 1) {
+			return Summary{}, fmt.Errorf("quantile %v is not between 0 and 1", q)
+		}
+	}
+
+	summary := Summary{Count: a.count, Sum: a.sum, Mean: a.mean, Min: a.min, Max: a.max, Exact: a.digest == nil}
+	if a.count > 1 {
+		summary.Variance = a.squares / float64(a.count-1)
+	}
+	summary.StdDev = math.Sqrt(summary.Variance)
+	if math.IsInf(summary.Sum, 0) || math.IsInf(summary.Variance, 0) || math.IsNaN(summary.Variance) {
+		return Summary{}, magicmath.ErrOverflow
+	}
+
+	quantile := a.digestQuantile
+	if summary.Exact {
+		slices.Sort(a.values)
+		quantile = a.exactQuantile
+	}
+	summary.Median = quantile(0.5)
+	for _, q := range quantiles {
+		summary.Quantiles = append(summary.Quantiles, Quantile{Q: q, Value: quantile(q)})
+	}
+
+	return summary, nil
+}
+
+// This function returns the quantile of the sorted values, interpolating between the two values either side of it.
+// This is the same definition of quantiles as the default of R and of numpy.
+func (a *Accumulator) exactQuantile(q float64) float64 {
+	position := q * float64(len(a.values)-1)
+	below := int(math.Floor(position))
+	above := min(below+1, len(a.values)-1)
+
+	return a.values[below] + (position-float64(below))*(a.values[above]-a.values[below])
+}
+
+// This function returns the quantile estimated by the t-digest.
+func (a *Accumulator) digestQuantile(q float64) float64 {
+	return a.digest.Quantile(q)
+}
+
+// Summarize returns the statistics of a dataset, with the given quantiles.
+func Summarize(values []float64, quantiles []float64) (Summary, error) {
+	accumulator := NewAccumulator(len(values))
+	for _, value := range values {
+		if err := accumulator.Add(value); err != nil {
+			return Summary{}, err
+		}
+	}
+
+	return accumulator.Summarize(quantiles)
+}
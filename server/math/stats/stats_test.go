@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	magicmath "github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+// This function reports whether two floats are within a relative tolerance of each other.
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance*math.Max(1, math.Abs(want))
+}
+
+func TestSummarizeKnownDatasets(t *testing.T) {
+	var tests = []struct {
+		name   string
+		values []float64
+		want   Summary
+	}{
+		// The standard textbook example, whose population standard deviation is exactly 2.
+		{"textbook", []float64{2, 4, 4, 4, 5, 5, 7, 9},
+			Summary{Count: 8, Sum: 40, Mean: 5, Variance: 32.0 / 7, StdDev: math.Sqrt(32.0 / 7), Min: 2, Max: 9, Median: 4.5}},
+		// The x values of Anscombe's quartet, whose sample variance is 11.
+		{"anscombe", []float64{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5},
+			Summary{Count: 11, Sum: 99, Mean: 9, Variance: 11, StdDev: math.Sqrt(11), Min: 4, Max: 14, Median: 9}},
+		// A large offset loses every digit of the variance when summing squares, but not with Welford's algorithm.
+		{"offset", []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16},
+			Summary{Count: 4, Sum: 4e9 + 40, Mean: 1e9 + 10, Variance: 30, StdDev: math.Sqrt(30), Min: 1e9 + 4, Max: 1e9 + 16, Median: 1e9 + 10}},
+		{"single", []float64{-3.5},
+			Summary{Count: 1, Sum: -3.5, Mean: -3.5, Variance: 0, StdDev: 0, Min: -3.5, Max: -3.5, Median: -3.5}},
+	}
+
+	for _, test := range tests {
+		got, err := Summarize(test.values, nil)
+		if err != nil {
+			t.Errorf("Summarize(%s) returned error: %v", test.name, err)
+			continue
+		}
+		if got.Count != test.want.Count || !near(got.Sum, test.want.Sum, 1e-12) || !near(got.Mean, test.want.Mean, 1e-12) ||
+			!near(got.Variance, test.want.Variance, 1e-9) || !near(got.StdDev, test.want.StdDev, 1e-9) ||
+			got.Min != test.want.Min || got.Max != test.want.Max || got.Median != test.want.Median || !got.Exact {
+			t.Errorf("Summarize(%s) = %+v; want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestExactQuantiles(t *testing.T) {
+	values := []float64{15, 20, 35, 40, 50}
+	quantiles := []float64{0, 0.25, 0.4, 0.5, 0.9, 1}
+	// These are the quantiles R's quantile() and numpy.quantile() give for the same values.
+	want := []float64{15, 20, 29, 35, 46, 50}
+
+	got, err := Summarize(values, quantiles)
+	if err != nil {
+		t.Fatalf("Summarize() returned error: %v", err)
+	}
+
+	for i, quantile := range got.Quantiles {
+		if quantile.Q != quantiles[i] || !near(quantile.Value, want[i], 1e-12) {
+			t.Errorf("quantile %v = %v; want %v", quantiles[i], quantile.Value, want[i])
+		}
+	}
+}
+
+func TestSummarizeErrors(t *testing.T) {
+	if _, err := Summarize(nil, nil); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Summarize(nil) returned error %v; want %v", err, ErrEmpty)
+	}
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := Summarize([]float64{1, value}, nil); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("Summarize(1, %v) returned error %v; want %v", value, err, ErrNotFinite)
+		}
+	}
+	if _, err := Summarize([]float64{math.MaxFloat64, math.MaxFloat64}, nil); !errors.Is(err, magicmath.ErrOverflow) {
+		t.Errorf("Summarize(max, max) returned error %v; want %v", err, magicmath.ErrOverflow)
+	}
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := Summarize([]float64{1}, []float64{q}); err == nil {
+			t.Errorf("Summarize() with quantile %v succeeded; want an error", q)
+		}
+	}
+}
+
+func TestAccumulatorSwitchesToSketch(t *testing.T) {
+	accumulator := NewAccumulator(100)
+	for i := 1; i <= 1000; i++ {
+		if err := accumulator.Add(float64(i)); err != nil {
+			t.Fatalf("Add(%d) returned error: %v", i, err)
+		}
+	}
+
+	got, err := accumulator.Summarize([]float64{0.99})
+	if err != nil {
+		t.Fatalf("Summarize() returned error: %v", err)
+	}
+	if got.Exact || got.Count != 1000 || got.Sum != 500500 || got.Min != 1 || got.Max != 1000 {
+		t.Errorf("Summarize() = %+v; want an estimate of 1000 values summing to 500500", got)
+	}
+	if !near(got.Median, 500.5, 0.01) || !near(got.Quantiles[0].Value, 990, 0.01) {
+		t.Errorf("Summarize() has median %v and 99th percentile %v; want about 500.5 and 990",
+			got.Median, got.Quantiles[0].Value)
+	}
+}
@@ -0,0 +1,147 @@
+package stats
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// DefaultCompression is the compression of a t-digest when none is given. The digest keeps at most about twice as
+// many centroids, and its quantiles are typically within a fraction of a percent of the exact ones.
+const DefaultCompression = 100
+
+// A centroid is a group of nearby values, summed up by their mean and how many there are.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest is a sketch of a stream of values which estimates their quantiles in a small, fixed amount of memory.
+// It is a merging t-digest: values are buffered, and then merged into centroids which are small near the ends of the
+// distribution and large in its middle, so that extreme quantiles stay accurate.
+// See Dunning and Ertl, "Computing Extremely Accurate Quantiles Using t-Digests".
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min, max    float64
+}
+
+// NewTDigest creates an empty t-digest. A compression of zero or less means DefaultCompression.
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+
+	return &TDigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// Add adds a value to the digest.
+func (d *TDigest) Add(value float64) {
+	d.buffer = append(d.buffer, centroid{mean: value, weight: 1})
+	d.count++
+	d.min = math.Min(d.min, value)
+	d.max = math.Max(d.max, value)
+
+	if len(d.buffer) >= d.bufferSize() {
+		d.merge()
+	}
+}
+
+// Count returns how many values have been added.
+func (d *TDigest) Count() int64 {
+	return int64(d.count)
+}
+
+// Quantile estimates the value below which the fraction q of the values fall, with q between 0 and 1.
+// It returns NaN if the digest is empty.
+func (d *TDigest) Quantile(q float64) float64 {
+	d.merge()
+	if len(d.centroids) == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return d.min
+	}
+	if q >= 1 {
+		return d.max
+	}
+	if len(d.centroids) == 1 {
+		return d.centroids[0].mean
+	}
+
+	// Each centroid's mean sits at the middle of its weight. The quantile is interpolated between the two centroids
+	// on either side of the target, or between a centroid and the smallest or largest value at the ends.
+	target := q * d.count
+	first, last := d.centroids[0], d.centroids[len(d.centroids)-1]
+	if target < first.weight/2 {
+		return d.min + (first.mean-d.min)*target/(first.weight/2)
+	}
+	if target > d.count-last.weight/2 {
+		return last.mean + (d.max-last.mean)*(target-(d.count-last.weight/2))/(last.weight/2)
+	}
+
+	cumulative := first.weight / 2
+	for i := 1; i < len(d.centroids); i++ {
+		left, right := d.centroids[i-1], d.centroids[i]
+		step := (left.weight + right.weight) / 2
+		if target <= cumulative+step {
+			return left.mean + (right.mean-left.mean)*(target-cumulative)/step
+		}
+		cumulative += step
+	}
+
+	return last.mean
+}
+
+// This function returns how many values are buffered before they are merged into the centroids.
+func (d *TDigest) bufferSize() int {
+	return int(5 * d.compression)
+}
+
+// This function merges the buffered values into the centroids. Neighbouring centroids are combined for as long as
+// the combined centroid spans no more than one unit of the scale function k, which is what keeps the centroids at the
+// ends of the distribution small.
+func (d *TDigest) merge() {
+	if len(d.buffer) == 0 {
+		return
+	}
+
+	all := append(d.centroids, d.buffer...)
+	slices.SortFunc(all, func(a, b centroid) int { return cmp.Compare(a.mean, b.mean) })
+	d.buffer = d.buffer[:0]
+
+	merged := []centroid{all[0]}
+	weightBefore := 0.0
+	limit := d.count * d.inverseScale(d.scale(0)+1)
+	for _, next := range all[1:] {
+		current := &merged[len(merged)-1]
+		if weightBefore+current.weight+next.weight <= limit {
+			current.mean += (next.mean - current.mean) * next.weight / (current.weight + next.weight)
+			current.weight += next.weight
+			continue
+		}
+
+		weightBefore += current.weight
+		limit = d.count * d.inverseScale(d.scale(weightBefore/d.count)+1)
+		merged = append(merged, next)
+	}
+
+	d.centroids = merged
+}
+
+// This function is the scale function k1 of the t-digest paper, which maps a quantile to the number of centroids
+// which may come before it.
+func (d *TDigest) scale(q float64) float64 {
+	return d.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// This function is the inverse of scale, capped at the quantile one.
+func (d *TDigest) inverseScale(k float64) float64 {
+	if k >= d.compression/4 {
+		return 1
+	}
+
+	return (math.Sin(k*2*math.Pi/d.compression) + 1) / 2
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTDigestAccuracy(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	datasets := map[string]func() float64{
+		"uniform":     rng.Float64,
+		"normal":      rng.NormFloat64,
+		"exponential": rng.ExpFloat64,
+	}
+
+	for name, next := range datasets {
+		digest := NewTDigest(0)
+		values := make([]float64, 100000)
+		for i := range values {
+			values[i] = next()
+			digest.Add(values[i])
+		}
+		slices.Sort(values)
+
+		// The error is measured in rank, how far the estimate's position among the sorted values is from the target.
+		// The sketch is most accurate at the ends of the distribution.
+		for _, q := range []float64{0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999} {
+			estimate := digest.Quantile(q)
+			rank := float64(sortedRank(values, estimate)) / float64(len(values))
+			tolerance := 0.005
+			if q < 0.05 || q > 0.95 {
+				tolerance = 0.001
+			}
+			if math.Abs(rank-q) > tolerance {
+				t.Errorf("%s: Quantile(%v) = %v, which is at quantile %v; want within %v", name, q, estimate, rank, tolerance)
+			}
+		}
+
+		if digest.Count() != int64(len(values)) || digest.Quantile(0) != values[0] || digest.Quantile(1) != values[len(values)-1] {
+			t.Errorf("%s: digest has count %d, minimum %v and maximum %v; want %d, %v and %v", name,
+				digest.Count(), digest.Quantile(0), digest.Quantile(1), len(values), values[0], values[len(values)-1])
+		}
+		if len(digest.centroids) > 2*DefaultCompression {
+			t.Errorf("%s: digest has %d centroids; want at most %d", name, len(digest.centroids), 2*DefaultCompression)
+		}
+	}
+}
+
+// This function returns how many of the sorted values are below the given value.
+func sortedRank(values []float64, value float64) int {
+	rank, _ := slices.BinarySearch(values, value)
+	return rank
+}
+
+func TestTDigestEdgeCases(t *testing.T) {
+	digest := NewTDigest(0)
+	if got := digest.Quantile(0.5); !math.IsNaN(got) {
+		t.Errorf("empty digest Quantile(0.5) = %v; want NaN", got)
+	}
+
+	for range 1000 {
+		digest.Add(7)
+	}
+	for _, q := range []float64{0, 0.5, 1} {
+		if got := digest.Quantile(q); got != 7 {
+			t.Errorf("constant digest Quantile(%v) = %v; want 7", q, got)
+		}
+	}
+}
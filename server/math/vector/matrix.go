@@ -0,0 +1,98 @@
+package vector
+
+import (
+	"fmt"
+)
+
+// MaxMultiplyOperations is the most multiplications a matrix multiplication may take, so that no caller can tie up
+// the server for more than a fraction of a second with a single call. Operands within MaxElements could otherwise
+// take up to 1<<30.
+const MaxMultiplyOperations = 1 << 28
+
+// The size of the square blocks Transpose copies at a time, small enough that a block of the source and of the
+// destination both stay in the processor's cache.
+const transposeBlock = 32
+
+// Matrix is a matrix of doubles, stored row by row, so the element in row i and column j is Values[i*Columns+j].
+type Matrix struct {
+	Rows    int
+	Columns int
+	Values  []float64
+}
+
+// Multiply returns the matrix product of a and b. The number of columns of a must equal the number of rows of b.
+//
+// The loops run in the order row, inner, column rather than the textbook row, column, inner, so that the innermost
+// loop walks along a row of b and a row of the result, which are both contiguous in memory.
+func Multiply(a, b Matrix) (Matrix, error) {
+	if err := a.check(); err != nil {
+		return Matrix{}, err
+	}
+	if err := b.check(); err != nil {
+		return Matrix{}, err
+	}
+	if a.Columns != b.Rows {
+		return Matrix{}, fmt.Errorf("%w: can't multiply a %dx%d matrix by a %dx%d matrix",
+			ErrShape, a.Rows, a.Columns, b.Rows, b.Columns)
+	}
+	if a.Rows*b.Columns > MaxElements {
+		return Matrix{}, fmt.Errorf("%w: the product would have %d elements, the limit is %d",
+			ErrTooLarge, a.Rows*b.Columns, MaxElements)
+	}
+	if operations := a.Rows * a.Columns * b.Columns; operations > MaxMultiplyOperations {
+		return Matrix{}, fmt.Errorf("%w: the product would take %d multiplications, the limit is %d",
+			ErrTooLarge, operations, MaxMultiplyOperations)
+	}
+
+	product := Matrix{Rows: a.Rows, Columns: b.Columns, Values: make([]float64, a.Rows*b.Columns)}
+	for i := range a.Rows {
+		productRow := product.Values[i*b.Columns : (i+1)*b.Columns]
+		for k := range a.Columns {
+			aik := a.Values[i*a.Columns+k]
+			bRow := b.Values[k*b.Columns : (k+1)*b.Columns]
+			for j := range productRow {
+				productRow[j] += aik * bRow[j]
+			}
+		}
+	}
+
+	return product, checkResult(product.Values)
+}
+
+// Transpose returns the transpose of a matrix, whose rows are the matrix's columns.
+// It copies square blocks at a time, so that reading the columns doesn't miss the cache on every element.
+func Transpose(m Matrix) (Matrix, error) {
+	if err := m.check(); err != nil {
+		return Matrix{}, err
+	}
+
+	transposed := Matrix{Rows: m.Columns, Columns: m.Rows, Values: make([]float64, len(m.Values))}
+	for rowBlock := 0; rowBlock < m.Rows; rowBlock += transposeBlock {
+		for columnBlock := 0; columnBlock < m.Columns; columnBlock += transposeBlock {
+			for i := rowBlock; i < min(rowBlock+transposeBlock, m.Rows); i++ {
+				for j := columnBlock; j < min(columnBlock+transposeBlock, m.Columns); j++ {
+					transposed.Values[j*m.Rows+i] = m.Values[i*m.Columns+j]
+				}
+			}
+		}
+	}
+
+	return transposed, nil
+}
+
+// This function checks that a matrix's shape matches its values, that it isn't too large, and that all of its
+// elements are finite.
+func (m Matrix) check() error {
+	if m.Rows < 0 || m.Columns < 0 {
+		return fmt.Errorf("%w: a %dx%d matrix has a negative size", ErrShape, m.Rows, m.Columns)
+	}
+	if m.Rows > MaxElements || m.Columns > MaxElements || m.Rows*m.Columns > MaxElements {
+		return fmt.Errorf("%w: a %dx%d matrix has more than %d elements", ErrTooLarge, m.Rows, m.Columns, MaxElements)
+	}
+	if m.Rows*m.Columns != len(m.Values) {
+		return fmt.Errorf("%w: a %dx%d matrix needs %d values, it has %d",
+			ErrShape, m.Rows, m.Columns, m.Rows*m.Columns, len(m.Values))
+	}
+
+	return checkVector(m.Values)
+}
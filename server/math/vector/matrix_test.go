@@ -0,0 +1,168 @@
+package vector
+
+import (
+	"errors"
+	gomath "math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+func TestMultiply(t *testing.T) {
+	var tests = []struct {
+		a, b Matrix
+		want Matrix
+	}{
+		{
+			Matrix{2, 3, []float64{1, 2, 3, 4, 5, 6}},
+			Matrix{3, 2, []float64{7, 8, 9, 10, 11, 12}},
+			Matrix{2, 2, []float64{58, 64, 139, 154}},
+		},
+		{
+			Matrix{1, 3, []float64{1, 2, 3}},
+			Matrix{3, 1, []float64{4, 5, 6}},
+			Matrix{1, 1, []float64{32}},
+		},
+		{
+			Matrix{2, 2, []float64{1, 2, 3, 4}},
+			Matrix{2, 2, []float64{1, 0, 0, 1}},
+			Matrix{2, 2, []float64{1, 2, 3, 4}},
+		},
+		{Matrix{0, 3, nil}, Matrix{3, 2, make([]float64, 6)}, Matrix{0, 2, []float64{}}},
+	}
+
+	for _, test := range tests {
+		got, err := Multiply(test.a, test.b)
+		if err != nil || got.Rows != test.want.Rows || got.Columns != test.want.Columns ||
+			!slices.Equal(got.Values, test.want.Values) {
+			t.Errorf("Multiply(%v, %v) = %v, %v; want %v", test.a, test.b, got, err, test.want)
+		}
+	}
+}
+
+func TestMultiplyMatchesNaiveLoops(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	a := randomMatrix(rng, 37, 53)
+	b := randomMatrix(rng, 53, 29)
+
+	got, err := Multiply(a, b)
+	if err != nil {
+		t.Fatalf("Multiply() returned error: %v", err)
+	}
+	want := naiveMultiply(a, b)
+	for i := range want.Values {
+		if gomath.Abs(got.Values[i]-want.Values[i]) > 1e-12 {
+			t.Fatalf("Multiply() element %d = %v; want %v", i, got.Values[i], want.Values[i])
+		}
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	m := Matrix{2, 3, []float64{1, 2, 3, 4, 5, 6}}
+	want := Matrix{3, 2, []float64{1, 4, 2, 5, 3, 6}}
+	if got, err := Transpose(m); err != nil || got.Rows != 3 || got.Columns != 2 || !slices.Equal(got.Values, want.Values) {
+		t.Errorf("Transpose(%v) = %v, %v; want %v", m, got, err, want)
+	}
+
+	// A matrix larger than a block, whose sides aren't multiples of the block size.
+	rng := rand.New(rand.NewPCG(1, 2))
+	large := randomMatrix(rng, 70, 45)
+	got, err := Transpose(large)
+	if err != nil || !slices.Equal(got.Values, naiveTranspose(large).Values) {
+		t.Errorf("Transpose(70x45) differs from the naive transpose, error %v", err)
+	}
+}
+
+func TestMatrixErrors(t *testing.T) {
+	var tests = []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"inner sizes", second(Multiply(Matrix{2, 3, make([]float64, 6)}, Matrix{2, 3, make([]float64, 6)})), ErrShape},
+		{"values", second(Multiply(Matrix{2, 2, make([]float64, 3)}, Matrix{2, 2, make([]float64, 4)})), ErrShape},
+		{"negative", second(Transpose(Matrix{-1, -1, make([]float64, 1)})), ErrShape},
+		{"elements", second(Transpose(Matrix{MaxElements, 2, nil})), ErrTooLarge},
+		{"product", second(Multiply(Matrix{1 << 11, 1, make([]float64, 1<<11)}, Matrix{1, 1 << 11, make([]float64, 1<<11)})), ErrTooLarge},
+		{"operations", second(Multiply(Matrix{1 << 10, 1 << 10, make([]float64, 1<<20)}, Matrix{1 << 10, 1 << 10, make([]float64, 1<<20)})), ErrTooLarge},
+		{"NaN", second(Transpose(Matrix{1, 1, []float64{gomath.NaN()}})), math.ErrUndefined},
+		{"overflow", second(Multiply(Matrix{1, 2, []float64{1e300, 1e300}}, Matrix{2, 1, []float64{1e10, 1e10}})), math.ErrOverflow},
+	}
+
+	for _, test := range tests {
+		if !errors.Is(test.err, test.want) {
+			t.Errorf("%s returned error %v; want %v", test.name, test.err, test.want)
+		}
+	}
+}
+
+// This function creates a matrix of random values.
+func randomMatrix(rng *rand.Rand, rows, columns int) Matrix {
+	return Matrix{Rows: rows, Columns: columns, Values: randomVector(rng, rows*columns)}
+}
+
+// This function is the textbook matrix multiplication, which Multiply is tested and benchmarked against.
+func naiveMultiply(a, b Matrix) Matrix {
+	product := Matrix{Rows: a.Rows, Columns: b.Columns, Values: make([]float64, a.Rows*b.Columns)}
+	for i := range a.Rows {
+		for j := range b.Columns {
+			var sum float64
+			for k := range a.Columns {
+				sum += a.Values[i*a.Columns+k] * b.Values[k*b.Columns+j]
+			}
+			product.Values[i*b.Columns+j] = sum
+		}
+	}
+
+	return product
+}
+
+// This function is the naive transpose, which Transpose is tested and benchmarked against.
+func naiveTranspose(m Matrix) Matrix {
+	transposed := Matrix{Rows: m.Columns, Columns: m.Rows, Values: make([]float64, len(m.Values))}
+	for i := range m.Rows {
+		for j := range m.Columns {
+			transposed.Values[j*m.Rows+i] = m.Values[i*m.Columns+j]
+		}
+	}
+
+	return transposed
+}
+
+func BenchmarkMultiply(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	x, y := randomMatrix(rng, 256, 256), randomMatrix(rng, 256, 256)
+
+	for b.Loop() {
+		Multiply(x, y)
+	}
+}
+
+func BenchmarkNaiveMultiply(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	x, y := randomMatrix(rng, 256, 256), randomMatrix(rng, 256, 256)
+
+	for b.Loop() {
+		naiveMultiply(x, y)
+	}
+}
+
+func BenchmarkTranspose(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	m := randomMatrix(rng, 1024, 1024)
+
+	for b.Loop() {
+		Transpose(m)
+	}
+}
+
+func BenchmarkNaiveTranspose(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	m := randomMatrix(rng, 1024, 1024)
+
+	for b.Loop() {
+		naiveTranspose(m)
+	}
+}
@@ -0,0 +1,171 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	gomath "math"
+	"slices"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+// MaxElements is the most elements a vector or a matrix may have, so that no caller can make the server allocate
+// more than a few megabytes for a single result.
+const MaxElements = 1 << 20
+
+// ErrShape is returned when the operands of a function don't have the shapes it needs, for example when adding two
+// vectors of different lengths.
+var ErrShape = errors.New("shape mismatch")
+
+// ErrTooLarge is returned when an operand or a result would have more than MaxElements elements, or a matrix
+// multiplication would take more than MaxMultiplyOperations operations.
+var ErrTooLarge = errors.New("too many elements")
+
+// These are the norms Norm can work out.
+const (
+	// L1 is the sum of the absolute values, also known as the Manhattan norm.
+	L1 = "l1"
+	// L2 is the square root of the sum of the squares, the Euclidean length of the vector. It is the default.
+	L2 = "l2"
+	// Max is the largest absolute value, also known as the infinity norm.
+	Max = "max"
+)
+
+// Add returns the element-wise sum of two vectors of the same length.
+func Add(a, b []float64) ([]float64, error) {
+	if err := checkPair(a, b); err != nil {
+		return nil, err
+	}
+
+	sum := make([]float64, len(a))
+	for i := range sum {
+		sum[i] = a[i] + b[i]
+	}
+
+	return sum, checkResult(sum)
+}
+
+// Subtract returns the element-wise difference of two vectors of the same length.
+func Subtract(a, b []float64) ([]float64, error) {
+	if err := checkPair(a, b); err != nil {
+		return nil, err
+	}
+
+	difference := make([]float64, len(a))
+	for i := range difference {
+		difference[i] = a[i] - b[i]
+	}
+
+	return difference, checkResult(difference)
+}
+
+// Dot returns the dot product of two vectors of the same length, the sum of the products of their elements.
+func Dot(a, b []float64) (float64, error) {
+	if err := checkPair(a, b); err != nil {
+		return 0, err
+	}
+
+	result := dot(a, b)
+	return result, checkResult([]float64{result})
+}
+
+// This function works out the dot product of two vectors of the same length. It sums four products at a time, which
+// lets the processor overlap the additions.
+func dot(a, b []float64) float64 {
+	var sum0, sum1, sum2, sum3 float64
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		sum0 += a[i] * b[i]
+		sum1 += a[i+1] * b[i+1]
+		sum2 += a[i+2] * b[i+2]
+		sum3 += a[i+3] * b[i+3]
+	}
+	for ; i < len(a); i++ {
+		sum0 += a[i] * b[i]
+	}
+
+	return (sum0 + sum1) + (sum2 + sum3)
+}
+
+// Norm returns the named norm of a vector, one of L1, L2 or Max. An empty name means L2.
+// The L2 norm is scaled by the largest element, so that it doesn't overflow when the squares would.
+func Norm(v []float64, name string) (float64, error) {
+	if err := checkVector(v); err != nil {
+		return 0, err
+	}
+
+	var norm float64
+	switch name {
+	case L1:
+		for _, x := range v {
+			norm += gomath.Abs(x)
+		}
+	case L2, "":
+		largest, _ := Norm(v, Max)
+		if largest == 0 {
+			return 0, nil
+		}
+		for _, x := range v {
+			scaled := x / largest
+			norm += scaled * scaled
+		}
+		norm = largest * gomath.Sqrt(norm)
+	case Max:
+		for _, x := range v {
+			norm = gomath.Max(norm, gomath.Abs(x))
+		}
+	default:
+		return 0, fmt.Errorf("unknown norm %q, use %q, %q or %q", name, L1, L2, Max)
+	}
+
+	return norm, checkResult([]float64{norm})
+}
+
+// This function checks that two vectors can be combined element by element.
+func checkPair(a, b []float64) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("%w: vectors of lengths %d and %d", ErrShape, len(a), len(b))
+	}
+	if err := checkVector(a); err != nil {
+		return err
+	}
+
+	return checkVector(b)
+}
+
+// This function checks that a vector isn't too large, and that all of its elements are finite.
+func checkVector(v []float64) error {
+	if len(v) > MaxElements {
+		return fmt.Errorf("%w: %d elements, the limit is %d", ErrTooLarge, len(v), MaxElements)
+	}
+	// Zero times a finite number is zero, and times NaN or infinity is NaN, so the sum is only NaN if some element isn't
+	// finite. This avoids a branch per element, which would take longer than the arithmetic it checks.
+	var zero0, zero1, zero2, zero3 float64
+	i := 0
+	for ; i+4 <= len(v); i += 4 {
+		zero0 += v[i] * 0
+		zero1 += v[i+1] * 0
+		zero2 += v[i+2] * 0
+		zero3 += v[i+3] * 0
+	}
+	for ; i < len(v); i++ {
+		zero0 += v[i] * 0
+	}
+	if zero0+zero1+zero2+zero3 == 0 {
+		return nil
+	}
+
+	i = slices.IndexFunc(v, func(x float64) bool { return gomath.IsNaN(x) || gomath.IsInf(x, 0) })
+	return fmt.Errorf("%w: element %d is %v", math.ErrUndefined, i, v[i])
+}
+
+// This function checks that a result worked out from finite elements didn't overflow.
+func checkResult(result []float64) error {
+	for i, x := range result {
+		if gomath.IsInf(x, 0) || gomath.IsNaN(x) {
+			return fmt.Errorf("%w: element %d", math.ErrOverflow, i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package vector
+
+import (
+	"errors"
+	gomath "math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+)
+
+func TestAddAndSubtract(t *testing.T) {
+	a, b := []float64{1, 2, 3}, []float64{0.5, -2, 10}
+
+	if got, err := Add(a, b); err != nil || !slices.Equal(got, []float64{1.5, 0, 13}) {
+		t.Errorf("Add(%v, %v) = %v, %v; want [1.5 0 13]", a, b, got, err)
+	}
+	if got, err := Subtract(a, b); err != nil || !slices.Equal(got, []float64{0.5, 4, -7}) {
+		t.Errorf("Subtract(%v, %v) = %v, %v; want [0.5 4 -7]", a, b, got, err)
+	}
+	if got, err := Add(nil, nil); err != nil || len(got) != 0 {
+		t.Errorf("Add(nil, nil) = %v, %v; want []", got, err)
+	}
+}
+
+func TestDot(t *testing.T) {
+	var tests = []struct {
+		a, b []float64
+		want float64
+	}{
+		{[]float64{}, []float64{}, 0},
+		{[]float64{1, 2, 3}, []float64{4, 5, 6}, 32},
+		{[]float64{1, 2, 3, 4, 5, 6, 7}, []float64{1, 1, 1, 1, 1, 1, 1}, 28},
+		{[]float64{1, 0}, []float64{0, 1}, 0},
+	}
+
+	for _, test := range tests {
+		if got, err := Dot(test.a, test.b); err != nil || got != test.want {
+			t.Errorf("Dot(%v, %v) = %v, %v; want %v", test.a, test.b, got, err, test.want)
+		}
+	}
+}
+
+func TestNorm(t *testing.T) {
+	var tests = []struct {
+		v    []float64
+		name string
+		want float64
+	}{
+		{[]float64{3, -4}, L1, 7},
+		{[]float64{3, -4}, L2, 5},
+		{[]float64{3, -4}, "", 5},
+		{[]float64{3, -4}, Max, 4},
+		{[]float64{0, 0}, L2, 0},
+		{[]float64{}, L2, 0},
+		// The squares overflow a double, but the scaled norm doesn't.
+		{[]float64{3e200, 4e200}, L2, 5e200},
+	}
+
+	for _, test := range tests {
+		if got, err := Norm(test.v, test.name); err != nil || gomath.Abs(got-test.want) > 1e-12*test.want {
+			t.Errorf("Norm(%v, %q) = %v, %v; want %v", test.v, test.name, got, err, test.want)
+		}
+	}
+
+	if _, err := Norm([]float64{1}, "l3"); err == nil {
+		t.Errorf("Norm(%q) succeeded; want an error", "l3")
+	}
+}
+
+func TestVectorErrors(t *testing.T) {
+	large := make([]float64, MaxElements+1)
+
+	var tests = []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"Add lengths", second(Add([]float64{1}, []float64{1, 2})), ErrShape},
+		{"Dot lengths", second(Dot([]float64{1, 2}, []float64{1})), ErrShape},
+		{"Add NaN", second(Add([]float64{gomath.NaN()}, []float64{1})), math.ErrUndefined},
+		{"Norm infinity", second(Norm([]float64{gomath.Inf(1)}, L1)), math.ErrUndefined},
+		{"Add overflow", second(Add([]float64{gomath.MaxFloat64}, []float64{gomath.MaxFloat64})), math.ErrOverflow},
+		{"Dot overflow", second(Dot([]float64{1e300}, []float64{1e300})), math.ErrOverflow},
+		{"Norm too large", second(Norm(large, L2)), ErrTooLarge},
+	}
+
+	for _, test := range tests {
+		if !errors.Is(test.err, test.want) {
+			t.Errorf("%s returned error %v; want %v", test.name, test.err, test.want)
+		}
+	}
+}
+
+// This function returns the error of a function which returns a result and an error.
+func second[T any](_ T, err error) error {
+	return err
+}
+
+// This function creates a vector of random values.
+func randomVector(rng *rand.Rand, length int) []float64 {
+	v := make([]float64, length)
+	for i := range v {
+		v[i] = rng.Float64()
+	}
+
+	return v
+}
+
+// This function is the naive dot product, which the unrolled loop of Dot is benchmarked against.
+func naiveDot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+
+	return sum
+}
+
+func BenchmarkDot(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	x, y := randomVector(rng, 100000), randomVector(rng, 100000)
+
+	for b.Loop() {
+		Dot(x, y)
+	}
+}
+
+func BenchmarkDotLoop(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	x, y := randomVector(rng, 100000), randomVector(rng, 100000)
+
+	for b.Loop() {
+		dot(x, y)
+	}
+}
+
+func BenchmarkNaiveDot(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	x, y := randomVector(rng, 100000), randomVector(rng, 100000)
+
+	for b.Loop() {
+		naiveDot(x, y)
+	}
+}
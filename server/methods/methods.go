@@ -0,0 +1,30 @@
+// Package methods tells which full gRPC method names belong to which service, so that the interceptors which only
+// apply to the math functions all agree on what the math functions are.
+package methods
+
+import (
+	"strings"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	pbv2 "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+)
+
+// The prefixes of the method names of every math service: both versions of MagicMath, and VectorMath.
+var mathPrefixes = []string{
+	"/" + pb.MagicMath_ServiceDesc.ServiceName + "/",
+	"/" + pbv2.MagicMath_ServiceDesc.ServiceName + "/",
+	"/" + pb.VectorMath_ServiceDesc.ServiceName + "/",
+}
+
+// IsMath returns true if the full method name, such as "/shared.MagicMath/MagicAdd", belongs to one of the math
+// services: either version of MagicMath, or VectorMath. Rate limits, load shedding, idempotency keys, fault injection
+// and recording apply to all of their calls, and to no others.
+func IsMath(fullMethod string) bool {
+	for _, prefix := range mathPrefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
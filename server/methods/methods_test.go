@@ -0,0 +1,29 @@
+package methods
+
+import (
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	pbv2 "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+)
+
+func TestIsMath(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{pb.MagicMath_MagicAdd_FullMethodName, true},
+		{pb.MagicMath_GetQuota_FullMethodName, true},
+		{pbv2.MagicMath_Calculate_FullMethodName, true},
+		{pb.VectorMath_Dot_FullMethodName, true},
+		{pb.Operations_Submit_FullMethodName, false},
+		{"/grpc.health.v1.Health/Check", false},
+		{"/shared.MagicMathExtra/MagicAdd", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsMath(tt.method); got != tt.want {
+			t.Errorf("IsMath(%q) = %v; want %v", tt.method, got, tt.want)
+		}
+	}
+}
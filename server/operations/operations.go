@@ -0,0 +1,452 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NamePrefix starts the name of every operation, the rest of the name is the operation's ID.
+const NamePrefix = "operations/"
+
+// MaxPageSize is the most operations ListOperations returns at once.
+const MaxPageSize = 100
+
+// Options tunes the manager. A zero value means the default given for each option.
+type Options struct {
+	// Workers is how many operations run at once. It defaults to 4.
+	Workers int
+	// QueueSize is how many operations may wait for a worker, once it is full Submit fails. It defaults to 100.
+	QueueSize int
+	// Expiry is how long an operation is kept once it is done, after which it is forgotten. It defaults to an hour.
+	Expiry time.Duration
+	// MaxWait is the longest WaitOperation waits. It defaults to a minute.
+	MaxWait time.Duration
+	// Store saves the operations which are done, so that their results survive a restart. Defaults to keeping them
+	// only in memory.
+	Store Store
+	// Admit is called by Submit with the caller's context and the method's name, and the operation is rejected with
+	// its error. app.New sets it to the rate limiter, so that an operation uses up its caller's limits and quotas when it
+	// is submitted rather than when it runs. Defaults to admitting every operation.
+	Admit func(ctx context.Context, method string) error
+	// Interceptor runs around every call an operation makes. app.New sets it to the server's interceptors, apart from
+	// the rate limiter which Admit has already applied, so that queued calls are validated, shed and cached like any
+	// other call. Defaults to calling the method's handler directly.
+	Interceptor grpc.UnaryServerInterceptor
+}
+
+// Store saves the operations which are done. Implementations must be safe to use from many go routines at once.
+type Store interface {
+	// Load returns the operations which were saved.
+	Load() ([]*pb.Operation, error)
+	// Save saves an operation which is done.
+	Save(operation *pb.Operation) error
+	// Compact replaces the saved operations with the given ones. The manager calls it with the operations which haven't
+	// expired when it is created, so that the store doesn't grow forever.
+	Compact(operations []*pb.Operation) error
+}
+
+// Manager implements the Operations service, which runs MagicMath calls in the background on a bounded pool of
+// workers. Operations which are done are kept until they expire.
+type Manager struct {
+	pb.UnsafeOperationsServer
+
+	// server is the MagicMath service whose methods the operations call.
+	server  pb.MagicMathServer
+	options Options
+	// now returns the current time, tests replace it to control the clock.
+	now func() time.Time
+	// workers holds a token for each operation which is running, so that no more than Options.Workers run at once.
+	workers chan struct{}
+
+	// This mutex is used to protect all of the fields below, and the operations' messages.
+	mutex      sync.Mutex
+	operations map[string]*operation
+	// order holds the operations in the order they were submitted, which is the order they are listed in.
+	order []*operation
+	// sequence is the sequence number of the last operation submitted, used as the page token when listing.
+	sequence  int64
+	queued    int
+	nextPurge time.Time
+}
+
+// An operation, with what is needed to cancel it and wait for it.
+type operation struct {
+	message  *pb.Operation
+	sequence int64
+	// cancel cancels the context of the operation's call.
+	cancel context.CancelFunc
+	// done is closed once the operation is done.
+	done chan struct{}
+}
+
+// New creates a manager which runs operations by calling the methods of the MagicMath service. The operations saved
+// in the store which haven't expired are loaded. If they can't be loaded the problem is logged, and the manager starts
+// without them, so that the server still starts.
+func New(server pb.MagicMathServer, options Options) *Manager {
+	if options.Workers <= 0 {
+		options.Workers = 4
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = 100
+	}
+	if options.Expiry <= 0 {
+		options.Expiry = time.Hour
+	}
+	if options.MaxWait <= 0 {
+		options.MaxWait = time.Minute
+	}
+
+	m := &Manager{
+		server:     server,
+		options:    options,
+		now:        time.Now,
+		workers:    make(chan struct{}, options.Workers),
+		operations: make(map[string]*operation),
+	}
+
+	if options.Store != nil {
+		m.load()
+	}
+
+	return m
+}
+
+// This function loads the saved operations which haven't expired, and compacts the store.
+func (m *Manager) load() {
+	saved, err := m.options.Store.Load()
+	if err != nil {
+		log.Printf("failed to load the saved operations: %v", err)
+		return
+	}
+
+	var live []*pb.Operation
+	for _, message := range saved {
+		if !message.Done || m.expired(message) {
+			continue
+		}
+		m.sequence++
+		done := make(chan struct{})
+		close(done)
+		m.add(&operation{message: message, sequence: m.sequence, cancel: func() {}, done: done})
+		live = append(live, message)
+	}
+	if err := m.options.Store.Compact(live); err != nil {
+		log.Printf("failed to compact the saved operations: %v", err)
+	}
+}
+
+// Submit queues a call to a MagicMath method, and returns the operation which will run it. The call is made with the
+// metadata of the call to Submit, so that the interceptors see it as coming from the same caller.
+func (m *Manager) Submit(ctx context.Context, in *pb.SubmitRequest) (*pb.Operation, error) {
+	method, err := findMethod(in.Method, in.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.purge()
+	if m.queued >= m.options.QueueSize {
+		return nil, status.Errorf(codes.ResourceExhausted, "%d operations are already waiting to run", m.queued)
+	}
+	// The operation is only admitted once it is sure to be queued, so that a full queue doesn't use up any quota.
+	if m.options.Admit != nil {
+		if err := m.options.Admit(ctx, in.Method); err != nil {
+			return nil, err
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(detach(ctx))
+	m.sequence++
+	op := &operation{
+		message: &pb.Operation{
+			Name:       fmt.Sprintf("%s%016x", NamePrefix, rand.Uint64()),
+			Method:     in.Method,
+			CreateTime: timestamppb.New(m.now()),
+			Caller:     caller.ID(ctx),
+		},
+		sequence: m.sequence,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	m.add(op)
+	m.queued++
+
+	go m.run(runCtx, op, method, in.Request)
+
+	return proto.Clone(op.message).(*pb.Operation), nil
+}
+
+// GetOperation returns the latest state of an operation.
+func (m *Manager) GetOperation(ctx context.Context, in *pb.OperationName) (*pb.Operation, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	op, err := m.find(in.Name, caller.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Clone(op.message).(*pb.Operation), nil
+}
+
+// WaitOperation waits until an operation is done, the timeout has passed or the call ends, and returns its latest state.
+func (m *Manager) WaitOperation(ctx context.Context, in *pb.WaitOperationRequest) (*pb.Operation, error) {
+	m.mutex.Lock()
+	op, err := m.find(in.Name, caller.ID(ctx))
+	m.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := m.options.MaxWait
+	if in.Timeout != nil {
+		if err := in.Timeout.CheckValid(); err != nil || in.Timeout.AsDuration() < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid timeout %v", in.Timeout)
+		}
+		timeout = min(timeout, in.Timeout.AsDuration())
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-op.done:
+	case <-timer.C:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return proto.Clone(op.message).(*pb.Operation), nil
+}
+
+// CancelOperation cancels an operation which isn't done, which then fails with codes.Canceled.
+// The method's handler may keep running for a while, but its result is thrown away.
+func (m *Manager) CancelOperation(ctx context.Context, in *pb.OperationName) (*pb.Operation, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	op, err := m.find(in.Name, caller.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if !op.message.Done {
+		op.cancel()
+		m.finish(op, nil, status.New(codes.Canceled, "the operation was cancelled"))
+	}
+
+	return proto.Clone(op.message).(*pb.Operation), nil
+}
+
+// ListOperations lists the caller's operations which haven't expired, oldest first, a page at a time.
+func (m *Manager) ListOperations(ctx context.Context, in *pb.ListOperationsRequest) (*pb.ListOperationsResponse, error) {
+	pageSize := int(in.PageSize)
+	if pageSize <= 0 || pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	var after int64
+	if in.PageToken != "" {
+		var err error
+		if after, err = strconv.ParseInt(in.PageToken, 10, 64); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", in.PageToken)
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.purge()
+	id := caller.ID(ctx)
+	response := &pb.ListOperationsResponse{}
+	var last int64
+	for _, op := range m.order {
+		if op.sequence <= after || op.message.Caller != id {
+			continue
+		}
+		if len(response.Operations) == pageSize {
+			response.NextPageToken = strconv.FormatInt(last, 10)
+			break
+		}
+		response.Operations = append(response.Operations, proto.Clone(op.message).(*pb.Operation))
+		last = op.sequence
+	}
+
+	return response, nil
+}
+
+// This function runs an operation once a worker is free, unless it is cancelled while it waits.
+func (m *Manager) run(ctx context.Context, op *operation, method grpc.MethodDesc, request *anypb.Any) {
+	select {
+	case m.workers <- struct{}{}:
+		defer func() { <-m.workers }()
+	case <-ctx.Done():
+	}
+
+	m.mutex.Lock()
+	m.queued--
+	m.mutex.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+
+	response, callStatus := m.call(ctx, method, request)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// The operation may have been cancelled while it ran.
+	if !op.message.Done {
+		m.finish(op, response, callStatus)
+	}
+}
+
+// This function calls the method's handler through the interceptor with the request, and returns either its response
+// or its status. A panic in the handler is turned into an internal error, rather than taking down the server.
+func (m *Manager) call(
+	ctx context.Context,
+	method grpc.MethodDesc,
+	request *anypb.Any,
+) (response *anypb.Any, callStatus *status.Status) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("operation calling %s panicked: %v", method.MethodName, recovered)
+			response, callStatus = nil, status.New(codes.Internal, "the operation panicked")
+		}
+	}()
+
+	decode := func(in any) error {
+		if err := request.UnmarshalTo(in.(proto.Message)); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid %s request: %v", method.MethodName, err)
+		}
+		return nil
+	}
+	resp, err := method.Handler(m.server, ctx, decode, m.options.Interceptor)
+	if err != nil {
+		return nil, status.Convert(err)
+	}
+	response, err = anypb.New(resp.(proto.Message))
+	if err != nil {
+		return nil, status.Convert(err)
+	}
+
+	return response, nil
+}
+
+// This function marks an operation as done, with either a response or an error, and saves it to the store.
+func (m *Manager) finish(op *operation, response *anypb.Any, callStatus *status.Status) {
+	op.message.Done = true
+	op.message.EndTime = timestamppb.New(m.now())
+	if response != nil {
+		op.message.Result = &pb.Operation_Response{Response: response}
+	} else {
+		op.message.Result = &pb.Operation_Error{Error: callStatus.Proto()}
+	}
+	close(op.done)
+
+	if m.options.Store != nil {
+		if err := m.options.Store.Save(op.message); err != nil {
+			log.Printf("failed to save %s: %v", op.message.Name, err)
+		}
+	}
+}
+
+// This function returns a context which carries the metadata and the peer of a call, but nothing else from it, so that
+// an operation can run once the call to Submit has ended.
+func detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		detached = metadata.NewIncomingContext(detached, md.Copy())
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		detached = peer.NewContext(detached, p)
+	}
+
+	return detached
+}
+
+// This function adds an operation to the ones the manager knows about.
+func (m *Manager) add(op *operation) {
+	m.operations[op.message.Name] = op
+	m.order = append(m.order, op)
+}
+
+// This function returns the operation with the name, or a gRPC error if there is none. Operations submitted by other
+// callers are treated as if they don't exist, so that callers can't even find out about each other's operations.
+func (m *Manager) find(name, id string) (*operation, error) {
+	m.purge()
+	op, ok := m.operations[name]
+	if !ok || op.message.Caller != id {
+		return nil, status.Errorf(codes.NotFound, "there is no operation %q", name)
+	}
+
+	return op, nil
+}
+
+// This function forgets the operations which have expired. It only looks for them once a second, as it has to look at
+// every operation.
+func (m *Manager) purge() {
+	now := m.now()
+	if now.Before(m.nextPurge) {
+		return
+	}
+	m.nextPurge = now.Add(time.Second)
+
+	kept := m.order[:0]
+	for _, op := range m.order {
+		if m.expired(op.message) {
+			delete(m.operations, op.message.Name)
+			continue
+		}
+		kept = append(kept, op)
+	}
+	clear(m.order[len(kept):])
+	m.order = kept
+}
+
+// This function returns true if an operation is done, and was done longer ago than the expiry.
+func (m *Manager) expired(message *pb.Operation) bool {
+	return message.Done && !m.now().Before(message.EndTime.AsTime().Add(m.options.Expiry))
+}
+
+// This function finds the handler of a unary MagicMath method, and checks that the request is the method's request
+// message. Streaming methods can't be run as operations.
+func findMethod(name string, request *anypb.Any) (grpc.MethodDesc, error) {
+	service := pb.File_magicMath_magic_math_proto.Services().ByName("MagicMath")
+	descriptor := service.Methods().ByName(protoreflect.Name(name))
+	if descriptor == nil || descriptor.IsStreamingClient() || descriptor.IsStreamingServer() {
+		return grpc.MethodDesc{}, status.Errorf(codes.InvalidArgument, "%q is not a unary MagicMath method", name)
+	}
+	if request == nil || request.MessageName() != descriptor.Input().FullName() {
+		return grpc.MethodDesc{}, status.Errorf(codes.InvalidArgument, "%s needs a %s request", name,
+			descriptor.Input().FullName())
+	}
+
+	for _, method := range pb.MagicMath_ServiceDesc.Methods {
+		if method.MethodName == name {
+			return method, nil
+		}
+	}
+
+	return grpc.MethodDesc{}, status.Errorf(codes.InvalidArgument, "%q is not a unary MagicMath method", name)
+}
@@ -0,0 +1,355 @@
+package operations
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// This object is a MagicMath server whose MagicAdd blocks until it is released, so that tests can fill the workers.
+type blockingServer struct {
+	pb.UnimplementedMagicMathServer
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingServer() *blockingServer {
+	return &blockingServer{started: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (s *blockingServer) MagicAdd(_ context.Context, in *pb.DoubleTerms) (*pb.DoubleResult, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return &pb.DoubleResult{Result: in.TermOne + in.TermTwo}, nil
+}
+
+// This function submits a call to a method through the manager, failing the test if it can't be submitted.
+func submit(t *testing.T, m *Manager, method string, request proto.Message) *pb.Operation {
+	t.Helper()
+
+	packed, _ := anypb.New(request)
+	op, err := m.Submit(context.Background(), &pb.SubmitRequest{Method: method, Request: packed})
+	if err != nil {
+		t.Fatalf("Submit(%s) returned error: %v", method, err)
+	}
+
+	return op
+}
+
+// This function waits for an operation to be done, failing the test if it isn't done within a few seconds.
+func wait(t *testing.T, m *Manager, name string) *pb.Operation {
+	t.Helper()
+
+	op, err := m.WaitOperation(context.Background(),
+		&pb.WaitOperationRequest{Name: name, Timeout: durationpb.New(5 * time.Second)})
+	if err != nil || !op.Done {
+		t.Fatalf("WaitOperation(%s) = %v, %v; want a done operation", name, op, err)
+	}
+
+	return op
+}
+
+func TestOperationRunsMethod(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	m := New(service.New(counters, nil, nil, nil, nil), Options{})
+
+	op := submit(t, m, "MagicStats", &pb.StatsRequest{Values: []float64{1, 2, 3, 4}})
+	if op.Done || op.CreateTime == nil {
+		t.Errorf("Submit() = %v; want an operation which isn't done", op)
+	}
+
+	op = wait(t, m, op.Name)
+	stats := &pb.Stats{}
+	if err := op.GetResponse().UnmarshalTo(stats); err != nil || stats.Mean != 2.5 || op.EndTime == nil {
+		t.Errorf("operation = %v; want a stats response with mean 2.5", op)
+	}
+	if got := counters.Count(counter.Stats); got != 1 {
+		t.Errorf("stats count = %d; want 1", got)
+	}
+}
+
+func TestOperationRecordsError(t *testing.T) {
+	m := New(service.New(counter.NewMemoryStore(), nil, nil, nil, nil), Options{})
+
+	terms := &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{Ints: &pb.IntPair{TermOne: 1}}}
+	op := wait(t, m, submit(t, m, "MagicDivide", terms).Name)
+	if code := codes.Code(op.GetError().GetCode()); code != codes.InvalidArgument {
+		t.Errorf("division by zero operation = %v; want an %v error", op, codes.InvalidArgument)
+	}
+}
+
+func TestOperationsAreAdmittedAndIntercepted(t *testing.T) {
+	var admitted, intercepted []string
+	m := New(service.New(counter.NewMemoryStore(), nil, nil, nil, nil), Options{
+		Admit: func(ctx context.Context, method string) error {
+			md, _ := metadata.FromIncomingContext(ctx)
+			admitted = append(admitted, method+" "+strings.Join(md.Get("x-caller-id"), ""))
+			if method == "MagicFindMax" {
+				return status.Error(codes.ResourceExhausted, "quota used up")
+			}
+			return nil
+		},
+		Interceptor: func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			intercepted = append(intercepted, info.FullMethod+" "+strings.Join(md.Get("x-caller-id"), ""))
+			return handler(ctx, req)
+		},
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-caller-id", "alice"))
+
+	add, _ := anypb.New(&pb.DoubleTerms{TermOne: 1, TermTwo: 2})
+	op, err := m.Submit(ctx, &pb.SubmitRequest{Method: "MagicAdd", Request: add})
+	if err != nil {
+		t.Fatalf("Submit(MagicAdd) returned error: %v", err)
+	}
+	if op, err = m.WaitOperation(ctx, &pb.WaitOperationRequest{Name: op.Name}); err != nil || !op.Done {
+		t.Fatalf("WaitOperation(%s) = %v, %v; want a done operation", op.Name, op, err)
+	}
+
+	max, _ := anypb.New(&pb.IntTerms{})
+	if _, err := m.Submit(ctx, &pb.SubmitRequest{Method: "MagicFindMax", Request: max}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Submit(MagicFindMax) returned %v; want the error from Admit", err)
+	}
+
+	if want := []string{"MagicAdd alice", "MagicFindMax alice"}; !slices.Equal(admitted, want) {
+		t.Errorf("admitted %q; want %q", admitted, want)
+	}
+	if want := []string{pb.MagicMath_MagicAdd_FullMethodName + " alice"}; !slices.Equal(intercepted, want) {
+		t.Errorf("intercepted %q; want %q", intercepted, want)
+	}
+}
+
+func TestOperationsBelongToTheirCaller(t *testing.T) {
+	m := New(service.New(counter.NewMemoryStore(), nil, nil, nil, nil), Options{})
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-caller-id", "alice"))
+	bob := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-caller-id", "bob"))
+
+	add, _ := anypb.New(&pb.DoubleTerms{TermOne: 1, TermTwo: 2})
+	op, err := m.Submit(alice, &pb.SubmitRequest{Method: "MagicAdd", Request: add})
+	if err != nil || op.Caller != "alice" {
+		t.Fatalf("Submit() = %v, %v; want an operation of alice", op, err)
+	}
+
+	if _, err := m.GetOperation(bob, &pb.OperationName{Name: op.Name}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOperation() by another caller returned %v; want %v", err, codes.NotFound)
+	}
+	if _, err := m.WaitOperation(bob, &pb.WaitOperationRequest{Name: op.Name}); status.Code(err) != codes.NotFound {
+		t.Errorf("WaitOperation() by another caller returned %v; want %v", err, codes.NotFound)
+	}
+	if _, err := m.CancelOperation(bob, &pb.OperationName{Name: op.Name}); status.Code(err) != codes.NotFound {
+		t.Errorf("CancelOperation() by another caller returned %v; want %v", err, codes.NotFound)
+	}
+	if list, err := m.ListOperations(bob, &pb.ListOperationsRequest{}); err != nil || len(list.Operations) != 0 {
+		t.Errorf("ListOperations() by another caller = %v, %v; want no operations", list, err)
+	}
+
+	if list, err := m.ListOperations(alice, &pb.ListOperationsRequest{}); err != nil || len(list.Operations) != 1 {
+		t.Errorf("ListOperations() by alice = %v, %v; want the operation of alice", list, err)
+	}
+	if got, err := m.GetOperation(alice, &pb.OperationName{Name: op.Name}); err != nil || got.Name != op.Name {
+		t.Errorf("GetOperation() by alice = %v, %v; want the operation of alice", got, err)
+	}
+}
+
+func TestSubmitRejectsInvalidRequests(t *testing.T) {
+	m := New(service.New(counter.NewMemoryStore(), nil, nil, nil, nil), Options{})
+	stats, _ := anypb.New(&pb.StatsRequest{})
+
+	var tests = []struct {
+		method  string
+		request *anypb.Any
+	}{
+		{"MagicNothing", stats},
+		{"MagicStatsStream", stats},
+		{"MagicAdd", stats},
+		{"MagicStats", nil},
+	}
+
+	for _, test := range tests {
+		_, err := m.Submit(context.Background(), &pb.SubmitRequest{Method: test.method, Request: test.request})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Submit(%s, %v) returned %v; want %v", test.method, test.request, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestWorkersAndQueueAreBounded(t *testing.T) {
+	server := newBlockingServer()
+	m := New(server, Options{Workers: 1, QueueSize: 1})
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+
+	running := submit(t, m, "MagicAdd", terms)
+	<-server.started
+	queued := submit(t, m, "MagicAdd", terms)
+
+	packed, _ := anypb.New(terms)
+	_, err := m.Submit(context.Background(), &pb.SubmitRequest{Method: "MagicAdd", Request: packed})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Submit() with a full queue returned %v; want %v", err, codes.ResourceExhausted)
+	}
+
+	// Only one operation runs at a time, so the queued one only starts once the first has finished.
+	select {
+	case <-server.started:
+		t.Fatalf("a second operation started while the only worker was busy")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(server.release)
+	for _, op := range []*pb.Operation{running, queued} {
+		if op = wait(t, m, op.Name); op.GetResponse() == nil {
+			t.Errorf("operation = %v; want a response", op)
+		}
+	}
+}
+
+func TestCancelOperation(t *testing.T) {
+	server := newBlockingServer()
+	defer close(server.release)
+	m := New(server, Options{Workers: 1})
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+
+	running := submit(t, m, "MagicAdd", terms)
+	<-server.started
+	queued := submit(t, m, "MagicAdd", terms)
+
+	for _, op := range []*pb.Operation{running, queued} {
+		cancelled, err := m.CancelOperation(context.Background(), &pb.OperationName{Name: op.Name})
+		if err != nil || !cancelled.Done || codes.Code(cancelled.GetError().GetCode()) != codes.Canceled {
+			t.Errorf("CancelOperation(%s) = %v, %v; want a cancelled operation", op.Name, cancelled, err)
+		}
+	}
+
+	// Cancelling the queued operation frees its place in the queue.
+	m.mutex.Lock()
+	queuedCount := m.queued
+	m.mutex.Unlock()
+	for deadline := time.Now().Add(5 * time.Second); queuedCount != 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+		m.mutex.Lock()
+		queuedCount = m.queued
+		m.mutex.Unlock()
+	}
+	if queuedCount != 0 {
+		t.Errorf("%d operations queued after cancelling; want 0", queuedCount)
+	}
+}
+
+func TestGetAndWaitOperation(t *testing.T) {
+	server := newBlockingServer()
+	defer close(server.release)
+	m := New(server, Options{})
+
+	if _, err := m.GetOperation(context.Background(), &pb.OperationName{Name: "operations/missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOperation() of a missing operation returned %v; want %v", err, codes.NotFound)
+	}
+
+	op := submit(t, m, "MagicAdd", &pb.DoubleTerms{})
+	waited, err := m.WaitOperation(context.Background(),
+		&pb.WaitOperationRequest{Name: op.Name, Timeout: durationpb.New(10 * time.Millisecond)})
+	if err != nil || waited.Done {
+		t.Errorf("WaitOperation() of a blocked operation = %v, %v; want an operation which isn't done", waited, err)
+	}
+	if got, err := m.GetOperation(context.Background(), &pb.OperationName{Name: op.Name}); err != nil || got.Name != op.Name {
+		t.Errorf("GetOperation(%s) = %v, %v", op.Name, got, err)
+	}
+}
+
+func TestListOperationsInPages(t *testing.T) {
+	m := New(service.New(counter.NewMemoryStore(), nil, nil, nil, nil), Options{})
+
+	var names []string
+	for range 5 {
+		names = append(names, submit(t, m, "MagicAdd", &pb.DoubleTerms{}).Name)
+	}
+
+	var listed []string
+	request := &pb.ListOperationsRequest{PageSize: 2}
+	for pages := 1; ; pages++ {
+		response, err := m.ListOperations(context.Background(), request)
+		if err != nil {
+			t.Fatalf("ListOperations() returned error: %v", err)
+		}
+		for _, op := range response.Operations {
+			listed = append(listed, op.Name)
+		}
+		if response.NextPageToken == "" {
+			if pages != 3 {
+				t.Errorf("listed %d pages; want 3", pages)
+			}
+			break
+		}
+		request.PageToken = response.NextPageToken
+	}
+
+	if len(listed) != len(names) {
+		t.Fatalf("listed %v; want %v", listed, names)
+	}
+	for i := range names {
+		if listed[i] != names[i] {
+			t.Errorf("listed %v; want %v", listed, names)
+			break
+		}
+	}
+
+	if _, err := m.ListOperations(context.Background(), &pb.ListOperationsRequest{PageToken: "x"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListOperations() with an invalid token returned %v; want %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestOperationsExpire(t *testing.T) {
+	m := New(service.New(counter.NewMemoryStore(), nil, nil, nil, nil), Options{Expiry: time.Minute})
+	now := time.Now()
+	m.mutex.Lock()
+	m.now = func() time.Time { return now }
+	m.mutex.Unlock()
+
+	op := wait(t, m, submit(t, m, "MagicAdd", &pb.DoubleTerms{}).Name)
+
+	m.mutex.Lock()
+	now = op.EndTime.AsTime().Add(time.Minute)
+	m.mutex.Unlock()
+	if _, err := m.GetOperation(context.Background(), &pb.OperationName{Name: op.Name}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetOperation() of an expired operation returned %v; want %v", err, codes.NotFound)
+	}
+}
+
+func TestOperationsSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "operations.jsonl")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() returned error: %v", err)
+	}
+	magicMath := service.New(counter.NewMemoryStore(), nil, nil, nil, nil)
+	m := New(magicMath, Options{Store: store})
+
+	op := wait(t, m, submit(t, m, "MagicFindMax", &pb.IntTerms{TermOne: 4, TermTwo: 9, TermThree: 2}).Name)
+	store.Close()
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore() returned error: %v", err)
+	}
+	defer reopened.Close()
+	restarted := New(magicMath, Options{Store: reopened})
+
+	got, err := restarted.GetOperation(context.Background(), &pb.OperationName{Name: op.Name})
+	result := &pb.IntResult{}
+	if err != nil || !got.Done || got.GetResponse().UnmarshalTo(result) != nil || result.Result != 9 {
+		t.Errorf("GetOperation(%s) after a restart = %v, %v; want the result 9", op.Name, got, err)
+	}
+}
@@ -0,0 +1,121 @@
+package operations
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// FileStore is a Store which appends each operation which is done to a file, as a line of JSON, so that the results of
+// operations survive a restart.
+type FileStore struct {
+	path string
+
+	// This mutex is used to protect writes to the file.
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// OpenFileStore opens the store kept in the file, creating the file if it doesn't exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileStore{path: path, file: file}, nil
+}
+
+// Load returns the operations in the file. Lines which can't be read, such as a line the server stopped halfway
+// through writing, are skipped.
+func (s *FileStore) Load() ([]*pb.Operation, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var operations []*pb.Operation
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		operation := &pb.Operation{}
+		if err := protojson.Unmarshal(scanner.Bytes(), operation); err != nil {
+			log.Printf("skipping line %d of %s: %v", line, s.path, err)
+			continue
+		}
+		operations = append(operations, operation)
+	}
+
+	return operations, scanner.Err()
+}
+
+// Save appends an operation to the file.
+func (s *FileStore) Save(operation *pb.Operation) error {
+	line, err := protojson.Marshal(operation)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Compact rewrites the file with only the given operations, replacing the old file in one step so that a crash can't
+// lose operations.
+func (s *FileStore) Compact(operations []*pb.Operation) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	compacted := s.path + ".tmp"
+	file, err := os.OpenFile(compacted, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	for _, operation := range operations {
+		line, err := protojson.Marshal(operation)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		writer.Write(append(line, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := os.Rename(compacted, s.path); err != nil {
+		file.Close()
+		return err
+	}
+
+	s.file.Close()
+	s.file = file
+	return nil
+}
+
+// Close closes the file.
+func (s *FileStore) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.file.Close()
+}
@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// tokenBucket allows calls at a steady rate, with bursts of up to its size. It holds tokens which refill at the
+// rate, and each call takes one. It isn't safe for concurrent use, the limiter guards it with its mutex.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// This function creates a full bucket for the limit.
+func newTokenBucket(limit Limit, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: limit.Rate, burst: float64(limit.Burst), tokens: float64(limit.Burst), last: now}
+}
+
+// This function refills the bucket for the time since it was last used,
+// and returns how long to wait until it holds a token. It returns zero if a token is available now.
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	if now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		return 0
+	}
+	if b.rate <= 0 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+}
+
+// This function takes a token, which wait must have just reported as available.
+func (b *tokenBucket) take() {
+	b.tokens--
+}
+
+// This function reports whether the bucket has refilled completely, so that dropping it changes nothing.
+func (b *tokenBucket) full(now time.Time) bool {
+	return b.wait(now) == 0 && b.tokens >= b.burst
+}
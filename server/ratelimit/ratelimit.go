@@ -0,0 +1,291 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/methods"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Any is the key of the limit which applies to every method, or every caller, without a limit of its own.
+const Any = "*"
+
+// The most buckets kept before the full ones, which belong to callers who have gone quiet, are dropped.
+const maxBuckets = 10000
+
+// Limit is a token bucket rate limit: calls are allowed at Rate per second on average, in bursts of up to Burst calls.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseLimits parses a comma separated list of limits, each written as "name=rate" or "name=rate:burst",
+// for example "MagicAdd=100:200,*=1000". The burst defaults to the rate, rounded up.
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	if spec == "" {
+		return limits, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		name, rawLimit, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid limit %q, expected name=rate[:burst]", entry)
+		}
+
+		rawRate, rawBurst, hasBurst := strings.Cut(rawLimit, ":")
+		rate, err := strconv.ParseFloat(rawRate, 64)
+		if err != nil || !(rate > 0) || math.IsInf(rate, 0) {
+			return nil, fmt.Errorf("invalid rate in limit %q, expected a positive number of calls per second", entry)
+		}
+
+		burst := int(math.Ceil(rate))
+		if hasBurst {
+			burst, err = strconv.Atoi(rawBurst)
+			if err != nil || burst < 1 {
+				return nil, fmt.Errorf("invalid burst in limit %q, expected a positive whole number of calls", entry)
+			}
+		}
+
+		limits[name] = Limit{Rate: rate, Burst: burst}
+	}
+
+	return limits, nil
+}
+
+// Config chooses which limits and quotas the limiter enforces. Each caller, as identified by caller.ID,
+// is limited separately. Zero values mean no limit.
+type Config struct {
+	// Methods limits how fast each caller may call a method. It is keyed by the method's name, for example
+	// "MagicAdd", and the limit under Any applies to every method without its own limit.
+	Methods map[string]Limit
+	// Callers limits how fast a caller may make calls to all methods together. It is keyed by the caller's
+	// identity, and the limit under Any applies to every caller without its own limit.
+	Callers map[string]Limit
+	// HourlyQuota and DailyQuota are how many calls each caller may make per clock hour and per day, in UTC.
+	HourlyQuota int64
+	DailyQuota  int64
+}
+
+// Usage describes how much of its quotas a caller has used.
+type Usage struct {
+	Caller      string
+	HourlyLimit int64
+	HourlyUsed  int64
+	HourlyReset time.Time
+	DailyLimit  int64
+	DailyUsed   int64
+	DailyReset  time.Time
+}
+
+// Limiter enforces rate limits and quotas on MagicMath and VectorMath calls. The rate limits are kept in memory, while
+// the quotas are counted in a counter store, so that servers in a cluster share them.
+// Quota counters are named after their window, for example "quota/hour/2026-10-19T13/alice". Once an hour the
+// counters of the windows which have ended are dropped, if the store is a counter.Pruner.
+type Limiter struct {
+	config   Config
+	counters counter.Store
+	// now returns the current time, tests replace it to control the clock.
+	now func() time.Time
+
+	// This mutex is used to protect the buckets and nextPrune, and to make checking and counting a quota one step.
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+	// nextPrune is when the quota counters of the windows which have ended are next dropped.
+	nextPrune time.Time
+}
+
+// New creates a limiter for the config, which counts quota usage in the given store.
+func New(config Config, counters counter.Store) *Limiter {
+	return &Limiter{config: config, counters: counters, now: time.Now, buckets: make(map[string]*tokenBucket)}
+}
+
+// UnaryServerInterceptor rejects MagicMath and VectorMath calls over a rate limit or quota with
+// codes.ResourceExhausted. The error carries a RetryInfo detail saying when to try again, and a QuotaFailure detail if
+// a quota ran out. GetQuota is never limited, so that callers can always see why they are being rejected.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !methods.IsMath(info.FullMethod) || info.FullMethod == pb.MagicMath_GetQuota_FullMethodName {
+			return handler(ctx, req)
+		}
+
+		if err := l.allow(caller.ID(ctx), path.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Allow decides whether the caller making the call may call a MagicMath or VectorMath method now, given by its name
+// such as "MagicAdd", and if so counts the call against the caller's limits and quotas, just like the interceptor does.
+// The Operations service uses it to charge for operations when they are submitted.
+func (l *Limiter) Allow(ctx context.Context, method string) error {
+	if method == path.Base(pb.MagicMath_GetQuota_FullMethodName) {
+		return nil
+	}
+
+	return l.allow(caller.ID(ctx), method)
+}
+
+// Usage returns how much of its quotas the caller making the call has used.
+func (l *Limiter) Usage(ctx context.Context) Usage {
+	now := l.now().UTC()
+	id := caller.ID(ctx)
+	hourName, hourReset := hourWindow(id, now)
+	dayName, dayReset := dayWindow(id, now)
+
+	return Usage{
+		Caller:      id,
+		HourlyLimit: l.config.HourlyQuota,
+		HourlyUsed:  l.counters.Count(hourName),
+		HourlyReset: hourReset,
+		DailyLimit:  l.config.DailyQuota,
+		DailyUsed:   l.counters.Count(dayName),
+		DailyReset:  dayReset,
+	}
+}
+
+// This function decides whether the caller may call the method now, and if so takes a token from each of its buckets
+// and counts the call against its quotas. Nothing is used up by a call which is rejected.
+func (l *Limiter) allow(id, method string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.pruneBuckets(now)
+	l.pruneQuotas(now)
+
+	var buckets []*tokenBucket
+	if limit, ok := lookup(l.config.Callers, id); ok {
+		buckets = append(buckets, l.bucket("caller/"+id, limit, now))
+	}
+	if limit, ok := lookup(l.config.Methods, method); ok {
+		buckets = append(buckets, l.bucket("method/"+method+"/"+id, limit, now))
+	}
+
+	var wait time.Duration
+	for _, bucket := range buckets {
+		wait = max(wait, bucket.wait(now))
+	}
+	if wait > 0 {
+		return exhausted(fmt.Sprintf("rate limit exceeded for %s calling %s", id, method), wait, nil)
+	}
+
+	utc := now.UTC()
+	hourName, hourReset := hourWindow(id, utc)
+	dayName, dayReset := dayWindow(id, utc)
+	if l.config.HourlyQuota > 0 && l.counters.Count(hourName) >= l.config.HourlyQuota {
+		return exhausted(fmt.Sprintf("hourly quota of %d calls used up by %s", l.config.HourlyQuota, id),
+			hourReset.Sub(utc), &errdetails.QuotaFailure_Violation{Subject: id, Description: "hourly call quota"})
+	}
+	if l.config.DailyQuota > 0 && l.counters.Count(dayName) >= l.config.DailyQuota {
+		return exhausted(fmt.Sprintf("daily quota of %d calls used up by %s", l.config.DailyQuota, id),
+			dayReset.Sub(utc), &errdetails.QuotaFailure_Violation{Subject: id, Description: "daily call quota"})
+	}
+
+	for _, bucket := range buckets {
+		bucket.take()
+	}
+	if l.config.HourlyQuota > 0 {
+		l.counters.Increment(hourName)
+	}
+	if l.config.DailyQuota > 0 {
+		l.counters.Increment(dayName)
+	}
+
+	return nil
+}
+
+// This function returns the bucket with the given key, creating a full one if it doesn't exist yet.
+func (l *Limiter) bucket(key string, limit Limit, now time.Time) *tokenBucket {
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(limit, now)
+		l.buckets[key] = bucket
+	}
+
+	return bucket
+}
+
+// This function drops every full bucket once there are too many, since a full bucket is the same as a new one.
+func (l *Limiter) pruneBuckets(now time.Time) {
+	if len(l.buckets) < maxBuckets {
+		return
+	}
+
+	for key, bucket := range l.buckets {
+		if bucket.full(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// This function drops the quota counters of the windows which have ended, at most once an hour. The names of the
+// windows sort in time order, so a counter has expired when its name sorts before the current window's prefix.
+func (l *Limiter) pruneQuotas(now time.Time) {
+	pruner, ok := l.counters.(counter.Pruner)
+	if !ok || now.Before(l.nextPrune) {
+		return
+	}
+	utc := now.UTC()
+	l.nextPrune = utc.Truncate(time.Hour).Add(time.Hour)
+
+	hourPrefix, _ := hourWindow("", utc)
+	pruner.Prune("quota/hour/", func(name string) bool { return name < hourPrefix })
+	dayPrefix, _ := dayWindow("", utc)
+	pruner.Prune("quota/day/", func(name string) bool { return name < dayPrefix })
+}
+
+// This function returns the limit for name, or the limit under Any if name doesn't have one.
+func lookup(limits map[string]Limit, name string) (Limit, bool) {
+	if limit, ok := limits[name]; ok {
+		return limit, true
+	}
+	limit, ok := limits[Any]
+
+	return limit, ok
+}
+
+// This function returns the name of the caller's counter for the current hour, and when the hour ends.
+func hourWindow(id string, now time.Time) (string, time.Time) {
+	start := now.Truncate(time.Hour)
+	return "quota/hour/" + start.Format("2006-01-02T15") + "/" + id, start.Add(time.Hour)
+}
+
+// This function returns the name of the caller's counter for the current day, and when the day ends.
+func dayWindow(id string, now time.Time) (string, time.Time) {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return "quota/day/" + start.Format("2006-01-02") + "/" + id, start.AddDate(0, 0, 1)
+}
+
+// This function creates the ResourceExhausted error, telling the caller to retry after the delay.
+func exhausted(message string, retryDelay time.Duration, violation *errdetails.QuotaFailure_Violation) error {
+	details := []protoadapt.MessageV1{&errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)}}
+	if violation != nil {
+		details = append(details, &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{violation}})
+	}
+
+	st, err := status.New(codes.ResourceExhausted, message).WithDetails(details...)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, message)
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,224 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("MagicAdd=100:200, *=2.5")
+	if err != nil {
+		t.Fatalf("ParseLimits() returned error: %v", err)
+	}
+	if got, want := limits["MagicAdd"], (Limit{Rate: 100, Burst: 200}); got != want {
+		t.Errorf("limits[MagicAdd] = %v; want %v", got, want)
+	}
+	if got, want := limits[Any], (Limit{Rate: 2.5, Burst: 3}); got != want {
+		t.Errorf("limits[*] = %v; want %v", got, want)
+	}
+
+	for _, spec := range []string{"MagicAdd", "=5", "MagicAdd=fast", "MagicAdd=0", "MagicAdd=5:0", "MagicAdd=5:x"} {
+		if _, err := ParseLimits(spec); err == nil {
+			t.Errorf("ParseLimits(%q) returned no error", spec)
+		}
+	}
+}
+
+// fakeClock is a clock which only moves when the test moves it.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time             { return c.now }
+func (c *fakeClock) Advance(step time.Duration) { c.now = c.now.Add(step) }
+
+func newTestLimiter(config Config) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 10, 19, 13, 59, 0, 0, time.UTC)}
+	limiter := New(config, counter.NewMemoryStore())
+	limiter.now = clock.Now
+
+	return limiter, clock
+}
+
+// This function returns the retry delay in an error, or -1 if it has none.
+func retryDelay(err error) time.Duration {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.RetryDelay.AsDuration()
+		}
+	}
+
+	return -1
+}
+
+func TestMethodLimit(t *testing.T) {
+	limiter, clock := newTestLimiter(Config{Methods: map[string]Limit{"MagicAdd": {Rate: 2, Burst: 3}}})
+
+	// The burst is allowed at once, then the bucket is empty.
+	for i := range 3 {
+		if err := limiter.allow("alice", "MagicAdd"); err != nil {
+			t.Fatalf("call %d returned error: %v", i, err)
+		}
+	}
+	err := limiter.allow("alice", "MagicAdd")
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("call over the burst returned %v; want ResourceExhausted", err)
+	}
+	if got, want := retryDelay(err), 500*time.Millisecond; got != want {
+		t.Errorf("retry delay = %v; want %v", got, want)
+	}
+
+	// Other callers, and methods without a limit, are unaffected.
+	if err := limiter.allow("bob", "MagicAdd"); err != nil {
+		t.Errorf("another caller returned error: %v", err)
+	}
+	if err := limiter.allow("alice", "MagicSubtract"); err != nil {
+		t.Errorf("an unlimited method returned error: %v", err)
+	}
+
+	// Half a second later one more token has been added.
+	clock.Advance(500 * time.Millisecond)
+	if err := limiter.allow("alice", "MagicAdd"); err != nil {
+		t.Errorf("call after refill returned error: %v", err)
+	}
+	if err := limiter.allow("alice", "MagicAdd"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call after refill returned %v; want ResourceExhausted", err)
+	}
+}
+
+func TestCallerLimit(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{Callers: map[string]Limit{
+		"batch": {Rate: 1, Burst: 1},
+		Any:     {Rate: 1, Burst: 2},
+	}})
+
+	// A caller's limit covers all methods together.
+	tests := []struct {
+		caller, method string
+		want           codes.Code
+	}{
+		{"batch", "MagicAdd", codes.OK},
+		{"batch", "MagicFindMin", codes.ResourceExhausted},
+		{"alice", "MagicAdd", codes.OK},
+		{"alice", "MagicFindMin", codes.OK},
+		{"alice", "MagicFindMax", codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		if got := status.Code(limiter.allow(tt.caller, tt.method)); got != tt.want {
+			t.Errorf("allow(%q, %q) = %v; want %v", tt.caller, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestRejectedCallUsesNothing(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{
+		Callers: map[string]Limit{Any: {Rate: 1, Burst: 10}},
+		Methods: map[string]Limit{"MagicAdd": {Rate: 1, Burst: 1}},
+	})
+
+	limiter.allow("alice", "MagicAdd")
+	for range 5 {
+		limiter.allow("alice", "MagicAdd") // rejected by the method limit
+	}
+
+	// The rejected calls didn't take tokens from the caller's bucket, so nine calls are still allowed.
+	for i := range 9 {
+		if err := limiter.allow("alice", "MagicSubtract"); err != nil {
+			t.Fatalf("call %d returned error: %v", i, err)
+		}
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	limiter, clock := newTestLimiter(Config{HourlyQuota: 2, DailyQuota: 3})
+
+	limiter.allow("alice", "MagicAdd")
+	limiter.allow("alice", "MagicAdd")
+	err := limiter.allow("alice", "MagicAdd")
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("call over the hourly quota returned %v; want ResourceExhausted", err)
+	}
+	// The clock is at 13:59, so the hour resets in a minute.
+	if got, want := retryDelay(err), time.Minute; got != want {
+		t.Errorf("retry delay = %v; want %v", got, want)
+	}
+
+	var violation *errdetails.QuotaFailure_Violation
+	for _, detail := range status.Convert(err).Details() {
+		if failure, ok := detail.(*errdetails.QuotaFailure); ok {
+			violation = failure.Violations[0]
+		}
+	}
+	if violation == nil || violation.Subject != "alice" {
+		t.Errorf("quota failure = %v; want a violation for alice", violation)
+	}
+
+	// In the next hour the daily quota runs out after one more call.
+	clock.Advance(time.Minute)
+	if err := limiter.allow("alice", "MagicAdd"); err != nil {
+		t.Errorf("call in the next hour returned error: %v", err)
+	}
+	if err := limiter.allow("alice", "MagicAdd"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("call over the daily quota returned %v; want ResourceExhausted", err)
+	}
+
+	usage := limiter.Usage(metadata.NewIncomingContext(context.Background(), metadata.Pairs(caller.MetadataKey, "alice")))
+	want := Usage{
+		Caller:      "alice",
+		HourlyLimit: 2,
+		HourlyUsed:  1,
+		HourlyReset: time.Date(2026, 10, 19, 15, 0, 0, 0, time.UTC),
+		DailyLimit:  3,
+		DailyUsed:   3,
+		DailyReset:  time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+	}
+	if usage != want {
+		t.Errorf("Usage() = %+v; want %+v", usage, want)
+	}
+}
+
+func TestAllow(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{HourlyQuota: 1})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(caller.MetadataKey, "alice"))
+
+	if err := limiter.Allow(ctx, "MagicAdd"); err != nil {
+		t.Errorf("Allow(MagicAdd) returned error: %v", err)
+	}
+	if err := limiter.Allow(ctx, "MagicAdd"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Allow(MagicAdd) over the quota returned %v; want ResourceExhausted", err)
+	}
+	if err := limiter.Allow(ctx, "GetQuota"); err != nil {
+		t.Errorf("Allow(GetQuota) returned error: %v; want GetQuota to never be limited", err)
+	}
+}
+
+func TestQuotasOfEndedWindowsArePruned(t *testing.T) {
+	limiter, clock := newTestLimiter(Config{HourlyQuota: 10, DailyQuota: 10})
+	counters := limiter.counters
+
+	limiter.allow("alice", "MagicAdd")
+	hourName, _ := hourWindow("alice", clock.Now())
+	dayName, _ := dayWindow("alice", clock.Now())
+
+	// The clock is at 13:59, so a minute later the hour has ended but the day hasn't.
+	clock.Advance(time.Minute)
+	limiter.allow("bob", "MagicAdd")
+	if hour, day := counters.Count(hourName), counters.Count(dayName); hour != 0 || day != 1 {
+		t.Errorf("counts of the ended hour and the current day = %d, %d; want 0, 1", hour, day)
+	}
+
+	// Ten hours later the day has ended too.
+	clock.Advance(10 * time.Hour)
+	limiter.allow("bob", "MagicAdd")
+	if day := counters.Count(dayName); day != 0 {
+		t.Errorf("count of the ended day = %d; want 0", day)
+	}
+}
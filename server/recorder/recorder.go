@@ -0,0 +1,63 @@
+package recorder
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/karldmenzel/go-grpc-client-server/capture"
+	"github.com/karldmenzel/go-grpc-client-server/server/methods"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryServerInterceptor records every MagicMath and VectorMath call, with its request, response, status and latency,
+// to the capture writer. A call which can't be recorded is still served, and the problem is logged.
+func UnaryServerInterceptor(writer *capture.Writer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !methods.IsMath(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		latency := time.Since(start)
+
+		callStatus := status.Convert(err)
+		record := &capture.Record{
+			Method:       info.FullMethod,
+			Time:         start,
+			Request:      toJSON(req),
+			Code:         callStatus.Code().String(),
+			Message:      callStatus.Message(),
+			LatencyNanos: latency.Nanoseconds(),
+		}
+		if err == nil {
+			record.Response = toJSON(resp)
+		}
+
+		if writeErr := writer.Write(record); writeErr != nil {
+			log.Printf("failed to record call to %s: %v", info.FullMethod, writeErr)
+		}
+
+		return resp, err
+	}
+}
+
+// This function converts a message to the protobuf JSON format, or returns nil if it isn't a message.
+func toJSON(message any) []byte {
+	protoMessage, ok := message.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	encoded, err := protojson.Marshal(protoMessage)
+	if err != nil {
+		return nil
+	}
+
+	return encoded
+}
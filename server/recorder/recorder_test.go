@@ -0,0 +1,78 @@
+package recorder
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/karldmenzel/go-grpc-client-server/capture"
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRecordsEveryMagicMathCall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	writer, err := capture.NewWriter(path, 0, 0)
+	if err != nil {
+		t.Fatalf("NewWriter() returned error: %v", err)
+	}
+
+	// A failing call must be recorded with its status.
+	failMax := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if info.FullMethod == pb.MagicMath_MagicFindMax_FullMethodName {
+			return nil, status.Error(codes.InvalidArgument, "no max today")
+		}
+		return handler(ctx, req)
+	}
+	client := servertest.Start(t, servertest.WithUnaryInterceptors(UnaryServerInterceptor(writer), failMax)).Client
+
+	ctx := context.Background()
+	client.MagicAdd(ctx, &pb.DoubleTerms{TermOne: 1.5, TermTwo: 2})
+	client.MagicFindMax(ctx, &pb.IntTerms{TermOne: 1, TermTwo: 2, TermThree: 3})
+	client.GetAddCount(ctx, &pb.Empty{})
+	writer.Close()
+
+	records, err := capture.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() returned error: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("recorded %d calls; want 3", len(records))
+	}
+
+	tests := []struct {
+		method            string
+		request, response proto.Message
+		code              codes.Code
+	}{
+		{pb.MagicMath_MagicAdd_FullMethodName, &pb.DoubleTerms{TermOne: 1.5, TermTwo: 2}, &pb.DoubleResult{Result: 3.5}, codes.OK},
+		{pb.MagicMath_MagicFindMax_FullMethodName, &pb.IntTerms{TermOne: 1, TermTwo: 2, TermThree: 3}, nil, codes.InvalidArgument},
+		{pb.MagicMath_GetAddCount_FullMethodName, &pb.Empty{}, &pb.Count{Count: 1}, codes.OK},
+	}
+
+	for i, tt := range tests {
+		record := records[i]
+		if record.Method != tt.method || !holds(record.Request, tt.request) || !holds(record.Response, tt.response) ||
+			record.StatusCode() != tt.code || record.Time.IsZero() || record.LatencyNanos <= 0 {
+			t.Errorf("record %d = %+v; want method %s, request %s, response %s, code %s",
+				i, record, tt.method, tt.request, tt.response, tt.code)
+		}
+	}
+}
+
+// This function reports whether the JSON holds the message, however the JSON is spaced and its fields are ordered.
+// A nil message is only held by empty JSON.
+func holds(raw []byte, want proto.Message) bool {
+	if want == nil {
+		return len(raw) == 0
+	}
+
+	got := want.ProtoReflect().New().Interface()
+	return protojson.Unmarshal(raw, got) == nil && proto.Equal(got, want)
+}
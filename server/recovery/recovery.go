@@ -0,0 +1,175 @@
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Report is a crash report, written as a single line of JSON in the crash report file.
+type Report struct {
+	// ID identifies the report, it is also sent to the client in the error message.
+	ID string `json:"id"`
+	// Time is when the panic happened.
+	Time time.Time `json:"time"`
+	// Method is the full gRPC method name, for example "/shared.MagicMath/MagicAdd".
+	Method string `json:"method"`
+	// Caller is the identity of the client which made the call.
+	Caller string `json:"caller"`
+	// Request is the request message in the protobuf JSON format, it is empty for streaming calls.
+	Request json.RawMessage `json:"request,omitempty"`
+	// Panic is the value the handler panicked with, and Stack the go routine's stack trace at the time.
+	Panic string `json:"panic"`
+	Stack string `json:"stack"`
+}
+
+// Options configures the recoverer. Every field is optional.
+type Options struct {
+	// Counters counts the panics under counter.Panic.
+	Counters counter.Store
+	// ReportFile is the file crash reports are appended to. If it is empty, crash reports are only logged.
+	ReportFile string
+	// Health is the server's health service, every service of which is set to NOT_SERVING once UnhealthyAfter panics
+	// have happened, so that load balancers and orchestrators stop sending calls to the server and restart it.
+	// Zero means the health check never fails because of panics.
+	Health         *health.Server
+	UnhealthyAfter int64
+}
+
+// Recoverer turns panics in handlers into codes.Internal errors, instead of letting them take down the server.
+// Each panic is counted and written to a crash report.
+type Recoverer struct {
+	options Options
+	// panics counts the panics in this server, whatever counter store is used.
+	panics atomic.Int64
+
+	// This mutex is used to protect the crash report file, so that reports are never interleaved.
+	mutex sync.Mutex
+}
+
+// New creates a recoverer with the given options.
+func New(options Options) *Recoverer {
+	return &Recoverer{options: options}
+}
+
+// UnaryServerInterceptor recovers from panics in unary calls.
+func (r *Recoverer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				resp, err = nil, r.recovered(ctx, info.FullMethod, req, value)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor recovers from panics in streaming calls.
+func (r *Recoverer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				err = r.recovered(stream.Context(), info.FullMethod, nil, value)
+			}
+		}()
+
+		return handler(srv, stream)
+	}
+}
+
+// Panics returns how many panics this server has recovered from.
+func (r *Recoverer) Panics() int64 {
+	return r.panics.Load()
+}
+
+// This function handles a recovered panic: it writes the crash report, counts the panic, fails the health check
+// if there have been too many, and returns the error the call fails with.
+func (r *Recoverer) recovered(ctx context.Context, method string, req any, value any) error {
+	now := time.Now()
+	report := &Report{
+		ID:      fmt.Sprintf("%x", now.UnixNano()),
+		Time:    now,
+		Method:  method,
+		Caller:  caller.ID(ctx),
+		Request: toJSON(req),
+		Panic:   fmt.Sprint(value),
+		Stack:   string(debug.Stack()),
+	}
+
+	log.Printf("recovered from panic in %s, crash report %s: %s", method, report.ID, report.Panic)
+	if err := r.writeReport(report); err != nil {
+		log.Printf("failed to write crash report %s: %v\n%s", report.ID, err, report.Stack)
+	}
+
+	if r.options.Counters != nil {
+		r.options.Counters.Increment(counter.Panic)
+	}
+
+	panics := r.panics.Add(1)
+	if r.options.Health != nil && r.options.UnhealthyAfter > 0 && panics == r.options.UnhealthyAfter {
+		log.Printf("%d panics, marking the server as not serving", panics)
+		// Shutdown marks every service the health server knows as not serving, whichever services this server has,
+		// and keeps them that way until the server is restarted.
+		r.options.Health.Shutdown()
+	}
+
+	return status.Errorf(codes.Internal, "internal error, see crash report %s", report.ID)
+}
+
+// This function appends the crash report to the crash report file, if there is one.
+func (r *Recoverer) writeReport(report *Report) error {
+	if r.options.ReportFile == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	file, err := os.OpenFile(r.options.ReportFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// This function converts a message to the protobuf JSON format, or returns nil if it isn't a message.
+func toJSON(message any) []byte {
+	protoMessage, ok := message.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	encoded, err := protojson.Marshal(protoMessage)
+	if err != nil {
+		return nil
+	}
+
+	return encoded
+}
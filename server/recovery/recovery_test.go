@@ -0,0 +1,148 @@
+package recovery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	pbv2 "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var addInfo = &grpc.UnaryServerInfo{FullMethod: pb.MagicMath_MagicAdd_FullMethodName}
+
+func panicking(context.Context, any) (any, error) {
+	panic("division by zero")
+}
+
+// This function reads every crash report in the file.
+func readReports(t *testing.T, path string) []Report {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open crash report file: %v", err)
+	}
+	defer file.Close()
+
+	var reports []Report
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var report Report
+		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil {
+			t.Fatalf("invalid crash report %s: %v", scanner.Text(), err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+func TestUnaryPanicBecomesInternalError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crashes.jsonl")
+	counters := counter.NewMemoryStore()
+	recoverer := New(Options{Counters: counters, ReportFile: path})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(caller.MetadataKey, "alice"))
+	_, err := recoverer.UnaryServerInterceptor()(ctx, &pb.DoubleTerms{TermOne: 1, TermTwo: 0}, addInfo, panicking)
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("panicking call returned %v; want Internal", err)
+	}
+
+	reports := readReports(t, path)
+	if len(reports) != 1 {
+		t.Fatalf("wrote %d crash reports; want 1", len(reports))
+	}
+	report := reports[0]
+	request := &pb.DoubleTerms{}
+	if err := protojson.Unmarshal(report.Request, request); err != nil {
+		t.Errorf("crash report request %s isn't a DoubleTerms: %v", report.Request, err)
+	}
+	if report.Method != pb.MagicMath_MagicAdd_FullMethodName || report.Caller != "alice" ||
+		!proto.Equal(request, &pb.DoubleTerms{TermOne: 1}) || report.Panic != "division by zero" {
+		t.Errorf("crash report = %+v; want the method, caller, request and panic", report)
+	}
+	if !strings.Contains(report.Stack, "panicking") {
+		t.Errorf("crash report stack doesn't mention the panicking function:\n%s", report.Stack)
+	}
+	if !strings.Contains(status.Convert(err).Message(), report.ID) {
+		t.Errorf("error message %q doesn't mention crash report %s", status.Convert(err).Message(), report.ID)
+	}
+
+	if got := counters.Count(counter.Panic); got != 1 {
+		t.Errorf("panic count = %d; want 1", got)
+	}
+}
+
+func TestCallsWithoutPanicPassThrough(t *testing.T) {
+	recoverer := New(Options{})
+
+	resp, err := recoverer.UnaryServerInterceptor()(context.Background(), nil, addInfo, func(context.Context, any) (any, error) {
+		return "result", status.Error(codes.InvalidArgument, "bad")
+	})
+	if resp != "result" || status.Code(err) != codes.InvalidArgument || recoverer.Panics() != 0 {
+		t.Errorf("call returned %v, %v with %d panics; want the handler's result and error, and no panics",
+			resp, err, recoverer.Panics())
+	}
+}
+
+// fakeStream is a server stream which only has a context.
+type fakeStream struct {
+	grpc.ServerStream
+}
+
+func (fakeStream) Context() context.Context { return context.Background() }
+
+func TestStreamPanicBecomesInternalError(t *testing.T) {
+	recoverer := New(Options{})
+
+	err := recoverer.StreamServerInterceptor()(nil, fakeStream{}, &grpc.StreamServerInfo{FullMethod: "/shared.MagicMath/Stream"},
+		func(any, grpc.ServerStream) error { panic("stream broke") })
+	if status.Code(err) != codes.Internal || recoverer.Panics() != 1 {
+		t.Errorf("panicking stream returned %v with %d panics; want Internal and 1 panic", err, recoverer.Panics())
+	}
+}
+
+func TestHealthFailsAfterThreshold(t *testing.T) {
+	services := []string{"", pb.MagicMath_ServiceDesc.ServiceName, pbv2.MagicMath_ServiceDesc.ServiceName,
+		pb.VectorMath_ServiceDesc.ServiceName, pb.Operations_ServiceDesc.ServiceName}
+	healthServer := health.NewServer()
+	for _, service := range services[1:] {
+		healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+	recoverer := New(Options{Health: healthServer, UnhealthyAfter: 3})
+
+	for i := 1; i <= 4; i++ {
+		recoverer.UnaryServerInterceptor()(context.Background(), nil, addInfo, panicking)
+
+		want := healthpb.HealthCheckResponse_SERVING
+		if i >= 3 {
+			want = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		for _, service := range services {
+			response, err := healthServer.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatalf("Check(%q) returned error: %v", service, err)
+			}
+			if response.Status != want {
+				t.Errorf("after %d panics Check(%q) = %v; want %v", i, service, response.Status, want)
+			}
+		}
+	}
+}
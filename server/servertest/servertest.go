@@ -0,0 +1,196 @@
+package servertest
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/app"
+	"github.com/karldmenzel/go-grpc-client-server/server/cache"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/faults"
+	"github.com/karldmenzel/go-grpc-client-server/server/idempotency"
+	"github.com/karldmenzel/go-grpc-client-server/server/loadshed"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/bignum"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/decimal"
+	"github.com/karldmenzel/go-grpc-client-server/server/operations"
+	"github.com/karldmenzel/go-grpc-client-server/server/ratelimit"
+	"github.com/karldmenzel/go-grpc-client-server/server/recovery"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// The size of the in-memory connection buffer, large enough that tests never block on it.
+const bufferSize = 1 << 20
+
+// Server is a fully configured MagicMath server running in memory, along with a client connected to it.
+type Server struct {
+	// Client is connected to the server and ready to use.
+	Client pb.MagicMathClient
+	// Conn is the connection Client uses, for creating clients of the server's other services.
+	Conn *grpc.ClientConn
+	// GRPCServer is the server itself.
+	GRPCServer *grpc.Server
+	// Counters is the store the server keeps its function counters in.
+	Counters counter.Store
+	// Health is the server's health service.
+	Health *health.Server
+
+	listener          *bufconn.Listener
+	clientCredentials credentials.TransportCredentials
+}
+
+// Option changes how the server is configured.
+type Option func(*settings)
+
+type settings struct {
+	config            app.Config
+	clientCredentials credentials.TransportCredentials
+}
+
+// WithCounterStore makes the server keep its function counters in the given store.
+func WithCounterStore(store counter.Store) Option {
+	return func(s *settings) { s.config.Counters = store }
+}
+
+// WithRateLimiter enforces the limiter's rate limits and quotas on the server.
+func WithRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(s *settings) { s.config.RateLimiter = limiter }
+}
+
+// WithDeduplicator answers duplicate calls with the same idempotency key with the response of the first one.
+func WithDeduplicator(deduplicator *idempotency.Deduplicator) Option {
+	return func(s *settings) { s.config.Deduplicator = deduplicator }
+}
+
+// WithConcurrencyLimiter limits how many calls the server runs at once with the limiter.
+func WithConcurrencyLimiter(limiter *loadshed.Limiter) Option {
+	return func(s *settings) { s.config.ConcurrencyLimiter = limiter }
+}
+
+// WithBigLimits limits the size of the numbers used by the server's arbitrary precision functions.
+func WithBigLimits(limits bignum.Limits) Option {
+	return func(s *settings) { s.config.BigLimits = limits }
+}
+
+// WithDecimalLimits limits the size of the decimals used by the server's exact decimal functions.
+func WithDecimalLimits(limits decimal.Limits) Option {
+	return func(s *settings) { s.config.DecimalLimits = limits }
+}
+
+// WithOperations serves the Operations service with the given options.
+func WithOperations(options operations.Options) Option {
+	return func(s *settings) { s.config.Operations = &options }
+}
+
+// WithRecoverer makes the server recover from panics with the given recoverer.
+func WithRecoverer(recoverer *recovery.Recoverer) Option {
+	return func(s *settings) { s.config.Recoverer = recoverer }
+}
+
+// WithHealth registers the given health service on the server, instead of a new one.
+func WithHealth(healthServer *health.Server) Option {
+	return func(s *settings) { s.config.Health = healthServer }
+}
+
+// WithFaultInjector injects faults into the server's calls with the injector, and serves its FaultInjection service.
+func WithFaultInjector(injector *faults.Injector) Option {
+	return func(s *settings) { s.config.FaultInjector = injector }
+}
+
+// WithCache answers the server's calls from a cache with the given options when it can.
+func WithCache(options cache.Options) Option {
+	return func(s *settings) { s.config.Cache = &options }
+}
+
+// WithUnaryInterceptors adds interceptors which run around every unary call, the first one is the outermost.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(s *settings) { s.config.UnaryInterceptors = append(s.config.UnaryInterceptors, interceptors...) }
+}
+
+// WithStreamInterceptors adds interceptors which run around every streaming call, the first one is the outermost.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(s *settings) { s.config.StreamInterceptors = append(s.config.StreamInterceptors, interceptors...) }
+}
+
+// WithTLS serves over TLS using a freshly generated self-signed certificate, which the client trusts.
+func WithTLS() Option {
+	return func(s *settings) {
+		serverConfig, clientConfig := selfSignedTLS()
+		s.config.Credentials = credentials.NewTLS(serverConfig)
+		s.clientCredentials = credentials.NewTLS(clientConfig)
+	}
+}
+
+// WithTLSConfig serves over TLS using the given server configuration, and connects the client using the given
+// client configuration.
+func WithTLSConfig(serverConfig, clientConfig *tls.Config) Option {
+	return func(s *settings) {
+		s.config.Credentials = credentials.NewTLS(serverConfig)
+		s.clientCredentials = credentials.NewTLS(clientConfig)
+	}
+}
+
+// Start starts a MagicMath server over an in-memory connection and connects a client to it.
+// Both are torn down when the test finishes.
+func Start(t testing.TB, options ...Option) *Server {
+	t.Helper()
+
+	s := &settings{clientCredentials: insecure.NewCredentials()}
+	for _, option := range options {
+		option(s)
+	}
+	if s.config.Counters == nil {
+		s.config.Counters = counter.NewMemoryStore()
+	}
+	if s.config.Health == nil {
+		s.config.Health = health.NewServer()
+	}
+
+	grpcServer := app.New(s.config)
+	listener := bufconn.Listen(bufferSize)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	server := &Server{
+		GRPCServer:        grpcServer,
+		Counters:          s.config.Counters,
+		Health:            s.config.Health,
+		listener:          listener,
+		clientCredentials: s.clientCredentials,
+	}
+	server.Conn = server.Dial(t)
+	server.Client = pb.NewMagicMathClient(server.Conn)
+
+	return server
+}
+
+// Dial opens another connection to the server, with any extra dial options such as client interceptors.
+// The connection is closed when the test finishes.
+func (s *Server) Dial(t testing.TB, options ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+
+	options = append([]grpc.DialOption{
+		grpc.WithContextDialer(s.dialContext),
+		grpc.WithTransportCredentials(s.clientCredentials),
+	}, options...)
+
+	connection, err := grpc.NewClient("passthrough:///bufnet", options...)
+	if err != nil {
+		t.Fatalf("failed to connect to the test server: %v", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+
+	return connection
+}
+
+// This function opens a raw in-memory connection to the server.
+func (s *Server) dialContext(ctx context.Context, _ string) (net.Conn, error) {
+	return s.listener.DialContext(ctx)
+}
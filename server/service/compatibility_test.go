@@ -0,0 +1,335 @@
+package service_test
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// These tests pin the wire behavior of version 1 of MagicMath, which existing clients depend on. Adding remote
+// functions and fields is fine, but renaming, renumbering, retyping or removing any of the ones below breaks clients,
+// so make those changes in version 2 instead.
+
+// The version 1 remote functions, with their messages and REST routes.
+var v1Methods = []string{
+	"/shared.MagicMath/MagicAdd(shared.DoubleTerms) shared.DoubleResult POST /v1/add",
+	"/shared.MagicMath/MagicSubtract(shared.DoubleTerms) shared.DoubleResult POST /v1/subtract",
+	"/shared.MagicMath/MagicFindMin(shared.IntTerms) shared.IntResult POST /v1/min",
+	"/shared.MagicMath/MagicFindMax(shared.IntTerms) shared.IntResult POST /v1/max",
+	"/shared.MagicMath/MagicMultiply(shared.ArithmeticTerms) shared.ArithmeticResult POST /v1/multiply",
+	"/shared.MagicMath/MagicDivide(shared.ArithmeticTerms) shared.ArithmeticResult POST /v1/divide",
+	"/shared.MagicMath/MagicModulo(shared.ArithmeticTerms) shared.ArithmeticResult POST /v1/modulo",
+	"/shared.MagicMath/MagicPower(shared.ArithmeticTerms) shared.ArithmeticResult POST /v1/power",
+	"/shared.MagicMath/MagicBigAdd(shared.BigTerms) shared.BigResult POST /v1/big/add",
+	"/shared.MagicMath/MagicBigSubtract(shared.BigTerms) shared.BigResult POST /v1/big/subtract",
+	"/shared.MagicMath/MagicBigMultiply(shared.BigTerms) shared.BigResult POST /v1/big/multiply",
+	"/shared.MagicMath/MagicBigDivide(shared.BigTerms) shared.BigResult POST /v1/big/divide",
+	"/shared.MagicMath/MagicBigPower(shared.BigTerms) shared.BigResult POST /v1/big/power",
+	"/shared.MagicMath/MagicDecimalAdd(shared.DecimalTerms) shared.DecimalResult POST /v1/decimal/add",
+	"/shared.MagicMath/MagicDecimalSubtract(shared.DecimalTerms) shared.DecimalResult POST /v1/decimal/subtract",
+	"/shared.MagicMath/MagicDecimalMultiply(shared.DecimalTerms) shared.DecimalResult POST /v1/decimal/multiply",
+	"/shared.MagicMath/MagicDecimalDivide(shared.DecimalTerms) shared.DecimalResult POST /v1/decimal/divide",
+	"/shared.MagicMath/MagicEvaluate(shared.Expression) shared.DoubleResult POST /v1/evaluate",
+	"/shared.MagicMath/MagicStats(shared.StatsRequest) shared.Stats POST /v1/stats",
+	"/shared.MagicMath/MagicStatsStream(stream shared.StatsRequest) shared.Stats",
+	"/shared.MagicMath/GetAddCount(shared.Empty) shared.Count GET /v1/counts/add",
+	"/shared.MagicMath/GetSubCount(shared.Empty) shared.Count GET /v1/counts/sub",
+	"/shared.MagicMath/GetMinCount(shared.Empty) shared.Count GET /v1/counts/min",
+	"/shared.MagicMath/GetMaxCount(shared.Empty) shared.Count GET /v1/counts/max",
+	"/shared.MagicMath/GetMulCount(shared.Empty) shared.Count GET /v1/counts/mul",
+	"/shared.MagicMath/GetDivCount(shared.Empty) shared.Count GET /v1/counts/div",
+	"/shared.MagicMath/GetModCount(shared.Empty) shared.Count GET /v1/counts/mod",
+	"/shared.MagicMath/GetPowCount(shared.Empty) shared.Count GET /v1/counts/pow",
+	"/shared.MagicMath/GetStatsCount(shared.Empty) shared.Count GET /v1/counts/stats",
+	"/shared.MagicMath/GetBigCount(shared.Empty) shared.Count GET /v1/counts/big",
+	"/shared.MagicMath/GetDecimalCount(shared.Empty) shared.Count GET /v1/counts/decimal",
+	"/shared.MagicMath/GetEvalCount(shared.Empty) shared.Count GET /v1/counts/eval",
+	"/shared.MagicMath/GetPanicCount(shared.Empty) shared.Count GET /v1/counts/panic",
+	"/shared.MagicMath/GetCacheStats(shared.Empty) shared.CacheStats GET /v1/counts/cache",
+	"/shared.MagicMath/GetQuota(shared.Empty) shared.Quota GET /v1/quota",
+}
+
+// The fields of the version 1 messages. The field names are also the names used by the REST gateway's JSON.
+var v1Fields = []string{
+	"shared.DoubleTerms: double termOne = 1",
+	"shared.DoubleTerms: double termTwo = 2",
+	"shared.DoubleResult: double result = 1",
+	"shared.IntTerms: sint64 termOne = 1",
+	"shared.IntTerms: sint64 termTwo = 2",
+	"shared.IntTerms: sint64 termThree = 3",
+	"shared.IntResult: sint64 result = 1",
+	"shared.IntPair: sint64 termOne = 1",
+	"shared.IntPair: sint64 termTwo = 2",
+	"shared.ArithmeticTerms: oneof terms shared.DoubleTerms doubles = 1",
+	"shared.ArithmeticTerms: oneof terms shared.IntPair ints = 2",
+	"shared.ArithmeticResult: oneof result double doubleResult = 1",
+	"shared.ArithmeticResult: oneof result sint64 intResult = 2",
+	"shared.BigTerms: string termOne = 1",
+	"shared.BigTerms: string termTwo = 2",
+	"shared.BigTerms: string kind = 3",
+	"shared.BigTerms: sint32 precision = 4",
+	"shared.BigTerms: string rounding = 5",
+	"shared.BigResult: string result = 1",
+	"shared.Decimal: oneof value string text = 1",
+	"shared.Decimal: oneof value shared.ScaledDecimal scaled = 2",
+	"shared.ScaledDecimal: string unscaled = 1",
+	"shared.ScaledDecimal: sint32 scale = 2",
+	"shared.DecimalTerms: shared.Decimal termOne = 1",
+	"shared.DecimalTerms: shared.Decimal termTwo = 2",
+	"shared.DecimalTerms: optional sint32 scale = 3",
+	"shared.DecimalTerms: string rounding = 4",
+	"shared.DecimalResult: string text = 1",
+	"shared.DecimalResult: shared.ScaledDecimal scaled = 2",
+	"shared.Expression: string expression = 1",
+	"shared.Expression: map<string, double> variables = 2",
+	"shared.StatsRequest: repeated double values = 1",
+	"shared.StatsRequest: repeated double quantiles = 2",
+	"shared.Stats: sint64 count = 1",
+	"shared.Stats: double sum = 2",
+	"shared.Stats: double mean = 3",
+	"shared.Stats: double variance = 4",
+	"shared.Stats: double stddev = 5",
+	"shared.Stats: double min = 6",
+	"shared.Stats: double max = 7",
+	"shared.Stats: double median = 8",
+	"shared.Stats: repeated shared.QuantileValue quantiles = 9",
+	"shared.Stats: bool exact = 10",
+	"shared.QuantileValue: double quantile = 1",
+	"shared.QuantileValue: double value = 2",
+	"shared.Count: sint64 count = 1",
+	"shared.CacheStats: sint64 hits = 1",
+	"shared.CacheStats: sint64 misses = 2",
+	"shared.CacheStats: sint64 entries = 3",
+	"shared.CacheStats: sint64 evictions = 4",
+	"shared.Quota: string caller = 1",
+	"shared.Quota: sint64 hourlyLimit = 2",
+	"shared.Quota: sint64 hourlyUsed = 3",
+	"shared.Quota: google.protobuf.Timestamp hourlyReset = 4",
+	"shared.Quota: sint64 dailyLimit = 5",
+	"shared.Quota: sint64 dailyUsed = 6",
+	"shared.Quota: google.protobuf.Timestamp dailyReset = 7",
+}
+
+// This function describes a remote function in the form used by v1Methods.
+func describeMethod(method protoreflect.MethodDescriptor) string {
+	input := string(method.Input().FullName())
+	if method.IsStreamingClient() {
+		input = "stream " + input
+	}
+	output := string(method.Output().FullName())
+	if method.IsStreamingServer() {
+		output = "stream " + output
+	}
+
+	description := fmt.Sprintf("/%s/%s(%s) %s", method.Parent().FullName(), method.Name(), input, output)
+	rule, _ := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+	switch route := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		description += " GET " + route.Get
+	case *annotations.HttpRule_Post:
+		description += " POST " + route.Post
+	}
+
+	return description
+}
+
+// This function describes a field in the form used by v1Fields.
+func describeField(field protoreflect.FieldDescriptor) string {
+	fieldType := func(field protoreflect.FieldDescriptor) string {
+		if field.Message() != nil {
+			return string(field.Message().FullName())
+		}
+		return field.Kind().String()
+	}
+
+	var label string
+	switch {
+	case field.IsMap():
+		label = fmt.Sprintf("map<%s, %s>", fieldType(field.MapKey()), fieldType(field.MapValue()))
+	case field.IsList():
+		label = "repeated " + fieldType(field)
+	case field.ContainingOneof() != nil && !field.ContainingOneof().IsSynthetic():
+		label = fmt.Sprintf("oneof %s %s", field.ContainingOneof().Name(), fieldType(field))
+	case field.HasOptionalKeyword():
+		label = "optional " + fieldType(field)
+	default:
+		label = fieldType(field)
+	}
+
+	return fmt.Sprintf("%s: %s %s = %d", field.Parent().FullName(), label, field.JSONName(), field.Number())
+}
+
+func TestV1MethodsAreUnchanged(t *testing.T) {
+	methods := map[string]bool{}
+	descriptors := pb.File_magicMath_magic_math_proto.Services().ByName("MagicMath").Methods()
+	for i := range descriptors.Len() {
+		methods[describeMethod(descriptors.Get(i))] = true
+	}
+
+	for _, method := range v1Methods {
+		if !methods[method] {
+			t.Errorf("the version 1 remote function %q has been removed or changed", method)
+		}
+	}
+}
+
+func TestV1FieldsAreUnchanged(t *testing.T) {
+	fields := map[string]bool{}
+	messages := pb.File_magicMath_magic_math_proto.Messages()
+	for i := range messages.Len() {
+		descriptors := messages.Get(i).Fields()
+		for j := range descriptors.Len() {
+			fields[describeField(descriptors.Get(j))] = true
+		}
+	}
+
+	for _, field := range v1Fields {
+		if !fields[field] {
+			t.Errorf("the version 1 field %q has been removed or changed", field)
+		}
+	}
+}
+
+func TestV1WireFormatIsUnchanged(t *testing.T) {
+	tests := []struct {
+		message proto.Message
+		wire    string
+	}{
+		{&pb.DoubleTerms{TermOne: 1.5, TermTwo: -2}, "09000000000000f83f1100000000000000c0"},
+		{&pb.IntTerms{TermOne: 3, TermTwo: -5, TermThree: 7}, "08061009180e"},
+		{&pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{Ints: &pb.IntPair{TermOne: -6, TermTwo: 7}}}, "1204080b100e"},
+		{&pb.ArithmeticResult{Result: &pb.ArithmeticResult_DoubleResult{DoubleResult: 10}}, "090000000000002440"},
+		{&pb.DecimalTerms{TermOne: &pb.Decimal{Value: &pb.Decimal_Text{Text: "0.1"}}, Scale: proto.Int32(0)},
+			"0a050a03302e311800"},
+		{&pb.Count{Count: 42}, "0854"},
+	}
+
+	for _, tt := range tests {
+		encoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(tt.message)
+		if err != nil || hex.EncodeToString(encoded) != tt.wire {
+			t.Errorf("Marshal(%v) = %x, %v; want %s", tt.message, encoded, err, tt.wire)
+		}
+
+		// Messages encoded by old clients must still decode to the same values.
+		wire, _ := hex.DecodeString(tt.wire)
+		decoded := tt.message.ProtoReflect().New().Interface()
+		if err := proto.Unmarshal(wire, decoded); err != nil || !proto.Equal(decoded, tt.message) {
+			t.Errorf("Unmarshal(%s) = %v, %v; want %v", tt.wire, decoded, err, tt.message)
+		}
+	}
+}
+
+func TestV1ResultsAreUnchanged(t *testing.T) {
+	client := servertest.Start(t).Client
+	ctx := context.Background()
+	ints := func(a, b int64) *pb.ArithmeticTerms {
+		return &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{Ints: &pb.IntPair{TermOne: a, TermTwo: b}}}
+	}
+	doubles := func(a, b float64) *pb.ArithmeticTerms {
+		return &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Doubles{Doubles: &pb.DoubleTerms{TermOne: a, TermTwo: b}}}
+	}
+	intResult := func(result int64) *pb.ArithmeticResult {
+		return &pb.ArithmeticResult{Result: &pb.ArithmeticResult_IntResult{IntResult: result}}
+	}
+	doubleResult := func(result float64) *pb.ArithmeticResult {
+		return &pb.ArithmeticResult{Result: &pb.ArithmeticResult_DoubleResult{DoubleResult: result}}
+	}
+
+	tests := []struct {
+		name     string
+		call     func() (proto.Message, error)
+		want     proto.Message
+		wantCode codes.Code
+	}{
+		{"MagicAdd(1.5, 2)", func() (proto.Message, error) {
+			return client.MagicAdd(ctx, &pb.DoubleTerms{TermOne: 1.5, TermTwo: 2})
+		}, &pb.DoubleResult{Result: 3.5}, codes.OK},
+		// Version 1 sums overflow to infinity rather than failing.
+		{"MagicAdd(MaxFloat64, MaxFloat64)", func() (proto.Message, error) {
+			return client.MagicAdd(ctx, &pb.DoubleTerms{TermOne: math.MaxFloat64, TermTwo: math.MaxFloat64})
+		}, &pb.DoubleResult{Result: math.Inf(1)}, codes.OK},
+		// Infinite terms are allowed.
+		{"MagicAdd(+Inf, 1)", func() (proto.Message, error) {
+			return client.MagicAdd(ctx, &pb.DoubleTerms{TermOne: math.Inf(1), TermTwo: 1})
+		}, &pb.DoubleResult{Result: math.Inf(1)}, codes.OK},
+		{"MagicSubtract(1, 3)", func() (proto.Message, error) {
+			return client.MagicSubtract(ctx, &pb.DoubleTerms{TermOne: 1, TermTwo: 3})
+		}, &pb.DoubleResult{Result: -2}, codes.OK},
+		{"MagicFindMin(3, -1, 7)", func() (proto.Message, error) {
+			return client.MagicFindMin(ctx, &pb.IntTerms{TermOne: 3, TermTwo: -1, TermThree: 7})
+		}, &pb.IntResult{Result: -1}, codes.OK},
+		{"MagicFindMax(3, -1, 7)", func() (proto.Message, error) {
+			return client.MagicFindMax(ctx, &pb.IntTerms{TermOne: 3, TermTwo: -1, TermThree: 7})
+		}, &pb.IntResult{Result: 7}, codes.OK},
+		{"MagicMultiply(6, 7)", func() (proto.Message, error) {
+			return client.MagicMultiply(ctx, ints(6, 7))
+		}, intResult(42), codes.OK},
+		{"MagicMultiply(MaxInt64, 2)", func() (proto.Message, error) {
+			return client.MagicMultiply(ctx, ints(math.MaxInt64, 2))
+		}, nil, codes.OutOfRange},
+		{"MagicMultiply(1.5, 2.0)", func() (proto.Message, error) {
+			return client.MagicMultiply(ctx, doubles(1.5, 2))
+		}, doubleResult(3), codes.OK},
+		{"MagicMultiply(+Inf, 2.0)", func() (proto.Message, error) {
+			return client.MagicMultiply(ctx, doubles(math.Inf(1), 2))
+		}, doubleResult(math.Inf(1)), codes.OK},
+		{"MagicMultiply(NaN, 2.0)", func() (proto.Message, error) {
+			return client.MagicMultiply(ctx, doubles(math.NaN(), 2))
+		}, nil, codes.InvalidArgument},
+		{"MagicDivide(7, 2)", func() (proto.Message, error) {
+			return client.MagicDivide(ctx, ints(7, 2))
+		}, intResult(3), codes.OK},
+		{"MagicDivide(7.0, 2.0)", func() (proto.Message, error) {
+			return client.MagicDivide(ctx, doubles(7, 2))
+		}, doubleResult(3.5), codes.OK},
+		{"MagicDivide(7, 0)", func() (proto.Message, error) {
+			return client.MagicDivide(ctx, ints(7, 0))
+		}, nil, codes.InvalidArgument},
+		{"MagicDivide()", func() (proto.Message, error) {
+			return client.MagicDivide(ctx, &pb.ArithmeticTerms{})
+		}, nil, codes.InvalidArgument},
+		{"MagicModulo(-7, 3)", func() (proto.Message, error) {
+			return client.MagicModulo(ctx, ints(-7, 3))
+		}, intResult(-1), codes.OK},
+		{"MagicPower(2, 10)", func() (proto.Message, error) {
+			return client.MagicPower(ctx, ints(2, 10))
+		}, intResult(1024), codes.OK},
+		{"MagicPower(2, -1)", func() (proto.Message, error) {
+			return client.MagicPower(ctx, ints(2, -1))
+		}, nil, codes.InvalidArgument},
+		{"MagicPower(2.0, -1.0)", func() (proto.Message, error) {
+			return client.MagicPower(ctx, doubles(2, -1))
+		}, doubleResult(0.5), codes.OK},
+		{"MagicBigAdd(0.1, 0.2)", func() (proto.Message, error) {
+			return client.MagicBigAdd(ctx, &pb.BigTerms{TermOne: "0.1", TermTwo: "0.2"})
+		}, &pb.BigResult{Result: "0.3"}, codes.OK},
+		{"MagicEvaluate((a + 1) * 2)", func() (proto.Message, error) {
+			return client.MagicEvaluate(ctx, &pb.Expression{Expression: "(a + 1) * 2", Variables: map[string]float64{"a": 3}})
+		}, &pb.DoubleResult{Result: 8}, codes.OK},
+		{"MagicEvaluate(1 +)", func() (proto.Message, error) {
+			return client.MagicEvaluate(ctx, &pb.Expression{Expression: "1 +"})
+		}, nil, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.call()
+		if status.Code(err) != tt.wantCode {
+			t.Errorf("%s returned error %v; want code %v", tt.name, err, tt.wantCode)
+			continue
+		}
+		if err == nil && !proto.Equal(got, tt.want) {
+			t.Errorf("%s = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
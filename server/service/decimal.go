@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/decimal"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ========================================== Decimal Functions ==========================================
+
+// MagicDecimalAdd takes a request context (which is ignored) and two decimals, and returns their sum.
+func (s *MagicMath) MagicDecimalAdd(_ context.Context, in *pb.DecimalTerms) (*pb.DecimalResult, error) {
+	s.counters.Increment(counter.Decimal)
+
+	return s.decimalArithmetic("add", in, s.decimals.Add)
+}
+
+// MagicDecimalSubtract takes a request context (which is ignored) and two decimals, and returns their difference.
+func (s *MagicMath) MagicDecimalSubtract(_ context.Context, in *pb.DecimalTerms) (*pb.DecimalResult, error) {
+	s.counters.Increment(counter.Decimal)
+
+	return s.decimalArithmetic("subtract", in, s.decimals.Sub)
+}
+
+// MagicDecimalMultiply takes a request context (which is ignored) and two decimals, and returns their product.
+func (s *MagicMath) MagicDecimalMultiply(_ context.Context, in *pb.DecimalTerms) (*pb.DecimalResult, error) {
+	s.counters.Increment(counter.Decimal)
+
+	return s.decimalArithmetic("multiply", in, s.decimals.Mul)
+}
+
+// MagicDecimalDivide takes a request context (which is ignored) and two decimals, and returns the first divided by the
+// second, rounded to the scale given in the terms.
+func (s *MagicMath) MagicDecimalDivide(_ context.Context, in *pb.DecimalTerms) (*pb.DecimalResult, error) {
+	s.counters.Increment(counter.Decimal)
+
+	var violations fieldViolations
+	a := s.parseDecimal("termOne", in.TermOne, &violations)
+	b := s.parseDecimal("termTwo", in.TermTwo, &violations)
+	rounding := parseRounding(in.Rounding, &violations)
+	if in.Scale == nil {
+		violations.add("scale", errors.New("division needs the scale of the result"))
+	}
+	if len(violations) > 0 {
+		return nil, violations.err("divide")
+	}
+
+	quotient, err := s.decimals.Quo(a, b, in.GetScale(), rounding)
+	if errors.Is(err, math.ErrOverflow) {
+		return nil, arithmeticError(err, "divide(%.40s, %.40s)", a, b)
+	} else if errors.Is(err, math.ErrDivisionByZero) {
+		violations.add("termTwo", err)
+	} else if err != nil {
+		violations.add("scale", err)
+	}
+	if len(violations) > 0 {
+		return nil, violations.err("divide")
+	}
+
+	return decimalResult(quotient), nil
+}
+
+// This function works out an exact sum, difference or product of the terms, and then rounds it if the terms have
+// a scale. Results beyond the server's limits are OUT_OF_RANGE.
+func (s *MagicMath) decimalArithmetic(
+	name string,
+	in *pb.DecimalTerms,
+	function func(a, b decimal.Decimal) (decimal.Decimal, error),
+) (*pb.DecimalResult, error) {
+	var violations fieldViolations
+	a := s.parseDecimal("termOne", in.TermOne, &violations)
+	b := s.parseDecimal("termTwo", in.TermTwo, &violations)
+	rounding := parseRounding(in.Rounding, &violations)
+	if len(violations) > 0 {
+		return nil, violations.err(name)
+	}
+
+	result, err := function(a, b)
+	if err != nil {
+		// The terms are cut short, as they may be very long.
+		return nil, arithmeticError(err, "%s(%.40s, %.40s)", name, a, b)
+	}
+	if in.Scale != nil {
+		rounded, err := s.decimals.Round(result, *in.Scale, rounding)
+		if errors.Is(err, math.ErrOverflow) {
+			return nil, arithmeticError(err, "%s(%.40s, %.40s)", name, a, b)
+		} else if err != nil {
+			violations.add("scale", err)
+			return nil, violations.err(name)
+		}
+		result = rounded
+	}
+
+	return decimalResult(result), nil
+}
+
+// This function parses a decimal in either of its forms, and records a violation of the field if it can't.
+func (s *MagicMath) parseDecimal(field string, in *pb.Decimal, violations *fieldViolations) decimal.Decimal {
+	var d decimal.Decimal
+	var err error
+
+	switch value := in.GetValue().(type) {
+	case *pb.Decimal_Text:
+		field += ".text"
+		d, err = s.decimals.Parse(value.Text)
+	case *pb.Decimal_Scaled:
+		field += ".scaled"
+		d, err = s.decimals.ParseUnscaled(value.Scaled.GetUnscaled(), value.Scaled.GetScale())
+	default:
+		err = errors.New("the decimal is missing, set either its text or its scaled form")
+	}
+	if err != nil {
+		violations.add(field, err)
+	}
+
+	return d
+}
+
+// This function parses the rounding mode, and records a violation of the rounding field if it can't.
+func parseRounding(name string, violations *fieldViolations) decimal.RoundingMode {
+	rounding, err := decimal.ParseRoundingMode(name)
+	if err != nil {
+		violations.add("rounding", err)
+	}
+
+	return rounding
+}
+
+// This function writes a decimal in both of its forms.
+func decimalResult(d decimal.Decimal) *pb.DecimalResult {
+	return &pb.DecimalResult{
+		Text:   d.String(),
+		Scaled: &pb.ScaledDecimal{Unscaled: d.Unscaled().String(), Scale: d.Scale()},
+	}
+}
+
+// This type collects what is wrong with each field of a request, so that the caller can fix all of them at once.
+type fieldViolations []*errdetails.BadRequest_FieldViolation
+
+// This function records that the field is wrong, and why.
+func (v *fieldViolations) add(field string, err error) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{Field: field, Description: err.Error()})
+}
+
+// This function creates an InvalidArgument error with a BadRequest detail listing the violations.
+func (v fieldViolations) err(name string) error {
+	message := name + ": invalid decimal terms"
+	for _, violation := range v {
+		message += ", " + violation.Field + ": " + violation.Description
+	}
+
+	st, err := status.New(codes.InvalidArgument, message).WithDetails(&errdetails.BadRequest{FieldViolations: v})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, message)
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/decimal"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// These functions create the two forms of a decimal.
+func text(value string) *pb.Decimal {
+	return &pb.Decimal{Value: &pb.Decimal_Text{Text: value}}
+}
+
+func scaled(unscaled string, scale int32) *pb.Decimal {
+	return &pb.Decimal{Value: &pb.Decimal_Scaled{Scaled: &pb.ScaledDecimal{Unscaled: unscaled, Scale: scale}}}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		name  string
+		call  func(context.Context, *pb.DecimalTerms, ...grpc.CallOption) (*pb.DecimalResult, error)
+		terms *pb.DecimalTerms
+		want  *pb.DecimalResult
+	}{
+		{"MagicDecimalAdd", client.MagicDecimalAdd, &pb.DecimalTerms{TermOne: text("0.1"), TermTwo: text("0.2")},
+			&pb.DecimalResult{Text: "0.3", Scaled: &pb.ScaledDecimal{Unscaled: "3", Scale: 1}}},
+		{"MagicDecimalAdd", client.MagicDecimalAdd, &pb.DecimalTerms{TermOne: scaled("1250", 2), TermTwo: text("1")},
+			&pb.DecimalResult{Text: "13.50", Scaled: &pb.ScaledDecimal{Unscaled: "1350", Scale: 2}}},
+		{"MagicDecimalSubtract", client.MagicDecimalSubtract, &pb.DecimalTerms{TermOne: text("1"), TermTwo: text("1.01")},
+			&pb.DecimalResult{Text: "-0.01", Scaled: &pb.ScaledDecimal{Unscaled: "-1", Scale: 2}}},
+		{"MagicDecimalMultiply", client.MagicDecimalMultiply,
+			&pb.DecimalTerms{TermOne: text("19.99"), TermTwo: text("0.075"), Scale: proto.Int32(2), Rounding: "half_up"},
+			&pb.DecimalResult{Text: "1.50", Scaled: &pb.ScaledDecimal{Unscaled: "150", Scale: 2}}},
+		{"MagicDecimalDivide", client.MagicDecimalDivide,
+			&pb.DecimalTerms{TermOne: text("10"), TermTwo: text("3"), Scale: proto.Int32(2)},
+			&pb.DecimalResult{Text: "3.33", Scaled: &pb.ScaledDecimal{Unscaled: "333", Scale: 2}}},
+		{"MagicDecimalDivide", client.MagicDecimalDivide,
+			&pb.DecimalTerms{TermOne: text("10"), TermTwo: text("3"), Scale: proto.Int32(2), Rounding: "up"},
+			&pb.DecimalResult{Text: "3.34", Scaled: &pb.ScaledDecimal{Unscaled: "334", Scale: 2}}},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.call(context.Background(), tt.terms)
+		if err != nil || !proto.Equal(result, tt.want) {
+			t.Errorf("%s(%v) = %v, %v; want %v", tt.name, tt.terms, result, err, tt.want)
+		}
+	}
+
+	count, err := client.GetDecimalCount(context.Background(), &pb.Empty{})
+	if err != nil || count.Count != int64(len(tests)) {
+		t.Errorf("GetDecimalCount() = %v, %v; want %d", count, err, len(tests))
+	}
+}
+
+func TestDecimalFieldViolations(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		name       string
+		call       func(context.Context, *pb.DecimalTerms, ...grpc.CallOption) (*pb.DecimalResult, error)
+		terms      *pb.DecimalTerms
+		wantFields []string
+	}{
+		{"MagicDecimalAdd", client.MagicDecimalAdd,
+			&pb.DecimalTerms{TermOne: text("1.2.3"), TermTwo: scaled("12x", 0)}, []string{"termOne.text", "termTwo.scaled"}},
+		{"MagicDecimalAdd", client.MagicDecimalAdd, &pb.DecimalTerms{TermTwo: text("1")}, []string{"termOne"}},
+		{"MagicDecimalSubtract", client.MagicDecimalSubtract,
+			&pb.DecimalTerms{TermOne: text("1"), TermTwo: text("1"), Rounding: "ceiling"}, []string{"rounding"}},
+		{"MagicDecimalMultiply", client.MagicDecimalMultiply,
+			&pb.DecimalTerms{TermOne: text("1"), TermTwo: text("1"), Scale: proto.Int32(5000)}, []string{"scale"}},
+		{"MagicDecimalDivide", client.MagicDecimalDivide, &pb.DecimalTerms{TermOne: text("1"), TermTwo: text("3")},
+			[]string{"scale"}},
+		{"MagicDecimalDivide", client.MagicDecimalDivide,
+			&pb.DecimalTerms{TermOne: text("1"), TermTwo: text("0.00"), Scale: proto.Int32(2)}, []string{"termTwo"}},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.call(context.Background(), tt.terms)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s(%v) returned error %v; want code %v", tt.name, tt.terms, err, codes.InvalidArgument)
+			continue
+		}
+
+		var fields []string
+		for _, detail := range status.Convert(err).Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, violation := range badRequest.FieldViolations {
+					fields = append(fields, violation.Field)
+				}
+			}
+		}
+		if !slices.Equal(fields, tt.wantFields) {
+			t.Errorf("%s(%v) has field violations %v; want %v", tt.name, tt.terms, fields, tt.wantFields)
+		}
+	}
+}
+
+func TestDecimalLimits(t *testing.T) {
+	client := servertest.Start(t, servertest.WithDecimalLimits(decimal.Limits{MaxScale: 4, MaxDigits: 6})).Client
+
+	tests := []struct {
+		name     string
+		call     func(context.Context, *pb.DecimalTerms, ...grpc.CallOption) (*pb.DecimalResult, error)
+		terms    *pb.DecimalTerms
+		wantCode codes.Code
+	}{
+		{"MagicDecimalMultiply", client.MagicDecimalMultiply, &pb.DecimalTerms{TermOne: text("0.01"), TermTwo: text("0.01")},
+			codes.OK},
+		{"MagicDecimalMultiply", client.MagicDecimalMultiply, &pb.DecimalTerms{TermOne: text("0.001"), TermTwo: text("0.01")},
+			codes.OutOfRange},
+		{"MagicDecimalAdd", client.MagicDecimalAdd, &pb.DecimalTerms{TermOne: text("999999"), TermTwo: text("1")},
+			codes.OutOfRange},
+		{"MagicDecimalDivide", client.MagicDecimalDivide,
+			&pb.DecimalTerms{TermOne: text("999999"), TermTwo: text("0.1"), Scale: proto.Int32(0)}, codes.OutOfRange},
+		{"MagicDecimalAdd", client.MagicDecimalAdd, &pb.DecimalTerms{TermOne: text("0.00001"), TermTwo: text("1")},
+			codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.call(context.Background(), tt.terms); status.Code(err) != tt.wantCode {
+			t.Errorf("%s(%v) returned error %v; want code %v", tt.name, tt.terms, err, tt.wantCode)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/expression"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ========================================== Expression Functions ==========================================
+
+// These are the reasons given in the ErrorInfo detail of a failed MagicEvaluate call.
+const (
+	// SyntaxErrorReason means the expression doesn't parse.
+	SyntaxErrorReason = "EXPRESSION_SYNTAX_ERROR"
+	// EvaluationErrorReason means the expression parses, but has no value.
+	EvaluationErrorReason = "EXPRESSION_EVALUATION_ERROR"
+	// ErrorDomain is the domain of the ErrorInfo details.
+	ErrorDomain = "magicmath"
+)
+
+// MagicEvaluate takes a request context (which is ignored) and an expression with the values of its variables,
+// and returns the value of the expression.
+func (s *MagicMath) MagicEvaluate(_ context.Context, in *pb.Expression) (*pb.DoubleResult, error) {
+	s.counters.Increment(counter.Evaluate)
+
+	result, err := expression.Evaluate(in.Expression, in.Variables)
+	if err != nil {
+		return nil, expressionError(err)
+	}
+
+	return &pb.DoubleResult{Result: result}, nil
+}
+
+// This function turns an error from the expression package into a gRPC error, with an ErrorInfo detail giving the
+// position of the problem, and a BadRequest detail for the expression field. Results which overflow are OUT_OF_RANGE,
+// and every other error is INVALID_ARGUMENT.
+func expressionError(err error) error {
+	code, reason, position := codes.InvalidArgument, SyntaxErrorReason, 0
+
+	var syntaxError *expression.SyntaxError
+	var evaluationError *expression.EvaluationError
+	switch {
+	case errors.As(err, &syntaxError):
+		position = syntaxError.Position
+	case errors.As(err, &evaluationError):
+		reason, position = EvaluationErrorReason, evaluationError.Position
+		if errors.Is(err, math.ErrOverflow) {
+			code = codes.OutOfRange
+		}
+	default:
+		return status.Errorf(codes.Internal, "evaluate: %v", err)
+	}
+
+	st, detailErr := status.New(code, "evaluate: "+err.Error()).WithDetails(
+		&errdetails.ErrorInfo{
+			Reason:   reason,
+			Domain:   ErrorDomain,
+			Metadata: map[string]string{"position": strconv.Itoa(position)},
+		},
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "expression", Description: err.Error()},
+		}},
+	)
+	if detailErr != nil {
+		return status.Error(code, "evaluate: "+err.Error())
+	}
+
+	return st.Err()
+}
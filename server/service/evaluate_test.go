@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+	"github.com/karldmenzel/go-grpc-client-server/server/service"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMagicEvaluate(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	result, err := client.MagicEvaluate(context.Background(), &pb.Expression{
+		Expression: "min(price, 100) * (1 + tax)",
+		Variables:  map[string]float64{"price": 120, "tax": 0.25},
+	})
+	if err != nil || result.Result != 125 {
+		t.Errorf("MagicEvaluate() = %v, %v; want 125", result, err)
+	}
+}
+
+func TestMagicEvaluateErrors(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		expression   string
+		wantCode     codes.Code
+		wantReason   string
+		wantPosition string
+	}{
+		{"1 + (2 * 3", codes.InvalidArgument, service.SyntaxErrorReason, "10"},
+		{"1 + # 2", codes.InvalidArgument, service.SyntaxErrorReason, "4"},
+		{"x / (1 - 1)", codes.InvalidArgument, service.EvaluationErrorReason, "2"},
+		{"1 + y", codes.InvalidArgument, service.EvaluationErrorReason, "4"},
+		{"1e300 * 1e300", codes.OutOfRange, service.EvaluationErrorReason, "6"},
+	}
+
+	for _, tt := range tests {
+		_, err := client.MagicEvaluate(context.Background(),
+			&pb.Expression{Expression: tt.expression, Variables: map[string]float64{"x": 1}})
+		if status.Code(err) != tt.wantCode {
+			t.Errorf("MagicEvaluate(%q) returned error %v; want code %v", tt.expression, err, tt.wantCode)
+			continue
+		}
+
+		var info *errdetails.ErrorInfo
+		for _, detail := range status.Convert(err).Details() {
+			if errorInfo, ok := detail.(*errdetails.ErrorInfo); ok {
+				info = errorInfo
+			}
+		}
+		if info.GetReason() != tt.wantReason || info.GetMetadata()["position"] != tt.wantPosition {
+			t.Errorf("MagicEvaluate(%q) has error info %v; want reason %s at position %s",
+				tt.expression, info, tt.wantReason, tt.wantPosition)
+		}
+	}
+}
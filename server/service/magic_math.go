@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/cache"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/bignum"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/decimal"
+	"github.com/karldmenzel/go-grpc-client-server/server/ratelimit"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MagicMath is the server object that we will bind to in order to expose the remote methods.
+type MagicMath struct {
+	pb.UnsafeMagicMathServer
+
+	// This stores how many times each function has been called.
+	counters counter.Store
+
+	// This enforces the call quotas, it is nil if the server has no limits.
+	limiter *ratelimit.Limiter
+
+	// This does the arbitrary precision math, within the server's limits on the size of numbers.
+	calculator *bignum.Calculator
+
+	// This does the exact decimal math, within the server's limits on the size of decimals.
+	decimals *decimal.Calculator
+
+	// This memoizes the results of the math functions, it is nil if the server has no cache.
+	cache *cache.Cache
+}
+
+// New creates the server object, which keeps its function counters in the given store.
+// The limiter reports the callers' quota usage, and may be nil if the server has no limits.
+// The calculator does the arbitrary precision math, and decimals the exact decimal math, either may be nil to use the
+// default limits.
+// The cache's size is reported by GetCacheStats, and it may be nil if the server has no cache.
+func New(
+	counters counter.Store,
+	limiter *ratelimit.Limiter,
+	calculator *bignum.Calculator,
+	decimals *decimal.Calculator,
+	resultCache *cache.Cache,
+) *MagicMath {
+	if calculator == nil {
+		calculator = bignum.New(bignum.DefaultLimits)
+	}
+	if decimals == nil {
+		decimals = decimal.NewCalculator(decimal.DefaultLimits)
+	}
+
+	return &MagicMath{counters: counters, limiter: limiter, calculator: calculator, decimals: decimals, cache: resultCache}
+}
+
+// ========================================== Math Functions ==========================================
+
+// MagicAdd takes a request context (which is ignored) and two doubles, and returns their sum.
+func (s *MagicMath) MagicAdd(_ context.Context, in *pb.DoubleTerms) (*pb.DoubleResult, error) {
+	s.counters.Increment(counter.Add)
+
+	sum := math.LocalAdd(in.TermOne, in.TermTwo)
+	responseObject := &pb.DoubleResult{Result: sum}
+
+	return responseObject, nil
+}
+
+// MagicSubtract takes a request context (which is ignored) and two doubles, and returns their difference.
+func (s *MagicMath) MagicSubtract(_ context.Context, in *pb.DoubleTerms) (*pb.DoubleResult, error) {
+	s.counters.Increment(counter.Subtract)
+
+	difference := math.LocalSubtract(in.TermOne, in.TermTwo)
+	responseObject := &pb.DoubleResult{Result: difference}
+
+	return responseObject, nil
+}
+
+// MagicFindMin takes a request context (which is ignored) and three integers, and returns the lowest value.
+// If all three values are equal it returns the first value.
+func (s *MagicMath) MagicFindMin(_ context.Context, in *pb.IntTerms) (*pb.IntResult, error) {
+	s.counters.Increment(counter.FindMin)
+
+	minimum := math.LocalFindMin(in.TermOne, in.TermTwo, in.TermThree)
+	responseObject := &pb.IntResult{Result: minimum}
+
+	return responseObject, nil
+}
+
+// MagicFindMax takes a request context (which is ignored) and three integers, and returns the highest value.
+// If all three values are equal it returns the first value.
+func (s *MagicMath) MagicFindMax(_ context.Context, in *pb.IntTerms) (*pb.IntResult, error) {
+	s.counters.Increment(counter.FindMax)
+
+	maximum := math.LocalFindMax(in.TermOne, in.TermTwo, in.TermThree)
+	responseObject := &pb.IntResult{Result: maximum}
+
+	return responseObject, nil
+}
+
+// ========================================== Arithmetic Functions ==========================================
+
+// MagicMultiply takes a request context (which is ignored) and two doubles or two integers, and returns their product.
+func (s *MagicMath) MagicMultiply(_ context.Context, in *pb.ArithmeticTerms) (*pb.ArithmeticResult, error) {
+	s.counters.Increment(counter.Multiply)
+
+	return arithmetic("multiply", in, math.LocalMultiply, math.LocalMultiplyInt)
+}
+
+// MagicDivide takes a request context (which is ignored) and two doubles or two integers, and returns the first divided
+// by the second. Integer division rounds towards zero.
+func (s *MagicMath) MagicDivide(_ context.Context, in *pb.ArithmeticTerms) (*pb.ArithmeticResult, error) {
+	s.counters.Increment(counter.Divide)
+
+	return arithmetic("divide", in, math.LocalDivide, math.LocalDivideInt)
+}
+
+// MagicModulo takes a request context (which is ignored) and two doubles or two integers, and returns the remainder of
+// the first divided by the second, which has the same sign as the first.
+func (s *MagicMath) MagicModulo(_ context.Context, in *pb.ArithmeticTerms) (*pb.ArithmeticResult, error) {
+	s.counters.Increment(counter.Modulo)
+
+	return arithmetic("modulo", in, math.LocalModulo, math.LocalModuloInt)
+}
+
+// MagicPower takes a request context (which is ignored) and two doubles or two integers, and returns the first raised
+// to the power of the second.
+func (s *MagicMath) MagicPower(_ context.Context, in *pb.ArithmeticTerms) (*pb.ArithmeticResult, error) {
+	s.counters.Increment(counter.Power)
+
+	return arithmetic("power", in, math.LocalPower, math.LocalPowerInt)
+}
+
+// This function runs the double or the integer variant of an arithmetic function, depending on the type of the terms,
+// and turns the function's error into a gRPC error.
+func arithmetic(
+	name string,
+	in *pb.ArithmeticTerms,
+	doubleFunction func(a, b float64) (float64, error),
+	intFunction func(a, b int64) (int64, error),
+) (*pb.ArithmeticResult, error) {
+	switch terms := in.Terms.(type) {
+	case *pb.ArithmeticTerms_Doubles:
+		result, err := doubleFunction(terms.Doubles.TermOne, terms.Doubles.TermTwo)
+		if err != nil {
+			return nil, arithmeticError(err, "%s(%v, %v)", name, terms.Doubles.TermOne, terms.Doubles.TermTwo)
+		}
+		return &pb.ArithmeticResult{Result: &pb.ArithmeticResult_DoubleResult{DoubleResult: result}}, nil
+	case *pb.ArithmeticTerms_Ints:
+		result, err := intFunction(terms.Ints.TermOne, terms.Ints.TermTwo)
+		if err != nil {
+			return nil, arithmeticError(err, "%s(%d, %d)", name, terms.Ints.TermOne, terms.Ints.TermTwo)
+		}
+		return &pb.ArithmeticResult{Result: &pb.ArithmeticResult_IntResult{IntResult: result}}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "%s needs either two doubles or two integers", name)
+	}
+}
+
+// This function turns an error from the math package into a gRPC error, whose message starts with the call.
+// Results which overflow are OUT_OF_RANGE, and every other error is INVALID_ARGUMENT.
+func arithmeticError(err error, format string, args ...any) error {
+	code := codes.InvalidArgument
+	if errors.Is(err, math.ErrOverflow) {
+		code = codes.OutOfRange
+	}
+
+	return status.Errorf(code, "%s: %v", fmt.Sprintf(format, args...), err)
+}
+
+// ========================================== Arbitrary Precision Functions ==========================================
+
+// MagicBigAdd takes a request context (which is ignored) and two decimal numbers, and returns their sum.
+func (s *MagicMath) MagicBigAdd(_ context.Context, in *pb.BigTerms) (*pb.BigResult, error) {
+	s.counters.Increment(counter.Big)
+
+	return s.bigArithmetic("add", in, s.calculator.Add)
+}
+
+// MagicBigSubtract takes a request context (which is ignored) and two decimal numbers, and returns their difference.
+func (s *MagicMath) MagicBigSubtract(_ context.Context, in *pb.BigTerms) (*pb.BigResult, error) {
+	s.counters.Increment(counter.Big)
+
+	return s.bigArithmetic("subtract", in, s.calculator.Subtract)
+}
+
+// MagicBigMultiply takes a request context (which is ignored) and two decimal numbers, and returns their product.
+func (s *MagicMath) MagicBigMultiply(_ context.Context, in *pb.BigTerms) (*pb.BigResult, error) {
+	s.counters.Increment(counter.Big)
+
+	return s.bigArithmetic("multiply", in, s.calculator.Multiply)
+}
+
+// MagicBigDivide takes a request context (which is ignored) and two decimal numbers, and returns the first divided by
+// the second. Integer division rounds towards zero.
+func (s *MagicMath) MagicBigDivide(_ context.Context, in *pb.BigTerms) (*pb.BigResult, error) {
+	s.counters.Increment(counter.Big)
+
+	return s.bigArithmetic("divide", in, s.calculator.Divide)
+}
+
+// MagicBigPower takes a request context (which is ignored) and two decimal numbers, and returns the first raised to
+// the power of the second, which must be a whole number.
+func (s *MagicMath) MagicBigPower(_ context.Context, in *pb.BigTerms) (*pb.BigResult, error) {
+	s.counters.Increment(counter.Big)
+
+	return s.bigArithmetic("power", in, s.calculator.Power)
+}
+
+// This function reads the kind of number, precision and rounding mode from the terms, runs the arbitrary precision
+// function with them, and turns the function's error into a gRPC error.
+func (s *MagicMath) bigArithmetic(
+	name string,
+	in *pb.BigTerms,
+	function func(a, b string, mode bignum.Mode) (string, error),
+) (*pb.BigResult, error) {
+	rounding, err := bignum.ParseRounding(in.Rounding)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: %v", name, err)
+	}
+	if in.Precision < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: precision %d is negative", name, in.Precision)
+	}
+
+	mode := bignum.Mode{Kind: in.Kind, Precision: uint(in.Precision), Rounding: rounding}
+	result, err := function(in.TermOne, in.TermTwo, mode)
+	if err != nil {
+		// The operands are cut short, as they may be very long.
+		return nil, arithmeticError(err, "%s(%.40q, %.40q)", name, in.TermOne, in.TermTwo)
+	}
+
+	return &pb.BigResult{Result: result}, nil
+}
+
+// ========================================== Counter Functions ==========================================
+
+// GetAddCount returns the total number of times MagicAdd has been called.
+func (s *MagicMath) GetAddCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Add)}, nil
+}
+
+// GetSubCount returns the total number of times MagicSubtract has been called.
+func (s *MagicMath) GetSubCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Subtract)}, nil
+}
+
+// GetMinCount returns the total number of times MagicFindMin has been called.
+func (s *MagicMath) GetMinCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.FindMin)}, nil
+}
+
+// GetMaxCount returns the total number of times MagicFindMax has been called.
+func (s *MagicMath) GetMaxCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.FindMax)}, nil
+}
+
+// GetMulCount returns the total number of times MagicMultiply has been called.
+func (s *MagicMath) GetMulCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Multiply)}, nil
+}
+
+// GetDivCount returns the total number of times MagicDivide has been called.
+func (s *MagicMath) GetDivCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Divide)}, nil
+}
+
+// GetModCount returns the total number of times MagicModulo has been called.
+func (s *MagicMath) GetModCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Modulo)}, nil
+}
+
+// GetPowCount returns the total number of times MagicPower has been called.
+func (s *MagicMath) GetPowCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Power)}, nil
+}
+
+// GetEvalCount returns the total number of times MagicEvaluate has been called.
+func (s *MagicMath) GetEvalCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Evaluate)}, nil
+}
+
+// GetStatsCount returns the total number of times MagicStats and MagicStatsStream have been called.
+func (s *MagicMath) GetStatsCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Stats)}, nil
+}
+
+// GetBigCount returns the total number of times the five arbitrary precision functions have been called.
+func (s *MagicMath) GetBigCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Big)}, nil
+}
+
+// GetDecimalCount returns the total number of times the four decimal functions have been called.
+func (s *MagicMath) GetDecimalCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Decimal)}, nil
+}
+
+// GetPanicCount returns the total number of calls which failed because the server panicked while handling them.
+func (s *MagicMath) GetPanicCount(_ context.Context, _ *pb.Empty) (*pb.Count, error) {
+	return &pb.Count{Count: s.counters.Count(counter.Panic)}, nil
+}
+
+// GetCacheStats returns how many calls have been answered from the result cache, and how many cacheable calls missed it,
+// along with how many results are in the cache and how many have been evicted from it.
+func (s *MagicMath) GetCacheStats(_ context.Context, _ *pb.Empty) (*pb.CacheStats, error) {
+	stats := &pb.CacheStats{Hits: s.counters.Count(counter.CacheHit), Misses: s.counters.Count(counter.CacheMiss)}
+	if s.cache != nil {
+		cacheStats := s.cache.Stats()
+		stats.Entries = int64(cacheStats.Entries)
+		stats.Evictions = cacheStats.Evictions
+	}
+
+	return stats, nil
+}
+
+// ========================================== Quota Functions ==========================================
+
+// GetQuota returns how much of its hourly and daily quotas the caller has used.
+// A server without limits reports quotas of zero, which means unlimited.
+func (s *MagicMath) GetQuota(ctx context.Context, _ *pb.Empty) (*pb.Quota, error) {
+	if s.limiter == nil {
+		return &pb.Quota{Caller: caller.ID(ctx)}, nil
+	}
+
+	usage := s.limiter.Usage(ctx)
+
+	return &pb.Quota{
+		Caller:      usage.Caller,
+		HourlyLimit: usage.HourlyLimit,
+		HourlyUsed:  usage.HourlyUsed,
+		HourlyReset: timestamppb.New(usage.HourlyReset),
+		DailyLimit:  usage.DailyLimit,
+		DailyUsed:   usage.DailyUsed,
+		DailyReset:  timestamppb.New(usage.DailyReset),
+	}, nil
+}
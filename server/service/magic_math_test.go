@@ -0,0 +1,630 @@
+package service_test
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/cache"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/faults"
+	"github.com/karldmenzel/go-grpc-client-server/server/idempotency"
+	"github.com/karldmenzel/go-grpc-client-server/server/loadshed"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/bignum"
+	"github.com/karldmenzel/go-grpc-client-server/server/ratelimit"
+	"github.com/karldmenzel/go-grpc-client-server/server/recovery"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMagicAddAndSubtract(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		name string
+		call func(context.Context, *pb.DoubleTerms, ...grpc.CallOption) (*pb.DoubleResult, error)
+		a, b float64
+		want float64
+	}{
+		{"MagicAdd", client.MagicAdd, 2.5, 3.5, 6},
+		{"MagicAdd", client.MagicAdd, -1, 1, 0},
+		{"MagicSubtract", client.MagicSubtract, 10, 3, 7},
+		{"MagicSubtract", client.MagicSubtract, 3, 10, -7},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.call(context.Background(), &pb.DoubleTerms{TermOne: tt.a, TermTwo: tt.b})
+		if err != nil {
+			t.Errorf("%s(%v, %v) returned error: %v", tt.name, tt.a, tt.b, err)
+			continue
+		}
+		if result.Result != tt.want {
+			t.Errorf("%s(%v, %v) = %v; want %v", tt.name, tt.a, tt.b, result.Result, tt.want)
+		}
+	}
+}
+
+func TestMagicFindMinAndMax(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		name    string
+		call    func(context.Context, *pb.IntTerms, ...grpc.CallOption) (*pb.IntResult, error)
+		a, b, c int64
+		want    int64
+	}{
+		{"MagicFindMin", client.MagicFindMin, 3, 5, 7, 3},
+		{"MagicFindMin", client.MagicFindMin, 10, -2, 8, -2},
+		{"MagicFindMax", client.MagicFindMax, 3, 5, 7, 7},
+		{"MagicFindMax", client.MagicFindMax, 10, -2, 8, 10},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.call(context.Background(), &pb.IntTerms{TermOne: tt.a, TermTwo: tt.b, TermThree: tt.c})
+		if err != nil {
+			t.Errorf("%s(%d, %d, %d) returned error: %v", tt.name, tt.a, tt.b, tt.c, err)
+			continue
+		}
+		if result.Result != tt.want {
+			t.Errorf("%s(%d, %d, %d) = %d; want %d", tt.name, tt.a, tt.b, tt.c, result.Result, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	doubles := func(a, b float64) *pb.ArithmeticTerms {
+		return &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Doubles{Doubles: &pb.DoubleTerms{TermOne: a, TermTwo: b}}}
+	}
+	ints := func(a, b int64) *pb.ArithmeticTerms {
+		return &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{Ints: &pb.IntPair{TermOne: a, TermTwo: b}}}
+	}
+	doubleResult := func(result float64) *pb.ArithmeticResult {
+		return &pb.ArithmeticResult{Result: &pb.ArithmeticResult_DoubleResult{DoubleResult: result}}
+	}
+	intResult := func(result int64) *pb.ArithmeticResult {
+		return &pb.ArithmeticResult{Result: &pb.ArithmeticResult_IntResult{IntResult: result}}
+	}
+
+	tests := []struct {
+		name     string
+		call     func(context.Context, *pb.ArithmeticTerms, ...grpc.CallOption) (*pb.ArithmeticResult, error)
+		terms    *pb.ArithmeticTerms
+		want     *pb.ArithmeticResult
+		wantCode codes.Code
+	}{
+		{"MagicMultiply", client.MagicMultiply, doubles(2.5, 4), doubleResult(10), codes.OK},
+		{"MagicMultiply", client.MagicMultiply, ints(-6, 7), intResult(-42), codes.OK},
+		{"MagicMultiply", client.MagicMultiply, ints(math.MaxInt64, 2), nil, codes.OutOfRange},
+		{"MagicMultiply", client.MagicMultiply, doubles(math.MaxFloat64, 2), nil, codes.OutOfRange},
+		{"MagicDivide", client.MagicDivide, doubles(7, 2), doubleResult(3.5), codes.OK},
+		{"MagicDivide", client.MagicDivide, ints(7, 2), intResult(3), codes.OK},
+		{"MagicDivide", client.MagicDivide, ints(7, 0), nil, codes.InvalidArgument},
+		{"MagicDivide", client.MagicDivide, doubles(7, 0), nil, codes.InvalidArgument},
+		{"MagicDivide", client.MagicDivide, ints(math.MinInt64, -1), nil, codes.OutOfRange},
+		{"MagicModulo", client.MagicModulo, ints(-7, 3), intResult(-1), codes.OK},
+		{"MagicModulo", client.MagicModulo, doubles(7.5, 2), doubleResult(1.5), codes.OK},
+		{"MagicModulo", client.MagicModulo, doubles(math.Inf(1), 2), nil, codes.InvalidArgument},
+		{"MagicPower", client.MagicPower, ints(2, 10), intResult(1024), codes.OK},
+		{"MagicPower", client.MagicPower, doubles(2, -1), doubleResult(0.5), codes.OK},
+		{"MagicPower", client.MagicPower, ints(2, -1), nil, codes.InvalidArgument},
+		{"MagicPower", client.MagicPower, ints(2, 64), nil, codes.OutOfRange},
+		{"MagicPower", client.MagicPower, doubles(math.NaN(), 1), nil, codes.InvalidArgument},
+		{"MagicPower", client.MagicPower, &pb.ArithmeticTerms{}, nil, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.call(context.Background(), tt.terms)
+		if status.Code(err) != tt.wantCode {
+			t.Errorf("%s(%v) returned error %v; want code %v", tt.name, tt.terms, err, tt.wantCode)
+			continue
+		}
+		if tt.want != nil && !proto.Equal(result, tt.want) {
+			t.Errorf("%s(%v) = %v; want %v", tt.name, tt.terms, result, tt.want)
+		}
+	}
+
+	// Every call is counted, including the ones which fail.
+	mulCount, err := client.GetMulCount(context.Background(), &pb.Empty{})
+	if err != nil || mulCount.Count != 4 {
+		t.Errorf("GetMulCount() = %v, %v; want 4", mulCount, err)
+	}
+}
+
+func TestBigArithmetic(t *testing.T) {
+	client := servertest.Start(t, servertest.WithBigLimits(bignum.Limits{MaxDigits: 100, MaxPrecision: 1024})).Client
+
+	tests := []struct {
+		name     string
+		call     func(context.Context, *pb.BigTerms, ...grpc.CallOption) (*pb.BigResult, error)
+		terms    *pb.BigTerms
+		want     string
+		wantCode codes.Code
+	}{
+		{"MagicBigAdd", client.MagicBigAdd, &pb.BigTerms{TermOne: "0.1", TermTwo: "0.2"}, "0.3", codes.OK},
+		{"MagicBigAdd", client.MagicBigAdd,
+			&pb.BigTerms{TermOne: "9223372036854775807", TermTwo: "1", Kind: "int"}, "9223372036854775808", codes.OK},
+		{"MagicBigSubtract", client.MagicBigSubtract, &pb.BigTerms{TermOne: "1/2", TermTwo: "1/3"}, "1/6", codes.OK},
+		{"MagicBigMultiply", client.MagicBigMultiply, &pb.BigTerms{TermOne: "19.99", TermTwo: "3"}, "59.97", codes.OK},
+		{"MagicBigDivide", client.MagicBigDivide,
+			&pb.BigTerms{TermOne: "2", TermTwo: "3", Kind: "float", Precision: 10, Rounding: "to_zero"}, "0.666", codes.OK},
+		{"MagicBigPower", client.MagicBigPower, &pb.BigTerms{TermOne: "2", TermTwo: "64", Kind: "int"},
+			"18446744073709551616", codes.OK},
+		{"MagicBigDivide", client.MagicBigDivide, &pb.BigTerms{TermOne: "1", TermTwo: "0"}, "", codes.InvalidArgument},
+		{"MagicBigAdd", client.MagicBigAdd, &pb.BigTerms{TermOne: "one", TermTwo: "1"}, "", codes.InvalidArgument},
+		{"MagicBigAdd", client.MagicBigAdd, &pb.BigTerms{TermOne: "1", TermTwo: "1", Rounding: "up"}, "", codes.InvalidArgument},
+		{"MagicBigAdd", client.MagicBigAdd,
+			&pb.BigTerms{TermOne: "1", TermTwo: "1", Kind: "float", Precision: 2048}, "", codes.InvalidArgument},
+		{"MagicBigAdd", client.MagicBigAdd, &pb.BigTerms{TermOne: "1e1000000", TermTwo: "1"}, "", codes.InvalidArgument},
+		{"MagicBigPower", client.MagicBigPower, &pb.BigTerms{TermOne: "10", TermTwo: "101"}, "", codes.OutOfRange},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.call(context.Background(), tt.terms)
+		if status.Code(err) != tt.wantCode {
+			t.Errorf("%s(%v) returned error %v; want code %v", tt.name, tt.terms, err, tt.wantCode)
+			continue
+		}
+		if result.GetResult() != tt.want {
+			t.Errorf("%s(%v) = %q; want %q", tt.name, tt.terms, result.GetResult(), tt.want)
+		}
+	}
+
+	// The five functions share a counter, which counts the calls which fail as well.
+	bigCount, err := client.GetBigCount(context.Background(), &pb.Empty{})
+	if err != nil || bigCount.Count != int64(len(tests)) {
+		t.Errorf("GetBigCount() = %v, %v; want %d", bigCount, err, len(tests))
+	}
+}
+
+// callEach calls each math function the given number of times, all at once from separate go routines.
+func callEach(t *testing.T, client pb.MagicMathClient, times int) {
+	t.Helper()
+
+	ctx := context.Background()
+	doubles := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+	ints := &pb.IntTerms{TermOne: 1, TermTwo: 2, TermThree: 3}
+	arithmeticTerms := &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{Ints: &pb.IntPair{TermOne: 6, TermTwo: 3}}}
+	expression := &pb.Expression{Expression: "min(x, 2) * 3", Variables: map[string]float64{"x": 1}}
+	arithmeticCalls := []struct {
+		name string
+		call func(context.Context, *pb.ArithmeticTerms, ...grpc.CallOption) (*pb.ArithmeticResult, error)
+	}{
+		{"MagicMultiply", client.MagicMultiply},
+		{"MagicDivide", client.MagicDivide},
+		{"MagicModulo", client.MagicModulo},
+		{"MagicPower", client.MagicPower},
+	}
+
+	var waitGroup sync.WaitGroup
+	for range times {
+		waitGroup.Go(func() {
+			if _, err := client.MagicAdd(ctx, doubles); err != nil {
+				t.Errorf("MagicAdd() returned error: %v", err)
+			}
+		})
+		waitGroup.Go(func() {
+			if _, err := client.MagicSubtract(ctx, doubles); err != nil {
+				t.Errorf("MagicSubtract() returned error: %v", err)
+			}
+		})
+		waitGroup.Go(func() {
+			if _, err := client.MagicFindMin(ctx, ints); err != nil {
+				t.Errorf("MagicFindMin() returned error: %v", err)
+			}
+		})
+		waitGroup.Go(func() {
+			if _, err := client.MagicFindMax(ctx, ints); err != nil {
+				t.Errorf("MagicFindMax() returned error: %v", err)
+			}
+		})
+		waitGroup.Go(func() {
+			if _, err := client.MagicEvaluate(ctx, expression); err != nil {
+				t.Errorf("MagicEvaluate() returned error: %v", err)
+			}
+		})
+		for _, arithmeticCall := range arithmeticCalls {
+			waitGroup.Go(func() {
+				if _, err := arithmeticCall.call(ctx, arithmeticTerms); err != nil {
+					t.Errorf("%s() returned error: %v", arithmeticCall.name, err)
+				}
+			})
+		}
+	}
+	waitGroup.Wait()
+}
+
+// checkCounts checks that every Get*Count function returns the expected count.
+func checkCounts(t *testing.T, client pb.MagicMathClient, want int64) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		call func(context.Context, *pb.Empty, ...grpc.CallOption) (*pb.Count, error)
+	}{
+		{"GetAddCount", client.GetAddCount},
+		{"GetSubCount", client.GetSubCount},
+		{"GetMinCount", client.GetMinCount},
+		{"GetMaxCount", client.GetMaxCount},
+		{"GetMulCount", client.GetMulCount},
+		{"GetDivCount", client.GetDivCount},
+		{"GetModCount", client.GetModCount},
+		{"GetPowCount", client.GetPowCount},
+		{"GetEvalCount", client.GetEvalCount},
+	}
+
+	for _, tt := range tests {
+		count, err := tt.call(context.Background(), &pb.Empty{})
+		if err != nil {
+			t.Errorf("%s() returned error: %v", tt.name, err)
+			continue
+		}
+		if count.Count != want {
+			t.Errorf("%s() = %d; want %d", tt.name, count.Count, want)
+		}
+	}
+}
+
+func TestCountersStartAtZero(t *testing.T) {
+	checkCounts(t, servertest.Start(t).Client, 0)
+}
+
+func TestConcurrentCounters(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	callEach(t, client, 250)
+	checkCounts(t, client, 250)
+}
+
+func TestCustomCounterStore(t *testing.T) {
+	store := counter.NewMemoryStore()
+	client := servertest.Start(t, servertest.WithCounterStore(store)).Client
+
+	callEach(t, client, 3)
+
+	if got := store.Count(counter.Add); got != 3 {
+		t.Errorf("store.Count(%q) = %d; want 3", counter.Add, got)
+	}
+}
+
+func TestCachedCallsAreCounted(t *testing.T) {
+	store := counter.NewMemoryStore()
+	client := servertest.Start(t,
+		servertest.WithCounterStore(store),
+		servertest.WithCache(cache.Options{MaxEntries: 4}),
+	).Client
+
+	callEach(t, client, 50)
+	checkCounts(t, client, 50)
+
+	// Every function callEach calls but MagicEvaluate is cached, so there are 50 calls to each of eight cached functions.
+	stats, err := client.GetCacheStats(context.Background(), &pb.Empty{})
+	if err != nil || stats.Hits+stats.Misses != 8*50 || stats.Hits == 0 || stats.Misses < 8 {
+		t.Errorf("GetCacheStats() = %v, %v; want 400 calls, with at least 8 misses and some hits", stats, err)
+	}
+	// The cache only holds four results, so the results of the other functions have been evicted.
+	if stats.Entries != 4 || stats.Evictions == 0 {
+		t.Errorf("GetCacheStats() = %v; want 4 entries and some evictions", stats)
+	}
+}
+
+func TestIdempotentCallsAreCountedOnce(t *testing.T) {
+	client := servertest.Start(t, servertest.WithDeduplicator(idempotency.New(idempotency.Options{}))).Client
+	ctx := metadata.AppendToOutgoingContext(context.Background(), idempotency.MetadataKey, "payment-42")
+
+	// Submit the same call many times at once, as a client retrying aggressively might.
+	var waitGroup sync.WaitGroup
+	for range 50 {
+		waitGroup.Go(func() {
+			result, err := client.MagicAdd(ctx, &pb.DoubleTerms{TermOne: 1, TermTwo: 2})
+			if err != nil || result.Result != 3 {
+				t.Errorf("MagicAdd() = %v, %v; want 3", result, err)
+			}
+		})
+	}
+	waitGroup.Wait()
+
+	if count, err := client.GetAddCount(context.Background(), &pb.Empty{}); err != nil || count.Count != 1 {
+		t.Errorf("GetAddCount() = %v, %v; want 1", count, err)
+	}
+}
+
+func TestInvalidRequestsAreRejected(t *testing.T) {
+	counters := counter.NewMemoryStore()
+	client := servertest.Start(t, servertest.WithCounterStore(counters)).Client
+
+	request := &pb.StatsRequest{Values: []float64{math.NaN()}, Quantiles: []float64{1.5}}
+	_, err := client.MagicStats(context.Background(), request)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("MagicStats(%v) returned error %v; want code %v", request, err, codes.InvalidArgument)
+	}
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		}
+	}
+	if !slices.Equal(fields, []string{"values[0]", "quantiles[0]"}) {
+		t.Errorf("MagicStats(%v) has field violations %v; want [values[0] quantiles[0]]", request, fields)
+	}
+
+	// The handler never sees the call, so it isn't counted.
+	if count := counters.Count(counter.Stats); count != 0 {
+		t.Errorf("Count(%q) = %d; want 0", counter.Stats, count)
+	}
+
+	// Streamed messages are checked one by one.
+	stream, err := client.MagicStatsStream(context.Background())
+	if err != nil {
+		t.Fatalf("MagicStatsStream() returned error: %v", err)
+	}
+	if err := stream.Send(&pb.StatsRequest{Values: []float64{1, 2}}); err != nil {
+		t.Fatalf("Send() returned error: %v", err)
+	}
+	if err := stream.Send(&pb.StatsRequest{Quantiles: []float64{2}}); err != nil {
+		t.Fatalf("Send() returned error: %v", err)
+	}
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CloseAndRecv() returned error %v; want code %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestOverTLS(t *testing.T) {
+	client := servertest.Start(t, servertest.WithTLS()).Client
+
+	callEach(t, client, 2)
+	checkCounts(t, client, 2)
+}
+
+func TestInterceptorsSeeEveryCall(t *testing.T) {
+	var mutex sync.Mutex
+	seen := make(map[string]int)
+	interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		mutex.Lock()
+		seen[info.FullMethod]++
+		mutex.Unlock()
+		return handler(ctx, req)
+	}
+
+	client := servertest.Start(t, servertest.WithUnaryInterceptors(interceptor)).Client
+	callEach(t, client, 1)
+	checkCounts(t, client, 1)
+
+	for _, method := range []string{
+		pb.MagicMath_MagicAdd_FullMethodName, pb.MagicMath_MagicSubtract_FullMethodName,
+		pb.MagicMath_MagicFindMin_FullMethodName, pb.MagicMath_MagicFindMax_FullMethodName,
+		pb.MagicMath_GetAddCount_FullMethodName, pb.MagicMath_GetSubCount_FullMethodName,
+		pb.MagicMath_GetMinCount_FullMethodName, pb.MagicMath_GetMaxCount_FullMethodName,
+		pb.MagicMath_MagicMultiply_FullMethodName, pb.MagicMath_MagicDivide_FullMethodName,
+		pb.MagicMath_MagicModulo_FullMethodName, pb.MagicMath_MagicPower_FullMethodName,
+		pb.MagicMath_GetMulCount_FullMethodName, pb.MagicMath_GetDivCount_FullMethodName,
+		pb.MagicMath_GetModCount_FullMethodName, pb.MagicMath_GetPowCount_FullMethodName,
+		pb.MagicMath_MagicEvaluate_FullMethodName, pb.MagicMath_GetEvalCount_FullMethodName,
+	} {
+		if seen[method] != 1 {
+			t.Errorf("interceptor saw %s %d times; want 1", method, seen[method])
+		}
+	}
+}
+
+func TestRateLimitsAndQuota(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{
+		Methods:     map[string]ratelimit.Limit{ratelimit.Any: {Rate: 0.001, Burst: 2}},
+		HourlyQuota: 100,
+	}, counter.NewMemoryStore())
+	client := servertest.Start(t, servertest.WithRateLimiter(limiter)).Client
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), caller.MetadataKey, "alice")
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+	for i := range 2 {
+		if _, err := client.MagicAdd(ctx, terms); err != nil {
+			t.Fatalf("MagicAdd() call %d returned error: %v", i, err)
+		}
+	}
+
+	_, err := client.MagicAdd(ctx, terms)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("MagicAdd() over the limit returned %v; want ResourceExhausted", err)
+	}
+	details := status.Convert(err).Details()
+	if len(details) == 0 {
+		t.Fatalf("MagicAdd() over the limit returned no details; want RetryInfo")
+	}
+	if info, ok := details[0].(*errdetails.RetryInfo); !ok || info.RetryDelay.AsDuration() <= 0 {
+		t.Errorf("MagicAdd() over the limit returned detail %v; want RetryInfo with a delay", details[0])
+	}
+
+	// GetQuota is never limited, however often it is called.
+	for range 3 {
+		quota, err := client.GetQuota(ctx, &pb.Empty{})
+		if err != nil {
+			t.Fatalf("GetQuota() returned error: %v", err)
+		}
+		if quota.Caller != "alice" || quota.HourlyLimit != 100 || quota.HourlyUsed != 2 {
+			t.Errorf("GetQuota() = %v; want alice with 2 of 100 hourly calls used", quota)
+		}
+	}
+}
+
+func TestGetQuotaWithoutLimits(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), caller.MetadataKey, "bob")
+	quota, err := client.GetQuota(ctx, &pb.Empty{})
+	if err != nil {
+		t.Fatalf("GetQuota() returned error: %v", err)
+	}
+	if quota.Caller != "bob" || quota.HourlyLimit != 0 || quota.DailyLimit != 0 {
+		t.Errorf("GetQuota() = %v; want bob with no limits", quota)
+	}
+}
+
+// blockingStore is a counter store whose increments wait until it is unblocked, which holds up the calls to the math
+// functions inside the server.
+type blockingStore struct {
+	*counter.MemoryStore
+	unblock chan struct{}
+}
+
+func (s *blockingStore) Increment(name string) {
+	<-s.unblock
+	s.MemoryStore.Increment(name)
+}
+
+func TestConcurrencyLimiterShedsCalls(t *testing.T) {
+	store := &blockingStore{MemoryStore: counter.NewMemoryStore(), unblock: make(chan struct{})}
+	limiter := loadshed.New(loadshed.Options{MinLimit: 1, MaxLimit: 1, QueueSize: 1, MaxQueueWait: time.Minute})
+	client := servertest.Start(t, servertest.WithCounterStore(store), servertest.WithConcurrencyLimiter(limiter)).Client
+
+	// The first call takes the only slot, and the second waits in the queue.
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+	var waitGroup sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		waitGroup.Go(func() {
+			_, errs[i] = client.MagicAdd(context.Background(), terms)
+		})
+		for stats := limiter.Stats(); stats.InFlight+stats.Queued != i+1; stats = limiter.Stats() {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// The queue is full, so a low priority call is shed straight away.
+	ctx := metadata.AppendToOutgoingContext(context.Background(), loadshed.MetadataKey, "low")
+	if _, err := client.MagicAdd(ctx, terms); status.Code(err) != codes.Unavailable {
+		t.Errorf("MagicAdd() on an overloaded server returned %v; want Unavailable", err)
+	}
+
+	close(store.unblock)
+	waitGroup.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("MagicAdd() call %d returned error: %v", i, err)
+		}
+	}
+	if got := store.Count(counter.Add); got != 2 {
+		t.Errorf("add count = %d; want 2", got)
+	}
+}
+
+// panickingStore is a counter store which panics when the add counter is incremented, like a bug in a handler would.
+type panickingStore struct {
+	*counter.MemoryStore
+}
+
+func (s panickingStore) Increment(name string) {
+	if name == counter.Add {
+		panic("add counter is broken")
+	}
+	s.MemoryStore.Increment(name)
+}
+
+func TestPanicsAreRecovered(t *testing.T) {
+	store := panickingStore{counter.NewMemoryStore()}
+	server := servertest.Start(t, servertest.WithCounterStore(store))
+	healthClient := healthpb.NewHealthClient(server.Conn)
+
+	for range 2 {
+		_, err := server.Client.MagicAdd(context.Background(), &pb.DoubleTerms{TermOne: 1, TermTwo: 2})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("MagicAdd() returned %v; want Internal", err)
+		}
+	}
+
+	// The server keeps serving, and counts the panics.
+	if _, err := server.Client.MagicSubtract(context.Background(), &pb.DoubleTerms{TermOne: 1, TermTwo: 2}); err != nil {
+		t.Errorf("MagicSubtract() after a panic returned error: %v", err)
+	}
+	count, err := server.Client.GetPanicCount(context.Background(), &pb.Empty{})
+	if err != nil || count.Count != 2 {
+		t.Errorf("GetPanicCount() = %v, %v; want 2", count, err)
+	}
+
+	response, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: pb.MagicMath_ServiceDesc.ServiceName})
+	if err != nil || response.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("health Check() = %v, %v; want SERVING, panics only fail the health check when a threshold is set", response, err)
+	}
+}
+
+func TestHealthFailsAfterPanicThreshold(t *testing.T) {
+	store := panickingStore{counter.NewMemoryStore()}
+	healthServer := health.NewServer()
+	recoverer := recovery.New(recovery.Options{Counters: store, Health: healthServer, UnhealthyAfter: 1})
+	server := servertest.Start(t, servertest.WithCounterStore(store), servertest.WithHealth(healthServer),
+		servertest.WithRecoverer(recoverer))
+	healthClient := healthpb.NewHealthClient(server.Conn)
+
+	server.Client.MagicAdd(context.Background(), &pb.DoubleTerms{TermOne: 1, TermTwo: 2})
+
+	response, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil || response.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("health Check() = %v, %v; want NOT_SERVING", response, err)
+	}
+}
+
+func TestFaultInjectionAtRuntime(t *testing.T) {
+	server := servertest.Start(t, servertest.WithFaultInjector(faults.New()))
+	admin := pb.NewFaultInjectionClient(server.Conn)
+	terms := &pb.DoubleTerms{TermOne: 1, TermTwo: 2}
+
+	// No faults are injected until the rules are set.
+	if _, err := server.Client.MagicAdd(context.Background(), terms); err != nil {
+		t.Fatalf("MagicAdd() without faults returned error: %v", err)
+	}
+
+	_, err := admin.SetFaults(context.Background(), &pb.FaultRules{Rules: []*pb.FaultRule{{
+		Method:     "MagicAdd",
+		Percentage: 100,
+		Fault:      &pb.FaultRule_Error{Error: &pb.ErrorFault{Code: int32(codes.Unavailable), Message: "injected"}},
+	}}})
+	if err != nil {
+		t.Fatalf("SetFaults() returned error: %v", err)
+	}
+
+	for range 3 {
+		if _, err := server.Client.MagicAdd(context.Background(), terms); status.Code(err) != codes.Unavailable {
+			t.Errorf("MagicAdd() with an injected error returned %v; want Unavailable", err)
+		}
+	}
+	if _, err := server.Client.MagicSubtract(context.Background(), terms); err != nil {
+		t.Errorf("MagicSubtract() returned error: %v; want no fault, the rule only targets MagicAdd", err)
+	}
+
+	rules, err := admin.GetFaults(context.Background(), &pb.FaultRulesRequest{})
+	if err != nil || len(rules.Rules) != 1 || rules.Rules[0].Injected != 3 {
+		t.Errorf("GetFaults() = %v, %v; want one rule which injected 3 faults", rules, err)
+	}
+
+	// Clearing the rules stops the faults.
+	if _, err := admin.SetFaults(context.Background(), &pb.FaultRules{}); err != nil {
+		t.Fatalf("SetFaults() returned error: %v", err)
+	}
+	if _, err := server.Client.MagicAdd(context.Background(), terms); err != nil {
+		t.Errorf("MagicAdd() after clearing the faults returned error: %v", err)
+	}
+}
+
+func TestFaultInjectionIsOffByDefault(t *testing.T) {
+	server := servertest.Start(t)
+
+	_, err := pb.NewFaultInjectionClient(server.Conn).GetFaults(context.Background(), &pb.FaultRulesRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("GetFaults() on a server without fault injection returned %v; want Unimplemented", err)
+	}
+}
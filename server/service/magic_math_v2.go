@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	pbv2 "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MagicMathV2 implements version 2 of the MagicMath service. It shares its counters with version 1, so the two
+// versions can be served side by side while clients move from one to the other.
+type MagicMathV2 struct {
+	pbv2.UnsafeMagicMathServer
+
+	// This stores how many times each function has been called, through either version.
+	counters counter.Store
+}
+
+// NewV2 creates the version 2 server object, which keeps its function counters in the given store.
+// Give it the same store as the version 1 server object, so that both count every call.
+func NewV2(counters counter.Store) *MagicMathV2 {
+	return &MagicMathV2{counters: counters}
+}
+
+// operationV2 is how Calculate does an operation.
+type operationV2 struct {
+	// name is used in error messages, and counter is the version 1 counter of the operation.
+	name, counter string
+	// binary operations take exactly two operands, the others fold any number of operands from left to right.
+	binary bool
+	double func(a, b float64) (float64, error)
+	int    func(a, b int64) (int64, error)
+}
+
+// These are the operations Calculate can do.
+var operationsV2 = map[pbv2.Operation]operationV2{
+	pbv2.Operation_OPERATION_ADD:      {"add", counter.Add, false, math.LocalCheckedAdd, math.LocalAddInt},
+	pbv2.Operation_OPERATION_MULTIPLY: {"multiply", counter.Multiply, false, math.LocalMultiply, math.LocalMultiplyInt},
+	pbv2.Operation_OPERATION_MIN:      {"min", counter.FindMin, false, lesser[float64], lesser[int64]},
+	pbv2.Operation_OPERATION_MAX:      {"max", counter.FindMax, false, greater[float64], greater[int64]},
+	pbv2.Operation_OPERATION_SUBTRACT: {"subtract", counter.Subtract, true, math.LocalCheckedSubtract, math.LocalSubtractInt},
+	pbv2.Operation_OPERATION_DIVIDE:   {"divide", counter.Divide, true, math.LocalDivide, math.LocalDivideInt},
+	pbv2.Operation_OPERATION_MODULO:   {"modulo", counter.Modulo, true, math.LocalModulo, math.LocalModuloInt},
+	pbv2.Operation_OPERATION_POWER:    {"power", counter.Power, true, math.LocalPower, math.LocalPowerInt},
+}
+
+// This function returns the lesser of two numbers, it never fails.
+func lesser[T float64 | int64](a, b T) (T, error) {
+	return min(a, b), nil
+}
+
+// This function returns the greater of two numbers, it never fails.
+func greater[T float64 | int64](a, b T) (T, error) {
+	return max(a, b), nil
+}
+
+// Calculate takes a request context (which is ignored), an operation and its operands, which are all doubles or all
+// integers, and returns the result of the operation. Every call with a known operation is counted, including the ones
+// which fail, under the counter of the matching version 1 function.
+func (s *MagicMathV2) Calculate(_ context.Context, in *pbv2.CalculateRequest) (*pbv2.CalculateResponse, error) {
+	operation, ok := operationsV2[in.Operation]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown operation %v", in.Operation)
+	}
+	s.counters.Increment(operation.counter)
+
+	switch operands := in.Operands.(type) {
+	case *pbv2.CalculateRequest_Doubles:
+		result, err := fold(operation, operands.Doubles.GetValues(), operation.double)
+		if err != nil {
+			return nil, err
+		}
+		return &pbv2.CalculateResponse{Result: &pbv2.CalculateResponse_DoubleResult{DoubleResult: result}}, nil
+	case *pbv2.CalculateRequest_Integers:
+		result, err := fold(operation, operands.Integers.GetValues(), operation.int)
+		if err != nil {
+			return nil, err
+		}
+		return &pbv2.CalculateResponse{Result: &pbv2.CalculateResponse_IntegerResult{IntegerResult: result}}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "%s needs either doubles or integers", operation.name)
+	}
+}
+
+// This function applies the function of an operation to its operands from left to right, and turns the function's
+// error into a gRPC error.
+func fold[T float64 | int64](operation operationV2, operands []T, function func(a, b T) (T, error)) (T, error) {
+	if len(operands) < 2 || (operation.binary && len(operands) != 2) {
+		count := "two or more"
+		if operation.binary {
+			count = "exactly two"
+		}
+		return 0, status.Errorf(codes.InvalidArgument, "%s needs %s operands, not %d", operation.name, count, len(operands))
+	}
+
+	result := operands[0]
+	for _, operand := range operands[1:] {
+		var err error
+		if result, err = function(result, operand); err != nil {
+			return 0, arithmeticError(err, "%s(%s)", operation.name, joinOperands(operands))
+		}
+	}
+
+	return result, nil
+}
+
+// This function writes operands as a comma separated list, such as "7, 0".
+func joinOperands[T float64 | int64](operands []T) string {
+	text := make([]string, len(operands))
+	for i, operand := range operands {
+		text[i] = fmt.Sprint(operand)
+	}
+
+	return strings.Join(text, ", ")
+}
+
+// GetCounters takes a request context (which is ignored) and the names of counters, and returns their values.
+// Without any names it returns every counter.
+func (s *MagicMathV2) GetCounters(_ context.Context, in *pbv2.GetCountersRequest) (*pbv2.GetCountersResponse, error) {
+	names := in.Names
+	if len(names) == 0 {
+		names = counter.Names
+	}
+
+	counters := make(map[string]int64, len(names))
+	for _, name := range names {
+		if !slices.Contains(counter.Names, name) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown counter %q, the counters are %s",
+				name, strings.Join(counter.Names, ", "))
+		}
+		counters[name] = s.counters.Count(name)
+	}
+
+	return &pbv2.GetCountersResponse{Counters: counters}, nil
+}
@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	pbv2 "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/ratelimit"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// This function creates a Calculate request with double operands.
+func doublesV2(operation pbv2.Operation, values ...float64) *pbv2.CalculateRequest {
+	return &pbv2.CalculateRequest{
+		Operation: operation,
+		Operands:  &pbv2.CalculateRequest_Doubles{Doubles: &pbv2.DoubleOperands{Values: values}},
+	}
+}
+
+// This function creates a Calculate request with integer operands.
+func integersV2(operation pbv2.Operation, values ...int64) *pbv2.CalculateRequest {
+	return &pbv2.CalculateRequest{
+		Operation: operation,
+		Operands:  &pbv2.CalculateRequest_Integers{Integers: &pbv2.IntegerOperands{Values: values}},
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	client := pbv2.NewMagicMathClient(servertest.Start(t).Conn)
+
+	doubleResult := func(result float64) *pbv2.CalculateResponse {
+		return &pbv2.CalculateResponse{Result: &pbv2.CalculateResponse_DoubleResult{DoubleResult: result}}
+	}
+	integerResult := func(result int64) *pbv2.CalculateResponse {
+		return &pbv2.CalculateResponse{Result: &pbv2.CalculateResponse_IntegerResult{IntegerResult: result}}
+	}
+
+	tests := []struct {
+		request  *pbv2.CalculateRequest
+		want     *pbv2.CalculateResponse
+		wantCode codes.Code
+	}{
+		{doublesV2(pbv2.Operation_OPERATION_ADD, 1.5, 2, 3), doubleResult(6.5), codes.OK},
+		{integersV2(pbv2.Operation_OPERATION_ADD, 1, 2, 3, 4), integerResult(10), codes.OK},
+		{integersV2(pbv2.Operation_OPERATION_ADD, math.MaxInt64, 1), nil, codes.OutOfRange},
+		{doublesV2(pbv2.Operation_OPERATION_ADD, math.MaxFloat64, math.MaxFloat64), nil, codes.OutOfRange},
+		{integersV2(pbv2.Operation_OPERATION_MULTIPLY, 2, -3, 4), integerResult(-24), codes.OK},
+		{doublesV2(pbv2.Operation_OPERATION_MIN, 3, -1.5, 2), doubleResult(-1.5), codes.OK},
+		{integersV2(pbv2.Operation_OPERATION_MIN, 3, 5, 7, 1), integerResult(1), codes.OK},
+		{integersV2(pbv2.Operation_OPERATION_MAX, 10, -2, 8), integerResult(10), codes.OK},
+		{doublesV2(pbv2.Operation_OPERATION_SUBTRACT, 10, 3), doubleResult(7), codes.OK},
+		{integersV2(pbv2.Operation_OPERATION_SUBTRACT, math.MinInt64, 1), nil, codes.OutOfRange},
+		{doublesV2(pbv2.Operation_OPERATION_SUBTRACT, 10, 3, 2), nil, codes.InvalidArgument},
+		{integersV2(pbv2.Operation_OPERATION_DIVIDE, -7, 2), integerResult(-3), codes.OK},
+		{integersV2(pbv2.Operation_OPERATION_DIVIDE, 7, 0), nil, codes.InvalidArgument},
+		{integersV2(pbv2.Operation_OPERATION_MODULO, -7, 3), integerResult(-1), codes.OK},
+		{doublesV2(pbv2.Operation_OPERATION_POWER, 2, -1), doubleResult(0.5), codes.OK},
+		{integersV2(pbv2.Operation_OPERATION_POWER, 2, 64), nil, codes.OutOfRange},
+		{integersV2(pbv2.Operation_OPERATION_UNSPECIFIED, 1, 2), nil, codes.InvalidArgument},
+		{&pbv2.CalculateRequest{Operation: pbv2.Operation_OPERATION_ADD}, nil, codes.InvalidArgument},
+		// These break the validation rules on the operands, so they never reach the handler.
+		{integersV2(pbv2.Operation_OPERATION_ADD, 1), nil, codes.InvalidArgument},
+		{doublesV2(pbv2.Operation_OPERATION_ADD, 1, math.NaN()), nil, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		result, err := client.Calculate(context.Background(), tt.request)
+		if status.Code(err) != tt.wantCode {
+			t.Errorf("Calculate(%v) returned error %v; want code %v", tt.request, err, tt.wantCode)
+			continue
+		}
+		if tt.want != nil && !proto.Equal(result, tt.want) {
+			t.Errorf("Calculate(%v) = %v; want %v", tt.request, result, tt.want)
+		}
+	}
+}
+
+func TestGetCounters(t *testing.T) {
+	client := pbv2.NewMagicMathClient(servertest.Start(t).Conn)
+
+	if _, err := client.Calculate(context.Background(), doublesV2(pbv2.Operation_OPERATION_ADD, 1, 2)); err != nil {
+		t.Fatalf("Calculate(add 1, 2) returned error: %v", err)
+	}
+
+	all, err := client.GetCounters(context.Background(), &pbv2.GetCountersRequest{})
+	if err != nil {
+		t.Fatalf("GetCounters() returned error: %v", err)
+	}
+	if len(all.Counters) != len(counter.Names) || all.Counters[counter.Add] != 1 || all.Counters[counter.Subtract] != 0 {
+		t.Errorf("GetCounters() = %v; want every counter, with add 1 and the rest 0", all.Counters)
+	}
+
+	some, err := client.GetCounters(context.Background(), &pbv2.GetCountersRequest{Names: []string{counter.Add}})
+	if err != nil || len(some.Counters) != 1 || some.Counters[counter.Add] != 1 {
+		t.Errorf("GetCounters(add) = %v, %v; want add 1", some, err)
+	}
+
+	_, err = client.GetCounters(context.Background(), &pbv2.GetCountersRequest{Names: []string{"addition"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetCounters(addition) returned error %v; want code %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestVersionsShareCounters(t *testing.T) {
+	server := servertest.Start(t)
+	clientV2 := pbv2.NewMagicMathClient(server.Conn)
+
+	// Two additions and a multiplication through each version.
+	for range 2 {
+		if _, err := server.Client.MagicAdd(context.Background(), &pb.DoubleTerms{TermOne: 1, TermTwo: 2}); err != nil {
+			t.Fatalf("MagicAdd() returned error: %v", err)
+		}
+		if _, err := clientV2.Calculate(context.Background(), doublesV2(pbv2.Operation_OPERATION_ADD, 1, 2)); err != nil {
+			t.Fatalf("Calculate(add) returned error: %v", err)
+		}
+	}
+	multiply := &pb.ArithmeticTerms{Terms: &pb.ArithmeticTerms_Ints{Ints: &pb.IntPair{TermOne: 2, TermTwo: 3}}}
+	if _, err := server.Client.MagicMultiply(context.Background(), multiply); err != nil {
+		t.Fatalf("MagicMultiply() returned error: %v", err)
+	}
+	if _, err := clientV2.Calculate(context.Background(), integersV2(pbv2.Operation_OPERATION_MULTIPLY, 2, 3)); err != nil {
+		t.Fatalf("Calculate(multiply) returned error: %v", err)
+	}
+
+	if count, err := server.Client.GetAddCount(context.Background(), &pb.Empty{}); err != nil || count.Count != 4 {
+		t.Errorf("GetAddCount() = %v, %v; want 4", count, err)
+	}
+	if count, err := server.Client.GetMulCount(context.Background(), &pb.Empty{}); err != nil || count.Count != 2 {
+		t.Errorf("GetMulCount() = %v, %v; want 2", count, err)
+	}
+	counters, err := clientV2.GetCounters(context.Background(), &pbv2.GetCountersRequest{})
+	if err != nil || counters.Counters[counter.Add] != 4 || counters.Counters[counter.Multiply] != 2 {
+		t.Errorf("GetCounters() = %v, %v; want add 4 and mul 2", counters, err)
+	}
+}
+
+func TestQuotaCoversBothVersions(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{HourlyQuota: 3}, counter.NewMemoryStore())
+	server := servertest.Start(t, servertest.WithRateLimiter(limiter))
+	clientV2 := pbv2.NewMagicMathClient(server.Conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), caller.MetadataKey, "alice")
+	if _, err := server.Client.MagicAdd(ctx, &pb.DoubleTerms{TermOne: 1, TermTwo: 2}); err != nil {
+		t.Fatalf("MagicAdd() returned error: %v", err)
+	}
+	for i := range 2 {
+		if _, err := clientV2.Calculate(ctx, doublesV2(pbv2.Operation_OPERATION_ADD, 1, 2)); err != nil {
+			t.Fatalf("Calculate() call %d returned error: %v", i, err)
+		}
+	}
+
+	_, err := clientV2.Calculate(ctx, doublesV2(pbv2.Operation_OPERATION_ADD, 1, 2))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Calculate() over the quota returned %v; want ResourceExhausted", err)
+	}
+}
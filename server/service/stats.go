@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/math"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/stats"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ========================================== Statistics Functions ==========================================
+
+// MagicStats takes a request context (which is ignored) and a dataset, and returns its summary statistics.
+func (s *MagicMath) MagicStats(_ context.Context, in *pb.StatsRequest) (*pb.Stats, error) {
+	s.counters.Increment(counter.Stats)
+
+	accumulator := stats.NewAccumulator(stats.DefaultExactLimit)
+	if err := addValues(accumulator, in.Values); err != nil {
+		return nil, err
+	}
+
+	return summarize(accumulator, in.Quantiles)
+}
+
+// MagicStatsStream receives a dataset in any number of messages, and once the client has sent them all returns the
+// summary statistics of the whole dataset. The values are never all held in memory.
+func (s *MagicMath) MagicStatsStream(stream grpc.ClientStreamingServer[pb.StatsRequest, pb.Stats]) error {
+	s.counters.Increment(counter.Stats)
+
+	accumulator := stats.NewAccumulator(stats.DefaultExactLimit)
+	var quantiles []float64
+	for {
+		in, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := addValues(accumulator, in.Values); err != nil {
+			return err
+		}
+		if quantiles == nil {
+			quantiles = in.Quantiles
+		}
+	}
+
+	result, err := summarize(accumulator, quantiles)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(result)
+}
+
+// This function adds the values to the dataset, and turns the first value which isn't a number into a gRPC error.
+func addValues(accumulator *stats.Accumulator, values []float64) error {
+	for _, value := range values {
+		if err := accumulator.Add(value); err != nil {
+			return status.Errorf(codes.InvalidArgument, "stats: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// This function works out the statistics of the dataset, and turns the error into a gRPC error.
+// Statistics which overflow are OUT_OF_RANGE, and every other error is INVALID_ARGUMENT.
+func summarize(accumulator *stats.Accumulator, quantiles []float64) (*pb.Stats, error) {
+	summary, err := accumulator.Summarize(quantiles)
+	if errors.Is(err, math.ErrOverflow) {
+		return nil, status.Errorf(codes.OutOfRange, "stats: %v", err)
+	} else if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "stats: %v", err)
+	}
+
+	result := &pb.Stats{
+		Count:    summary.Count,
+		Sum:      summary.Sum,
+		Mean:     summary.Mean,
+		Variance: summary.Variance,
+		Stddev:   summary.StdDev,
+		Min:      summary.Min,
+		Max:      summary.Max,
+		Median:   summary.Median,
+		Exact:    summary.Exact,
+	}
+	for _, quantile := range summary.Quantiles {
+		result.Quantiles = append(result.Quantiles, &pb.QuantileValue{Quantile: quantile.Q, Value: quantile.Value})
+	}
+
+	return result, nil
+}
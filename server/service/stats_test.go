@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMagicStats(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	result, err := client.MagicStats(context.Background(), &pb.StatsRequest{
+		Values:    []float64{2, 4, 4, 4, 5, 5, 7, 9},
+		Quantiles: []float64{0.25, 0.75},
+	})
+	want := &pb.Stats{
+		Count: 8, Sum: 40, Mean: 5, Variance: 32.0 / 7, Stddev: math.Sqrt(32.0 / 7), Min: 2, Max: 9, Median: 4.5,
+		Quantiles: []*pb.QuantileValue{{Quantile: 0.25, Value: 4}, {Quantile: 0.75, Value: 5.5}},
+		Exact:     true,
+	}
+	if err != nil || !proto.Equal(result, want) {
+		t.Errorf("MagicStats() = %v, %v; want %v", result, err, want)
+	}
+}
+
+func TestMagicStatsStream(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	stream, err := client.MagicStatsStream(context.Background())
+	if err != nil {
+		t.Fatalf("MagicStatsStream() returned error: %v", err)
+	}
+
+	// Send the numbers from 1 to 100000 in chunks, which is too many to keep for exact quantiles.
+	for chunk := range 100 {
+		request := &pb.StatsRequest{Values: make([]float64, 1000)}
+		for i := range request.Values {
+			request.Values[i] = float64(chunk*1000 + i + 1)
+		}
+		if chunk == 0 {
+			request.Quantiles = []float64{0.99}
+		}
+		if err := stream.Send(request); err != nil {
+			t.Fatalf("Send() returned error: %v", err)
+		}
+	}
+
+	result, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv() returned error: %v", err)
+	}
+	if result.Count != 100000 || result.Sum != 5000050000 || result.Mean != 50000.5 || result.Min != 1 ||
+		result.Max != 100000 || result.Exact {
+		t.Errorf("MagicStatsStream() = %v; want an estimate of the numbers from 1 to 100000", result)
+	}
+	if math.Abs(result.Median-50000.5) > 500 || len(result.Quantiles) != 1 || math.Abs(result.Quantiles[0].Value-99000) > 100 {
+		t.Errorf("MagicStatsStream() has median %v and quantiles %v; want about 50000.5 and 99000",
+			result.Median, result.Quantiles)
+	}
+
+	count, err := client.GetStatsCount(context.Background(), &pb.Empty{})
+	if err != nil || count.Count != 1 {
+		t.Errorf("GetStatsCount() = %v, %v; want 1", count, err)
+	}
+}
+
+func TestMagicStatsErrors(t *testing.T) {
+	client := servertest.Start(t).Client
+
+	tests := []struct {
+		request  *pb.StatsRequest
+		wantCode codes.Code
+	}{
+		{&pb.StatsRequest{}, codes.InvalidArgument},
+		{&pb.StatsRequest{Values: []float64{1, math.NaN()}}, codes.InvalidArgument},
+		{&pb.StatsRequest{Values: []float64{1}, Quantiles: []float64{1.5}}, codes.InvalidArgument},
+		{&pb.StatsRequest{Values: []float64{math.MaxFloat64, math.MaxFloat64}}, codes.OutOfRange},
+	}
+
+	for _, tt := range tests {
+		if _, err := client.MagicStats(context.Background(), tt.request); status.Code(err) != tt.wantCode {
+			t.Errorf("MagicStats(%v) returned error %v; want code %v", tt.request, err, tt.wantCode)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/math/vector"
+)
+
+// VectorMath implements the VectorMath service, which works on vectors and matrices rather than single terms.
+// Operands with the wrong shapes, too many elements or elements which aren't finite are INVALID_ARGUMENT,
+// and results which overflow are OUT_OF_RANGE.
+type VectorMath struct {
+	pb.UnsafeVectorMathServer
+}
+
+// NewVectorMath creates the VectorMath service.
+func NewVectorMath() *VectorMath {
+	return &VectorMath{}
+}
+
+// Add takes a request context (which is ignored) and two vectors, and returns their element-wise sum.
+func (v *VectorMath) Add(_ context.Context, in *pb.VectorPair) (*pb.Vector, error) {
+	sum, err := vector.Add(in.GetA().GetValues(), in.GetB().GetValues())
+	if err != nil {
+		return nil, arithmeticError(err, "vector add")
+	}
+
+	return &pb.Vector{Values: sum}, nil
+}
+
+// Subtract takes a request context (which is ignored) and two vectors, and returns their element-wise difference.
+func (v *VectorMath) Subtract(_ context.Context, in *pb.VectorPair) (*pb.Vector, error) {
+	difference, err := vector.Subtract(in.GetA().GetValues(), in.GetB().GetValues())
+	if err != nil {
+		return nil, arithmeticError(err, "vector subtract")
+	}
+
+	return &pb.Vector{Values: difference}, nil
+}
+
+// Dot takes a request context (which is ignored) and two vectors, and returns their dot product.
+func (v *VectorMath) Dot(_ context.Context, in *pb.VectorPair) (*pb.Scalar, error) {
+	dot, err := vector.Dot(in.GetA().GetValues(), in.GetB().GetValues())
+	if err != nil {
+		return nil, arithmeticError(err, "dot product")
+	}
+
+	return &pb.Scalar{Value: dot}, nil
+}
+
+// Norm takes a request context (which is ignored) and a vector, and returns its norm.
+func (v *VectorMath) Norm(_ context.Context, in *pb.NormRequest) (*pb.Scalar, error) {
+	norm, err := vector.Norm(in.GetVector().GetValues(), in.Norm)
+	if err != nil {
+		return nil, arithmeticError(err, "norm")
+	}
+
+	return &pb.Scalar{Value: norm}, nil
+}
+
+// MatrixMultiply takes a request context (which is ignored) and two matrices, and returns their product.
+func (v *VectorMath) MatrixMultiply(_ context.Context, in *pb.MatrixPair) (*pb.Matrix, error) {
+	product, err := vector.Multiply(matrixFromProto(in.GetA()), matrixFromProto(in.GetB()))
+	if err != nil {
+		return nil, arithmeticError(err, "matrix multiply")
+	}
+
+	return matrixToProto(product), nil
+}
+
+// Transpose takes a request context (which is ignored) and a matrix, and returns its transpose.
+func (v *VectorMath) Transpose(_ context.Context, in *pb.Matrix) (*pb.Matrix, error) {
+	transposed, err := vector.Transpose(matrixFromProto(in))
+	if err != nil {
+		return nil, arithmeticError(err, "transpose")
+	}
+
+	return matrixToProto(transposed), nil
+}
+
+// This function converts a matrix message into a matrix. A missing matrix has no rows and no columns.
+func matrixFromProto(m *pb.Matrix) vector.Matrix {
+	return vector.Matrix{Rows: int(m.GetRows()), Columns: int(m.GetColumns()), Values: m.GetValues()}
+}
+
+// This function converts a matrix into a matrix message.
+func matrixToProto(m vector.Matrix) *pb.Matrix {
+	return &pb.Matrix{Rows: int32(m.Rows), Columns: int32(m.Columns), Values: m.Values}
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/counter"
+	"github.com/karldmenzel/go-grpc-client-server/server/idempotency"
+	"github.com/karldmenzel/go-grpc-client-server/server/ratelimit"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestVectorMath(t *testing.T) {
+	client := pb.NewVectorMathClient(servertest.Start(t).Conn)
+	ctx := context.Background()
+	pair := &pb.VectorPair{A: &pb.Vector{Values: []float64{1, 2, 3}}, B: &pb.Vector{Values: []float64{4, 5, 6}}}
+
+	sum, err := client.Add(ctx, pair)
+	if want := (&pb.Vector{Values: []float64{5, 7, 9}}); err != nil || !proto.Equal(sum, want) {
+		t.Errorf("Add() = %v, %v; want %v", sum, err, want)
+	}
+	difference, err := client.Subtract(ctx, pair)
+	if want := (&pb.Vector{Values: []float64{-3, -3, -3}}); err != nil || !proto.Equal(difference, want) {
+		t.Errorf("Subtract() = %v, %v; want %v", difference, err, want)
+	}
+	dot, err := client.Dot(ctx, pair)
+	if err != nil || dot.Value != 32 {
+		t.Errorf("Dot() = %v, %v; want 32", dot, err)
+	}
+	norm, err := client.Norm(ctx, &pb.NormRequest{Vector: &pb.Vector{Values: []float64{3, -4}}})
+	if err != nil || norm.Value != 5 {
+		t.Errorf("Norm() = %v, %v; want 5", norm, err)
+	}
+
+	a := &pb.Matrix{Rows: 2, Columns: 3, Values: []float64{1, 2, 3, 4, 5, 6}}
+	product, err := client.MatrixMultiply(ctx, &pb.MatrixPair{
+		A: a,
+		B: &pb.Matrix{Rows: 3, Columns: 2, Values: []float64{7, 8, 9, 10, 11, 12}},
+	})
+	if want := (&pb.Matrix{Rows: 2, Columns: 2, Values: []float64{58, 64, 139, 154}}); err != nil || !proto.Equal(product, want) {
+		t.Errorf("MatrixMultiply() = %v, %v; want %v", product, err, want)
+	}
+	transposed, err := client.Transpose(ctx, a)
+	if want := (&pb.Matrix{Rows: 3, Columns: 2, Values: []float64{1, 4, 2, 5, 3, 6}}); err != nil || !proto.Equal(transposed, want) {
+		t.Errorf("Transpose() = %v, %v; want %v", transposed, err, want)
+	}
+}
+
+func TestVectorMathErrors(t *testing.T) {
+	client := pb.NewVectorMathClient(servertest.Start(t).Conn)
+	ctx := context.Background()
+
+	var tests = []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"Add with different lengths", func() error {
+			_, err := client.Add(ctx, &pb.VectorPair{A: &pb.Vector{Values: []float64{1}}, B: &pb.Vector{Values: []float64{1, 2}}})
+			return err
+		}, codes.InvalidArgument},
+		{"Dot with NaN", func() error {
+			_, err := client.Dot(ctx, &pb.VectorPair{A: &pb.Vector{Values: []float64{math.NaN()}}, B: &pb.Vector{Values: []float64{1}}})
+			return err
+		}, codes.InvalidArgument},
+		{"Dot which overflows", func() error {
+			_, err := client.Dot(ctx, &pb.VectorPair{A: &pb.Vector{Values: []float64{1e300}}, B: &pb.Vector{Values: []float64{1e300}}})
+			return err
+		}, codes.OutOfRange},
+		{"Norm with an unknown name", func() error {
+			_, err := client.Norm(ctx, &pb.NormRequest{Vector: &pb.Vector{Values: []float64{1}}, Norm: "l7"})
+			return err
+		}, codes.InvalidArgument},
+		{"MatrixMultiply with mismatched shapes", func() error {
+			square := &pb.Matrix{Rows: 2, Columns: 2, Values: []float64{1, 2, 3, 4}}
+			_, err := client.MatrixMultiply(ctx, &pb.MatrixPair{A: square, B: &pb.Matrix{Rows: 3, Columns: 1, Values: []float64{1, 2, 3}}})
+			return err
+		}, codes.InvalidArgument},
+		{"Transpose with too few values", func() error {
+			_, err := client.Transpose(ctx, &pb.Matrix{Rows: 2, Columns: 2, Values: []float64{1, 2, 3}})
+			return err
+		}, codes.InvalidArgument},
+		{"Transpose with too many elements", func() error {
+			_, err := client.Transpose(ctx, &pb.Matrix{Rows: 1 << 20, Columns: 1 << 20})
+			return err
+		}, codes.InvalidArgument},
+	}
+
+	for _, test := range tests {
+		if err := test.call(); status.Code(err) != test.want {
+			t.Errorf("%s returned %v; want %v", test.name, err, test.want)
+		}
+	}
+}
+
+func TestVectorMathIsLimitedAndDeduplicated(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{HourlyQuota: 2}, counter.NewMemoryStore())
+	deduplicator := idempotency.New(idempotency.Options{})
+	client := pb.NewVectorMathClient(servertest.Start(t,
+		servertest.WithRateLimiter(limiter), servertest.WithDeduplicator(deduplicator)).Conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), caller.MetadataKey, "alice")
+	pair := &pb.VectorPair{A: &pb.Vector{Values: []float64{1, 2}}, B: &pb.Vector{Values: []float64{3, 4}}}
+
+	// A retry with the same idempotency key is answered with the first response, and counts against the quota once.
+	keyed := metadata.AppendToOutgoingContext(ctx, idempotency.MetadataKey, "dot-1")
+	for i := range 2 {
+		if dot, err := client.Dot(keyed, pair); err != nil || dot.Value != 11 {
+			t.Fatalf("Dot() call %d = %v, %v; want 11", i, dot, err)
+		}
+	}
+	if _, err := client.Norm(ctx, &pb.NormRequest{Vector: pair.A}); err != nil {
+		t.Fatalf("Norm() returned error: %v", err)
+	}
+
+	if _, err := client.Dot(ctx, pair); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Dot() over the quota returned %v; want ResourceExhausted", err)
+	}
+}
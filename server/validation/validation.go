@@ -0,0 +1,266 @@
+// Package validation checks requests against the validation rules declared on their fields in the proto files,
+// with the (rules) option from validation.proto, before they reach the handlers. New messages and remote functions
+// are validated as soon as their fields have rules, without any code in their handlers.
+package validation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"sync"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// MaxViolations is the most violations reported for one message, so that a long list of bad values doesn't make
+// an error bigger than the request.
+const MaxViolations = 20
+
+// Validate checks the message, and every message inside it, against the rules of their fields.
+// It returns the violations it finds, or nothing if the message is valid.
+func Validate(message proto.Message) []*errdetails.BadRequest_FieldViolation {
+	var v violations
+	v.message("", message.ProtoReflect())
+	return v
+}
+
+// UnaryServerInterceptor rejects unary calls whose request breaks the rules of its fields with codes.InvalidArgument,
+// and a BadRequest detail listing every violation.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if message, ok := req.(proto.Message); ok {
+			if v := Validate(message); len(v) > 0 {
+				return nil, invalid(info.FullMethod, v)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor checks every message a client streams to the server, and fails the call with
+// codes.InvalidArgument when one of them breaks the rules of its fields.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: stream, method: info.FullMethod})
+	}
+}
+
+// validatingStream validates each message it receives.
+type validatingStream struct {
+	grpc.ServerStream
+	method string
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if message, ok := m.(proto.Message); ok {
+		if v := Validate(message); len(v) > 0 {
+			return invalid(s.method, v)
+		}
+	}
+
+	return nil
+}
+
+// This function creates an InvalidArgument error with a BadRequest detail listing the violations.
+func invalid(method string, v []*errdetails.BadRequest_FieldViolation) error {
+	message := path.Base(method) + ": invalid request"
+	for _, violation := range v {
+		message += ", " + violation.Field + ": " + violation.Description
+	}
+
+	st, err := status.New(codes.InvalidArgument, message).WithDetails(&errdetails.BadRequest{FieldViolations: v})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, message)
+	}
+
+	return st.Err()
+}
+
+// checkedField is a field which has rules, or which may hold messages whose fields have rules.
+type checkedField struct {
+	field protoreflect.FieldDescriptor
+	rules *pb.FieldRules
+}
+
+// This caches the checked fields of each message type, as reading the options of every field on every call is slow.
+var checkedFields sync.Map // protoreflect.MessageDescriptor -> []checkedField
+
+// This function returns the fields of a message type which need to be checked.
+func fieldsOf(descriptor protoreflect.MessageDescriptor) []checkedField {
+	if cached, ok := checkedFields.Load(descriptor); ok {
+		return cached.([]checkedField)
+	}
+
+	var fields []checkedField
+	all := descriptor.Fields()
+	for i := range all.Len() {
+		field := all.Get(i)
+		rules, _ := proto.GetExtension(field.Options(), pb.E_Rules).(*pb.FieldRules)
+		if rules != nil || holdsMessages(field) {
+			fields = append(fields, checkedField{field: field, rules: rules})
+		}
+	}
+
+	checkedFields.Store(descriptor, fields)
+	return fields
+}
+
+// This function returns true if the field is a message, a list of messages or a map with message values.
+func holdsMessages(field protoreflect.FieldDescriptor) bool {
+	if field.IsMap() {
+		field = field.MapValue()
+	}
+
+	return field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind
+}
+
+// violations collects the violations in a message, up to MaxViolations.
+type violations []*errdetails.BadRequest_FieldViolation
+
+// This function records that the field is wrong, and why.
+func (v *violations) add(field, format string, args ...any) {
+	if len(*v) < MaxViolations {
+		*v = append(*v, &errdetails.BadRequest_FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+	}
+}
+
+// This function checks the fields of a message, whose own path is prefix.
+func (v *violations) message(prefix string, message protoreflect.Message) {
+	if packed, ok := message.Interface().(*anypb.Any); ok {
+		// The message inside an Any is checked as if it was the field itself. If its type is unknown, the handler
+		// will reject it.
+		if inner, err := packed.UnmarshalNew(); err == nil {
+			v.message(prefix, inner.ProtoReflect())
+		}
+		return
+	}
+
+	for _, checked := range fieldsOf(message.Descriptor()) {
+		if len(*v) >= MaxViolations {
+			return
+		}
+
+		field := checked.field
+		name := prefix + string(field.Name())
+		switch {
+		case field.IsList():
+			list := message.Get(field).List()
+			if !v.count(name, list.Len(), checked.rules) {
+				continue
+			}
+			for i := range list.Len() {
+				v.value(func() string { return name + "[" + strconv.Itoa(i) + "]" }, field, list.Get(i), checked.rules)
+			}
+		case field.IsMap():
+			entries := message.Get(field).Map()
+			if !v.count(name, entries.Len(), checked.rules) {
+				continue
+			}
+			entries.Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				v.value(func() string { return name + "[" + strconv.Quote(key.String()) + "]" }, field.MapValue(), value,
+					checked.rules)
+				return true
+			})
+		case field.Message() != nil:
+			if message.Has(field) {
+				v.value(func() string { return name }, field, message.Get(field), checked.rules)
+			} else if checked.rules.GetRequired() {
+				v.add(name, "is required")
+			}
+		case field.HasPresence() && !message.Has(field):
+			// Optional fields, and the fields of a oneof, are only checked when they are set.
+		default:
+			v.value(func() string { return name }, field, message.Get(field), checked.rules)
+		}
+	}
+}
+
+// This function checks the number of elements in a list or map. It returns false if there are too many of them
+// to check each one.
+func (v *violations) count(name string, count int, rules *pb.FieldRules) bool {
+	if rules == nil {
+		return true
+	}
+	if rules.MinItems != nil && count < int(rules.GetMinItems()) {
+		v.add(name, "must have at least %d elements", rules.GetMinItems())
+	}
+	if rules.MaxItems != nil && count > int(rules.GetMaxItems()) {
+		v.add(name, "must have at most %d elements", rules.GetMaxItems())
+		return false
+	}
+
+	return true
+}
+
+// This function checks a single value of a field, which is the field itself or an element of a list or map.
+// Messages are checked field by field, with name as the prefix of their fields' paths. The name of any other
+// value is only worked out if it is wrong, as the values of long lists are checked one by one.
+func (v *violations) value(
+	name func() string,
+	field protoreflect.FieldDescriptor,
+	value protoreflect.Value,
+	rules *pb.FieldRules,
+) {
+	var problems []string
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		v.message(name()+".", value.Message())
+	case protoreflect.DoubleKind, protoreflect.FloatKind:
+		problems = checkNumber(value.Float(), rules)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		problems = checkNumber(float64(value.Int()), rules)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		problems = checkNumber(float64(value.Uint()), rules)
+	case protoreflect.StringKind:
+		problems = checkLength(len(value.String()), rules)
+	case protoreflect.BytesKind:
+		problems = checkLength(len(value.Bytes()), rules)
+	}
+
+	for _, problem := range problems {
+		v.add(name(), "%s", problem)
+	}
+}
+
+// This function checks a number against the finite, min and max rules, and returns the ones it breaks.
+func checkNumber(number float64, rules *pb.FieldRules) []string {
+	if rules == nil {
+		return nil
+	}
+	if rules.GetFinite() && (math.IsNaN(number) || math.IsInf(number, 0)) {
+		return []string{fmt.Sprintf("must be a finite number, not %v", number)}
+	}
+
+	var problems []string
+	// NaN is neither at least min nor at most max, so it breaks both rules.
+	if rules.Min != nil && !(number >= rules.GetMin()) {
+		problems = append(problems, fmt.Sprintf("must be at least %v, not %v", rules.GetMin(), number))
+	}
+	if rules.Max != nil && !(number <= rules.GetMax()) {
+		problems = append(problems, fmt.Sprintf("must be at most %v, not %v", rules.GetMax(), number))
+	}
+	return problems
+}
+
+// This function checks the length of a string or bytes against the maxLength rule.
+func checkLength(length int, rules *pb.FieldRules) []string {
+	if rules != nil && rules.MaxLength != nil && length > int(rules.GetMaxLength()) {
+		return []string{fmt.Sprintf("must be at most %d bytes long, not %d", rules.GetMaxLength(), length)}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	pbv2 "github.com/karldmenzel/go-grpc-client-server/magicMath/v2"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// This function packs a message into an Any, failing the test if it can't.
+func mustPack(t *testing.T, message proto.Message) *anypb.Any {
+	t.Helper()
+	packed, err := anypb.New(message)
+	if err != nil {
+		t.Fatalf("anypb.New(%v) returned error %v", message, err)
+	}
+	return packed
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    proto.Message
+		wantFields []string
+	}{
+		{"finite values", &pb.StatsRequest{Values: []float64{1.5, -2}}, nil},
+		{"infinite value", &pb.StatsRequest{Values: []float64{math.Inf(1), 2}}, []string{"values[0]"}},
+		{"NaN values", &pb.StatsRequest{Values: []float64{math.NaN(), math.Inf(-1)}}, []string{"values[0]", "values[1]"}},
+		{"integer terms have no rules", &pb.IntTerms{TermOne: math.MinInt64, TermTwo: math.MaxInt64}, nil},
+		// The version 1 double terms have always allowed infinities and NaN.
+		{"double terms have no rules", &pb.DoubleTerms{TermOne: math.Inf(1), TermTwo: math.NaN()}, nil},
+		{"oneof member", &pbv2.CalculateRequest{Operands: &pbv2.CalculateRequest_Doubles{
+			Doubles: &pbv2.DoubleOperands{Values: []float64{1, math.NaN()}},
+		}}, []string{"doubles.values[1]"}},
+		{"unset oneof", &pbv2.CalculateRequest{}, nil},
+		{"negative precision", &pb.BigTerms{TermOne: "1", TermTwo: "2", Precision: -1}, []string{"precision"}},
+		{"list elements", &pb.StatsRequest{Values: []float64{1, math.NaN(), 3}, Quantiles: []float64{0.5, 1.5, -1}},
+			[]string{"values[1]", "quantiles[1]", "quantiles[2]"}},
+		{"NaN quantile", &pb.StatsRequest{Quantiles: []float64{math.NaN()}}, []string{"quantiles[0]", "quantiles[0]"}},
+		{"too many quantiles", &pb.StatsRequest{Quantiles: make([]float64, 101)}, []string{"quantiles"}},
+		{"map values", &pb.Expression{Expression: "a", Variables: map[string]float64{"a": math.Inf(1)}},
+			[]string{`variables["a"]`}},
+		{"long expression", &pb.Expression{Expression: strings.Repeat("1+", 2049)}, []string{"expression"}},
+		{"nested messages", &pb.MatrixPair{A: &pb.Matrix{Rows: -1, Columns: 1}, B: &pb.Matrix{Values: []float64{math.NaN()}}},
+			[]string{"a.rows", "b.values[0]"}},
+		{"packed request", &pb.SubmitRequest{
+			Method:  "MagicStats",
+			Request: mustPack(t, &pb.StatsRequest{Values: []float64{math.Inf(1)}}),
+		}, []string{"request.values[0]"}},
+		{"many violations", &pb.StatsRequest{Values: slices.Repeat([]float64{math.NaN()}, 100)}, []string{
+			"values[0]", "values[1]", "values[2]", "values[3]", "values[4]", "values[5]", "values[6]", "values[7]",
+			"values[8]", "values[9]", "values[10]", "values[11]", "values[12]", "values[13]", "values[14]",
+			"values[15]", "values[16]", "values[17]", "values[18]", "values[19]",
+		}},
+	}
+
+	for _, tt := range tests {
+		var fields []string
+		for _, violation := range Validate(tt.message) {
+			fields = append(fields, violation.Field)
+		}
+		if !slices.Equal(fields, tt.wantFields) {
+			t.Errorf("%s: Validate(%v) has field violations %v; want %v", tt.name, tt.message, fields, tt.wantFields)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/shared.MagicMath/MagicStats"}
+	called := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		called = true
+		return &pb.Stats{}, nil
+	}
+
+	_, err := interceptor(context.Background(), &pb.StatsRequest{Values: []float64{math.NaN(), 1}}, info, handler)
+	if status.Code(err) != codes.InvalidArgument || called {
+		t.Fatalf("interceptor([NaN 1]) returned error %v and called the handler %v; want %v and false",
+			err, called, codes.InvalidArgument)
+	}
+	want := "MagicStats: invalid request, values[0]: must be a finite number, not NaN"
+	if message := status.Convert(err).Message(); message != want {
+		t.Errorf("interceptor([NaN 1]) returned message %q; want %q", message, want)
+	}
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("interceptor([NaN 1]) returned details %v; want a BadRequest", details)
+	}
+	if badRequest, ok := details[0].(*errdetails.BadRequest); !ok || len(badRequest.FieldViolations) != 1 {
+		t.Errorf("interceptor([NaN 1]) returned detail %v; want a BadRequest with one violation", details[0])
+	}
+
+	_, err = interceptor(context.Background(), &pb.StatsRequest{Values: []float64{1, 2}}, info, handler)
+	if err != nil || !called {
+		t.Errorf("interceptor([1 2]) returned error %v and called the handler %v; want nil and true", err, called)
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	request := &pb.StatsRequest{Values: make([]float64, 1000), Quantiles: []float64{0.5, 0.9, 0.99}}
+	for b.Loop() {
+		Validate(request)
+	}
+}
@@ -0,0 +1,242 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+
+	"connectrpc.com/connect"
+	connectcors "connectrpc.com/cors"
+	"github.com/rs/cors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Options configures the web handler.
+type Options struct {
+	// AllowedOrigins lists the origins browsers may call the server from, for example "https://dashboard.example.com".
+	// "*" allows every origin. If it is empty, browsers may only call the server from its own origin.
+	AllowedOrigins []string
+}
+
+// Handler serves native gRPC, gRPC-Web and the Connect protocol (JSON or binary, over HTTP/1.1 or HTTP/2)
+// on a single listener. Native gRPC requests are handed straight to the gRPC server. gRPC-Web and Connect
+// requests are translated into gRPC calls to the same server over an in-memory connection, so they go through
+// the same interceptors and handlers as every other request.
+type Handler struct {
+	grpcServer *grpc.Server
+	connect    http.Handler
+	listener   *bufconn.Listener
+	loopback   *grpc.ClientConn
+}
+
+// New creates the web handler for every service registered on the gRPC server.
+// It must be called after all the services have been registered.
+func New(grpcServer *grpc.Server, options Options) (*Handler, error) {
+	listener := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(listener)
+
+	loopback, err := grpc.NewClient("passthrough:///loopback",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	for serviceName := range grpcServer.GetServiceInfo() {
+		descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+		if err != nil {
+			continue
+		}
+		service, ok := descriptor.(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			if method.IsStreamingClient() || method.IsStreamingServer() {
+				continue
+			}
+			procedure := "/" + serviceName + "/" + string(method.Name())
+			mux.Handle(procedure, newUnaryHandler(loopback, procedure, method))
+		}
+	}
+
+	return &Handler{
+		grpcServer: grpcServer,
+		connect:    withCORS(mux, options.AllowedOrigins),
+		listener:   listener,
+		loopback:   loopback,
+	}, nil
+}
+
+// ServeHTTP sends native gRPC requests to the gRPC server, and every other request to the Connect handlers.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	if r.ProtoMajor == 2 && strings.HasPrefix(contentType, "application/grpc") && !strings.HasPrefix(contentType, "application/grpc-web") {
+		h.grpcServer.ServeHTTP(w, r)
+		return
+	}
+
+	h.connect.ServeHTTP(w, r)
+}
+
+// Close closes the in-memory connection to the gRPC server.
+func (h *Handler) Close() error {
+	err := h.loopback.Close()
+	h.listener.Close()
+
+	return err
+}
+
+// NewServer creates an HTTP server for the handler which accepts HTTP/1.1, and HTTP/2 without TLS,
+// which is what native gRPC clients use when they connect without TLS.
+func NewServer(handler http.Handler) *http.Server {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	return &http.Server{Handler: handler, Protocols: protocols}
+}
+
+// This function creates a Connect handler for one unary method. The request and response messages are
+// built from the method's descriptor, so the same code works for every method of every service.
+func newUnaryHandler(loopback *grpc.ClientConn, procedure string, method protoreflect.MethodDescriptor) http.Handler {
+	return connect.NewUnaryHandler(
+		procedure,
+		func(ctx context.Context, request *connect.Request[dynamicpb.Message]) (*connect.Response[dynamicpb.Message], error) {
+			ctx = metadata.NewOutgoingContext(ctx, forwardedMetadata(request.Header(), request.Peer().Addr))
+
+			var header, trailer metadata.MD
+			response := dynamicpb.NewMessage(method.Output())
+			err := loopback.Invoke(ctx, procedure, request.Msg, response, grpc.Header(&header), grpc.Trailer(&trailer))
+			if err != nil {
+				return nil, toConnectError(err, header, trailer)
+			}
+
+			connectResponse := connect.NewResponse(response)
+			copyMetadata(connectResponse.Header(), header)
+			copyMetadata(connectResponse.Trailer(), trailer)
+
+			return connectResponse, nil
+		},
+		connect.WithSchema(method),
+		connect.WithRequestInitializer(func(spec connect.Spec, message any) error {
+			dynamic, ok := message.(*dynamicpb.Message)
+			if !ok {
+				return errors.New("unexpected request message type")
+			}
+			*dynamic = *dynamicpb.NewMessage(method.Input())
+
+			return nil
+		}),
+	)
+}
+
+// These request headers belong to HTTP or to the Connect and gRPC-Web protocols, and are not passed to the
+// gRPC server as metadata.
+var protocolHeaders = map[string]bool{
+	"accept":          true,
+	"accept-encoding": true,
+	"connection":      true,
+	"content-length":  true,
+	"content-type":    true,
+	"host":            true,
+	"origin":          true,
+	"te":              true,
+	"user-agent":      true,
+	"x-grpc-web":      true,
+}
+
+// These request headers say who the caller is. They are only passed on for clients which the caller package trusts to
+// identify themselves, anyone else could set them to anything, so they are dropped. The address the request came from
+// is passed on in either case.
+var identityHeaders = map[string]bool{
+	"x-forwarded-for":  true,
+	caller.MetadataKey: true,
+}
+
+// This function copies the application headers of a request from the peer address into gRPC metadata,
+// and records the caller's address in the "x-forwarded-for" header.
+func forwardedMetadata(headers http.Header, peerAddress string) metadata.MD {
+	host, _, err := net.SplitHostPort(peerAddress)
+	if err != nil {
+		host = peerAddress
+	}
+	trusted := caller.Trusted(host)
+
+	md := metadata.MD{}
+	for key, values := range headers {
+		key = strings.ToLower(key)
+		if protocolHeaders[key] || (identityHeaders[key] && !trusted) || strings.HasPrefix(key, "connect-") ||
+			strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+		md.Append(key, values...)
+	}
+
+	if err == nil {
+		md.Append("x-forwarded-for", host)
+	}
+
+	return md
+}
+
+// This function copies gRPC metadata into HTTP headers, leaving out the ones which belong to the gRPC protocol.
+func copyMetadata(headers http.Header, md metadata.MD) {
+	for key, values := range md {
+		if key == "content-type" || strings.HasPrefix(key, "grpc-") {
+			continue
+		}
+		for _, value := range values {
+			headers.Add(key, value)
+		}
+	}
+}
+
+// This function turns the error returned by the gRPC server into a Connect error with the same code,
+// message and details.
+func toConnectError(err error, header, trailer metadata.MD) error {
+	grpcStatus := status.Convert(err)
+	connectError := connect.NewError(connect.Code(grpcStatus.Code()), errors.New(grpcStatus.Message()))
+
+	for _, detail := range grpcStatus.Proto().GetDetails() {
+		if errorDetail, err := connect.NewErrorDetail(detail); err == nil {
+			connectError.AddDetail(errorDetail)
+		}
+	}
+	copyMetadata(connectError.Meta(), header)
+	copyMetadata(connectError.Meta(), trailer)
+
+	return connectError
+}
+
+// This function wraps the Connect handlers so that browsers on the allowed origins may call them.
+func withCORS(handler http.Handler, allowedOrigins []string) http.Handler {
+	if len(allowedOrigins) == 0 {
+		return handler
+	}
+
+	return cors.New(cors.Options{
+		AllowedOrigins: allowedOrigins,
+		AllowedMethods: connectcors.AllowedMethods(),
+		AllowedHeaders: []string{"*"},
+		ExposedHeaders: connectcors.ExposedHeaders(),
+		MaxAge:         7200,
+	}).Handler(handler)
+}
@@ -0,0 +1,193 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/karldmenzel/go-grpc-client-server/magicMath"
+	"github.com/karldmenzel/go-grpc-client-server/server/caller"
+	"github.com/karldmenzel/go-grpc-client-server/server/servertest"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// startWebServer starts a gRPC server with the MagicMath service, and an HTTP test server running the web handler.
+func startWebServer(t *testing.T, options Options) *httptest.Server {
+	t.Helper()
+
+	handler, err := New(servertest.Start(t).GRPCServer, options)
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	t.Cleanup(func() { handler.Close() })
+
+	httpServer := httptest.NewUnstartedServer(handler)
+	httpServer.Config = NewServer(handler)
+	httpServer.Start()
+	t.Cleanup(httpServer.Close)
+
+	return httpServer
+}
+
+func TestConnectJSON(t *testing.T) {
+	httpServer := startWebServer(t, Options{})
+
+	resp, err := http.Post(httpServer.URL+pb.MagicMath_MagicAdd_FullMethodName, "application/json",
+		strings.NewReader(`{"termOne": 1.5, "termTwo": 2}`))
+	if err != nil {
+		t.Fatalf("POST returned error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	result := &pb.DoubleResult{}
+	if resp.StatusCode != http.StatusOK || protojson.Unmarshal(body, result) != nil || !proto.Equal(result, &pb.DoubleResult{Result: 3.5}) {
+		t.Errorf("POST %s = %d %s; want 200 {\"result\":3.5}", pb.MagicMath_MagicAdd_FullMethodName, resp.StatusCode, body)
+	}
+}
+
+func TestConnectAndGRPCWebBinary(t *testing.T) {
+	httpServer := startWebServer(t, Options{})
+
+	tests := []struct {
+		name    string
+		options []connect.ClientOption
+	}{
+		{"connect", nil},
+		{"connect json", []connect.ClientOption{connect.WithProtoJSON()}},
+		{"grpc-web", []connect.ClientOption{connect.WithGRPCWeb()}},
+	}
+
+	for _, tt := range tests {
+		client := connect.NewClient[pb.IntTerms, pb.IntResult](httpServer.Client(),
+			httpServer.URL+pb.MagicMath_MagicFindMin_FullMethodName, tt.options...)
+
+		resp, err := client.CallUnary(context.Background(), connect.NewRequest(&pb.IntTerms{TermOne: 3, TermTwo: -1, TermThree: 7}))
+		if err != nil {
+			t.Errorf("%s: MagicFindMin() returned error: %v", tt.name, err)
+			continue
+		}
+		if resp.Msg.Result != -1 {
+			t.Errorf("%s: MagicFindMin(3, -1, 7) = %d; want -1", tt.name, resp.Msg.Result)
+		}
+	}
+}
+
+func TestCallerHeadersOfLocalClientsAreTrusted(t *testing.T) {
+	httpServer := startWebServer(t, Options{})
+	client := connect.NewClient[pb.Empty, pb.Quota](httpServer.Client(), httpServer.URL+pb.MagicMath_GetQuota_FullMethodName)
+
+	// A client on this machine may name itself, as it could on the gRPC port.
+	request := connect.NewRequest(&pb.Empty{})
+	request.Header().Set("X-Caller-Id", "alice")
+	resp, err := client.CallUnary(context.Background(), request)
+	if err != nil {
+		t.Fatalf("GetQuota() returned error: %v", err)
+	}
+	if resp.Msg.Caller != "alice" {
+		t.Errorf("GetQuota() caller = %q; want the caller the local client named, alice", resp.Msg.Caller)
+	}
+}
+
+func TestForwardedMetadata(t *testing.T) {
+	trusted := caller.TrustedNetworks
+	caller.TrustedNetworks = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	t.Cleanup(func() { caller.TrustedNetworks = trusted })
+
+	headers := http.Header{}
+	headers.Set("X-Caller-Id", "alice")
+	headers.Set("X-Forwarded-For", "192.0.2.9")
+	headers.Set("Connect-Protocol-Version", "1")
+	headers.Set("X-Request-Id", "42")
+
+	tests := []struct {
+		peer          string
+		wantCaller    []string
+		wantForwarded []string
+	}{
+		// An untrusted client's identity headers are dropped, only its own address is passed on.
+		{"203.0.113.5:4000", nil, []string{"203.0.113.5"}},
+		// A trusted client's are passed on, with its address after the ones it sent.
+		{"10.1.2.3:4000", []string{"alice"}, []string{"192.0.2.9", "10.1.2.3"}},
+		{"127.0.0.1:4000", []string{"alice"}, []string{"192.0.2.9", "127.0.0.1"}},
+	}
+
+	for _, tt := range tests {
+		md := forwardedMetadata(headers, tt.peer)
+		if got := md.Get(caller.MetadataKey); !slices.Equal(got, tt.wantCaller) {
+			t.Errorf("forwardedMetadata(%s) has %s %v; want %v", tt.peer, caller.MetadataKey, got, tt.wantCaller)
+		}
+		if got := md.Get("x-forwarded-for"); !slices.Equal(got, tt.wantForwarded) {
+			t.Errorf("forwardedMetadata(%s) has x-forwarded-for %v; want %v", tt.peer, got, tt.wantForwarded)
+		}
+		if got := md.Get("x-request-id"); !slices.Equal(got, []string{"42"}) || len(md.Get("connect-protocol-version")) > 0 {
+			t.Errorf("forwardedMetadata(%s) = %v; want x-request-id passed on, and the protocol headers left out", tt.peer, md)
+		}
+	}
+}
+
+func TestNativeGRPCOnSameListener(t *testing.T) {
+	httpServer := startWebServer(t, Options{})
+
+	connection, err := grpc.NewClient(httpServer.Listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer connection.Close()
+	client := pb.NewMagicMathClient(connection)
+
+	if _, err := client.MagicSubtract(context.Background(), &pb.DoubleTerms{TermOne: 10, TermTwo: 3}); err != nil {
+		t.Fatalf("MagicSubtract() returned error: %v", err)
+	}
+
+	// The web protocols share the same server, and so the same counters.
+	countClient := connect.NewClient[pb.Empty, pb.Count](httpServer.Client(),
+		httpServer.URL+pb.MagicMath_GetSubCount_FullMethodName, connect.WithGRPCWeb())
+	count, err := countClient.CallUnary(context.Background(), connect.NewRequest(&pb.Empty{}))
+	if err != nil {
+		t.Fatalf("GetSubCount() returned error: %v", err)
+	}
+	if count.Msg.Count != 1 {
+		t.Errorf("GetSubCount() = %d; want 1", count.Msg.Count)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		wantOrigin     string
+	}{
+		{"allowed origin", []string{"https://dashboard.example.com"}, "https://dashboard.example.com"},
+		{"any origin", []string{"*"}, "*"},
+		{"no origins configured", nil, ""},
+	}
+
+	for _, tt := range tests {
+		httpServer := startWebServer(t, Options{AllowedOrigins: tt.allowedOrigins})
+
+		req, _ := http.NewRequest(http.MethodOptions, httpServer.URL+pb.MagicMath_MagicAdd_FullMethodName, nil)
+		req.Header.Set("Origin", "https://dashboard.example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "content-type,connect-protocol-version")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: OPTIONS returned error: %v", tt.name, err)
+		}
+		resp.Body.Close()
+
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+			t.Errorf("%s: Access-Control-Allow-Origin = %q; want %q", tt.name, got, tt.wantOrigin)
+		}
+	}
+}